@@ -6,6 +6,9 @@ import (
 	"github.com/influxdata/flux/internal/errors"
 )
 
+// And computes the logical and of l and r. Like the logical operators
+// of the interpreter, a null left value is treated as false and the
+// result is otherwise the right value, which may be null.
 func And(l, r *Boolean, mem memory.Allocator) (*Boolean, error) {
 	n := l.Len()
 	if n != r.Len() {
@@ -15,8 +18,10 @@ func And(l, r *Boolean, mem memory.Allocator) (*Boolean, error) {
 	b := NewBooleanBuilder(mem)
 	b.Resize(n)
 	for i := 0; i < n; i++ {
-		if l.IsValid(i) && r.IsValid(i) {
-			b.Append(l.Value(i) && r.Value(i))
+		if l.IsNull(i) || !l.Value(i) {
+			b.Append(false)
+		} else if r.IsValid(i) {
+			b.Append(r.Value(i))
 		} else {
 			b.AppendNull()
 		}
@@ -26,6 +31,9 @@ func And(l, r *Boolean, mem memory.Allocator) (*Boolean, error) {
 	return a, nil
 }
 
+// Or computes the logical or of l and r. Like the logical operators
+// of the interpreter, a null left value is treated as false and the
+// result is otherwise the right value, which may be null.
 func Or(l, r *Boolean, mem memory.Allocator) (*Boolean, error) {
 	n := l.Len()
 	if n != r.Len() {
@@ -35,8 +43,10 @@ func Or(l, r *Boolean, mem memory.Allocator) (*Boolean, error) {
 	b := NewBooleanBuilder(mem)
 	b.Resize(n)
 	for i := 0; i < n; i++ {
-		if l.IsValid(i) && r.IsValid(i) {
-			b.Append(l.Value(i) || r.Value(i))
+		if l.IsValid(i) && l.Value(i) {
+			b.Append(true)
+		} else if r.IsValid(i) {
+			b.Append(r.Value(i))
 		} else {
 			b.AppendNull()
 		}
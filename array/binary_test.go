@@ -0,0 +1,68 @@
+package array_test
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux/array"
+)
+
+func TestLogical(t *testing.T) {
+	newBooleans := func(mem memory.Allocator, vs ...interface{}) *array.Boolean {
+		b := array.NewBooleanBuilder(mem)
+		for _, v := range vs {
+			if v == nil {
+				b.AppendNull()
+				continue
+			}
+			b.Append(v.(bool))
+		}
+		return b.NewBooleanArray()
+	}
+
+	// Every combination of true, false and null.
+	l := []interface{}{true, true, true, false, false, false, nil, nil, nil}
+	r := []interface{}{true, false, nil, true, false, nil, true, false, nil}
+
+	for _, tc := range []struct {
+		name string
+		fn   func(l, r *array.Boolean, mem memory.Allocator) (*array.Boolean, error)
+		want []interface{}
+	}{
+		{
+			name: "And",
+			fn:   array.And,
+			want: []interface{}{true, false, nil, false, false, false, false, false, false},
+		},
+		{
+			name: "Or",
+			fn:   array.Or,
+			want: []interface{}{true, true, true, true, false, nil, true, false, nil},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+			defer mem.AssertSize(t, 0)
+
+			la, ra := newBooleans(mem, l...), newBooleans(mem, r...)
+			defer la.Release()
+			defer ra.Release()
+
+			got, err := tc.fn(la, ra, mem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer got.Release()
+
+			for i, want := range tc.want {
+				if want == nil {
+					if got.IsValid(i) {
+						t.Errorf("%d: expected null, got %v", i, got.Value(i))
+					}
+				} else if got.IsNull(i) || got.Value(i) != want {
+					t.Errorf("%d: expected %v, got %v (null: %v)", i, want, got.Value(i), got.IsNull(i))
+				}
+			}
+		})
+	}
+}
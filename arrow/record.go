@@ -0,0 +1,340 @@
+package arrow
+
+import (
+	stdarrow "github.com/apache/arrow/go/v7/arrow"
+	arrowarray "github.com/apache/arrow/go/v7/arrow/array"
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// GroupKeyMetadataKey is the field metadata key used to mark a column
+// as a member of the group key when a table is stored as an arrow record.
+const GroupKeyMetadataKey = "flux.group"
+
+// TimeType is the arrow data type used to store time columns.
+var TimeType = &stdarrow.TimestampType{Unit: stdarrow.Nanosecond, TimeZone: "UTC"}
+
+// DataType returns the arrow data type used to store
+// a column of the given type.
+func DataType(typ flux.ColType) (stdarrow.DataType, error) {
+	switch typ {
+	case flux.TInt:
+		return array.IntType, nil
+	case flux.TUInt:
+		return array.UintType, nil
+	case flux.TFloat:
+		return array.FloatType, nil
+	case flux.TString:
+		return array.StringType, nil
+	case flux.TBool:
+		return array.BooleanType, nil
+	case flux.TTime:
+		return TimeType, nil
+	default:
+		return nil, errors.Newf(codes.Invalid, "unsupported column type: %s", typ)
+	}
+}
+
+// ColType returns the flux column type that an arrow data type
+// is read as. It returns flux.TInvalid if the arrow data type
+// cannot be represented as a flux column.
+func ColType(dt stdarrow.DataType) flux.ColType {
+	switch dt.ID() {
+	case stdarrow.INT8, stdarrow.INT16, stdarrow.INT32, stdarrow.INT64:
+		return flux.TInt
+	case stdarrow.UINT8, stdarrow.UINT16, stdarrow.UINT32, stdarrow.UINT64:
+		return flux.TUInt
+	case stdarrow.FLOAT32, stdarrow.FLOAT64:
+		return flux.TFloat
+	case stdarrow.STRING, stdarrow.BINARY:
+		return flux.TString
+	case stdarrow.BOOL:
+		return flux.TBool
+	case stdarrow.TIMESTAMP, stdarrow.DATE32, stdarrow.DATE64:
+		return flux.TTime
+	default:
+		return flux.TInvalid
+	}
+}
+
+// Schema constructs an arrow schema from a list of columns.
+// Columns whose label is also present in keyCols are marked
+// as part of the group key using the field metadata.
+func Schema(cols []flux.ColMeta, keyCols []flux.ColMeta) (*stdarrow.Schema, error) {
+	fields := make([]stdarrow.Field, len(cols))
+	for i, col := range cols {
+		dt, err := DataType(col.Type)
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Inherit, "column %q", col.Label)
+		}
+		isKey := "false"
+		for _, c := range keyCols {
+			if c.Label == col.Label {
+				isKey = "true"
+				break
+			}
+		}
+		fields[i] = stdarrow.Field{
+			Name:     col.Label,
+			Type:     dt,
+			Nullable: true,
+			Metadata: stdarrow.NewMetadata(
+				[]string{GroupKeyMetadataKey},
+				[]string{isKey},
+			),
+		}
+	}
+	return stdarrow.NewSchema(fields, nil), nil
+}
+
+// Columns returns the flux columns described by an arrow schema.
+// The returned slice of booleans reports which of the columns were
+// marked as part of the group key by Schema.
+func Columns(schema *stdarrow.Schema) ([]flux.ColMeta, []bool, error) {
+	fields := schema.Fields()
+	cols := make([]flux.ColMeta, len(fields))
+	isKey := make([]bool, len(fields))
+	for i, f := range fields {
+		typ := ColType(f.Type)
+		if typ == flux.TInvalid {
+			return nil, nil, errors.Newf(codes.Invalid, "column %q has unsupported arrow type: %s", f.Name, f.Type)
+		}
+		cols[i] = flux.ColMeta{Label: f.Name, Type: typ}
+		if idx := f.Metadata.FindKey(GroupKeyMetadataKey); idx >= 0 {
+			isKey[i] = f.Metadata.Values()[idx] == "true"
+		}
+	}
+	return cols, isKey, nil
+}
+
+// NewRecord constructs an arrow record with the given schema
+// from the column reader. Columns in the schema that are not
+// present in the column reader are filled with null values.
+// The schema should have been created with Schema.
+//
+// The returned record must be released.
+func NewRecord(schema *stdarrow.Schema, cr flux.ColReader, mem memory.Allocator) (stdarrow.Record, error) {
+	n := cr.Len()
+	arrs := make([]stdarrow.Array, 0, len(schema.Fields()))
+	defer func() {
+		for _, arr := range arrs {
+			arr.Release()
+		}
+	}()
+
+	cols := cr.Cols()
+	for _, f := range schema.Fields() {
+		j := -1
+		for idx, col := range cols {
+			if col.Label == f.Name {
+				j = idx
+				break
+			}
+		}
+		if j < 0 {
+			arrs = append(arrs, newNullArray(f.Type, n, mem))
+			continue
+		}
+
+		if dt, err := DataType(cols[j].Type); err != nil {
+			return nil, err
+		} else if !stdarrow.TypeEqual(dt, f.Type) {
+			return nil, errors.Newf(codes.FailedPrecondition, "schema collision detected: column %q is both of type %s and %s", f.Name, f.Type, dt)
+		}
+
+		switch cols[j].Type {
+		case flux.TInt:
+			arr := cr.Ints(j)
+			arr.Retain()
+			arrs = append(arrs, arr)
+		case flux.TUInt:
+			arr := cr.UInts(j)
+			arr.Retain()
+			arrs = append(arrs, arr)
+		case flux.TFloat:
+			arr := cr.Floats(j)
+			arr.Retain()
+			arrs = append(arrs, arr)
+		case flux.TBool:
+			arr := cr.Bools(j)
+			arr.Retain()
+			arrs = append(arrs, arr)
+		case flux.TTime:
+			// Times are stored as int64 nanoseconds so the data
+			// can be reinterpreted as a timestamp without copying.
+			data := arrowarray.NewData(TimeType, n, cr.Times(j).Data().Buffers(), nil, cr.Times(j).NullN(), cr.Times(j).Data().Offset())
+			arrs = append(arrs, arrowarray.NewTimestampData(data))
+			data.Release()
+		case flux.TString:
+			arrs = append(arrs, toArrowString(cr.Strings(j), mem))
+		}
+	}
+	return arrowarray.NewRecord(schema, arrs, int64(n)), nil
+}
+
+// RecordValues converts the columns of an arrow record into flux arrays.
+// The columns should be the ones returned by Columns for the record's schema.
+//
+// The returned arrays must be released.
+func RecordValues(rec stdarrow.Record, cols []flux.ColMeta, mem memory.Allocator) ([]array.Array, error) {
+	vs := make([]array.Array, 0, len(cols))
+	for j, col := range cols {
+		arr, err := fromArrow(rec.Column(j), col.Type, mem)
+		if err != nil {
+			for _, v := range vs {
+				v.Release()
+			}
+			return nil, errors.Wrapf(err, codes.Inherit, "column %q", col.Label)
+		}
+		vs = append(vs, arr)
+	}
+	return vs, nil
+}
+
+func newNullArray(dt stdarrow.DataType, n int, mem memory.Allocator) stdarrow.Array {
+	b := arrowarray.NewBuilder(mem, dt)
+	defer b.Release()
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		b.AppendNull()
+	}
+	return b.NewArray()
+}
+
+func toArrowString(arr *array.String, mem memory.Allocator) stdarrow.Array {
+	b := arrowarray.NewStringBuilder(mem)
+	defer b.Release()
+	b.Resize(arr.Len())
+	for i, n := 0, arr.Len(); i < n; i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(arr.Value(i))
+	}
+	return b.NewArray()
+}
+
+// fromArrow converts an arrow array into a flux array of the given type.
+// Arrays that are already stored in the flux representation are reused
+// without copying.
+func fromArrow(arr stdarrow.Array, typ flux.ColType, mem memory.Allocator) (array.Array, error) {
+	switch typ {
+	case flux.TInt:
+		if arr, ok := arr.(*array.Int); ok {
+			arr.Retain()
+			return arr, nil
+		}
+		b := array.NewIntBuilder(mem)
+		defer b.Release()
+		b.Resize(arr.Len())
+		for i, n := 0, arr.Len(); i < n; i++ {
+			if arr.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			switch arr := arr.(type) {
+			case *arrowarray.Int8:
+				b.Append(int64(arr.Value(i)))
+			case *arrowarray.Int16:
+				b.Append(int64(arr.Value(i)))
+			case *arrowarray.Int32:
+				b.Append(int64(arr.Value(i)))
+			}
+		}
+		return b.NewArray(), nil
+	case flux.TUInt:
+		if arr, ok := arr.(*array.Uint); ok {
+			arr.Retain()
+			return arr, nil
+		}
+		b := array.NewUintBuilder(mem)
+		defer b.Release()
+		b.Resize(arr.Len())
+		for i, n := 0, arr.Len(); i < n; i++ {
+			if arr.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			switch arr := arr.(type) {
+			case *arrowarray.Uint8:
+				b.Append(uint64(arr.Value(i)))
+			case *arrowarray.Uint16:
+				b.Append(uint64(arr.Value(i)))
+			case *arrowarray.Uint32:
+				b.Append(uint64(arr.Value(i)))
+			}
+		}
+		return b.NewArray(), nil
+	case flux.TFloat:
+		if arr, ok := arr.(*array.Float); ok {
+			arr.Retain()
+			return arr, nil
+		}
+		b := array.NewFloatBuilder(mem)
+		defer b.Release()
+		b.Resize(arr.Len())
+		for i, n := 0, arr.Len(); i < n; i++ {
+			if arr.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			b.Append(float64(arr.(*arrowarray.Float32).Value(i)))
+		}
+		return b.NewArray(), nil
+	case flux.TBool:
+		arr.Retain()
+		return arr.(*array.Boolean), nil
+	case flux.TString:
+		// Both string and binary arrays share the same layout
+		// as the binary array wrapped by a flux string array.
+		data := arrowarray.NewBinaryData(arr.Data())
+		defer data.Release()
+		return array.NewStringFromBinaryArray(data), nil
+	case flux.TTime:
+		return timeFromArrow(arr, mem)
+	default:
+		return nil, errors.Newf(codes.Invalid, "unsupported column type: %s", typ)
+	}
+}
+
+func timeFromArrow(arr stdarrow.Array, mem memory.Allocator) (array.Array, error) {
+	if ts, ok := arr.(*arrowarray.Timestamp); ok && ts.DataType().(*stdarrow.TimestampType).Unit == stdarrow.Nanosecond {
+		data := arrowarray.NewData(array.IntType, ts.Len(), ts.Data().Buffers(), nil, ts.NullN(), ts.Data().Offset())
+		defer data.Release()
+		return arrowarray.NewInt64Data(data), nil
+	}
+
+	b := array.NewIntBuilder(mem)
+	defer b.Release()
+	b.Resize(arr.Len())
+	for i, n := 0, arr.Len(); i < n; i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		switch arr := arr.(type) {
+		case *arrowarray.Timestamp:
+			var mult int64
+			switch arr.DataType().(*stdarrow.TimestampType).Unit {
+			case stdarrow.Second:
+				mult = 1e9
+			case stdarrow.Millisecond:
+				mult = 1e6
+			case stdarrow.Microsecond:
+				mult = 1e3
+			default:
+				mult = 1
+			}
+			b.Append(int64(arr.Value(i)) * mult)
+		case *arrowarray.Date32:
+			b.Append(int64(arr.Value(i)) * 24 * 60 * 60 * 1e9)
+		case *arrowarray.Date64:
+			b.Append(int64(arr.Value(i)) * 1e6)
+		}
+	}
+	return b.NewArray(), nil
+}
@@ -6,9 +6,13 @@ import (
 	"os"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/csv"
 	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/json"
 	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/line"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/runtime"
 )
@@ -23,6 +27,10 @@ func executeE(ctx context.Context, script, format string) error {
 	}
 
 	mem := &memory.ResourceAllocator{}
+	if flags.MemoryLimit > 0 {
+		limit := flags.MemoryLimit
+		mem.Limit = &limit
+	}
 	q, err := prog.Start(ctx, mem)
 	if err != nil {
 		return err
@@ -31,7 +39,8 @@ func executeE(ctx context.Context, script, format string) error {
 	results := flux.NewResultIteratorFromQuery(q)
 	defer results.Release()
 
-	if format == "cli" {
+	switch format {
+	case "cli":
 		for results.More() {
 			res := results.Next()
 			fmt.Println("Result:", res.Name())
@@ -42,13 +51,31 @@ func executeE(ctx context.Context, script, format string) error {
 				return err
 			}
 		}
-	} else if format == "csv" {
+	case "csv":
 		config := csv.DefaultEncoderConfig()
 		encoder := csv.NewMultiResultEncoder(config)
 		_, err := encoder.Encode(os.Stdout, results)
 		if err != nil {
 			return err
 		}
+	case "json", "ndjson":
+		config := json.DefaultEncoderConfig()
+		if format == "ndjson" {
+			config.Format = json.NDJSON
+		}
+		encoder := json.NewMultiResultEncoder(config)
+		_, err := encoder.Encode(os.Stdout, results)
+		if err != nil {
+			return err
+		}
+	case "lp":
+		encoder := line.NewMultiResultEncoder(line.DefaultEncoderConfig())
+		_, err := encoder.Encode(os.Stdout, results)
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.Newf(codes.Invalid, "unknown output format %q", format)
 	}
 	results.Release()
 	return results.Err()
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/spf13/cobra"
+)
+
+var explainFlags struct {
+	ExecScript bool
+	Analyze    bool
+}
+
+func explainCommand() *cobra.Command {
+	explainCmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Print the query plan of a Flux script",
+		Long:  "Print the logical and physical plans of a Flux script (flux explain [--analyze] <file | -e script>)",
+		Args:  cobra.ExactArgs(1),
+		RunE:  explainE,
+	}
+	explainCmd.Flags().BoolVarP(&explainFlags.ExecScript, "exec", "e", false, "Interpret file argument as a raw flux script")
+	explainCmd.Flags().BoolVar(&explainFlags.Analyze, "analyze", false, "Execute the script and report the statistics of each operation")
+	return explainCmd
+}
+
+func explainE(cmd *cobra.Command, args []string) error {
+	script := args[0]
+	if !explainFlags.ExecScript {
+		content, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		script = string(content)
+	}
+
+	fluxinit.FluxInit()
+	ctx, span, err := injectDependencies(context.Background())
+	if err != nil {
+		return err
+	}
+	defer span.Finish()
+
+	return explain(ctx, os.Stdout, script, explainFlags.Analyze)
+}
+
+func explain(ctx context.Context, w io.Writer, script string, analyze bool) error {
+	var opts []lang.CompileOption
+	if analyze {
+		opts = append(opts, lang.WithProfilers("operator"))
+	}
+	prog, err := lang.Compile(script, runtime.Default, time.Now(), opts...)
+	if err != nil {
+		return err
+	}
+
+	mem := &memory.ResourceAllocator{}
+	logical, physical, err := prog.Plan(ctx, mem)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Logical Plan:\n%v\n", plan.Formatted(logical, plan.WithDetails()))
+	fmt.Fprintf(w, "Physical Plan:\n%v", plan.Formatted(physical, plan.WithDetails(), plan.WithPhysicalAttributes()))
+	if !analyze {
+		return nil
+	}
+
+	// The plan is created again when the program is started.
+	prog, err = lang.Compile(script, runtime.Default, prog.Now, opts...)
+	if err != nil {
+		return err
+	}
+	q, err := prog.Start(ctx, mem)
+	if err != nil {
+		return err
+	}
+	results := flux.NewResultIteratorFromQuery(q)
+	for results.More() {
+		if err := results.Next().Tables().Do(func(tbl flux.Table) error {
+			tbl.Done()
+			return nil
+		}); err != nil {
+			results.Release()
+			return err
+		}
+	}
+	results.Release()
+	if err := results.Err(); err != nil {
+		return err
+	}
+
+	tbl, err := (&execute.OperatorProfiler{}).GetResult(q, mem)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nAnalysis:")
+	return writeAnalysis(w, tbl)
+}
+
+// writeAnalysis writes the statistics of each operation
+// from the operator profiler as an aligned table.
+func writeAnalysis(w io.Writer, tbl flux.Table) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "Label\tType\tTables\tRows\tMaxAllocated\tTotalAllocated\tTime")
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		var (
+			labels   = cr.Strings(execute.ColIdx("Label", cr.Cols()))
+			types    = cr.Strings(execute.ColIdx("Type", cr.Cols()))
+			tables   = cr.Ints(execute.ColIdx("TableCount", cr.Cols()))
+			rows     = cr.Ints(execute.ColIdx("RowCount", cr.Cols()))
+			maxAlloc = cr.Ints(execute.ColIdx("MaxAllocated", cr.Cols()))
+			total    = cr.Ints(execute.ColIdx("TotalAllocated", cr.Cols()))
+			duration = cr.Ints(execute.ColIdx("DurationSum", cr.Cols()))
+		)
+		for i := 0; i < cr.Len(); i++ {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%v\n",
+				labels.Value(i), types.Value(i),
+				tables.Value(i), rows.Value(i),
+				maxAlloc.Value(i), total.Value(i),
+				time.Duration(duration.Value(i)),
+			)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
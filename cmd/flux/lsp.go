@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/internal/lsp"
+	"github.com/spf13/cobra"
+)
+
+func lspCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lsp",
+		Short: "Run a Language Server Protocol server for Flux",
+		Long:  "Run a Language Server Protocol server that communicates with the editor on stdin and stdout (flux lsp)",
+		Args:  cobra.NoArgs,
+		RunE:  lspE,
+	}
+}
+
+func lspE(cmd *cobra.Command, args []string) error {
+	fluxinit.FluxInit()
+	ctx, span, err := injectDependencies(context.Background())
+	if err != nil {
+		return err
+	}
+	defer span.Finish()
+
+	return lsp.Serve(ctx, os.Stdin, os.Stdout)
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/influxdata/flux/cmd/flux/cmd"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/dependencies"
 	"github.com/influxdata/flux/dependencies/feature"
+	"github.com/influxdata/flux/dependencies/http"
 	"github.com/influxdata/flux/dependency"
 	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/fluxinit"
@@ -30,6 +32,10 @@ var flags struct {
 	Format            string
 	Features          string
 	EnableSuggestions bool
+	HistoryFile       string
+	HTTPMaxAttempts   int
+	MemoryLimit       int64
+	SpillDir          string
 }
 
 func runE(cmd *cobra.Command, args []string) error {
@@ -56,7 +62,10 @@ func runE(cmd *cobra.Command, args []string) error {
 	// have already passed to avoid a long load time
 	// for a simple unrelated error.
 	fluxinit.FluxInit()
-	ctx, span := injectDependencies(ctx)
+	ctx, span, err := injectDependencies(ctx)
+	if err != nil {
+		return err
+	}
 	defer span.Finish()
 
 	flagger := executetest.TestFlagger{}
@@ -71,6 +80,12 @@ func runE(cmd *cobra.Command, args []string) error {
 	if flags.EnableSuggestions {
 		opts = append(opts, repl.EnableSuggestions())
 	}
+	if flags.HistoryFile != "" {
+		opts = append(opts, repl.HistoryFile(flags.HistoryFile))
+	}
+	if flags.MemoryLimit > 0 {
+		opts = append(opts, repl.MemoryLimit(flags.MemoryLimit))
+	}
 
 	if len(args) == 0 {
 		return replE(ctx, opts...)
@@ -112,9 +127,26 @@ func configureTracing(ctx context.Context) (context.Context, func(), error) {
 
 const DefaultInfluxDBHost = "http://localhost:9999"
 
-func injectDependencies(ctx context.Context) (context.Context, *dependency.Span) {
+func injectDependencies(ctx context.Context) (context.Context, *dependency.Span, error) {
 	deps := dependencies.NewDefaultDependencies(DefaultInfluxDBHost)
-	return dependency.Inject(ctx, deps)
+	if flags.HTTPMaxAttempts > 1 {
+		policy := http.DefaultRetryPolicy()
+		policy.MaxAttempts = flags.HTTPMaxAttempts
+		client, err := http.WithRetryPolicy(deps.Deps.Deps.HTTPClient, policy)
+		if err != nil {
+			return nil, nil, err
+		}
+		deps.Deps.Deps.HTTPClient = client
+	}
+	deps.Spill.Config.Dir = flags.SpillDir
+	ss, err := newSecretService(deps.Deps.Deps.HTTPClient)
+	if err != nil {
+		return nil, nil, err
+	} else if ss != nil {
+		deps.Deps.Deps.SecretService = ss
+	}
+	ctx, span := dependency.Inject(ctx, deps)
+	return ctx, span, nil
 }
 
 func main() {
@@ -127,9 +159,16 @@ func main() {
 	}
 	fluxCmd.Flags().BoolVarP(&flags.ExecScript, "exec", "e", false, "Interpret file argument as a raw flux script")
 	fluxCmd.Flags().BoolVarP(&flags.EnableSuggestions, "enable-suggestions", "", false, "enable suggestions in the repl")
+	fluxCmd.Flags().StringVar(&flags.HistoryFile, "history-file", defaultHistoryFile(), "File that stores the history of the repl; empty disables the history")
 	fluxCmd.Flags().StringVar(&flags.Trace, "trace", "", "Trace query execution")
-	fluxCmd.Flags().StringVarP(&flags.Format, "format", "", "cli", "Output format one of: cli,csv. Defaults to cli")
+	fluxCmd.Flags().StringVarP(&flags.Format, "format", "", "cli", "Output format one of: cli,csv,json,ndjson,lp. Defaults to cli")
 	fluxCmd.Flag("trace").NoOptDefVal = "jaeger"
+	fluxCmd.Flags().StringVar(&secretsFlags.File, "secrets-file", "", "JSON or YAML file with the secrets for secrets.get()")
+	fluxCmd.Flags().StringVar(&secretsFlags.KeyFile, "secrets-key-file", "", "File with the base64 encoded key to decrypt the secrets file")
+	fluxCmd.Flags().StringVar(&secretsFlags.VaultPath, "secrets-vault-path", "", "Path of a Vault KV v2 secret with the secrets for secrets.get() as [<mount>:]<path>; uses VAULT_ADDR and VAULT_TOKEN")
+	fluxCmd.Flags().IntVar(&flags.HTTPMaxAttempts, "http-max-attempts", 1, "Maximum number of times an HTTP request is sent when it fails with a transient error")
+	fluxCmd.Flags().Int64Var(&flags.MemoryLimit, "memory-limit", 0, "Maximum number of bytes a query may allocate; buffered data is spilled to disk near the limit. 0 means no limit")
+	fluxCmd.Flags().StringVar(&flags.SpillDir, "spill-dir", "", "Directory for the temporary files used to spill buffered data; defaults to the system temporary directory")
 	fluxCmd.Flags().StringVar(&flags.Features, "feature", "", "JSON object specifying the features to execute with. See internal/feature/flags.yml for a list of the current features")
 
 	fmtCmd := &cobra.Command{
@@ -146,6 +185,11 @@ func main() {
 	testCmd := cmd.TestCommand(NewTestExecutor)
 	fluxCmd.AddCommand(testCmd)
 
+	fluxCmd.AddCommand(explainCommand())
+	fluxCmd.AddCommand(lspCommand())
+	fluxCmd.AddCommand(promqlCommand())
+	fluxCmd.AddCommand(secretsCommand())
+
 	if err := fluxCmd.Execute(); err != nil {
 		if _, ok := err.(silentError); !ok {
 			fmt.Fprintln(fluxCmd.OutOrStderr(), err)
@@ -154,6 +198,15 @@ func main() {
 	}
 }
 
+// defaultHistoryFile returns the history file in the home directory of the user.
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".flux_history")
+}
+
 // silentError indicates the error should not be printed to stderr.
 type silentError interface {
 	Silent()
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast/astutil"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/promql"
+	"github.com/spf13/cobra"
+)
+
+var promqlFlags struct {
+	Bucket string
+	Start  string
+	End    string
+	Step   time.Duration
+	Run    bool
+	Format string
+}
+
+func promqlCommand() *cobra.Command {
+	promqlCmd := &cobra.Command{
+		Use:   "promql",
+		Short: "Transpile a PromQL expression to Flux",
+		Long: `Transpile a PromQL expression to Flux and print the Flux script (flux promql [--run] '<expr>').
+
+The expression is evaluated as an instant query at the end time unless a step is set,
+in which case it is evaluated as a range query from the start to the end time.
+The start and end times are RFC3339 timestamps or durations relative to now, such as -1h.`,
+		Args: cobra.ExactArgs(1),
+		RunE: promqlE,
+	}
+	promqlCmd.Flags().StringVar(&promqlFlags.Bucket, "bucket", "prometheus", "Bucket that stores the Prometheus data")
+	promqlCmd.Flags().StringVar(&promqlFlags.Start, "start", "", "Start time of a range query")
+	promqlCmd.Flags().StringVar(&promqlFlags.End, "end", "0s", "End time of the query")
+	promqlCmd.Flags().DurationVar(&promqlFlags.Step, "step", 0, "Resolution step of a range query; zero evaluates an instant query")
+	promqlCmd.Flags().BoolVar(&promqlFlags.Run, "run", false, "Execute the Flux script instead of printing it")
+	promqlCmd.Flags().StringVar(&promqlFlags.Format, "format", "cli", "Output format of --run, one of: cli,csv,json,ndjson,lp")
+	return promqlCmd
+}
+
+func promqlE(cmd *cobra.Command, args []string) error {
+	now := time.Now().UTC()
+	end, err := parsePromQLTime(promqlFlags.End, now)
+	if err != nil {
+		return err
+	}
+	start := end
+	if promqlFlags.Step > 0 {
+		if promqlFlags.Start == "" {
+			return errors.New(codes.Invalid, "a range query with a step requires a start time")
+		}
+		if start, err = parsePromQLTime(promqlFlags.Start, now); err != nil {
+			return err
+		}
+		if !start.Before(end) {
+			return errors.New(codes.Invalid, "the start time must be before the end time")
+		}
+	} else if promqlFlags.Start != "" {
+		return errors.New(codes.Invalid, "an instant query does not have a start time, set a step for a range query")
+	}
+
+	t := &promql.Transpiler{
+		Bucket:     promqlFlags.Bucket,
+		Start:      start,
+		End:        end,
+		Resolution: promqlFlags.Step,
+	}
+	script, err := transpilePromQL(t, args[0])
+	if err != nil {
+		return err
+	}
+	if !promqlFlags.Run {
+		return writePromQLScript(os.Stdout, script)
+	}
+
+	fluxinit.FluxInit()
+	ctx, span, err := injectDependencies(context.Background())
+	if err != nil {
+		return err
+	}
+	defer span.Finish()
+	return executeE(ctx, script, promqlFlags.Format)
+}
+
+// transpilePromQL transpiles the PromQL expression and formats the Flux script.
+func transpilePromQL(t *promql.Transpiler, expr string) (string, error) {
+	e, err := promql.ParseExpr(expr)
+	if err != nil {
+		return "", errors.Wrap(err, codes.Invalid, "invalid PromQL expression")
+	}
+	file, err := t.Transpile(e)
+	if err != nil {
+		return "", errors.Wrap(err, codes.Invalid, "failed to transpile PromQL expression")
+	}
+	return astutil.Format(file)
+}
+
+func writePromQLScript(w io.Writer, script string) error {
+	_, err := fmt.Fprintln(w, strings.TrimSpace(script))
+	return err
+}
+
+// parsePromQLTime parses an RFC3339 timestamp or a duration relative to now.
+func parsePromQLTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC(), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, errors.Newf(codes.Invalid, "invalid time %q, must be an RFC3339 timestamp or a duration relative to now", s)
+	}
+	return now.Add(d), nil
+}
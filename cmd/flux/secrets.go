@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/http"
+	"github.com/influxdata/flux/dependencies/secret"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+var secretsFlags struct {
+	File      string
+	KeyFile   string
+	VaultPath string
+}
+
+// newSecretService creates the secret service from the flags.
+// The services are chained so the secrets file takes precedence
+// over Vault. It returns nil if no secrets are configured.
+func newSecretService(client http.Client) (secret.Service, error) {
+	var chain secret.ChainedSecretService
+	if secretsFlags.File != "" {
+		var key []byte
+		if secretsFlags.KeyFile != "" {
+			k, err := readSecretsKey(secretsFlags.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			key = k
+		}
+		ss, err := secret.NewFileSecretService(secretsFlags.File, key)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, ss)
+	}
+	if secretsFlags.VaultPath != "" {
+		mount, path := "secret", secretsFlags.VaultPath
+		if i := strings.Index(path, ":"); i >= 0 {
+			mount, path = path[:i], path[i+1:]
+		}
+		addr := os.Getenv("VAULT_ADDR")
+		if addr == "" {
+			return nil, errors.New(codes.Invalid, "VAULT_ADDR must be set to read secrets from vault")
+		}
+		chain = append(chain, &secret.VaultSecretService{
+			Client:    client,
+			Address:   addr,
+			Token:     os.Getenv("VAULT_TOKEN"),
+			Namespace: os.Getenv("VAULT_NAMESPACE"),
+			Mount:     mount,
+			Path:      path,
+		})
+	}
+
+	switch len(chain) {
+	case 0:
+		return nil, nil
+	case 1:
+		return chain[0], nil
+	default:
+		return chain, nil
+	}
+}
+
+// readSecretsKey reads a base64 encoded key from the file.
+func readSecretsKey(fpath string) ([]byte, error) {
+	data, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to read secrets key file")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "secrets key must be base64 encoded")
+	}
+	return key, nil
+}
+
+func secretsCommand() *cobra.Command {
+	secretsCmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage files with secrets for the --secrets-file flag",
+	}
+
+	var keyFile string
+	encryptCmd := &cobra.Command{
+		Use:   "encrypt <file>",
+		Short: "Encrypt a secrets file",
+		Long: `Encrypt a JSON or YAML secrets file and write the result to stdout.
+
+The key file contains a base64 encoded AES key that is 16, 24 or 32 bytes long.
+A key can be generated with: head -c 32 /dev/urandom | base64`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := readSecretsKey(keyFile)
+			if err != nil {
+				return err
+			}
+			data, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			out, err := secret.Encrypt(key, data)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+	encryptCmd.Flags().StringVar(&keyFile, "key-file", "", "File with the base64 encoded key")
+	_ = encryptCmd.MarkFlagRequired("key-file")
+	secretsCmd.AddCommand(encryptCmd)
+	return secretsCmd
+}
@@ -646,6 +646,14 @@ func compile(n semantic.Node, subst semantic.Substitutor) (Evaluator, error) {
 		if err != nil {
 			return nil, err
 		}
+		if test.Type().Nature() == semantic.Vector {
+			return &conditionalVectorEvaluator{
+				t:          vectorType(apply(subst, nil, n.TypeOf())),
+				test:       test,
+				consequent: c,
+				alternate:  a,
+			}, nil
+		}
 		return &conditionalEvaluator{
 			test:       test,
 			consequent: c,
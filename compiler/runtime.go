@@ -335,7 +335,7 @@ type logicalVectorEvaluator struct {
 }
 
 func (e *logicalVectorEvaluator) Type() semantic.MonoType {
-	return semantic.BasicBool
+	return semantic.NewVectorType(semantic.BasicBool)
 }
 
 func (e *logicalVectorEvaluator) Eval(ctx context.Context, scope Scope) (values.Value, error) {
@@ -350,21 +350,29 @@ func (e *logicalVectorEvaluator) Eval(ctx context.Context, scope Scope) (values.
 	} else if elemType := l.Vector().ElementType().Nature(); elemType != semantic.Bool {
 		return nil, errors.Newf(codes.Invalid, "cannot use operand of type %s with logical %s; expected boolean", elemType, e.operator)
 	}
-	lv := l.Vector().Arr().(*array.Boolean)
 
 	r, err := e.right.Eval(ctx, scope)
 	if err != nil {
 		return nil, err
 	}
+	defer r.Release()
 
 	if typ := r.Type().Nature(); typ != semantic.Vector {
 		return nil, errors.Newf(codes.Invalid, "cannot use vectorized %s operation on non-vector %s", e.operator, typ)
 	} else if elemType := r.Vector().ElementType().Nature(); elemType != semantic.Bool {
 		return nil, errors.Newf(codes.Invalid, "cannot use operand of type %s with logical %s; expected boolean", elemType, e.operator)
 	}
-	rv := r.Vector().Arr().(*array.Boolean)
+
+	if l.Vector().IsRepeat() && r.Vector().IsRepeat() {
+		lv := l.Vector().(*values.VectorRepeatValue).Value()
+		rv := r.Vector().(*values.VectorRepeatValue).Value()
+		return values.NewVectorRepeatValue(logicalRepeat(e.operator, lv, rv)), nil
+	}
 
 	mem := memory.GetAllocator(ctx)
+	lv, rv := logicalOperand(l.Vector(), r.Vector(), mem), logicalOperand(r.Vector(), l.Vector(), mem)
+	defer lv.Release()
+	defer rv.Release()
 
 	switch e.operator {
 	case ast.AndOperator:
@@ -484,6 +492,85 @@ func (e *constVectorEvaluator) Eval(ctx context.Context, scope Scope) (values.Va
 	return values.NewVectorRepeatValue(v), nil
 }
 
+// conditionalVectorEvaluator evaluates both branches over every
+// row and selects the result of each row with the test. The
+// vectorizer only produces it when neither branch can fail.
+type conditionalVectorEvaluator struct {
+	t          semantic.MonoType
+	test       Evaluator
+	consequent Evaluator
+	alternate  Evaluator
+}
+
+func (e *conditionalVectorEvaluator) Type() semantic.MonoType {
+	return e.t
+}
+
+func (e *conditionalVectorEvaluator) Eval(ctx context.Context, scope Scope) (values.Value, error) {
+	t, err := eval(ctx, e.test, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Release()
+	if typ := t.Type().Nature(); typ != semantic.Vector {
+		return nil, errors.Newf(codes.Invalid, "cannot use vectorized conditional expression with non-vector test of type %s", typ)
+	} else if elemType := t.Vector().ElementType().Nature(); elemType != semantic.Bool {
+		return nil, errors.Newf(codes.Invalid, "cannot use test of type %s in conditional expression; expected boolean", elemType)
+	}
+
+	// A repeated test selects the same branch for every element.
+	if tv := t.Vector(); tv.IsRepeat() {
+		test := tv.(*values.VectorRepeatValue).Value()
+		branch := e.alternate
+		if !test.IsNull() && test.Bool() {
+			branch = e.consequent
+		}
+		v, err := eval(ctx, branch, scope)
+		if err != nil {
+			return nil, err
+		}
+		if v.Type().Nature() != semantic.Vector {
+			return values.NewVectorRepeatValue(v), nil
+		}
+		return v, nil
+	}
+	test := t.Vector().Arr().(*array.Boolean)
+
+	c, err := eval(ctx, e.consequent, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Release()
+	a, err := eval(ctx, e.alternate, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer a.Release()
+
+	mem := memory.GetAllocator(ctx)
+	if mem == nil {
+		return nil, errors.Newf(codes.Invalid, "missing allocator, cannot use vectorized conditional expression")
+	}
+	b, err := newVectorBuilder(e.t, mem)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Release()
+
+	n := test.Len()
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		v := a
+		if test.IsValid(i) && test.Value(i) {
+			v = c
+		}
+		if err := appendVectorElement(b, v, i); err != nil {
+			return nil, err
+		}
+	}
+	return newVectorValue(b, e.t), nil
+}
+
 type unaryEvaluator struct {
 	t    semantic.MonoType
 	node Evaluator
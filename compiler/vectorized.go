@@ -0,0 +1,118 @@
+package compiler
+
+import (
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/arrowutil"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// vectorType returns the vector type of t unless t is already a vector type.
+func vectorType(t semantic.MonoType) semantic.MonoType {
+	if t.Nature() == semantic.Vector {
+		return t
+	}
+	return semantic.NewVectorType(t)
+}
+
+// newVectorBuilder creates a builder for the elements of the vector type t.
+func newVectorBuilder(t semantic.MonoType, mem memory.Allocator) (array.Builder, error) {
+	elemType, err := t.ElemType()
+	if err != nil {
+		return nil, err
+	}
+	typ := flux.ColumnType(elemType)
+	if typ == flux.TInvalid {
+		return nil, errors.Newf(codes.Invalid, "cannot vectorize values of type %s", elemType)
+	}
+	return arrow.NewBuilder(typ, mem), nil
+}
+
+// newVectorValue creates a vector of type t from the values of the builder.
+func newVectorValue(b array.Builder, t semantic.MonoType) values.Vector {
+	elemType, err := t.ElemType()
+	if err != nil {
+		panic(err)
+	}
+	return values.NewVectorValue(b.NewArray(), flux.SemanticType(flux.ColumnType(elemType)))
+}
+
+// vectorElement returns the element of v at index i.
+// A value that is not a vector is the same for every index.
+func vectorElement(v values.Value, i int) values.Value {
+	if v.Type().Nature() != semantic.Vector {
+		return v
+	}
+	vec := v.Vector()
+	if vec.IsRepeat() {
+		return vec.(*values.VectorRepeatValue).Value()
+	}
+	if vec.Arr().IsNull(i) {
+		return values.Null
+	}
+	switch arr := vec.Arr().(type) {
+	case *array.Int:
+		if vec.ElementType().Nature() == semantic.Time {
+			return values.NewTime(values.Time(arr.Value(i)))
+		}
+		return values.NewInt(arr.Value(i))
+	case *array.Uint:
+		return values.NewUInt(arr.Value(i))
+	case *array.Float:
+		return values.NewFloat(arr.Value(i))
+	case *array.Boolean:
+		return values.NewBool(arr.Value(i))
+	case *array.String:
+		return values.NewString(arr.Value(i))
+	default:
+		panic(errors.Newf(codes.Internal, "unsupported vector data type %s", arr.DataType()))
+	}
+}
+
+// appendVectorElement appends the element of v at index i to the builder.
+func appendVectorElement(b array.Builder, v values.Value, i int) error {
+	if v.Type().Nature() == semantic.Vector && !v.Vector().IsRepeat() {
+		arrowutil.CopyValue(b, v.Vector().Arr(), i)
+		return nil
+	}
+	return arrow.AppendValue(b, vectorElement(v, i))
+}
+
+// logicalOperand returns the boolean array for the operand v of a
+// vectorized logical operation. A repeated operand is expanded to
+// the length of the other operand.
+func logicalOperand(v, other values.Vector, mem memory.Allocator) *array.Boolean {
+	if !v.IsRepeat() {
+		arr := v.Arr().(*array.Boolean)
+		arr.Retain()
+		return arr
+	}
+	rv := v.(*values.VectorRepeatValue).Value()
+	if rv.IsNull() {
+		return array.BooleanRepeat(false, true, other.Arr().Len(), mem)
+	}
+	return array.BooleanRepeat(rv.Bool(), false, other.Arr().Len(), mem)
+}
+
+// logicalRepeat evaluates a logical operation between two repeated values.
+func logicalRepeat(op ast.LogicalOperatorKind, l, r values.Value) values.Value {
+	switch op {
+	case ast.AndOperator:
+		if l.IsNull() || !l.Bool() {
+			return values.NewBool(false)
+		}
+	case ast.OrOperator:
+		if !l.IsNull() && l.Bool() {
+			return values.NewBool(true)
+		}
+	default:
+		panic(errors.Newf(codes.Internal, "unknown logical operator %v", op))
+	}
+	return r
+}
@@ -173,9 +173,83 @@ func TestVectorizedFns(t *testing.T) {
 			skipComp:     true,
 		},
 		{
-			// TODO: https://github.com/influxdata/flux/issues/4608
-			name:         "no equality operators",
+			name:         "conditional expression",
+			fn:           `(r) => ({c: if r.a then r.b else r.c})`,
+			vectorizable: true,
+			inType: semantic.NewObjectType([]semantic.PropertyType{
+				{Key: []byte("r"), Value: semantic.NewObjectType([]semantic.PropertyType{
+					{Key: []byte("a"), Value: semantic.NewVectorType(semantic.BasicBool)},
+					{Key: []byte("b"), Value: semantic.NewVectorType(semantic.BasicInt)},
+					{Key: []byte("c"), Value: semantic.NewVectorType(semantic.BasicInt)},
+				})},
+			}),
+			input: map[string]interface{}{
+				"r": map[string]interface{}{
+					"a": []interface{}{true, false, true},
+					"b": []interface{}{int64(1), int64(2), int64(3)},
+					"c": []interface{}{int64(4), int64(5), int64(6)},
+				},
+			},
+			want: map[string]interface{}{
+				"c": []interface{}{int64(1), int64(5), int64(3)},
+			},
+		},
+		{
+			name:         "nested field access",
+			fn:           `(r) => ({c: r.a.b})`,
+			vectorizable: true,
+			inType: semantic.NewObjectType([]semantic.PropertyType{
+				{Key: []byte("r"), Value: semantic.NewObjectType([]semantic.PropertyType{
+					{Key: []byte("a"), Value: semantic.NewObjectType([]semantic.PropertyType{
+						{Key: []byte("b"), Value: semantic.NewVectorType(semantic.BasicFloat)},
+					})},
+				})},
+			}),
+			input: map[string]interface{}{
+				"r": map[string]interface{}{
+					"a": map[string]interface{}{
+						"b": []interface{}{1.5, 2.5},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"c": []interface{}{1.5, 2.5},
+			},
+		},
+		{
+			name:         "no type conversions",
+			fn:           `(r) => ({c: float(v: r.a)})`,
+			vectorizable: false,
+			skipComp:     true,
+		},
+		{
+			name: "no package functions",
+			fn: `import "strings"
+(r) => ({c: strings.toUpper(v: r.a)})`,
+			vectorizable: false,
+			skipComp:     true,
+		},
+		{
+			name:         "no conditionals with division",
+			fn:           `(r) => ({c: if r.b != 0 then r.a / r.b else 0})`,
+			vectorizable: false,
+			skipComp:     true,
+		},
+		{
+			name:         "no calls to other functions",
+			fn:           `(r) => ({c: length(arr: [r.a])})`,
+			vectorizable: false,
+			skipComp:     true,
+		},
+		{
+			name:         "comparison operators",
 			fn:           `(r) => ({r with c: 2 > 1})`,
+			vectorizable: true,
+			skipComp:     true,
+		},
+		{
+			name:         "no regexp match operators",
+			fn:           `(r) => ({r with c: r.a =~ /x/})`,
 			vectorizable: false,
 			skipComp:     true,
 		},
@@ -314,6 +388,72 @@ func TestVectorizedFns(t *testing.T) {
 				return math.Pow(float64(l), float64(r))
 			},
 		},
+		{
+			operator: "==",
+			input: [][2]int64{
+				{1, 2},
+				{10, 5},
+				{7, 7},
+			},
+			transform: func(l, r int64) interface{} {
+				return l == r
+			},
+		},
+		{
+			operator: "!=",
+			input: [][2]int64{
+				{1, 2},
+				{10, 5},
+				{7, 7},
+			},
+			transform: func(l, r int64) interface{} {
+				return l != r
+			},
+		},
+		{
+			operator: "<",
+			input: [][2]int64{
+				{1, 2},
+				{10, 5},
+				{7, 7},
+			},
+			transform: func(l, r int64) interface{} {
+				return l < r
+			},
+		},
+		{
+			operator: "<=",
+			input: [][2]int64{
+				{1, 2},
+				{10, 5},
+				{7, 7},
+			},
+			transform: func(l, r int64) interface{} {
+				return l <= r
+			},
+		},
+		{
+			operator: ">",
+			input: [][2]int64{
+				{1, 2},
+				{10, 5},
+				{7, 7},
+			},
+			transform: func(l, r int64) interface{} {
+				return l > r
+			},
+		},
+		{
+			operator: ">=",
+			input: [][2]int64{
+				{1, 2},
+				{10, 5},
+				{7, 7},
+			},
+			transform: func(l, r int64) interface{} {
+				return l >= r
+			},
+		},
 	}
 	for _, test := range operatorTests {
 		a := []interface{}{}
@@ -0,0 +1,140 @@
+// Package cache provides storage for the encoded results of queries
+// so that repeated executions of the same plan can skip execution.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type key int
+
+const cacheKey key = iota
+
+// Cache stores encoded query results by key.
+//
+// Implementations must be safe for concurrent use.
+// A cache is allowed to drop entries at any time.
+type Cache interface {
+	// Get returns the value stored for the key.
+	// The second return value is false if the key is not present.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores the value for the key.
+	Set(ctx context.Context, key string, value []byte) error
+}
+
+// Inject will inject this Cache into the dependency chain.
+func Inject(ctx context.Context, c Cache) context.Context {
+	return context.WithValue(ctx, cacheKey, c)
+}
+
+// GetCache will return the Cache for the current context.
+// If no Cache has been injected into the dependencies,
+// this will return nil and results are not cached.
+func GetCache(ctx context.Context) Cache {
+	c, _ := ctx.Value(cacheKey).(Cache)
+	return c
+}
+
+// Dependency will inject the Cache into the dependency chain.
+type Dependency struct {
+	Cache Cache
+}
+
+// Inject will inject the Cache into the dependency chain.
+func (d Dependency) Inject(ctx context.Context) context.Context {
+	return Inject(ctx, d.Cache)
+}
+
+// MemoryCache is a Cache that keeps entries in memory.
+// Entries expire after a fixed duration and the least recently
+// used entries are evicted when the cache exceeds its size.
+type MemoryCache struct {
+	ttl      time.Duration
+	maxBytes int64
+	now      func() time.Time
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates a MemoryCache.
+// A ttl of zero means entries do not expire and a maxBytes
+// of zero means the size of the cache is not limited.
+func NewMemoryCache(ttl time.Duration, maxBytes int64) *MemoryCache {
+	return &MemoryCache{
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		now:      time.Now,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := elem.Value.(*memoryEntry)
+	if c.ttl > 0 && !c.now().Before(e.expires) {
+		c.remove(elem)
+		return nil, false, nil
+	}
+	c.lru.MoveToFront(elem)
+	return e.value, true, nil
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte) error {
+	size := int64(len(value))
+	if c.maxBytes > 0 && size > c.maxBytes {
+		// The entry would evict everything else and still not fit.
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	e := &memoryEntry{
+		key:     key,
+		value:   value,
+		expires: c.now().Add(c.ttl),
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	c.size += size
+
+	for c.maxBytes > 0 && c.size > c.maxBytes {
+		c.remove(c.lru.Back())
+	}
+	return nil
+}
+
+// Len returns the number of entries in the cache.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *MemoryCache) remove(elem *list.Element) {
+	e := c.lru.Remove(elem).(*memoryEntry)
+	delete(c.entries, e.key)
+	c.size -= int64(len(e.value))
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_Expire(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	c := NewMemoryCache(10*time.Second, 0)
+	c.now = func() time.Time { return now }
+
+	if err := c.Set(ctx, "a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(5 * time.Second)
+	if v, ok, err := c.Get(ctx, "a"); err != nil {
+		t.Fatal(err)
+	} else if !ok || string(v) != "1" {
+		t.Fatalf("unexpected value: %q %v", v, ok)
+	}
+
+	now = now.Add(5 * time.Second)
+	if _, ok, err := c.Get(ctx, "a"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("expected entry to expire")
+	}
+	if got := c.Len(); got != 0 {
+		t.Fatalf("unexpected number of entries: %d", got)
+	}
+}
+
+func TestMemoryCache_Evict(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(0, 6)
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := c.Set(ctx, key, []byte("xx")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Use a so that b is the least recently used entry.
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatal("expected a to be present")
+	}
+	if err := c.Set(ctx, "d", []byte("xx")); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if _, ok, _ := c.Get(ctx, key); ok != want {
+			t.Errorf("unexpected presence of %s: want %v, got %v", key, want, ok)
+		}
+	}
+
+	// Values larger than the cache are not stored.
+	if err := c.Set(ctx, "e", []byte("xxxxxxx")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := c.Get(ctx, "e"); ok {
+		t.Fatal("unexpected entry larger than the cache")
+	}
+	if got := c.Len(); got != 3 {
+		t.Fatalf("unexpected number of entries: %d", got)
+	}
+}
+
+func TestGetCache(t *testing.T) {
+	ctx := context.Background()
+	if c := GetCache(ctx); c != nil {
+		t.Fatal("expected no cache")
+	}
+	mc := NewMemoryCache(0, 0)
+	ctx = Dependency{Cache: mc}.Inject(ctx)
+	if c := GetCache(ctx); c != mc {
+		t.Fatal("expected injected cache")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/dependencies/influxdb"
 	"github.com/influxdata/flux/dependencies/mqtt"
+	"github.com/influxdata/flux/dependencies/spill"
 )
 
 type Dependencies struct {
@@ -15,13 +16,22 @@ type Dependencies struct {
 	influxdb influxdb.Dependency
 	bigtable bigtable.Dependency
 	mqtt     mqtt.Dependency
+
+	// Spill configures where blocking transformations move their
+	// buffered data when a query approaches its memory limit.
+	// Spilling is disabled when it is nil.
+	Spill *spill.Dependency
 }
 
 func (d Dependencies) Inject(ctx context.Context) context.Context {
 	ctx = d.Deps.Inject(ctx)
 	ctx = d.influxdb.Inject(ctx)
 	ctx = d.bigtable.Inject(ctx)
-	return d.mqtt.Inject(ctx)
+	ctx = d.mqtt.Inject(ctx)
+	if d.Spill != nil {
+		ctx = d.Spill.Inject(ctx)
+	}
+	return ctx
 }
 
 func NewDefaultDependencies(defaultInfluxDBHost string) Dependencies {
@@ -46,6 +56,10 @@ func NewDefaultDependencies(defaultInfluxDBHost string) Dependencies {
 		mqtt: mqtt.Dependency{
 			Dialer: mqtt.DefaultDialer{},
 		},
+
+		// Spilling only takes effect for queries
+		// that have a memory limit.
+		Spill: &spill.Dependency{},
 	}
 }
 
@@ -0,0 +1,91 @@
+package filesystem
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Dir implements the filesystem.Service and restricts every request
+// to the files inside of the named directory.
+//
+// Paths are resolved relative to the directory and cannot refer to
+// a file outside of it. A path that uses ".." to leave the directory
+// is resolved against the directory itself and an absolute path is
+// treated as relative to the directory. Symbolic links inside of the
+// directory are followed.
+type Dir string
+
+var (
+	_ Service   = Dir("")
+	_ Creator   = Dir("")
+	_ Renamer   = Dir("")
+	_ DirReader = Dir("")
+	_ Globber   = Dir("")
+)
+
+// resolve returns the path on the filesystem for the name.
+func (d Dir) resolve(name string) string {
+	dir := string(d)
+	if dir == "" {
+		dir = "."
+	}
+	name = path.Clean("/" + filepath.ToSlash(name))
+	return filepath.Join(dir, filepath.FromSlash(name))
+}
+
+// pathError replaces the resolved path in the error with the
+// requested name so the location of the directory is not revealed.
+func (d Dir) pathError(name string, err error) error {
+	if perr, ok := err.(*os.PathError); ok {
+		perr.Path = name
+	}
+	return err
+}
+
+func (d Dir) Open(fpath string) (File, error) {
+	f, err := os.Open(d.resolve(fpath))
+	if err != nil {
+		return nil, d.pathError(fpath, err)
+	}
+	return f, nil
+}
+
+func (d Dir) Create(fpath string) (io.WriteCloser, error) {
+	f, err := os.Create(d.resolve(fpath))
+	if err != nil {
+		return nil, d.pathError(fpath, err)
+	}
+	return f, nil
+}
+
+func (d Dir) Rename(oldpath, newpath string) error {
+	if err := os.Rename(d.resolve(oldpath), d.resolve(newpath)); err != nil {
+		if lerr, ok := err.(*os.LinkError); ok {
+			lerr.Old, lerr.New = oldpath, newpath
+		}
+		return err
+	}
+	return nil
+}
+
+func (d Dir) Remove(fpath string) error {
+	if err := os.Remove(d.resolve(fpath)); err != nil {
+		return d.pathError(fpath, err)
+	}
+	return nil
+}
+
+func (d Dir) ReadDir(dirname string) ([]os.FileInfo, error) {
+	infos, err := ioutil.ReadDir(d.resolve(dirname))
+	if err != nil {
+		return nil, d.pathError(dirname, err)
+	}
+	return infos, nil
+}
+
+func (d Dir) Glob(pattern string) ([]string, error) {
+	return glob(d, pattern)
+}
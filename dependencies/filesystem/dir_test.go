@@ -0,0 +1,234 @@
+package filesystem_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+func TestDir(t *testing.T) {
+	parent, err := ioutil.TempDir("", "flux-dir-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(parent) }()
+
+	root := filepath.Join(parent, "root")
+	if err := os.MkdirAll(filepath.Join(root, "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(parent, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"2021-01-02.csv", "2021-01-01.csv", "notes.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(root, "logs", name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := filesystem.Inject(context.Background(), filesystem.Dir(root))
+	data, err := filesystem.ReadFile(ctx, "logs/notes.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "notes.txt"; got != want {
+		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+
+	// Paths outside of the directory resolve to paths inside of it.
+	for _, name := range []string{"../secret.txt", "logs/../../secret.txt", filepath.Join(parent, "secret.txt")} {
+		if _, err := filesystem.ReadFile(ctx, name); !os.IsNotExist(err) {
+			t.Errorf("expected %q to not exist, got %v", name, err)
+		}
+	}
+
+	matches, err := filesystem.Glob(ctx, "logs/*.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"logs/2021-01-01.csv", "logs/2021-01-02.csv"}; !cmp.Equal(want, matches) {
+		t.Fatalf("unexpected matches -want/+got:\n%s", cmp.Diff(want, matches))
+	}
+	if matches, err := filesystem.Glob(ctx, "../*.txt"); err != nil {
+		t.Fatal(err)
+	} else if len(matches) != 0 {
+		t.Fatalf("expected no matches outside of the directory, got %v", matches)
+	}
+
+	f, err := filesystem.CreateFile(ctx, "../out.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(f, "out"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "out.csv")); err != nil {
+		t.Fatalf("expected the file to be created inside of the directory: %s", err)
+	}
+
+	// The error does not contain the location of the directory.
+	if _, err := filesystem.OpenFile(ctx, "missing.csv"); err == nil {
+		t.Fatal("expected an error")
+	} else if got, want := err.Error(), "open missing.csv: no such file or directory"; got != want {
+		t.Fatalf("unexpected error -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+}
+
+// openOnly only implements the Open method of the service.
+type openOnly struct {
+	filesystem.Service
+}
+
+// listing can list directories, but does not match patterns.
+type listing struct {
+	dir filesystem.Dir
+}
+
+func (l listing) Open(fpath string) (filesystem.File, error) {
+	return l.dir.Open(fpath)
+}
+
+func (l listing) ReadDir(dirname string) ([]os.FileInfo, error) {
+	return l.dir.ReadDir(dirname)
+}
+
+func TestGlob_ReadDir(t *testing.T) {
+	root, err := ioutil.TempDir("", "flux-glob-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(root) }()
+
+	for _, name := range []string{"a/1.csv", "a/2.txt", "b/3.csv", "c.csv"} {
+		fpath := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fpath, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := filesystem.Inject(context.Background(), listing{dir: filesystem.Dir(root)})
+	for _, tc := range []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "*/*.csv", want: []string{"a/1.csv", "b/3.csv"}},
+		{pattern: "*.csv", want: []string{"c.csv"}},
+		{pattern: "a/2.txt", want: []string{"a/2.txt"}},
+		{pattern: "a/3.txt", want: nil},
+		{pattern: "[a-b]/?.*", want: []string{"a/1.csv", "a/2.txt", "b/3.csv"}},
+	} {
+		got, err := filesystem.Glob(ctx, tc.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("unexpected matches for %q -want/+got:\n%s", tc.pattern, cmp.Diff(tc.want, got))
+		}
+	}
+
+	if _, err := filesystem.Glob(ctx, "[a-"); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
+
+// denied lists directories, but cannot open any file.
+type denied struct {
+	listing
+}
+
+func (denied) Open(fpath string) (filesystem.File, error) {
+	return nil, &os.PathError{Op: "open", Path: fpath, Err: os.ErrPermission}
+}
+
+func TestGlob_OpenError(t *testing.T) {
+	ctx := filesystem.Inject(context.Background(), denied{})
+	if _, err := filesystem.Glob(ctx, "a/1.csv"); !os.IsPermission(err) {
+		t.Errorf("expected a permission error, got %v", err)
+	}
+}
+
+func TestGlob_Unimplemented(t *testing.T) {
+	ctx := filesystem.Inject(context.Background(), openOnly{Service: filesystem.SystemFS})
+	if _, err := filesystem.Glob(ctx, "*.csv"); errors.Code(err) != codes.Unimplemented {
+		t.Errorf("expected an unimplemented error, got %v", err)
+	}
+	if _, err := filesystem.ReadDir(ctx, "."); errors.Code(err) != codes.Unimplemented {
+		t.Errorf("expected an unimplemented error, got %v", err)
+	}
+}
+
+func TestCreateAtomicFile(t *testing.T) {
+	root, err := ioutil.TempDir("", "flux-atomic-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(root) }()
+
+	if err := ioutil.WriteFile(filepath.Join(root, "out.csv"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := filesystem.Inject(context.Background(), filesystem.Dir(root))
+
+	readFile := func() string {
+		t.Helper()
+		data, err := filesystem.ReadFile(ctx, "out.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	write := func(data string, commit bool) {
+		t.Helper()
+		f, err := filesystem.CreateAtomicFile(ctx, "out.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(f, data); err != nil {
+			t.Fatal(err)
+		}
+		// The named file is unchanged until the file is closed.
+		if got, want := readFile(), "old"; got != want {
+			t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
+		}
+		if commit {
+			err = f.Close()
+		} else {
+			err = f.Abort()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("aborted", false)
+	if got, want := readFile(), "old"; got != want {
+		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+	write("new", true)
+	if got, want := readFile(), "new"; got != want {
+		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+
+	// The temporary files were removed.
+	infos, err := filesystem.ReadDir(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("expected a single file, got %d", len(infos))
+	}
+}
@@ -0,0 +1,89 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// glob matches the pattern by listing the directories with the service.
+// It follows the same rules as filepath.Glob, but it never reads
+// from the filesystem directly so the restrictions of the service
+// also apply to the pattern.
+func glob(fs Service, pattern string) ([]string, error) {
+	// Check the pattern is well formed.
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	if !hasMeta(pattern) {
+		// A file that does not exist does not match,
+		// but any other error is returned.
+		f, err := fs.Open(pattern)
+		if os.IsNotExist(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		_ = f.Close()
+		return []string{pattern}, nil
+	}
+
+	dir, file := filepath.Split(pattern)
+	dir = cleanGlobPath(dir)
+	if !hasMeta(dir) {
+		return globDir(fs, dir, file, nil)
+	}
+	// Prevent infinite recursion.
+	if dir == pattern {
+		return nil, filepath.ErrBadPattern
+	}
+
+	dirs, err := glob(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, d := range dirs {
+		matches, err = globDir(fs, d, file, matches)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// globDir appends the names of the entries in dir that match the pattern.
+// Errors reading the directory are ignored like they are by filepath.Glob.
+func globDir(fs Service, dir, pattern string, matches []string) ([]string, error) {
+	infos, err := fs.(DirReader).ReadDir(dir)
+	if err != nil {
+		return matches, nil
+	}
+	for _, info := range infos {
+		matched, err := filepath.Match(pattern, info.Name())
+		if err != nil {
+			return matches, err
+		}
+		if matched {
+			matches = append(matches, filepath.Join(dir, info.Name()))
+		}
+	}
+	return matches, nil
+}
+
+// cleanGlobPath prepares the directory of a pattern for matching.
+func cleanGlobPath(path string) string {
+	switch path {
+	case "":
+		return "."
+	case string(filepath.Separator):
+		return path
+	default:
+		// Remove the trailing separator.
+		return path[:len(path)-1]
+	}
+}
+
+func hasMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[\`)
+}
@@ -2,8 +2,14 @@ package filesystem
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"time"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
 )
 
 // ReadFile will open the file from the service and read
@@ -30,6 +36,107 @@ func OpenFile(ctx context.Context, filename string) (File, error) {
 	return fs.Open(filename)
 }
 
+// CreateFile will create the file from the service.
+// It returns an error if the service does not support creating files.
+func CreateFile(ctx context.Context, filename string) (io.WriteCloser, error) {
+	fs, err := Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := fs.(Creator)
+	if !ok {
+		return nil, errors.New(codes.Unimplemented, "filesystem service does not support creating files")
+	}
+	return c.Create(filename)
+}
+
+// AtomicFile is a file that is written to a temporary file
+// which replaces the named file when it is closed.
+// The named file is left unchanged if the file is aborted.
+type AtomicFile struct {
+	w    io.WriteCloser
+	fs   Renamer
+	name string
+	tmp  string
+	done bool
+}
+
+// CreateAtomicFile will create a temporary file next to the named file
+// with the service. The temporary file replaces the named file when
+// it is closed. If the service cannot rename files, the named file
+// is written directly and may be left incomplete when it is aborted.
+func CreateAtomicFile(ctx context.Context, filename string) (*AtomicFile, error) {
+	fs, err := Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := fs.(Creator)
+	if !ok {
+		return nil, errors.New(codes.Unimplemented, "filesystem service does not support creating files")
+	}
+	r, ok := fs.(Renamer)
+	if !ok {
+		w, err := c.Create(filename)
+		if err != nil {
+			return nil, err
+		}
+		return &AtomicFile{w: w, name: filename}, nil
+	}
+
+	tmp := fmt.Sprintf("%s.%d.tmp", filename, time.Now().UnixNano())
+	w, err := c.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	return &AtomicFile{
+		w:    w,
+		fs:   r,
+		name: filename,
+		tmp:  tmp,
+	}, nil
+}
+
+func (f *AtomicFile) Write(p []byte) (int, error) {
+	return f.w.Write(p)
+}
+
+// Close closes the temporary file and renames it to the named file.
+func (f *AtomicFile) Close() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	if err := f.w.Close(); err != nil {
+		f.remove()
+		return err
+	}
+	if f.fs == nil {
+		return nil
+	}
+	if err := f.fs.Rename(f.tmp, f.name); err != nil {
+		f.remove()
+		return err
+	}
+	return nil
+}
+
+// Abort closes and removes the temporary file.
+func (f *AtomicFile) Abort() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	err := f.w.Close()
+	f.remove()
+	return err
+}
+
+func (f *AtomicFile) remove() {
+	if f.fs != nil {
+		_ = f.fs.Remove(f.tmp)
+	}
+}
+
 // Stat will retrieve the os.FileInfo for a file.
 func Stat(ctx context.Context, filename string) (os.FileInfo, error) {
 	fs, err := Get(ctx)
@@ -43,3 +150,34 @@ func Stat(ctx context.Context, filename string) (os.FileInfo, error) {
 	defer func() { _ = f.Close() }()
 	return f.Stat()
 }
+
+// ReadDir will list the contents of the directory from the service.
+// It returns an error if the service does not support listing directories.
+func ReadDir(ctx context.Context, dirname string) ([]os.FileInfo, error) {
+	fs, err := Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := fs.(DirReader)
+	if !ok {
+		return nil, errors.New(codes.Unimplemented, "filesystem service does not support reading directories")
+	}
+	return r.ReadDir(dirname)
+}
+
+// Glob will return the names of the files from the service that
+// match the pattern. A service that does not implement Globber
+// must implement DirReader so the directories can be listed.
+func Glob(ctx context.Context, pattern string) ([]string, error) {
+	fs, err := Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if g, ok := fs.(Globber); ok {
+		return g.Glob(pattern)
+	}
+	if _, ok := fs.(DirReader); !ok {
+		return nil, errors.New(codes.Unimplemented, "filesystem service does not support matching file patterns")
+	}
+	return glob(fs, pattern)
+}
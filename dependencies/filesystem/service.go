@@ -20,6 +20,39 @@ type Service interface {
 	Open(fpath string) (File, error)
 }
 
+// Creator is implemented by a Service that is able to create files.
+type Creator interface {
+	// Create creates the named file, truncating it if it already exists.
+	Create(fpath string) (io.WriteCloser, error)
+}
+
+// Renamer is implemented by a Service that is able to rename and
+// remove files. It allows a file to be written to a temporary name
+// and replace the named file only once it is complete.
+type Renamer interface {
+	// Rename renames the file, replacing newpath if it already exists.
+	Rename(oldpath, newpath string) error
+
+	// Remove removes the named file.
+	Remove(fpath string) error
+}
+
+// DirReader is implemented by a Service that is able to list
+// the contents of a directory.
+type DirReader interface {
+	// ReadDir returns the entries of the named directory sorted by name.
+	ReadDir(dirname string) ([]os.FileInfo, error)
+}
+
+// Globber is implemented by a Service that matches file patterns itself.
+// A Service that implements DirReader and not Globber can still be used
+// with Glob since the pattern is matched against the directory listings.
+type Globber interface {
+	// Glob returns the names of the files that match the pattern.
+	// The pattern syntax is the same as in filepath.Match.
+	Glob(pattern string) ([]string, error)
+}
+
 type key int
 
 const serviceKey key = iota
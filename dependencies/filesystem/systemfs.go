@@ -1,7 +1,10 @@
 package filesystem
 
 import (
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 )
 
 // SystemFS implements the filesystem.Service by proxying all requests
@@ -17,3 +20,27 @@ func (systemFS) Open(fpath string) (File, error) {
 	}
 	return f, nil
 }
+
+func (systemFS) Create(fpath string) (io.WriteCloser, error) {
+	f, err := os.Create(fpath)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (systemFS) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
+func (systemFS) Remove(fpath string) error {
+	return os.Remove(fpath)
+}
+
+func (systemFS) ReadDir(dirname string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(dirname)
+}
+
+func (systemFS) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux/dependencies/filesystem"
 )
 
@@ -95,3 +96,68 @@ func TestSystemFS_ReadFile(t *testing.T) {
 		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
 	}
 }
+
+func TestSystemFS_CreateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flux-systemfs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	fpath := filepath.Join(dir, "out.txt")
+	ctx := filesystem.Inject(context.Background(), filesystem.SystemFS)
+	f, err := filesystem.CreateFile(ctx, fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(f, "Hello, World!"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := filesystem.ReadFile(ctx, fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(data), "Hello, World!"; got != want {
+		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+}
+
+func TestSystemFS_ReadDirAndGlob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flux-systemfs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for _, name := range []string{"b.csv", "a.csv", "c.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := filesystem.Inject(context.Background(), filesystem.SystemFS)
+	infos, err := filesystem.ReadDir(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	if want := []string{"a.csv", "b.csv", "c.txt"}; !cmp.Equal(want, names) {
+		t.Fatalf("unexpected directory entries -want/+got:\n%s", cmp.Diff(want, names))
+	}
+
+	matches, err := filesystem.Glob(ctx, filepath.Join(dir, "*.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(dir, "a.csv"), filepath.Join(dir, "b.csv")}; !cmp.Equal(want, matches) {
+		t.Fatalf("unexpected matches -want/+got:\n%s", cmp.Diff(want, matches))
+	}
+}
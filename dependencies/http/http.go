@@ -123,22 +123,37 @@ func WithTLSConfig(c Client, config *tls.Config) (Client, error) {
 
 	// We control the clients so we can safely deconstruct the client
 	// to change its transport config.
-	switch t := newClient.Transport.(type) {
+	transport, err := withTLSConfig(newClient.Transport, config)
+	if err != nil {
+		return nil, err
+	}
+	newClient.Transport = transport
+	return &newClient, nil
+}
+
+// withTLSConfig clones the transport wrapped by
+// the round trippers with the TLS config.
+func withTLSConfig(rt http.RoundTripper, config *tls.Config) (http.RoundTripper, error) {
+	switch t := rt.(type) {
 	case *http.Transport:
 		newTransport := t.Clone()
 		newTransport.TLSClientConfig = config
-		newClient.Transport = newTransport
+		return newTransport, nil
 	case roundTripLimiter:
-		transport, ok := t.RoundTripper.(*http.Transport)
-		if !ok {
-			return nil, errors.New(codes.Internal, "roundTripLimiter does not have http a known transport")
+		transport, err := withTLSConfig(t.RoundTripper, config)
+		if err != nil {
+			return nil, err
 		}
-		newTransport := transport.Clone()
-		newTransport.TLSClientConfig = config
-		t.RoundTripper = newTransport
-		newClient.Transport = t
+		t.RoundTripper = transport
+		return t, nil
+	case roundTripRetrier:
+		transport, err := withTLSConfig(t.RoundTripper, config)
+		if err != nil {
+			return nil, err
+		}
+		t.RoundTripper = transport
+		return t, nil
 	default:
 		return nil, errors.New(codes.Internal, "http client does not have http a known transport")
 	}
-	return &newClient, nil
 }
@@ -0,0 +1,346 @@
+package http
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// RetryPolicy controls how failed requests are retried.
+//
+// A request is retried when the transport returns an error,
+// such as a timeout or a refused connection, or when the response
+// has one of the retryable status codes. Requests with a body
+// are only retried if the body can be read again with GetBody.
+//
+// Only idempotent requests are retried so that a request is not
+// applied twice when a response is lost. These are the requests with
+// the GET, HEAD, OPTIONS, TRACE, PUT or DELETE methods and any request
+// with an Idempotency-Key or X-Idempotency-Key header.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent.
+	// A value of one or less disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the time to wait before the first retry.
+	// The backoff is doubled for each retry after that.
+	InitialBackoff time.Duration
+
+	// MaxBackoff limits the time to wait between attempts.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction of the backoff between 0 and 1
+	// that is randomly removed to spread out retries.
+	Jitter float64
+
+	// AttemptTimeout limits the time of each attempt so a request that
+	// times out can be retried. Zero means attempts have no timeout.
+	AttemptTimeout time.Duration
+
+	// RetryableStatusCodes are the response status codes that are retried.
+	RetryableStatusCodes []int
+
+	// CircuitBreaker configures the circuit breaker for each host.
+	CircuitBreaker CircuitBreakerPolicy
+}
+
+// CircuitBreakerPolicy controls when requests to a host fail
+// immediately because the previous requests to it failed.
+//
+// The circuit opens after a number of consecutive failed attempts
+// and requests fail until the reset timeout has passed. The next
+// request is then sent and closes the circuit if it succeeds or
+// opens it again if it fails.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failed attempts
+	// that opens the circuit. Zero disables the circuit breaker.
+	FailureThreshold int
+
+	// ResetTimeout is how long the circuit stays open.
+	ResetTimeout time.Duration
+}
+
+// DefaultRetryPolicy returns a policy that sends a request
+// up to three times and retries the status codes that
+// indicate the server is temporarily unavailable.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		CircuitBreaker: CircuitBreakerPolicy{
+			FailureThreshold: 5,
+			ResetTimeout:     30 * time.Second,
+		},
+	}
+}
+
+// WithRetryPolicy returns a client that retries requests with the policy.
+// The timeout of the client limits the total time of all attempts.
+func WithRetryPolicy(c Client, policy RetryPolicy) (Client, error) {
+	cli, ok := c.(*http.Client)
+	if !ok {
+		return nil, errors.New(codes.Internal, "cannot set retry policy on client")
+	}
+	// make shallow copy
+	newClient := *cli
+	if newClient.Transport == nil {
+		newClient.Transport = http.DefaultTransport
+	}
+	newClient.Transport = roundTripRetrier{
+		RoundTripper: newClient.Transport,
+		policy:       policy,
+		breakers:     &circuitBreakers{hosts: make(map[string]*circuitBreaker)},
+	}
+	return &newClient, nil
+}
+
+type attemptKey struct{}
+
+// Attempts returns the number of times the request
+// for the response was sent by a client with a retry policy.
+// It returns one if the client did not retry requests.
+func Attempts(resp *http.Response) int {
+	if resp == nil || resp.Request == nil {
+		return 1
+	}
+	if n, ok := resp.Request.Context().Value(attemptKey{}).(int); ok {
+		return n
+	}
+	return 1
+}
+
+type roundTripRetrier struct {
+	http.RoundTripper
+	policy   RetryPolicy
+	breakers *circuitBreakers
+}
+
+func (r roundTripRetrier) RoundTrip(req *http.Request) (*http.Response, error) {
+	breaker := r.breakers.get(req.URL.Host)
+	idempotent := isIdempotent(req)
+	for attempt := 1; ; attempt++ {
+		if !breaker.allow(r.policy.CircuitBreaker) {
+			return nil, errors.Newf(codes.Unavailable, "circuit breaker is open for host %q after repeated failures", req.URL.Host)
+		}
+
+		areq, err := r.newAttempt(req, attempt)
+		if err != nil {
+			// The attempt was not sent so it says nothing about the host.
+			breaker.release(r.policy.CircuitBreaker)
+			return nil, err
+		}
+		var cancel context.CancelFunc = func() {}
+		if r.policy.AttemptTimeout > 0 {
+			var ctx context.Context
+			ctx, cancel = context.WithTimeout(areq.Context(), r.policy.AttemptTimeout)
+			areq = areq.WithContext(ctx)
+		}
+
+		resp, err := r.RoundTripper.RoundTrip(areq)
+		failed := err != nil || r.retryable(resp.StatusCode)
+		breaker.record(r.policy.CircuitBreaker, !failed)
+		if !failed || !idempotent || attempt >= r.policy.MaxAttempts || req.Context().Err() != nil {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			// The attempt is canceled once the body is closed
+			// so the timeout still applies when reading the body.
+			resp.Body = cancelReadCloser{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		backoff := r.backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp); ok && d > backoff {
+				backoff = d
+			}
+			// Read the body so the connection can be reused.
+			_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseBody))
+			_ = resp.Body.Close()
+		}
+		cancel()
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// newAttempt creates the request for an attempt.
+// The context of the request records the attempt
+// and the body is read again for each retry.
+func (r roundTripRetrier) newAttempt(req *http.Request, attempt int) (*http.Request, error) {
+	areq := req.WithContext(context.WithValue(req.Context(), attemptKey{}, attempt))
+	if attempt == 1 || req.Body == nil || req.Body == http.NoBody {
+		return areq, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New(codes.Internal, "cannot retry a request with a body that cannot be read again")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	areq.Body = body
+	return areq, nil
+}
+
+// isIdempotent reports whether sending the request more than once
+// has the same effect as sending it once. This follows the rules
+// used by the http.Transport to retry requests on new connections.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	// The header marks the request as idempotent
+	// even when it does not have a value.
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+	if _, ok := req.Header["X-Idempotency-Key"]; ok {
+		return true
+	}
+	return false
+}
+
+func (r roundTripRetrier) retryable(statusCode int) bool {
+	for _, code := range r.policy.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the time to wait after the attempt.
+func (r roundTripRetrier) backoff(attempt int) time.Duration {
+	d := float64(r.policy.InitialBackoff) * math.Pow(2, float64(attempt-1))
+	if max := float64(r.policy.MaxBackoff); max > 0 && d > max {
+		d = max
+	}
+	if r.policy.Jitter > 0 {
+		d -= d * r.policy.Jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// retryAfter returns the delay in the Retry-After header of the response.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelReadCloser) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+// circuitBreakers holds the circuit breaker for each host.
+type circuitBreakers struct {
+	mu    sync.Mutex
+	hosts map[string]*circuitBreaker
+}
+
+func (c *circuitBreakers) get(host string) *circuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.hosts[host]
+	if !ok {
+		b = &circuitBreaker{}
+		c.hosts[host] = b
+	}
+	return b
+}
+
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// allow reports whether a request may be sent.
+// Once the circuit has been open for the reset timeout,
+// a single request is allowed to probe the host.
+func (b *circuitBreaker) allow(policy CircuitBreakerPolicy) bool {
+	if policy.FailureThreshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < policy.FailureThreshold {
+		return true
+	}
+	if b.probing || time.Now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// release ends the probe of an attempt that was allowed but not sent
+// so that another request may probe the host.
+func (b *circuitBreaker) release(policy CircuitBreakerPolicy) {
+	if policy.FailureThreshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// record records the result of an attempt.
+func (b *circuitBreaker) record(policy CircuitBreakerPolicy, success bool) {
+	if policy.FailureThreshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if success {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= policy.FailureThreshold {
+		b.openUntil = time.Now().Add(policy.ResetTimeout)
+	}
+}
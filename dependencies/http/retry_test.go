@@ -0,0 +1,291 @@
+package http
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/codes"
+	depsUrl "github.com/influxdata/flux/dependencies/url"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func newRetryClient(t *testing.T, policy RetryPolicy) Client {
+	t.Helper()
+	c, err := WithRetryPolicy(NewLimitedDefaultClient(depsUrl.PassValidator{}), policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "data" {
+			t.Errorf("unexpected body on attempt %d: %q", atomic.LoadInt32(&requests)+1, body)
+		}
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	c := newRetryClient(t, testRetryPolicy())
+	req, err := http.NewRequest("PUT", ts.URL, strings.NewReader("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if want, got := http.StatusOK, resp.StatusCode; want != got {
+		t.Errorf("unexpected status code: want %d, got %d", want, got)
+	}
+	if want, got := 3, Attempts(resp); want != got {
+		t.Errorf("unexpected attempts: want %d, got %d", want, got)
+	}
+}
+
+func TestRetryPolicy_MaxAttempts(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	c := newRetryClient(t, testRetryPolicy())
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	// The last response is returned when every attempt failed.
+	if want, got := http.StatusBadGateway, resp.StatusCode; want != got {
+		t.Errorf("unexpected status code: want %d, got %d", want, got)
+	}
+	if want, got := int32(3), atomic.LoadInt32(&requests); want != got {
+		t.Errorf("unexpected requests: want %d, got %d", want, got)
+	}
+}
+
+func TestRetryPolicy_NotRetryable(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	c := newRetryClient(t, testRetryPolicy())
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	if want, got := int32(1), atomic.LoadInt32(&requests); want != got {
+		t.Errorf("unexpected requests: want %d, got %d", want, got)
+	}
+	if want, got := 1, Attempts(resp); want != got {
+		t.Errorf("unexpected attempts: want %d, got %d", want, got)
+	}
+}
+
+func TestRetryPolicy_NotIdempotent(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		header       string
+		wantRequests int32
+	}{
+		{
+			name:         "post",
+			wantRequests: 1,
+		},
+		{
+			name:         "post with idempotency key",
+			header:       "Idempotency-Key",
+			wantRequests: 3,
+		},
+		{
+			name:         "post with x idempotency key",
+			header:       "X-Idempotency-Key",
+			wantRequests: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer ts.Close()
+
+			c := newRetryClient(t, testRetryPolicy())
+			req, err := http.NewRequest("POST", ts.URL, strings.NewReader("data"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.header != "" {
+				req.Header.Set(tc.header, "key")
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = resp.Body.Close()
+
+			if want, got := tc.wantRequests, atomic.LoadInt32(&requests); want != got {
+				t.Errorf("unexpected requests: want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_AttemptTimeout(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	policy := testRetryPolicy()
+	policy.AttemptTimeout = 50 * time.Millisecond
+	c := newRetryClient(t, policy)
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "ok", string(body); want != got {
+		t.Errorf("unexpected body: want %q, got %q", want, got)
+	}
+	if want, got := 2, Attempts(resp); want != got {
+		t.Errorf("unexpected attempts: want %d, got %d", want, got)
+	}
+}
+
+func TestRetryPolicy_CircuitBreaker(t *testing.T) {
+	var requests, healthy int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	policy := testRetryPolicy()
+	policy.MaxAttempts = 2
+	policy.CircuitBreaker = CircuitBreakerPolicy{
+		FailureThreshold: 2,
+		ResetTimeout:     50 * time.Millisecond,
+	}
+	c := newRetryClient(t, policy)
+
+	do := func() (*http.Response, error) {
+		req, _ := http.NewRequest("GET", ts.URL, nil)
+		resp, err := c.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	// The failed attempts open the circuit.
+	if _, err := do(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := do(); err == nil {
+		t.Fatal("expected error when the circuit is open")
+	} else if uerr, ok := err.(*url.Error); !ok {
+		t.Errorf("expected url error, got %T", err)
+	} else if want, got := codes.Unavailable, errors.Code(uerr.Err); want != got {
+		t.Errorf("unexpected error code: want %v, got %v: %s", want, got, err)
+	}
+	if want, got := int32(2), atomic.LoadInt32(&requests); want != got {
+		t.Errorf("unexpected requests: want %d, got %d", want, got)
+	}
+
+	// The request after the reset timeout closes the circuit.
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(policy.CircuitBreaker.ResetTimeout)
+	resp, err := do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := http.StatusOK, resp.StatusCode; want != got {
+		t.Errorf("unexpected status code: want %d, got %d", want, got)
+	}
+	if _, err := do(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCircuitBreaker_Release(t *testing.T) {
+	policy := CircuitBreakerPolicy{
+		FailureThreshold: 1,
+		ResetTimeout:     time.Millisecond,
+	}
+	var b circuitBreaker
+	b.record(policy, false)
+	time.Sleep(policy.ResetTimeout)
+
+	if !b.allow(policy) {
+		t.Fatal("expected a probe to be allowed after the reset timeout")
+	}
+	if b.allow(policy) {
+		t.Fatal("expected a single probe to be allowed")
+	}
+	// The probe was not sent so another request may probe the host.
+	b.release(policy)
+	if !b.allow(policy) {
+		t.Fatal("expected a probe to be allowed after the previous probe was released")
+	}
+}
+
+func TestRetryPolicy_WithTLSConfig(t *testing.T) {
+	c := newRetryClient(t, testRetryPolicy())
+	if _, err := WithTLSConfig(c, &tls.Config{InsecureSkipVerify: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WithTimeout(c, time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
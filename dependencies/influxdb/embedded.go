@@ -0,0 +1,697 @@
+package influxdb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"hash/fnv"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/compiler"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	fluxmemory "github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/line-protocol/v2/lineprotocol"
+)
+
+// EmbeddedProvider is an implementation of the Provider that reads
+// and writes series in memory instead of making calls to an influxdb
+// instance. It is used to run queries with no server, such as in tests.
+//
+// Series are loaded from line protocol with Load or LoadFile and are
+// written by to(). The host, organization and token in the Config are
+// ignored and every bucket belongs to every organization. The planner
+// only reads from a provider when a host is set so queries that do not
+// specify one should register DefaultFromAttributes with any host.
+type EmbeddedProvider struct {
+	mu      sync.RWMutex
+	buckets map[string]*embeddedBucket
+}
+
+var (
+	_ Provider           = (*EmbeddedProvider)(nil)
+	_ StatisticsProvider = (*EmbeddedProvider)(nil)
+)
+
+// NewEmbeddedProvider constructs an EmbeddedProvider with no buckets.
+func NewEmbeddedProvider() *EmbeddedProvider {
+	return &EmbeddedProvider{
+		buckets: make(map[string]*embeddedBucket),
+	}
+}
+
+// CreateBucket creates an empty bucket if it does not exist.
+func (p *EmbeddedProvider) CreateBucket(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bucket(name)
+}
+
+// bucket returns the named bucket and creates it if it does not exist.
+// The caller must hold the write lock.
+func (p *EmbeddedProvider) bucket(name string) *embeddedBucket {
+	b, ok := p.buckets[name]
+	if !ok {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(name))
+		b = &embeddedBucket{
+			name:   name,
+			id:     strconv.FormatUint(h.Sum64(), 16),
+			series: make(map[string]*embeddedSeries),
+		}
+		p.buckets[name] = b
+	}
+	return b
+}
+
+// LoadFile reads the line protocol in the named file into the bucket.
+// See Load for the format of the file.
+func (p *EmbeddedProvider) LoadFile(bucket, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, codes.Invalid, "failed to open %q", path)
+	}
+	defer func() { _ = f.Close() }()
+	return p.Load(bucket, f)
+}
+
+// Load reads line protocol with nanosecond timestamps into the bucket.
+//
+// The output of influx_inspect export, which exports the TSM files
+// of a server as line protocol, can also be loaded. The points that
+// follow a CONTEXT-DATABASE and CONTEXT-RETENTION-POLICY comment are
+// written to the bucket named "<database>/<retention policy>"
+// instead and the statements in the DDL section are ignored.
+func (p *EmbeddedProvider) Load(bucket string, r io.Reader) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var (
+		database, rp string
+		ddl          bool
+		lineno       int
+		touched      = make(map[*embeddedBucket]bool)
+	)
+	defer func() {
+		for b := range touched {
+			b.normalize()
+		}
+	}()
+
+	b := p.bucket(bucket)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		lineno++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		} else if line[0] == '#' {
+			comment := strings.TrimSpace(string(line[1:]))
+			switch {
+			case comment == "DDL":
+				ddl = true
+			case comment == "DML":
+				ddl = false
+			case strings.HasPrefix(comment, "CONTEXT-DATABASE:"):
+				database = strings.TrimSpace(strings.TrimPrefix(comment, "CONTEXT-DATABASE:"))
+				b = p.bucket(database + "/" + rp)
+			case strings.HasPrefix(comment, "CONTEXT-RETENTION-POLICY:"):
+				rp = strings.TrimSpace(strings.TrimPrefix(comment, "CONTEXT-RETENTION-POLICY:"))
+				b = p.bucket(database + "/" + rp)
+			}
+			continue
+		} else if ddl {
+			continue
+		}
+
+		touched[b] = true
+		if err := b.writeLine(line); err != nil {
+			return errors.Wrapf(err, codes.Invalid, "line %d", lineno)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, codes.Invalid, "failed to read line protocol")
+	}
+	return nil
+}
+
+func (p *EmbeddedProvider) lookup(conf Config) (*embeddedBucket, error) {
+	if conf.Bucket.IsZero() {
+		return nil, errors.New(codes.Invalid, "bucket is required")
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, b := range p.buckets {
+		if b.name == conf.Bucket.Name || b.id == conf.Bucket.ID {
+			return b, nil
+		}
+	}
+	return nil, errors.Newf(codes.NotFound, "bucket %q not found", conf.Bucket.IdOrName())
+}
+
+func (p *EmbeddedProvider) ReaderFor(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet) (Reader, error) {
+	b, err := p.lookup(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &embeddedReader{
+		p:            p,
+		bucket:       b,
+		bounds:       bounds,
+		predicateSet: predicateSet,
+	}, nil
+}
+
+func (p *EmbeddedProvider) SeriesCardinalityReaderFor(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet) (Reader, error) {
+	for _, pred := range predicateSet {
+		if pred.KeepEmpty {
+			return nil, errors.New(codes.Unimplemented, "keep empty filter option is not allowed for the series cardinality reader")
+		}
+	}
+	b, err := p.lookup(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &embeddedCardinalityReader{
+		embeddedReader{
+			p:            p,
+			bucket:       b,
+			bounds:       bounds,
+			predicateSet: predicateSet,
+		},
+	}, nil
+}
+
+// ReadStatistics counts the points and series within the bounds.
+// The predicates are not evaluated so the counts are an upper bound.
+func (p *EmbeddedProvider) ReadStatistics(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet) (plan.Statistics, error) {
+	b, err := p.lookup(conf)
+	if err != nil {
+		return plan.Statistics{}, err
+	}
+	start, stop := timeRange(bounds)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var stats plan.Statistics
+	for _, s := range b.series {
+		lo, hi := s.search(start, stop)
+		if lo >= hi {
+			continue
+		}
+		stats.Cardinality += int64(hi - lo)
+		stats.GroupCardinality++
+	}
+	return stats, nil
+}
+
+func (p *EmbeddedProvider) BucketsReaderFor(ctx context.Context, conf Config) (Reader, error) {
+	return &embeddedBucketsReader{p: p, org: conf.Org.IdOrName()}, nil
+}
+
+func (p *EmbeddedProvider) WriterFor(ctx context.Context, conf Config) (Writer, error) {
+	b, err := p.lookup(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &embeddedWriter{p: p, bucket: b}, nil
+}
+
+type embeddedBucket struct {
+	name   string
+	id     string
+	series map[string]*embeddedSeries
+}
+
+// writeLine writes every field in the line of line protocol.
+// The caller must hold the write lock.
+func (b *embeddedBucket) writeLine(line []byte) error {
+	dec := lineprotocol.NewDecoderWithBytes(line)
+	for dec.Next() {
+		m, err := dec.Measurement()
+		if err != nil {
+			return err
+		}
+		var tags []embeddedTag
+		for {
+			key, value, err := dec.NextTag()
+			if err != nil {
+				return err
+			} else if key == nil {
+				break
+			}
+			tags = append(tags, embeddedTag{key: string(key), value: string(value)})
+		}
+		type field struct {
+			key   string
+			value values.Value
+		}
+		var fields []field
+		for {
+			key, value, err := dec.NextField()
+			if err != nil {
+				return err
+			} else if key == nil {
+				break
+			}
+			fields = append(fields, field{key: string(key), value: values.New(value.Interface())})
+		}
+		ts, err := dec.Time(lineprotocol.Nanosecond, time.Now())
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if err := b.write(string(m), tags, f.key, values.ConvertTime(ts), f.value); err != nil {
+				return err
+			}
+		}
+	}
+	return dec.Err()
+}
+
+// write appends a point to a series. The caller must hold the write
+// lock and normalize the bucket once the points are written.
+func (b *embeddedBucket) write(measurement string, tags []embeddedTag, field string, ts values.Time, v values.Value) error {
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].key < tags[j].key
+	})
+	var sb strings.Builder
+	sb.WriteString(measurement)
+	for _, t := range tags {
+		sb.WriteByte(0)
+		sb.WriteString(t.key)
+		sb.WriteByte(0)
+		sb.WriteString(t.value)
+	}
+	sb.WriteByte(0)
+	sb.WriteString(field)
+	key := sb.String()
+
+	typ := flux.ColumnType(v.Type())
+	if typ == flux.TInvalid {
+		return errors.Newf(codes.Invalid, "field %q has an unsupported type %v", field, v.Type())
+	}
+	s, ok := b.series[key]
+	if !ok {
+		s = &embeddedSeries{
+			key:         key,
+			measurement: measurement,
+			field:       field,
+			tags:        append([]embeddedTag(nil), tags...),
+			typ:         typ,
+		}
+		b.series[key] = s
+	} else if s.typ != typ {
+		return errors.Newf(codes.Invalid, "field type conflict: %q is of type %s, got %s", field, s.typ, typ)
+	}
+	s.times = append(s.times, ts)
+	s.values = append(s.values, v)
+	return nil
+}
+
+// normalize sorts the points of every series by time and removes
+// duplicate timestamps. The last point that was written with the
+// same timestamp is kept like it is by influxdb.
+func (b *embeddedBucket) normalize() {
+	for _, s := range b.series {
+		idx := make([]int, len(s.times))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(i, j int) bool {
+			return s.times[idx[i]] < s.times[idx[j]]
+		})
+		times := make([]values.Time, 0, len(idx))
+		vs := make([]values.Value, 0, len(idx))
+		for _, i := range idx {
+			if n := len(times); n > 0 && times[n-1] == s.times[i] {
+				vs[n-1] = s.values[i]
+				continue
+			}
+			times = append(times, s.times[i])
+			vs = append(vs, s.values[i])
+		}
+		s.times, s.values = times, vs
+	}
+}
+
+type embeddedTag struct {
+	key, value string
+}
+
+// embeddedSeries holds the points for a field of a series.
+type embeddedSeries struct {
+	key         string
+	measurement string
+	field       string
+	tags        []embeddedTag
+	typ         flux.ColType
+	times       []values.Time
+	values      []values.Value
+}
+
+// search returns the range of indices of the points
+// with times in the range [start, stop).
+func (s *embeddedSeries) search(start, stop values.Time) (lo, hi int) {
+	lo = sort.Search(len(s.times), func(i int) bool { return s.times[i] >= start })
+	hi = sort.Search(len(s.times), func(i int) bool { return s.times[i] >= stop })
+	return lo, hi
+}
+
+// timeRange resolves the bounds to a start and stop time.
+func timeRange(bounds flux.Bounds) (start, stop values.Time) {
+	now := bounds.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	start = values.ConvertTime(bounds.Start.Time(now))
+	stop = values.ConvertTime(now)
+	if !bounds.Stop.IsZero() {
+		stop = values.ConvertTime(bounds.Stop.Time(now))
+	}
+	return start, stop
+}
+
+// embeddedReader reads the series in a bucket
+// that pass the predicates within the bounds.
+type embeddedReader struct {
+	p            *EmbeddedProvider
+	bucket       *embeddedBucket
+	bounds       flux.Bounds
+	predicateSet PredicateSet
+}
+
+// embeddedPoints is a copy of the points of a
+// series that are within the bounds.
+type embeddedPoints struct {
+	*embeddedSeries
+	times  []values.Time
+	values []values.Value
+}
+
+// snapshot copies the points within the bounds for each series
+// so the points can be read without holding the lock.
+func (r *embeddedReader) snapshot() (start, stop values.Time, points []embeddedPoints) {
+	start, stop = timeRange(r.bounds)
+
+	r.p.mu.RLock()
+	defer r.p.mu.RUnlock()
+	for _, s := range r.bucket.series {
+		lo, hi := s.search(start, stop)
+		if lo >= hi {
+			continue
+		}
+		points = append(points, embeddedPoints{
+			embeddedSeries: s,
+			times:          append([]values.Time(nil), s.times[lo:hi]...),
+			values:         append([]values.Value(nil), s.values[lo:hi]...),
+		})
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].key < points[j].key
+	})
+	return start, stop, points
+}
+
+func (r *embeddedReader) Read(ctx context.Context, f func(flux.Table) error, mem memory.Allocator) error {
+	start, stop, points := r.snapshot()
+	fns := make([]*execute.RowPredicateFn, len(r.predicateSet))
+	for i, pred := range r.predicateSet {
+		fns[i] = execute.NewRowPredicateFn(pred.Fn, compiler.ToScope(pred.Scope))
+	}
+
+	for _, pts := range points {
+		keyCols := []flux.ColMeta{
+			{Label: execute.DefaultStartColLabel, Type: flux.TTime},
+			{Label: execute.DefaultStopColLabel, Type: flux.TTime},
+			{Label: "_field", Type: flux.TString},
+			{Label: "_measurement", Type: flux.TString},
+		}
+		keyValues := []values.Value{
+			values.NewTime(start),
+			values.NewTime(stop),
+			values.NewString(pts.field),
+			values.NewString(pts.measurement),
+		}
+		for _, t := range pts.tags {
+			keyCols = append(keyCols, flux.ColMeta{Label: t.key, Type: flux.TString})
+			keyValues = append(keyValues, values.NewString(t.value))
+		}
+		cols := make([]flux.ColMeta, 0, len(keyCols)+2)
+		cols = append(cols, keyCols[:2]...)
+		cols = append(cols,
+			flux.ColMeta{Label: execute.DefaultTimeColLabel, Type: flux.TTime},
+			flux.ColMeta{Label: execute.DefaultValueColLabel, Type: pts.typ},
+		)
+		cols = append(cols, keyCols[2:]...)
+		key := execute.NewGroupKey(keyCols, keyValues)
+
+		rows, ok, err := r.filter(ctx, fns, key, cols, pts)
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		b := execute.NewColListTableBuilder(key, resourceAllocator(mem))
+		for _, c := range cols {
+			if _, err := b.AddCol(c); err != nil {
+				return err
+			}
+		}
+		for _, i := range rows {
+			if err := b.AppendTime(2, pts.times[i]); err != nil {
+				return err
+			}
+			if err := b.AppendValue(3, pts.values[i]); err != nil {
+				return err
+			}
+		}
+		if err := execute.AppendKeyValuesN(key, b, len(rows)); err != nil {
+			return err
+		}
+		tbl, err := b.Table()
+		if err != nil {
+			return err
+		}
+		if err := f(tbl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filter returns the indices of the points that pass every predicate.
+// The predicates are applied in order like a sequence of filter calls
+// so an empty table is only kept if each predicate after the table
+// became empty keeps empty tables.
+func (r *embeddedReader) filter(ctx context.Context, fns []*execute.RowPredicateFn, key flux.GroupKey, cols []flux.ColMeta, pts embeddedPoints) ([]int, bool, error) {
+	rows := make([]int, len(pts.times))
+	for i := range rows {
+		rows[i] = i
+	}
+	for i, fn := range fns {
+		prepared, err := fn.Prepare(cols)
+		if err != nil {
+			return nil, false, err
+		}
+		record := values.NewObject(prepared.InputType())
+		for j, c := range key.Cols() {
+			record.Set(c.Label, key.Value(j))
+		}
+
+		n := 0
+		for _, row := range rows {
+			record.Set(execute.DefaultTimeColLabel, values.NewTime(pts.times[row]))
+			record.Set(execute.DefaultValueColLabel, pts.values[row])
+			pass, err := prepared.Eval(ctx, record)
+			if err != nil {
+				return nil, false, errors.Wrap(err, codes.Inherit, "failed to evaluate filter function")
+			}
+			if pass {
+				rows[n] = row
+				n++
+			}
+		}
+		rows = rows[:n]
+		if n == 0 && !r.predicateSet[i].KeepEmpty {
+			return nil, false, nil
+		}
+	}
+	return rows, true, nil
+}
+
+// resourceAllocator returns the allocator that is used
+// by the executor or wraps the allocator if it is not.
+func resourceAllocator(mem memory.Allocator) fluxmemory.Allocator {
+	if alloc, ok := mem.(fluxmemory.Allocator); ok {
+		return alloc
+	}
+	return fluxmemory.NewResourceAllocator(mem)
+}
+
+// embeddedCardinalityReader counts the series with points
+// within the bounds that pass the predicates.
+type embeddedCardinalityReader struct {
+	embeddedReader
+}
+
+func (r *embeddedCardinalityReader) Read(ctx context.Context, f func(flux.Table) error, mem memory.Allocator) error {
+	_, _, points := r.snapshot()
+	fns := make([]*execute.RowPredicateFn, len(r.predicateSet))
+	for i, pred := range r.predicateSet {
+		fns[i] = execute.NewRowPredicateFn(pred.Fn, compiler.ToScope(pred.Scope))
+	}
+
+	var n int64
+	for _, pts := range points {
+		// The predicates are evaluated with the series key.
+		record := map[string]values.Value{
+			"_field":       values.NewString(pts.field),
+			"_measurement": values.NewString(pts.measurement),
+		}
+		for _, t := range pts.tags {
+			record[t.key] = values.NewString(t.value)
+		}
+		cols := make([]flux.ColMeta, 0, len(record))
+		for label := range record {
+			cols = append(cols, flux.ColMeta{Label: label, Type: flux.TString})
+		}
+		sort.Slice(cols, func(i, j int) bool {
+			return cols[i].Label < cols[j].Label
+		})
+
+		pass := true
+		for _, fn := range fns {
+			prepared, err := fn.Prepare(cols)
+			if err != nil {
+				return err
+			}
+			obj := values.NewObject(prepared.InputType())
+			for label, v := range record {
+				obj.Set(label, v)
+			}
+			if ok, err := prepared.Eval(ctx, obj); err != nil {
+				return errors.Wrap(err, codes.Inherit, "failed to evaluate predicate function")
+			} else if !ok {
+				pass = false
+				break
+			}
+		}
+		if pass {
+			n++
+		}
+	}
+
+	b := execute.NewColListTableBuilder(execute.NewGroupKey(nil, nil), resourceAllocator(mem))
+	if _, err := b.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: flux.TInt}); err != nil {
+		return err
+	}
+	if err := b.AppendInt(0, n); err != nil {
+		return err
+	}
+	tbl, err := b.Table()
+	if err != nil {
+		return err
+	}
+	return f(tbl)
+}
+
+// embeddedBucketsReader lists the buckets for an organization.
+type embeddedBucketsReader struct {
+	p   *EmbeddedProvider
+	org string
+}
+
+func (r *embeddedBucketsReader) Read(ctx context.Context, f func(flux.Table) error, mem memory.Allocator) error {
+	r.p.mu.RLock()
+	buckets := make([]*embeddedBucket, 0, len(r.p.buckets))
+	for _, b := range r.p.buckets {
+		buckets = append(buckets, b)
+	}
+	r.p.mu.RUnlock()
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].name < buckets[j].name
+	})
+
+	key := execute.NewGroupKey(
+		[]flux.ColMeta{{Label: "organizationID", Type: flux.TString}},
+		[]values.Value{values.NewString(r.org)},
+	)
+	b := execute.NewColListTableBuilder(key, resourceAllocator(mem))
+	for _, c := range []flux.ColMeta{
+		{Label: "organizationID", Type: flux.TString},
+		{Label: "name", Type: flux.TString},
+		{Label: "id", Type: flux.TString},
+		{Label: "retentionPolicy", Type: flux.TString},
+		{Label: "retentionPeriod", Type: flux.TInt},
+	} {
+		if _, err := b.AddCol(c); err != nil {
+			return err
+		}
+	}
+	for _, bucket := range buckets {
+		if err := b.AppendString(0, r.org); err != nil {
+			return err
+		}
+		if err := b.AppendString(1, bucket.name); err != nil {
+			return err
+		}
+		if err := b.AppendString(2, bucket.id); err != nil {
+			return err
+		}
+		if err := b.AppendNil(3); err != nil {
+			return err
+		}
+		if err := b.AppendInt(4, 0); err != nil {
+			return err
+		}
+	}
+	tbl, err := b.Table()
+	if err != nil {
+		return err
+	}
+	return f(tbl)
+}
+
+// embeddedWriter writes metrics to a bucket.
+type embeddedWriter struct {
+	p      *EmbeddedProvider
+	bucket *embeddedBucket
+}
+
+func (w *embeddedWriter) Write(metrics ...Metric) error {
+	w.p.mu.Lock()
+	defer w.p.mu.Unlock()
+	defer w.bucket.normalize()
+
+	for _, m := range metrics {
+		tags := make([]embeddedTag, 0, len(m.TagList()))
+		for _, t := range m.TagList() {
+			tags = append(tags, embeddedTag{key: t.Key, value: t.Value})
+		}
+		ts := values.ConvertTime(m.Time())
+		for _, field := range m.FieldList() {
+			if err := w.bucket.write(m.Name(), tags, field.Key, ts, values.New(field.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *embeddedWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,333 @@
+package influxdb_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/influxdb"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/flux/values/valuestest"
+	lp "github.com/influxdata/line-protocol"
+)
+
+const embeddedData = `
+cpu,host=a,cpu=cpu0 usage=1.5 1000000000
+cpu,host=b,cpu=cpu0 usage=2.5 1000000000
+cpu,cpu=cpu0,host=a usage=3.5 2000000000
+cpu,host=a,cpu=cpu0 usage=4.5 1000000000
+mem,host=a used=10i,total=20i 3000000000
+`
+
+func newEmbeddedProvider(t *testing.T) *influxdb.EmbeddedProvider {
+	t.Helper()
+	p := influxdb.NewEmbeddedProvider()
+	if err := p.Load("telegraf", strings.NewReader(embeddedData)); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func readEmbedded(t *testing.T, r influxdb.Reader) []*executetest.Table {
+	t.Helper()
+	var got []*executetest.Table
+	if err := r.Read(context.Background(), func(tbl flux.Table) error {
+		et, err := executetest.ConvertTable(tbl)
+		if err != nil {
+			return err
+		}
+		got = append(got, et)
+		return nil
+	}, memory.DefaultAllocator); err != nil {
+		t.Fatal(err)
+	}
+	executetest.NormalizeTables(got)
+	return got
+}
+
+var embeddedBounds = flux.Bounds{
+	Start: flux.Time{Absolute: time.Unix(0, 0).UTC()},
+	Stop:  flux.Time{Absolute: time.Unix(10, 0).UTC()},
+	Now:   time.Unix(10, 0).UTC(),
+}
+
+func seriesTable(field, host string, typ flux.ColType, data ...[]interface{}) *executetest.Table {
+	tbl := &executetest.Table{
+		KeyCols: []string{"_start", "_stop", "_field", "_measurement"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_start", Type: flux.TTime},
+			{Label: "_stop", Type: flux.TTime},
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: typ},
+			{Label: "_field", Type: flux.TString},
+			{Label: "_measurement", Type: flux.TString},
+		},
+	}
+	measurement, tags := "mem", []string{host}
+	if field == "usage" {
+		measurement, tags = "cpu", []string{"cpu0", host}
+		tbl.KeyCols = append(tbl.KeyCols, "cpu")
+		tbl.ColMeta = append(tbl.ColMeta, flux.ColMeta{Label: "cpu", Type: flux.TString})
+	}
+	tbl.KeyCols = append(tbl.KeyCols, "host")
+	tbl.ColMeta = append(tbl.ColMeta, flux.ColMeta{Label: "host", Type: flux.TString})
+
+	tbl.KeyValues = []interface{}{values.Time(0), values.Time(10e9), field, measurement}
+	for _, tag := range tags {
+		tbl.KeyValues = append(tbl.KeyValues, tag)
+	}
+	for _, row := range data {
+		r := []interface{}{tbl.KeyValues[0], tbl.KeyValues[1], row[0], row[1]}
+		tbl.Data = append(tbl.Data, append(r, tbl.KeyValues[2:]...))
+	}
+	return tbl
+}
+
+func TestEmbeddedProvider_ReaderFor(t *testing.T) {
+	p := newEmbeddedProvider(t)
+
+	for _, tt := range []struct {
+		name         string
+		bounds       flux.Bounds
+		predicateSet influxdb.PredicateSet
+		want         []*executetest.Table
+	}{
+		{
+			name:   "all",
+			bounds: embeddedBounds,
+			want: []*executetest.Table{
+				seriesTable("usage", "a", flux.TFloat,
+					[]interface{}{values.Time(1e9), 4.5},
+					[]interface{}{values.Time(2e9), 3.5},
+				),
+				seriesTable("usage", "b", flux.TFloat,
+					[]interface{}{values.Time(1e9), 2.5},
+				),
+				seriesTable("total", "a", flux.TInt,
+					[]interface{}{values.Time(3e9), int64(20)},
+				),
+				seriesTable("used", "a", flux.TInt,
+					[]interface{}{values.Time(3e9), int64(10)},
+				),
+			},
+		},
+		{
+			name: "bounds",
+			bounds: flux.Bounds{
+				Start: flux.Time{IsRelative: true, Relative: -9 * time.Second},
+				Stop:  flux.Time{IsRelative: true, Relative: -7 * time.Second},
+				Now:   time.Unix(10, 0).UTC(),
+			},
+			want: func() []*executetest.Table {
+				tables := []*executetest.Table{
+					seriesTable("usage", "a", flux.TFloat,
+						[]interface{}{values.Time(1e9), 4.5},
+						[]interface{}{values.Time(2e9), 3.5},
+					),
+					seriesTable("usage", "b", flux.TFloat,
+						[]interface{}{values.Time(1e9), 2.5},
+					),
+				}
+				for _, tbl := range tables {
+					tbl.KeyValues[0], tbl.KeyValues[1] = values.Time(1e9), values.Time(3e9)
+					for _, row := range tbl.Data {
+						row[0], row[1] = values.Time(1e9), values.Time(3e9)
+					}
+				}
+				return tables
+			}(),
+		},
+		{
+			name:   "predicates",
+			bounds: embeddedBounds,
+			predicateSet: influxdb.PredicateSet{
+				{ResolvedFunction: interpreter.ResolvedFunction{
+					Fn:    executetest.FunctionExpression(t, `(r) => r._measurement == "cpu"`),
+					Scope: valuestest.Scope(),
+				}},
+				{ResolvedFunction: interpreter.ResolvedFunction{
+					Fn:    executetest.FunctionExpression(t, `(r) => r._value > 3.0`),
+					Scope: valuestest.Scope(),
+				}},
+			},
+			want: []*executetest.Table{
+				seriesTable("usage", "a", flux.TFloat,
+					[]interface{}{values.Time(1e9), 4.5},
+					[]interface{}{values.Time(2e9), 3.5},
+				),
+			},
+		},
+		{
+			name:   "keep empty",
+			bounds: embeddedBounds,
+			predicateSet: influxdb.PredicateSet{
+				{
+					ResolvedFunction: interpreter.ResolvedFunction{
+						Fn:    executetest.FunctionExpression(t, `(r) => r.host == "b"`),
+						Scope: valuestest.Scope(),
+					},
+					KeepEmpty: true,
+				},
+			},
+			want: []*executetest.Table{
+				seriesTable("usage", "a", flux.TFloat),
+				seriesTable("usage", "b", flux.TFloat,
+					[]interface{}{values.Time(1e9), 2.5},
+				),
+				seriesTable("total", "a", flux.TInt),
+				seriesTable("used", "a", flux.TInt),
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := p.ReaderFor(context.Background(), influxdb.Config{
+				Bucket: influxdb.NameOrID{Name: "telegraf"},
+			}, tt.bounds, tt.predicateSet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.want
+			executetest.NormalizeTables(want)
+			if got := readEmbedded(t, r); !cmp.Equal(want, got) {
+				t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestEmbeddedProvider_SeriesCardinalityReaderFor(t *testing.T) {
+	p := newEmbeddedProvider(t)
+	r, err := p.SeriesCardinalityReaderFor(context.Background(), influxdb.Config{
+		Bucket: influxdb.NameOrID{Name: "telegraf"},
+	}, embeddedBounds, influxdb.PredicateSet{
+		{ResolvedFunction: interpreter.ResolvedFunction{
+			Fn:    executetest.FunctionExpression(t, `(r) => r.host == "a"`),
+			Scope: valuestest.Scope(),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*executetest.Table{{
+		ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TInt}},
+		Data:    [][]interface{}{{int64(3)}},
+	}}
+	executetest.NormalizeTables(want)
+	if got := readEmbedded(t, r); !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestEmbeddedProvider_BucketsReaderFor(t *testing.T) {
+	p := newEmbeddedProvider(t)
+	p.CreateBucket("_monitoring")
+	r, err := p.BucketsReaderFor(context.Background(), influxdb.Config{
+		Org: influxdb.NameOrID{Name: "influxdata"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := readEmbedded(t, r)
+	if len(got) != 1 || len(got[0].Data) != 2 {
+		t.Fatalf("expected one table with two buckets, got %v", got)
+	}
+	nameIdx := execute.ColIdx("name", got[0].ColMeta)
+	for i, name := range []string{"_monitoring", "telegraf"} {
+		if got := got[0].Data[i][nameIdx]; got != name {
+			t.Errorf("unexpected bucket name at row %d: want %q, got %q", i, name, got)
+		}
+	}
+
+	// The bucket can be read with its id.
+	id := got[0].Data[1][execute.ColIdx("id", got[0].ColMeta)].(string)
+	if _, err := p.ReaderFor(context.Background(), influxdb.Config{
+		Bucket: influxdb.NameOrID{ID: id},
+	}, embeddedBounds, nil); err != nil {
+		t.Errorf("unexpected error reading bucket by id: %s", err)
+	}
+}
+
+func TestEmbeddedProvider_WriterFor(t *testing.T) {
+	p := newEmbeddedProvider(t)
+	w, err := p.WriterFor(context.Background(), influxdb.Config{
+		Bucket: influxdb.NameOrID{Name: "telegraf"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := lp.New("cpu", map[string]string{"host": "b", "cpu": "cpu0"}, map[string]interface{}{"usage": 5.5}, time.Unix(0, 500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(m); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := p.ReaderFor(context.Background(), influxdb.Config{
+		Bucket: influxdb.NameOrID{Name: "telegraf"},
+	}, embeddedBounds, influxdb.PredicateSet{
+		{ResolvedFunction: interpreter.ResolvedFunction{
+			Fn:    executetest.FunctionExpression(t, `(r) => r.host == "b"`),
+			Scope: valuestest.Scope(),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*executetest.Table{
+		seriesTable("usage", "b", flux.TFloat,
+			[]interface{}{values.Time(500), 5.5},
+			[]interface{}{values.Time(1e9), 2.5},
+		),
+	}
+	executetest.NormalizeTables(want)
+	if got := readEmbedded(t, r); !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestEmbeddedProvider_Load(t *testing.T) {
+	const export = `# INFLUXDB EXPORT: 1677-09-21T00:12:43Z - 2262-04-11T23:47:16Z
+# DDL
+CREATE DATABASE telegraf WITH NAME autogen
+# DML
+# CONTEXT-DATABASE:telegraf
+# CONTEXT-RETENTION-POLICY:autogen
+# writing tsm data
+cpu,host=a usage=1.5 1000000000
+`
+	p := influxdb.NewEmbeddedProvider()
+	if err := p.Load("default", strings.NewReader(export)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.ReaderFor(context.Background(), influxdb.Config{
+		Bucket: influxdb.NameOrID{Name: "telegraf/autogen"},
+	}, embeddedBounds, nil); err != nil {
+		t.Errorf("unexpected error reading exported bucket: %s", err)
+	}
+
+	if _, err := p.ReaderFor(context.Background(), influxdb.Config{
+		Bucket: influxdb.NameOrID{Name: "missing"},
+	}, embeddedBounds, nil); errors.Code(err) != codes.NotFound {
+		t.Errorf("expected not found error for missing bucket, got %v", err)
+	}
+
+	if err := p.Load("telegraf/autogen", strings.NewReader("cpu,host=a usage=1i 1\n")); err == nil {
+		t.Fatal("expected error for a field type conflict")
+	}
+}
@@ -70,6 +70,14 @@ func (h HttpProvider) SeriesCardinalityReaderFor(ctx context.Context, conf Confi
 	}, nil
 }
 
+func (h HttpProvider) BucketsReaderFor(ctx context.Context, conf Config) (Reader, error) {
+	c, err := h.clientFor(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+	return bucketsHttpReader{HttpClient: c}, nil
+}
+
 func (h HttpProvider) WriterFor(ctx context.Context, conf Config) (Writer, error) {
 	httpClient, err := h.clientFor(ctx, conf)
 	if err != nil {
@@ -466,6 +474,22 @@ func (h seriesCardinalityHttpReader) Read(ctx context.Context, f func(flux.Table
 	return h.Query(ctx, f, &file, h.Bounds.Now, mem)
 }
 
+type bucketsHttpReader struct {
+	*HttpClient
+}
+
+func (h bucketsHttpReader) Read(ctx context.Context, f func(flux.Table) error, mem memory.Allocator) error {
+	file := h.newFile(nil)
+	file.Body = []ast.Statement{
+		&ast.ExpressionStatement{
+			Expression: &ast.CallExpression{
+				Callee: &ast.Identifier{Name: "buckets"},
+			},
+		},
+	}
+	return h.Query(ctx, f, &file, time.Now(), mem)
+}
+
 type httpWriter struct {
 	writer      *api.WriteAPIImpl
 	errChan     <-chan error
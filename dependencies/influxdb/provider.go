@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
 	protocol "github.com/influxdata/line-protocol"
 )
 
@@ -94,12 +95,23 @@ type Provider interface {
 	// for the SeriesCardinality operation.
 	SeriesCardinalityReaderFor(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet) (Reader, error)
 
+	// BucketsReaderFor will return a Reader that lists
+	// the buckets for the organization in the configuration.
+	BucketsReaderFor(ctx context.Context, conf Config) (Reader, error)
+
 	// WriterFor will construct a Writer using the given configuration parameters.
 	// If the parameters are their zero values, appropriate defaults may be used
 	// or an error may be returned if the implementation does not have a default.
 	WriterFor(ctx context.Context, conf Config) (Writer, error)
 }
 
+// StatisticsProvider is an optional interface that a Provider can implement
+// to estimate the number of rows and series that a read will produce.
+// The planner uses the estimate to make cost-based decisions.
+type StatisticsProvider interface {
+	ReadStatistics(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet) (plan.Statistics, error)
+}
+
 // Reader reads tables from an influxdb instance.
 type Reader interface {
 	// Read will produce flux.Table values using the memory.Allocator
@@ -143,6 +155,10 @@ func (u UnimplementedProvider) SeriesCardinalityReaderFor(ctx context.Context, c
 	return nil, errors.New(codes.Unimplemented, "influxdb series cardinality reader has not been implemented")
 }
 
+func (u UnimplementedProvider) BucketsReaderFor(ctx context.Context, conf Config) (Reader, error) {
+	return nil, errors.New(codes.Unimplemented, "influxdb buckets reader has not been implemented")
+}
+
 func (u UnimplementedProvider) WriterFor(ctx context.Context, conf Config) (Writer, error) {
 	return nil, errors.New(codes.Unimplemented, "influxdb writer has not been implemented")
 }
@@ -160,6 +176,10 @@ func (u ErrorProvider) SeriesCardinalityReaderFor(ctx context.Context, conf Conf
 	return nil, errors.New(codes.Invalid, "Provider.SeriesCardinalityReaderFor called on an error dependency")
 }
 
+func (u ErrorProvider) BucketsReaderFor(ctx context.Context, conf Config) (Reader, error) {
+	return nil, errors.New(codes.Invalid, "Provider.BucketsReaderFor called on an error dependency")
+}
+
 func (u ErrorProvider) WriterFor(ctx context.Context, conf Config) (Writer, error) {
 	return nil, errors.New(codes.Invalid, "Provider.WriterFor called on an error dependency")
 }
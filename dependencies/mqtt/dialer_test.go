@@ -27,6 +27,14 @@ func (m *MockClient) Publish(ctx context.Context, topic string, qos byte, retain
 	return m.PublishFn(ctx, topic, qos, retain, payload)
 }
 
+func (m *MockClient) Subscribe(ctx context.Context, topic string, qos byte, handler func(mqtt.Message)) error {
+	return nil
+}
+
+func (m *MockClient) Unsubscribe(ctx context.Context, topic string) error {
+	return nil
+}
+
 func (m *MockClient) Close() error {
 	return m.CloseFn()
 }
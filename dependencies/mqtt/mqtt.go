@@ -66,11 +66,24 @@ type Dialer interface {
 	Dial(ctx context.Context, brokers []string, options Options) (Client, error)
 }
 
-// Client is an mqtt client that can publish to an mqtt broker.
+// Message is a message that was received from an mqtt broker.
+type Message struct {
+	Topic   string
+	Payload []byte
+}
+
+// Client is an mqtt client that can publish to and subscribe to an mqtt broker.
 type Client interface {
 	// Publish will publish the payload to a particular topic.
 	Publish(ctx context.Context, topic string, qos byte, retain bool, payload interface{}) error
 
+	// Subscribe will call the handler with each message that is published
+	// to the topic until the client unsubscribes from the topic.
+	Subscribe(ctx context.Context, topic string, qos byte, handler func(Message)) error
+
+	// Unsubscribe will stop receiving the messages of the topic.
+	Unsubscribe(ctx context.Context, topic string) error
+
 	io.Closer
 }
 
@@ -141,6 +154,24 @@ func (d *defaultClient) Publish(ctx context.Context, topic string, qos byte, ret
 	return nil
 }
 
+func (d *defaultClient) Subscribe(ctx context.Context, topic string, qos byte, handler func(Message)) error {
+	token := d.client.Subscribe(topic, qos, func(_ mqtt.Client, m mqtt.Message) {
+		handler(Message{Topic: m.Topic(), Payload: m.Payload()})
+	})
+	return d.wait(token, "subscribe")
+}
+
+func (d *defaultClient) Unsubscribe(ctx context.Context, topic string) error {
+	return d.wait(d.client.Unsubscribe(topic), "unsubscribe")
+}
+
+func (d *defaultClient) wait(token mqtt.Token, op string) error {
+	if !token.WaitTimeout(d.timeout) {
+		return errors.Newf(codes.Canceled, "mqtt %s: timeout reached", op)
+	}
+	return token.Error()
+}
+
 func (d *defaultClient) Close() error {
 	d.client.Disconnect(250)
 	return nil
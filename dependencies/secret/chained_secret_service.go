@@ -0,0 +1,28 @@
+package secret
+
+import (
+	"context"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// ChainedSecretService looks up a secret in each service in order
+// and returns the first secret that is found.
+//
+// A service that does not have the secret must return an error with
+// the NotFound code and the next service is tried. Any other error
+// stops the lookup and is returned.
+type ChainedSecretService []Service
+
+func (s ChainedSecretService) LoadSecret(ctx context.Context, k string) (string, error) {
+	for _, service := range s {
+		v, err := service.LoadSecret(ctx, k)
+		if err == nil {
+			return v, nil
+		} else if errors.Code(err) != codes.NotFound {
+			return "", err
+		}
+	}
+	return "", errors.Newf(codes.NotFound, "secret key %q not found", k)
+}
@@ -0,0 +1,129 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// encryptedHeader starts the contents of an encrypted secrets file.
+var encryptedHeader = []byte("FLUXSECRETS1")
+
+// FileSecretService is a secret service with the secrets from a file.
+//
+// The file contains an object that maps each key to its secret.
+// Files with the .json extension are decoded as JSON and all other
+// files are decoded as YAML. The file may be encrypted with Encrypt
+// in which case the .enc extension is ignored to choose the format.
+type FileSecretService struct {
+	secrets map[string]string
+}
+
+// NewFileSecretService reads the secrets from the named file.
+// The key is used to decrypt the file and must be nil if the
+// file is not encrypted.
+func NewFileSecretService(path string, key []byte) (*FileSecretService, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to read secrets file")
+	}
+
+	if bytes.HasPrefix(data, encryptedHeader) {
+		if key == nil {
+			return nil, errors.New(codes.Invalid, "secrets file is encrypted and no key was provided")
+		}
+		if data, err = Decrypt(key, data); err != nil {
+			return nil, err
+		}
+	} else if key != nil {
+		return nil, errors.New(codes.Invalid, "secrets file is not encrypted")
+	}
+
+	format := filepath.Ext(strings.TrimSuffix(path, ".enc"))
+	secrets, err := decodeSecrets(data, format)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to decode secrets file")
+	}
+	return &FileSecretService{secrets: secrets}, nil
+}
+
+func decodeSecrets(data []byte, format string) (map[string]string, error) {
+	secrets := make(map[string]string)
+	if format == ".json" {
+		if err := json.Unmarshal(data, &secrets); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := yaml.Unmarshal(data, &secrets); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+func (s *FileSecretService) LoadSecret(ctx context.Context, k string) (string, error) {
+	v, ok := s.secrets[k]
+	if !ok {
+		return "", errors.Newf(codes.NotFound, "secret key %q not found", k)
+	}
+	return v, nil
+}
+
+// Encrypt encrypts the contents of a secrets file with AES-GCM.
+// The key must be 16, 24 or 32 bytes long to select AES-128,
+// AES-192 or AES-256.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "failed to generate nonce")
+	}
+
+	out := make([]byte, 0, len(encryptedHeader)+len(nonce)+len(plaintext)+gcm.Overhead())
+	out = append(out, encryptedHeader...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plaintext, encryptedHeader), nil
+}
+
+// Decrypt decrypts the contents of a secrets file that was encrypted with Encrypt.
+func Decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, encryptedHeader) {
+		return nil, errors.New(codes.Invalid, "secrets are not encrypted")
+	}
+	data = data[len(encryptedHeader):]
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New(codes.Invalid, "encrypted secrets are truncated")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, encryptedHeader)
+	if err != nil {
+		return nil, errors.New(codes.Invalid, "failed to decrypt secrets, the key is incorrect or the file is corrupted")
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid secrets key")
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,68 @@
+package secret_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/secret"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+func TestFileSecretService(t *testing.T) {
+	dir := t.TempDir()
+	key := []byte("0123456789abcdef0123456789abcdef")
+	encrypted, err := secret.Encrypt(key, []byte(`{"token": "abc"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+		key  []byte
+	}{
+		{name: "secrets.json", data: []byte(`{"token": "abc"}`)},
+		{name: "secrets.yml", data: []byte("token: abc\n")},
+		{name: "secrets.json.enc", data: encrypted, key: key},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.name)
+			if err := ioutil.WriteFile(path, tc.data, 0600); err != nil {
+				t.Fatal(err)
+			}
+			ss, err := secret.NewFileSecretService(path, tc.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v, err := ss.LoadSecret(context.Background(), "token"); err != nil {
+				t.Fatal(err)
+			} else if v != "abc" {
+				t.Errorf("unexpected secret: %q", v)
+			}
+			if _, err := ss.LoadSecret(context.Background(), "missing"); errors.Code(err) != codes.NotFound {
+				t.Errorf("expected a not found error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFileSecretService_WrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.yml.enc")
+	encrypted, err := secret.Encrypt([]byte("0123456789abcdef"), []byte("token: abc\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, encrypted, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := secret.NewFileSecretService(path, []byte("fedcba9876543210")); errors.Code(err) != codes.Invalid {
+		t.Errorf("expected an invalid error, got %v", err)
+	}
+	if _, err := secret.NewFileSecretService(path, nil); errors.Code(err) != codes.Invalid {
+		t.Errorf("expected an invalid error, got %v", err)
+	}
+}
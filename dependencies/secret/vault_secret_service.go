@@ -0,0 +1,138 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/influxdata/flux/codes"
+	fluxhttp "github.com/influxdata/flux/dependencies/http"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// VaultSecretService reads secrets from a secret in the
+// version 2 key/value secrets engine of HashiCorp Vault or
+// a server that implements the same API.
+//
+// Every key of the secret is a secret for Flux. The secret is
+// read for each lookup so changes are visible without a restart.
+type VaultSecretService struct {
+	// Client is the client used to make requests to the server.
+	Client fluxhttp.Client
+
+	// Address is the URL of the server such as https://vault:8200.
+	Address string
+
+	// Token authenticates the requests.
+	Token string
+
+	// Namespace is the namespace of the secret. It is only
+	// required for servers that support namespaces.
+	Namespace string
+
+	// Mount is the path where the secrets engine is mounted.
+	// It defaults to "secret".
+	Mount string
+
+	// Path is the path of the secret in the secrets engine.
+	Path string
+}
+
+// vaultResponse is the response for reading a secret.
+type vaultResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func (s *VaultSecretService) LoadSecret(ctx context.Context, k string) (string, error) {
+	secrets, err := s.read(ctx)
+	if err != nil {
+		return "", err
+	}
+	v, ok := secrets[k]
+	if !ok {
+		return "", errors.Newf(codes.NotFound, "secret key %q not found", k)
+	}
+	if str, ok := v.(string); ok {
+		return str, nil
+	}
+	// Secrets that are not strings are returned as JSON.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Wrapf(err, codes.Internal, "failed to encode secret key %q", k)
+	}
+	return string(data), nil
+}
+
+// read requests the latest version of the secret.
+func (s *VaultSecretService) read(ctx context.Context) (map[string]interface{}, error) {
+	mount := s.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+	u, err := url.Parse(s.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid vault address")
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/v1/" + strings.Trim(mount, "/") + "/data/" + strings.Trim(s.Path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to create vault request")
+	}
+	req.Header.Set("X-Vault-Token", s.Token)
+	if s.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.Namespace)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Unavailable, "failed to read secret from vault")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Unavailable, "failed to read secret from vault")
+	}
+
+	var vr vaultResponse
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &vr); err != nil && resp.StatusCode == http.StatusOK {
+			return nil, errors.Wrap(err, codes.Internal, "failed to decode vault response")
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg := resp.Status
+		if len(vr.Errors) > 0 {
+			msg = strings.Join(vr.Errors, ", ")
+		}
+		return nil, errors.Newf(vaultErrorCode(resp.StatusCode), "failed to read secret from vault: %s", msg)
+	}
+	return vr.Data.Data, nil
+}
+
+func vaultErrorCode(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.Invalid
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		// Vault responds with this status when it is sealed.
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
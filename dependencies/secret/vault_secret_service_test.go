@@ -0,0 +1,94 @@
+package secret_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/secret"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/mock"
+)
+
+func TestVaultSecretService(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/flux/prod" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"password": "p@ss", "port": 5432}, "metadata": {"version": 3}}}`))
+	}))
+	defer server.Close()
+
+	ss := &secret.VaultSecretService{
+		Client:  server.Client(),
+		Address: server.URL,
+		Token:   "s.token",
+		Mount:   "kv",
+		Path:    "flux/prod",
+	}
+	ctx := context.Background()
+	if v, err := ss.LoadSecret(ctx, "password"); err != nil {
+		t.Fatal(err)
+	} else if v != "p@ss" {
+		t.Errorf("unexpected secret: %q", v)
+	}
+	if v, err := ss.LoadSecret(ctx, "port"); err != nil {
+		t.Fatal(err)
+	} else if v != "5432" {
+		t.Errorf("unexpected secret: %q", v)
+	}
+	if _, err := ss.LoadSecret(ctx, "user"); errors.Code(err) != codes.NotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	ss.Path = "flux/dev"
+	if _, err := ss.LoadSecret(ctx, "password"); errors.Code(err) != codes.NotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	ss.Token = "s.other"
+	if _, err := ss.LoadSecret(ctx, "password"); errors.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected a permission denied error, got %v", err)
+	}
+}
+
+func TestChainedSecretService(t *testing.T) {
+	ss := secret.ChainedSecretService{
+		mock.SecretService{"a": "first"},
+		mock.SecretService{"a": "second", "b": "second"},
+	}
+	ctx := context.Background()
+	for k, want := range map[string]string{"a": "first", "b": "second"} {
+		if v, err := ss.LoadSecret(ctx, k); err != nil {
+			t.Fatal(err)
+		} else if v != want {
+			t.Errorf("unexpected secret for %q: %q", k, v)
+		}
+	}
+	if _, err := ss.LoadSecret(ctx, "c"); errors.Code(err) != codes.NotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	ss = secret.ChainedSecretService{
+		failingSecretService{},
+		mock.SecretService{"a": "second"},
+	}
+	if _, err := ss.LoadSecret(ctx, "a"); errors.Code(err) != codes.Unavailable {
+		t.Errorf("expected the error from the first service, got %v", err)
+	}
+}
+
+type failingSecretService struct{}
+
+func (failingSecretService) LoadSecret(ctx context.Context, k string) (string, error) {
+	return "", errors.New(codes.Unavailable, "unavailable")
+}
@@ -0,0 +1,183 @@
+package spill
+
+import (
+	"context"
+	"io"
+
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/execute/table"
+	itable "github.com/influxdata/flux/internal/execute/table"
+)
+
+// Builder is a table builder that buffers tables like table.BufferedBuilder
+// and can move the buffered data to a spill file when memory is under pressure.
+//
+// Every spill appends to the same spill file so a Builder holds at most
+// one file and never keeps it open between spills. The table produced by
+// a Builder that has spilled reads the spill file back one buffer at a time.
+type Builder struct {
+	builder *table.BufferedBuilder
+	spiller *Spiller
+	mem     memory.Allocator
+	file    *File
+}
+
+// NewBuilder constructs a new Builder. If the Spiller is nil,
+// the Builder never spills.
+func NewBuilder(key flux.GroupKey, spiller *Spiller, mem memory.Allocator) *Builder {
+	return &Builder{
+		builder: table.NewBufferedBuilder(key, mem),
+		spiller: spiller,
+		mem:     mem,
+	}
+}
+
+// GetBuilder is a convenience method for retrieving a
+// Builder from the BuilderCache.
+func GetBuilder(key flux.GroupKey, cache *table.BuilderCache) (builder *Builder, created bool) {
+	created = cache.Get(key, &builder)
+	return builder, created
+}
+
+// AppendTable appends all of the buffers inside of the table to the builder.
+func (b *Builder) AppendTable(tbl flux.Table) error {
+	return b.builder.AppendTable(tbl)
+}
+
+// AppendBuffer appends a buffer to the builder.
+func (b *Builder) AppendBuffer(cr flux.ColReader) error {
+	return b.builder.AppendBuffer(cr)
+}
+
+// Spill moves the data that is buffered in memory to the spill file.
+func (b *Builder) Spill() error {
+	if b.spiller == nil || len(b.builder.Buffers) == 0 {
+		return nil
+	}
+
+	cols := make([]flux.ColMeta, len(b.builder.Columns))
+	copy(cols, b.builder.Columns)
+	if b.file == nil {
+		f, err := b.spiller.Create(cols, b.mem)
+		if err != nil {
+			return err
+		}
+		b.file = f
+	} else if !equalCols(b.file.Cols(), cols) {
+		if err := b.file.SetCols(cols); err != nil {
+			return err
+		}
+	}
+
+	for _, buf := range b.builder.Buffers {
+		if err := b.file.Write(buf); err != nil {
+			return err
+		}
+	}
+	b.builder.Release()
+	b.builder.Buffers = nil
+	return b.file.Flush()
+}
+
+func (b *Builder) Table() (flux.Table, error) {
+	if b.file == nil {
+		return b.builder.Table()
+	}
+
+	key, cols := b.builder.GroupKey, b.builder.Columns
+	file, buffers := b.file, b.builder.Buffers
+	b.file, b.builder.Buffers = nil, nil
+	return itable.Stream(key, cols, func(ctx context.Context, w *itable.StreamWriter) error {
+		defer func() {
+			_ = file.Remove()
+			for _, buf := range buffers {
+				buf.Release()
+			}
+		}()
+
+		if err := b.readFile(file, w); err != nil {
+			return err
+		}
+
+		// The buffers that are still in memory already have all of the
+		// columns because the buffered builder backfills them.
+		for len(buffers) > 0 {
+			buf := buffers[0]
+			buffers = buffers[1:]
+			if err := w.UnsafeWriteBuffer(buf); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// readFile writes the buffers in the spill file to the stream.
+// Columns that were added after the file was written are filled with nulls.
+func (b *Builder) readFile(f *File, w *itable.StreamWriter) error {
+	r, err := f.Open(w.Key())
+	if err != nil {
+		return err
+	}
+	defer r.Release()
+
+	for {
+		buf, err := r.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		vs := normalize(buf, w.Cols(), b.mem)
+		buf.Release()
+		if err := w.UnsafeWrite(vs); err != nil {
+			return err
+		}
+	}
+}
+
+func (b *Builder) Release() {
+	b.builder.Release()
+	if b.file != nil {
+		_ = b.file.Remove()
+		b.file = nil
+	}
+}
+
+func equalCols(a, b []flux.ColMeta) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize returns the values of the buffer in the order of the given
+// columns. Columns that are missing from the buffer are filled with nulls.
+func normalize(buf *arrow.TableBuffer, cols []flux.ColMeta, mem memory.Allocator) []array.Array {
+	vs := make([]array.Array, len(cols))
+	for j, col := range cols {
+		idx := -1
+		for i, c := range buf.Columns {
+			if c.Label == col.Label {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			vs[j] = arrow.Nulls(col.Type, buf.Len(), mem)
+			continue
+		}
+		vs[j] = buf.Values[idx]
+		vs[j].Retain()
+	}
+	return vs
+}
@@ -0,0 +1,272 @@
+package spill
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+
+	stdarrow "github.com/apache/arrow/go/v7/arrow"
+	"github.com/apache/arrow/go/v7/arrow/ipc"
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// File is a temporary file that holds spilled buffers.
+//
+// Buffers are appended with Write and read back in the same order
+// with Open. The file must be removed with Remove when it is no
+// longer needed.
+//
+// The file is only kept open while it is being written or read.
+// Each batch of writes that ends with Flush is stored as a separate
+// segment of the file so that a file can be appended to many times
+// without holding on to a file descriptor in between.
+type File struct {
+	path   string
+	segs   []segment
+	schema *stdarrow.Schema
+	cols   []flux.ColMeta
+	mem    memory.Allocator
+	n      int
+
+	// The state of the segment that is currently being written.
+	f     *os.File
+	bw    *bufio.Writer
+	w     *ipc.Writer
+	start int64
+}
+
+// segment is a contiguous arrow stream within a spill file.
+type segment struct {
+	off, size int64
+	schema    *stdarrow.Schema
+	cols      []flux.ColMeta
+}
+
+// Create creates a new temporary file for buffers with the given columns.
+// Buffers are read back from the file using the given allocator.
+func (s *Spiller) Create(cols []flux.ColMeta, mem memory.Allocator) (*File, error) {
+	return NewFile(s.dir, cols, mem)
+}
+
+// NewFile creates a new temporary file in the directory for buffers
+// with the given columns. If dir is empty, the default directory for
+// temporary files is used.
+func NewFile(dir string, cols []flux.ColMeta, mem memory.Allocator) (*File, error) {
+	schema, err := arrow.Schema(cols, nil)
+	if err != nil {
+		return nil, err
+	}
+	f, err := ioutil.TempFile(dir, "flux-spill-")
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "failed to create spill file")
+	}
+	path := f.Name()
+	if err := f.Close(); err != nil {
+		_ = os.Remove(path)
+		return nil, errors.Wrap(err, codes.Internal, "failed to create spill file")
+	}
+	return &File{
+		path:   path,
+		schema: schema,
+		cols:   cols,
+		mem:    mem,
+	}, nil
+}
+
+// Cols returns the columns of the buffers that are written to the file.
+func (f *File) Cols() []flux.ColMeta {
+	return f.cols
+}
+
+// SetCols changes the columns of the buffers that are written
+// to the file from now on. Buffers that were already written
+// keep their columns.
+func (f *File) SetCols(cols []flux.ColMeta) error {
+	schema, err := arrow.Schema(cols, nil)
+	if err != nil {
+		return err
+	}
+	if err := f.Flush(); err != nil {
+		return err
+	}
+	f.schema, f.cols = schema, cols
+	return nil
+}
+
+// Len returns the number of rows written to the file.
+func (f *File) Len() int {
+	return f.n
+}
+
+// Write appends the column reader to the file.
+// Columns in the file that are missing from the column reader
+// are written as null values.
+func (f *File) Write(cr flux.ColReader) error {
+	if f.w == nil {
+		if err := f.begin(); err != nil {
+			return err
+		}
+	}
+	rec, err := arrow.NewRecord(f.schema, cr, f.mem)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+
+	if err := f.w.Write(rec); err != nil {
+		return errors.Wrap(err, codes.Internal, "failed to write spill file")
+	}
+	f.n += cr.Len()
+	return nil
+}
+
+// begin opens the file for appending and starts a new segment.
+func (f *File) begin() error {
+	fh, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return errors.Wrap(err, codes.Internal, "failed to open spill file")
+	}
+	fi, err := fh.Stat()
+	if err != nil {
+		_ = fh.Close()
+		return errors.Wrap(err, codes.Internal, "failed to open spill file")
+	}
+	f.f, f.start = fh, fi.Size()
+	f.bw = bufio.NewWriter(fh)
+	f.w = ipc.NewWriter(f.bw, ipc.WithSchema(f.schema), ipc.WithAllocator(f.mem))
+	return nil
+}
+
+// Flush finishes the segment that is currently being written
+// and closes the file until the next call to Write.
+func (f *File) Flush() error {
+	if f.w == nil {
+		return nil
+	}
+	w, bw, fh := f.w, f.bw, f.f
+	f.w, f.bw, f.f = nil, nil, nil
+
+	err := w.Close()
+	if err == nil {
+		err = bw.Flush()
+	}
+	var fi os.FileInfo
+	if err == nil {
+		fi, err = fh.Stat()
+	}
+	if cerr := fh.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Wrap(err, codes.Internal, "failed to write spill file")
+	}
+	f.segs = append(f.segs, segment{
+		off:    f.start,
+		size:   fi.Size() - f.start,
+		schema: f.schema,
+		cols:   f.cols,
+	})
+	return nil
+}
+
+// Open finishes writing the file and returns a Reader
+// for the buffers that were written to it. The buffers
+// returned by the reader use the given group key and have
+// the columns that were set when they were written.
+//
+// Open may be called more than once to read the file again.
+// The Reader holds the file open until it is released.
+func (f *File) Open(key flux.GroupKey) (*Reader, error) {
+	if err := f.Flush(); err != nil {
+		return nil, err
+	}
+	fh, err := os.Open(f.path)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "failed to read spill file")
+	}
+	return &Reader{
+		key:  key,
+		f:    fh,
+		segs: f.segs,
+		mem:  f.mem,
+	}, nil
+}
+
+// Remove deletes the file.
+func (f *File) Remove() error {
+	if f.w != nil {
+		_ = f.w.Close()
+		_ = f.f.Close()
+		f.w, f.bw, f.f = nil, nil, nil
+	}
+	if err := os.Remove(f.path); err != nil {
+		return errors.Wrap(err, codes.Internal, "failed to remove spill file")
+	}
+	return nil
+}
+
+// Reader reads the buffers in a spill file.
+type Reader struct {
+	key  flux.GroupKey
+	f    *os.File
+	segs []segment
+	cols []flux.ColMeta
+	r    *ipc.Reader
+	mem  memory.Allocator
+}
+
+// Next returns the next buffer in the file.
+// It returns io.EOF when there are no more buffers.
+// The returned buffer must be released.
+func (r *Reader) Next() (*arrow.TableBuffer, error) {
+	for {
+		if r.r == nil {
+			if len(r.segs) == 0 {
+				return nil, io.EOF
+			}
+			seg := r.segs[0]
+			r.segs = r.segs[1:]
+			sr := io.NewSectionReader(r.f, seg.off, seg.size)
+			ir, err := ipc.NewReader(bufio.NewReader(sr), ipc.WithSchema(seg.schema), ipc.WithAllocator(r.mem))
+			if err != nil {
+				return nil, errors.Wrap(err, codes.Internal, "failed to read spill file")
+			}
+			r.r, r.cols = ir, seg.cols
+		}
+
+		if r.r.Next() {
+			break
+		}
+		err := r.r.Err()
+		r.r.Release()
+		r.r = nil
+		if err != nil && err != io.EOF {
+			return nil, errors.Wrap(err, codes.Internal, "failed to read spill file")
+		}
+	}
+
+	vs, err := arrow.RecordValues(r.r.Record(), r.cols, r.mem)
+	if err != nil {
+		return nil, err
+	}
+	return &arrow.TableBuffer{
+		GroupKey: r.key,
+		Columns:  r.cols,
+		Values:   vs,
+	}, nil
+}
+
+// Release releases the resources held by the reader
+// and closes the file.
+func (r *Reader) Release() {
+	if r.r != nil {
+		r.r.Release()
+		r.r = nil
+	}
+	_ = r.f.Close()
+}
@@ -0,0 +1,107 @@
+// Package spill provides temporary on-disk storage for blocking
+// transformations that buffer more data than the memory limit
+// of a query allows.
+package spill
+
+import (
+	"context"
+
+	arrowmem "github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux/memory"
+)
+
+type key int
+
+const spillKey key = iota
+
+// DefaultThreshold is the fraction of the memory limit that must be
+// allocated before buffered data is spilled when no threshold is configured.
+const DefaultThreshold = 0.8
+
+// Config configures spilling to disk.
+type Config struct {
+	// Dir is the directory where spill files are created.
+	// If empty, the default directory for temporary files is used.
+	Dir string
+
+	// Threshold is the fraction of the memory limit that must be
+	// allocated before a transformation spills its buffered data.
+	// If zero, DefaultThreshold is used.
+	Threshold float64
+}
+
+// Inject enables spilling to disk with the given configuration.
+func Inject(ctx context.Context, c Config) context.Context {
+	return context.WithValue(ctx, spillKey, c)
+}
+
+// Get returns the spill configuration from the context.
+// The second return value is false if spilling was not enabled.
+func Get(ctx context.Context) (Config, bool) {
+	c, ok := ctx.Value(spillKey).(Config)
+	return c, ok
+}
+
+// Dependency enables spilling to disk when it is injected.
+type Dependency struct {
+	Config Config
+}
+
+func (d Dependency) Inject(ctx context.Context) context.Context {
+	return Inject(ctx, d.Config)
+}
+
+// Spiller decides when buffered data should be moved to disk
+// and creates the files that the data is moved to.
+//
+// A nil Spiller is valid and never spills.
+type Spiller struct {
+	dir       string
+	threshold float64
+	mem       *memory.ResourceAllocator
+}
+
+// New returns a Spiller for the given allocator using the configuration
+// in the context. It returns nil if spilling was not enabled or the
+// allocator does not have a memory limit.
+func New(ctx context.Context, mem arrowmem.Allocator) *Spiller {
+	c, ok := Get(ctx)
+	if !ok {
+		return nil
+	}
+	ra, ok := mem.(*memory.ResourceAllocator)
+	if !ok || ra.Limit == nil {
+		return nil
+	}
+	threshold := c.Threshold
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	return &Spiller{
+		dir:       c.Dir,
+		threshold: threshold,
+		mem:       ra,
+	}
+}
+
+// Always returns a Spiller that creates files in the directory
+// and reports that buffered data should always be spilled.
+// If dir is empty, the default directory for temporary files is used.
+func Always(dir string) *Spiller {
+	return &Spiller{dir: dir}
+}
+
+// ShouldSpill reports whether the allocated memory has reached
+// the configured fraction of the memory limit.
+func (s *Spiller) ShouldSpill() bool {
+	if s == nil {
+		return false
+	} else if s.mem == nil {
+		return true
+	}
+	limit, ok := s.mem.CurrentLimit()
+	if !ok {
+		return false
+	}
+	return float64(s.mem.Allocated()) >= s.threshold*float64(limit)
+}
@@ -0,0 +1,149 @@
+package spill_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/spill"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/execute/table/static"
+	"github.com/influxdata/flux/memory"
+)
+
+func TestNew(t *testing.T) {
+	limit := int64(1000)
+	mem := &memory.ResourceAllocator{Limit: &limit}
+
+	if s := spill.New(context.Background(), mem); s != nil {
+		t.Fatal("expected nil spiller when spilling is not enabled")
+	}
+
+	ctx := spill.Inject(context.Background(), spill.Config{Threshold: 0.5})
+	if s := spill.New(ctx, &memory.ResourceAllocator{}); s != nil {
+		t.Fatal("expected nil spiller when there is no memory limit")
+	}
+
+	s := spill.New(ctx, mem)
+	if s == nil {
+		t.Fatal("expected spiller")
+	}
+	if s.ShouldSpill() {
+		t.Fatal("unexpected spill with no memory allocated")
+	}
+	if err := mem.Account(500); err != nil {
+		t.Fatal(err)
+	}
+	if !s.ShouldSpill() {
+		t.Fatal("expected spill when the threshold is reached")
+	}
+}
+
+func TestBuilder_Spill(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		in    static.TableGroup
+		files int
+		want  static.Table
+	}{
+		{
+			name: "TwoTables",
+			in: static.TableGroup{
+				static.StringKey("_measurement", "m0"),
+				static.StringKey("_field", "f0"),
+				static.Table{
+					static.Times("_time", "2020-01-01T00:00:00Z", 10, 20, 30),
+					static.Ints("_value", 4, 8, 2, 7),
+				},
+				static.Table{
+					static.Times("_time", "2020-01-01T00:00:40Z", 10, 20),
+					static.Ints("_value", 3, 1, 9),
+				},
+			},
+			files: 1,
+			want: static.Table{
+				static.StringKey("_measurement", "m0"),
+				static.StringKey("_field", "f0"),
+				static.Times("_time", "2020-01-01T00:00:00Z", 10, 20, 30, 40, 50, 60),
+				static.Ints("_value", 4, 8, 2, 7, 3, 1, 9),
+			},
+		},
+		{
+			name: "BackfillNulls",
+			in: static.TableGroup{
+				static.StringKey("_measurement", "m0"),
+				static.Table{
+					static.Times("_time", "2020-01-01T00:00:00Z", 10, 20),
+					static.Floats("f0", 3, 8, 2),
+				},
+				static.Table{
+					static.Times("_time", "2020-01-01T00:00:00Z", 10, 20),
+					static.Floats("f0", 18, 2, 7),
+					static.Ints("f1", 5, 9, 2),
+				},
+			},
+			files: 1,
+			want: static.Table{
+				static.StringKey("_measurement", "m0"),
+				static.Times("_time", "2020-01-01T00:00:00Z", 10, 20, "2020-01-01T00:00:00Z", 10, 20),
+				static.Floats("f0", 3, 8, 2, 18, 2, 7),
+				static.Ints("f1", nil, nil, nil, 5, 9, 2),
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "flux-spill-test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			limit := int64(1 << 20)
+			mem := &memory.ResourceAllocator{Limit: &limit}
+			ctx := spill.Inject(context.Background(), spill.Config{Dir: dir})
+			s := spill.New(ctx, mem)
+
+			var b *spill.Builder
+			if err := tt.in.Do(func(tbl flux.Table) error {
+				if b == nil {
+					b = spill.NewBuilder(tbl.Key(), s, mem)
+				}
+				if err := b.AppendTable(tbl); err != nil {
+					return err
+				}
+				// Spill after every table so the output
+				// is read back from the spill file.
+				return b.Spill()
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if files, err := ioutil.ReadDir(dir); err != nil {
+				t.Fatal(err)
+			} else if want, got := tt.files, len(files); want != got {
+				t.Fatalf("unexpected number of spill files -want/+got:\n\t- %d\n\t+ %d", want, got)
+			}
+
+			out, err := b.Table()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, got := tt.want, table.Iterator{out}
+			if diff := table.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected diff -want/+got:\n%s", diff)
+			}
+			b.Release()
+
+			if files, err := ioutil.ReadDir(dir); err != nil {
+				t.Fatal(err)
+			} else if len(files) != 0 {
+				t.Fatalf("expected spill files to be removed, found %d", len(files))
+			}
+			if got := mem.Allocated(); got != 0 {
+				t.Fatalf("memory leak: %d bytes still allocated", got)
+			}
+		})
+	}
+}
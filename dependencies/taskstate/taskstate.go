@@ -0,0 +1,198 @@
+// Package taskstate persists the state of transformations between
+// runs of a periodic query so that each run only processes the
+// rows that are newer than the previous run.
+package taskstate
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+type key int
+
+const (
+	storeKey key = iota
+	sessionKey
+)
+
+// Store persists the state of the transformations in a query.
+//
+// A Store holds the state for a single task. Queries that are
+// not runs of the same task must not share a Store.
+type Store interface {
+	// Load returns the state saved under the key.
+	// The second return value is false if there is no state for the key.
+	Load(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Save stores the state under the key.
+	Save(ctx context.Context, key string, value []byte) error
+}
+
+// Inject will inject this Store into the dependency chain.
+// Queries that run with a Store persist the state of the
+// transformations that support incremental execution.
+func Inject(ctx context.Context, store Store) context.Context {
+	return context.WithValue(ctx, storeKey, store)
+}
+
+// GetStore will return the Store for the current context.
+// If no Store has been injected into the dependencies,
+// this will return nil and queries are not run incrementally.
+func GetStore(ctx context.Context) Store {
+	s, _ := ctx.Value(storeKey).(Store)
+	return s
+}
+
+// Dependency will inject the Store into the dependency chain.
+type Dependency struct {
+	Store Store
+}
+
+// Inject will inject the Store into the dependency chain.
+func (d Dependency) Inject(ctx context.Context) context.Context {
+	return Inject(ctx, d.Store)
+}
+
+// Session stages the state saved during a single run of a query.
+// The staged state is written to the Store with Commit once the
+// query has completed successfully so that a failed run does not
+// advance the state.
+type Session struct {
+	store Store
+
+	mu     sync.Mutex
+	staged map[string][]byte
+}
+
+// NewSession creates a Session for the Store.
+func NewSession(store Store) *Session {
+	return &Session{
+		store:  store,
+		staged: make(map[string][]byte),
+	}
+}
+
+// WithSession associates the Session with the context.
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionKey, s)
+}
+
+// GetSession returns the Session for the current context.
+// If the query is not run incrementally, this returns nil.
+func GetSession(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionKey).(*Session)
+	return s
+}
+
+// Load returns the state that was committed by a previous run.
+func (s *Session) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	return s.store.Load(ctx, key)
+}
+
+// Stage records the state to save for the key when the session is committed.
+func (s *Session) Stage(key string, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.staged[key] = value
+}
+
+// Commit writes the staged state to the Store.
+func (s *Session) Commit(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, value := range s.staged {
+		if err := s.store.Save(ctx, key, value); err != nil {
+			return err
+		}
+		delete(s.staged, key)
+	}
+	return nil
+}
+
+// MemoryStore is a Store that keeps the state in memory.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	state map[string][]byte
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		state: make(map[string][]byte),
+	}
+}
+
+func (s *MemoryStore) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.state[key]
+	return value, ok, nil
+}
+
+func (s *MemoryStore) Save(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state[key] = value
+	return nil
+}
+
+// DirStore is a Store that writes the state for each key
+// to a file in a directory.
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore creates a DirStore for the directory.
+// The directory is created when state is first saved.
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{dir: dir}
+}
+
+func (s *DirStore) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key))
+}
+
+func (s *DirStore) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := ioutil.ReadFile(s.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, errors.Wrapf(err, codes.Internal, "failed to load task state %q", key)
+	}
+	return value, true, nil
+}
+
+func (s *DirStore) Save(ctx context.Context, key string, value []byte) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return errors.Wrap(err, codes.Internal, "failed to create task state directory")
+	}
+
+	// Write to a temporary file first so a failure
+	// does not leave behind partially written state.
+	f, err := ioutil.TempFile(s.dir, ".tmp-")
+	if err != nil {
+		return errors.Wrapf(err, codes.Internal, "failed to save task state %q", key)
+	}
+	if _, err := f.Write(value); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return errors.Wrapf(err, codes.Internal, "failed to save task state %q", key)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return errors.Wrapf(err, codes.Internal, "failed to save task state %q", key)
+	}
+	if err := os.Rename(f.Name(), s.path(key)); err != nil {
+		_ = os.Remove(f.Name())
+		return errors.Wrapf(err, codes.Internal, "failed to save task state %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package taskstate_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/influxdata/flux/dependencies/taskstate"
+)
+
+func TestSession_Commit(t *testing.T) {
+	ctx := context.Background()
+	store := taskstate.NewMemoryStore()
+	if err := store.Save(ctx, "a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := taskstate.NewSession(store)
+	s.Stage("a", []byte("2"))
+	s.Stage("b", []byte("3"))
+
+	// Staged state is not visible until it is committed.
+	if v, ok, err := s.Load(ctx, "a"); err != nil {
+		t.Fatal(err)
+	} else if !ok || string(v) != "1" {
+		t.Fatalf("unexpected value before commit: %q %v", v, ok)
+	}
+	if _, ok, _ := s.Load(ctx, "b"); ok {
+		t.Fatal("unexpected state before commit")
+	}
+
+	if err := s.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"a": "2", "b": "3"} {
+		if v, ok, err := store.Load(ctx, key); err != nil {
+			t.Fatal(err)
+		} else if !ok || string(v) != want {
+			t.Errorf("unexpected value for %s: want %q, got %q", key, want, v)
+		}
+	}
+}
+
+func TestDirStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "taskstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	store := taskstate.NewDirStore(dir + "/state")
+	if _, ok, err := store.Load(ctx, "node/0"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("unexpected state in empty store")
+	}
+
+	if err := store.Save(ctx, "node/0", []byte("state")); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := store.Load(ctx, "node/0"); err != nil {
+		t.Fatal(err)
+	} else if !ok || string(v) != "state" {
+		t.Fatalf("unexpected value: %q %v", v, ok)
+	}
+
+	files, err := ioutil.ReadDir(dir + "/state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("unexpected number of files: %d", len(files))
+	}
+}
+
+func TestGetSession(t *testing.T) {
+	ctx := context.Background()
+	if s := taskstate.GetSession(ctx); s != nil {
+		t.Fatal("expected no session")
+	}
+	if s := taskstate.GetStore(ctx); s != nil {
+		t.Fatal("expected no store")
+	}
+
+	store := taskstate.NewMemoryStore()
+	ctx = taskstate.Dependency{Store: store}.Inject(ctx)
+	if s := taskstate.GetStore(ctx); s != store {
+		t.Fatal("expected injected store")
+	}
+	s := taskstate.NewSession(store)
+	ctx = taskstate.WithSession(ctx, s)
+	if got := taskstate.GetSession(ctx); got != s {
+		t.Fatal("expected session")
+	}
+}
@@ -2,6 +2,7 @@ package execute
 
 import (
 	"context"
+	"encoding"
 	"sync"
 
 	"github.com/apache/arrow/go/v7/arrow/memory"
@@ -124,6 +125,15 @@ func (t *aggregateTransformation) flushKey(key flux.GroupKey) error {
 
 // Finish is implemented to remain compatible with legacy upstreams.
 func (t *aggregateTransformation) Finish(id DatasetID, err error) {
+	if err == nil {
+		// Groups that were restored from a previous run of the query
+		// are computed even if they did not receive any new data.
+		if r, ok := t.t.(incrementalAggregateTransformation); ok {
+			err = r.restoreRemaining(func(key flux.GroupKey, state interface{}) {
+				t.d.Set(key, state)
+			})
+		}
+	}
 	if err == nil {
 		err = t.d.Range(func(key flux.GroupKey, value interface{}) error {
 			return t.computeFor(key, value)
@@ -150,6 +160,15 @@ func (t *aggregateTransformation) OperationType() string {
 	return OperationType(t.t)
 }
 
+// incrementalAggregateTransformation is implemented by an
+// AggregateTransformation that restores its state from a
+// previous run of the query.
+type incrementalAggregateTransformation interface {
+	// restoreRemaining calls the function with the state of each group
+	// that was restored and did not receive any data in this run.
+	restoreRemaining(f func(key flux.GroupKey, state interface{})) error
+}
+
 // AggregateParallelTransformation is an AggregateTransformation that is capable of
 // processing chunks from within the same group key in parallel.
 //
@@ -374,6 +393,13 @@ var DefaultSimpleAggregateConfig = SimpleAggregateConfig{
 	Columns: []string{DefaultValueColLabel},
 }
 
+// PassThroughAttribute implements plan.PassThroughAttributer.
+// Simple aggregates compute each table independently and keep its group key,
+// so they can run on partitions of the group keys.
+func (c SimpleAggregateConfig) PassThroughAttribute(attrKey string) bool {
+	return attrKey == plan.ParallelGroupRunKey
+}
+
 func (c SimpleAggregateConfig) Copy() SimpleAggregateConfig {
 	nc := c
 	if c.Columns != nil {
@@ -419,6 +445,30 @@ func NewSimpleAggregateTransformation(ctx context.Context, id DatasetID, agg Sim
 	}, d, nil
 }
 
+// NewIncrementalAggregateTransformation constructs a simple aggregate that
+// continues from the state of the previous run when the query is run
+// incrementally. The value functions created by the aggregate must implement
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler for their state
+// to be saved.
+//
+// When the query is not run incrementally, this is the same
+// as NewSimpleAggregateTransformation.
+func NewIncrementalAggregateTransformation(id DatasetID, agg SimpleAggregate, config SimpleAggregateConfig, a Administration) (Transformation, Dataset, error) {
+	state, err := NewIncrementalState(a, DefaultTimeColLabel)
+	if err != nil {
+		return nil, nil, err
+	} else if state == nil {
+		return NewSimpleAggregateTransformation(a.Context(), id, agg, config, a.Allocator())
+	}
+
+	tr := &simpleAggregateTransformation2{
+		agg:    agg,
+		config: config,
+		state:  state,
+	}
+	return NewAggregateTransformation(id, tr, a.Allocator())
+}
+
 type simpleAggregateTransformation struct {
 	ExecutionNode
 	d     Dataset
@@ -584,6 +634,10 @@ func (t *simpleAggregateTransformation) Finish(id DatasetID, err error) {
 type simpleAggregateTransformation2 struct {
 	agg    SimpleAggregate
 	config SimpleAggregateConfig
+
+	// state is the state saved between runs of the query.
+	// It is nil if the query is not run incrementally.
+	state *IncrementalState
 }
 
 type aggregateState struct {
@@ -614,6 +668,11 @@ func (t *simpleAggregateTransformation2) initializeState(chunk table.Chunk, curr
 	if current != nil {
 		return current.(aggregateStateList), nil
 	}
+	if t.state != nil {
+		if data, ok := t.state.Restore(chunk.Key()); ok {
+			return t.decodeState(data)
+		}
+	}
 
 	state := make(aggregateStateList, len(t.config.Columns))
 	for i, label := range t.config.Columns {
@@ -649,7 +708,117 @@ func (t *simpleAggregateTransformation2) initializeState(chunk table.Chunk, curr
 	return state, nil
 }
 
+func (t *simpleAggregateTransformation2) newValueFunc(typ flux.ColType) ValueFunc {
+	switch typ {
+	case flux.TBool:
+		if vf := t.agg.NewBoolAgg(); vf != nil {
+			return vf
+		}
+	case flux.TInt:
+		if vf := t.agg.NewIntAgg(); vf != nil {
+			return vf
+		}
+	case flux.TUInt:
+		if vf := t.agg.NewUIntAgg(); vf != nil {
+			return vf
+		}
+	case flux.TFloat:
+		if vf := t.agg.NewFloatAgg(); vf != nil {
+			return vf
+		}
+	case flux.TString:
+		if vf := t.agg.NewStringAgg(); vf != nil {
+			return vf
+		}
+	}
+	return nil
+}
+
+// encodeState encodes the aggregates of a group for the next run of the query.
+func (t *simpleAggregateTransformation2) encodeState(aggregates aggregateStateList) ([]byte, error) {
+	inTypes := make([]flux.ColType, len(aggregates))
+	data := make([][]byte, len(aggregates))
+	for i, s := range aggregates {
+		m, ok := s.agg.(encoding.BinaryMarshaler)
+		if !ok {
+			return nil, errors.Newf(codes.Unimplemented, "aggregate of column %q does not support incremental execution", t.config.Columns[i])
+		}
+		buf, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		inTypes[i], data[i] = s.inType, buf
+	}
+	return EncodeState(inTypes, data)
+}
+
+// decodeState decodes the aggregates of a group that were saved by the previous run.
+func (t *simpleAggregateTransformation2) decodeState(buf []byte) (aggregateStateList, error) {
+	var (
+		inTypes []flux.ColType
+		data    [][]byte
+	)
+	if err := DecodeState(buf, &inTypes, &data); err != nil {
+		return nil, err
+	}
+	if len(inTypes) != len(t.config.Columns) || len(data) != len(t.config.Columns) {
+		return nil, errors.New(codes.FailedPrecondition, "incremental state does not match the aggregate columns")
+	}
+
+	state := make(aggregateStateList, len(inTypes))
+	for i, typ := range inTypes {
+		vf := t.newValueFunc(typ)
+		u, ok := vf.(encoding.BinaryUnmarshaler)
+		if !ok {
+			return nil, errors.Newf(codes.Unimplemented, "aggregate of column %q does not support incremental execution", t.config.Columns[i])
+		}
+		if err := u.UnmarshalBinary(data[i]); err != nil {
+			return nil, err
+		}
+		state[i].agg, state[i].inType = vf, typ
+	}
+	return state, nil
+}
+
+func (t *simpleAggregateTransformation2) restoreRemaining(f func(key flux.GroupKey, state interface{})) error {
+	if t.state == nil {
+		return nil
+	}
+	return t.state.Remaining(func(key flux.GroupKey, data []byte) error {
+		state, err := t.decodeState(data)
+		if err != nil {
+			return err
+		}
+		f(key, state)
+		return nil
+	})
+}
+
 func (t *simpleAggregateTransformation2) Aggregate(chunk table.Chunk, state interface{}, mem memory.Allocator) (interface{}, bool, error) {
+	if t.state != nil {
+		// Only aggregate the rows that are newer than the previous run.
+		buffer := chunk.Buffer()
+		filtered, err := t.state.Filter(&buffer, mem)
+		if err != nil {
+			return nil, false, err
+		}
+		chunk = table.ChunkFromBuffer(*filtered)
+		defer chunk.Release()
+
+		// A group without new rows and without restored state
+		// was already computed by a previous run.
+		if chunk.Len() == 0 && state == nil {
+			if data, ok := t.state.Restore(chunk.Key()); ok {
+				aggregates, err := t.decodeState(data)
+				if err != nil {
+					return nil, false, err
+				}
+				return aggregates, true, nil
+			}
+			return nil, false, nil
+		}
+	}
+
 	aggregates, err := t.initializeState(chunk, state)
 	if err != nil {
 		return nil, false, err
@@ -690,6 +859,13 @@ func (t *simpleAggregateTransformation2) Aggregate(chunk table.Chunk, state inte
 
 func (t *simpleAggregateTransformation2) Compute(key flux.GroupKey, state interface{}, d *TransportDataset, mem memory.Allocator) error {
 	aggregates := state.(aggregateStateList)
+	if t.state != nil {
+		data, err := t.encodeState(aggregates)
+		if err != nil {
+			return err
+		}
+		t.state.Set(key, data)
+	}
 	buffer := arrow.TableBuffer{
 		GroupKey: key,
 		Columns:  make([]flux.ColMeta, 0, len(key.Cols())+len(aggregates)),
@@ -739,6 +915,11 @@ func (t *simpleAggregateTransformation2) Compute(key flux.GroupKey, state interf
 }
 
 func (t *simpleAggregateTransformation2) Close() error {
+	if t.state != nil {
+		if err := t.state.Save(); err != nil {
+			return err
+		}
+	}
 	if closer, ok := t.agg.(Closer); ok {
 		return closer.Close()
 	}
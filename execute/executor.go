@@ -66,6 +66,11 @@ type executionState struct {
 
 	transports []AsyncTransport
 
+	// nodeAllocs holds the allocators used by each plan node when
+	// the operator profiler is enabled. The allocators are keyed by
+	// the label of the node and there is one for each copy of the node.
+	nodeAllocs map[string][]*profileAllocator
+
 	dispatcher *poolDispatcher
 	logger     *zap.Logger
 }
@@ -171,7 +176,7 @@ func (v *createExecutionNodeVisitor) Visit(node plan.Node) error {
 		}
 	}
 
-	// There are three types of instantiations we need to support here, and for
+	// There are four types of instantiations we need to support here, and for
 	// each one of these, there are source nodes and non-source nodes.
 	//
 	// 1. Standard instantiation. These are non-parallel, non-merge nodes.
@@ -184,11 +189,13 @@ func (v *createExecutionNodeVisitor) Visit(node plan.Node) error {
 	//
 	// 3. Merge instantiation. There is a single copy of the node, but multiple copies of the
 	//    predecessors. These copies merge into the node.
+	//
+	// 4. Partition instantiation. There are multiple copies of the node, but a single copy
+	//    of the predecessors. Each copy of the node reads from the only copy of the predecessor.
+	//    When the predecessor is a plan.ParallelRouter, it adds the copies of the node
+	//    as transformations in the order of the copies and sends each table to one of them.
 
-	copies := 1
-	if attr := plan.GetOutputAttribute(ppn, plan.ParallelRunKey); attr != nil {
-		copies = attr.(plan.ParallelRunAttribute).Factor
-	}
+	copies := plan.ParallelRunFactor(ppn)
 
 	isParallelMerge := false
 	predCopies := 1
@@ -197,6 +204,11 @@ func (v *createExecutionNodeVisitor) Visit(node plan.Node) error {
 		predCopies = attr.(plan.ParallelMergeAttribute).Factor
 	}
 
+	readStart, hasReadStart, err := incrementalReadStart(v.es.ctx, node, streamContext.bounds)
+	if err != nil {
+		return err
+	}
+
 	// Build execution context for each copy.
 	ec := make([]executionContext, copies)
 	for i := 0; i < copies; i++ {
@@ -205,11 +217,15 @@ func (v *createExecutionNodeVisitor) Visit(node plan.Node) error {
 			parents:       make([]DatasetID, len(node.Predecessors())*predCopies),
 			streamContext: streamContext,
 			parallelOpts:  ParallelOpts{Group: i, Factor: copies},
+			alloc:         v.es.nodeAllocator(node),
+			nodeID:        node.ID(),
+			readStart:     readStart,
+			hasReadStart:  hasReadStart,
 		}
 
 		for pi, pred := range nonYieldPredecessors(node) {
 			for j := 0; j < predCopies; j++ {
-				ec[i].parents[pi*predCopies+j] = datasetIDFromNodeID(pred.ID(), i+j)
+				ec[i].parents[pi*predCopies+j] = datasetIDFromNodeID(pred.ID(), v.predecessorCopy(pred, i+j))
 			}
 		}
 	}
@@ -277,10 +293,15 @@ func (v *createExecutionNodeVisitor) Visit(node plan.Node) error {
 				// We link forward from all copies for the node to achieve the
 				// fan-in.
 				//   i == 0 AND ( iterating j )
+				//
+				// In case (4) above, copies is > 1 and there is a single copy
+				// of the predecessor. We link forward from that copy to achieve
+				// the fan-out.
+				//   ( iterating i ) AND j == 0
 				for j := 0; j < predCopies; j++ {
 					// Either i == 0 && j == 0: we are either iterating i, or we are iterating j.
-					executionNode := v.nodes[p][i+j]
-					transport := newConsecutiveTransport(v.es.ctx, v.es.dispatcher, tr, node, v.es.logger, v.es.alloc)
+					executionNode := v.nodes[p][v.predecessorCopy(p, i+j)]
+					transport := newConsecutiveTransport(v.es.ctx, v.es.dispatcher, tr, node, v.es.logger, ec[i].Allocator())
 					v.es.transports = append(v.es.transports, transport)
 					executionNode.AddTransformation(transport)
 				}
@@ -313,6 +334,58 @@ func (v *createExecutionNodeVisitor) Visit(node plan.Node) error {
 	return nil
 }
 
+// predecessorCopy returns the copy of the predecessor that the given copy
+// of a node reads from. A predecessor that does not run in parallel only has
+// a single copy that is read by every copy of the node.
+func (v *createExecutionNodeVisitor) predecessorCopy(pred plan.Node, i int) int {
+	if len(v.nodes[pred]) == 1 {
+		return 0
+	}
+	return i
+}
+
+// nodeAllocator returns the allocator for a copy of the node.
+// When the operator profiler is enabled, each copy of a node receives
+// its own allocator so the memory it uses can be reported in its profile.
+// The node allocators allocate memory from the query allocator so the
+// memory limit for the query still applies. Transformations only spill
+// to disk with the query allocator, so they do not spill while they
+// are profiled.
+func (es *executionState) nodeAllocator(node plan.Node) memory.Allocator {
+	if !HaveExecutionDependencies(es.ctx) {
+		return es.alloc
+	}
+	deps := GetExecutionDependencies(es.ctx)
+	if deps.ExecutionOptions == nil || deps.ExecutionOptions.OperatorProfiler == nil {
+		return es.alloc
+	}
+
+	alloc := &profileAllocator{mem: es.alloc}
+	if es.nodeAllocs == nil {
+		es.nodeAllocs = make(map[string][]*profileAllocator)
+	}
+	label := string(node.ID())
+	es.nodeAllocs[label] = append(es.nodeAllocs[label], alloc)
+	return alloc
+}
+
+// recordAllocations records the memory used by each node in the
+// first profile with the label of the node.
+func (es *executionState) recordAllocations(profiles []flux.TransportProfile) {
+	seen := make(map[string]bool, len(es.nodeAllocs))
+	for i := range profiles {
+		label := profiles[i].Label
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		for _, alloc := range es.nodeAllocs[label] {
+			profiles[i].MaxAllocated += alloc.MaxAllocated()
+			profiles[i].TotalAllocated += alloc.TotalAllocated()
+		}
+	}
+}
+
 // generateResult will attach a result to the query for the specified node.
 func (v *createExecutionNodeVisitor) generateResult(resultName string, node plan.Node, idx int) error {
 	// if the result name is already present in the result set, that's an error.
@@ -395,12 +468,7 @@ func (es *executionState) chooseDefaultResources(ctx context.Context, p *plan.Sp
 				// Do not include source nodes in the node list as
 				// they do not use the dispatcher.
 				if len(node.Predecessors()) > 0 {
-					addend := 1
-					ppn := node.(*plan.PhysicalPlanNode)
-					if attr := plan.GetOutputAttribute(ppn, plan.ParallelRunKey); attr != nil {
-						addend = attr.(plan.ParallelRunAttribute).Factor
-					}
-					concurrencyQuota += addend
+					concurrencyQuota += plan.ParallelRunFactor(node.(*plan.PhysicalPlanNode))
 				}
 				return nil
 			})
@@ -411,6 +479,15 @@ func (es *executionState) chooseDefaultResources(ctx context.Context, p *plan.Sp
 				concurrencyQuota = 1
 			}
 			es.resources.ConcurrencyQuota = concurrencyQuota
+		} else {
+			// The parallel copies of a node only run at the same
+			// time when there is a worker for each of them.
+			_ = p.TopDownWalk(func(node plan.Node) error {
+				if factor := plan.ParallelRunFactor(node.(*plan.PhysicalPlanNode)); factor > es.resources.ConcurrencyQuota {
+					es.resources.ConcurrencyQuota = factor
+				}
+				return nil
+			})
 		}
 	}
 }
@@ -507,6 +584,7 @@ func (es *executionState) do() {
 		// Merge the transport profiles in with the ones already filled
 		// by the sources.
 		stats.Profiles = append(stats.Profiles, profiles...)
+		es.recordAllocations(stats.Profiles)
 
 		es.statsCh <- stats
 	}()
@@ -523,6 +601,18 @@ type executionContext struct {
 	parents       []DatasetID
 	streamContext streamContext
 	parallelOpts  ParallelOpts
+
+	// alloc is the allocator for this node.
+	// If nil, the query allocator is used.
+	alloc memory.Allocator
+
+	// nodeID is the plan node that this context was created for.
+	nodeID plan.NodeID
+
+	// readStart is the time that a source of an incremental
+	// run reads from when hasReadStart is set.
+	readStart    Time
+	hasReadStart bool
 }
 
 func resolveTime(qt flux.Time, now time.Time) Time {
@@ -542,6 +632,9 @@ func (ec executionContext) StreamContext() StreamContext {
 }
 
 func (ec executionContext) Allocator() memory.Allocator {
+	if ec.alloc != nil {
+		return ec.alloc
+	}
 	return ec.es.alloc
 }
 
@@ -552,3 +645,17 @@ func (ec executionContext) Parents() []DatasetID {
 func (ec executionContext) ParallelOpts() ParallelOpts {
 	return ec.parallelOpts
 }
+
+// incrementalKey identifies the state of the node for IncrementalState.
+// Each copy of a parallel node has its own state.
+func (ec executionContext) incrementalKey() string {
+	if ec.parallelOpts.Factor > 1 {
+		return fmt.Sprintf("%s/%d", ec.nodeID, ec.parallelOpts.Group)
+	}
+	return string(ec.nodeID)
+}
+
+// incrementalReadStart implements IncrementalReadStart.
+func (ec executionContext) incrementalReadStart() (Time, bool) {
+	return ec.readStart, ec.hasReadStart
+}
@@ -0,0 +1,589 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+
+	"github.com/apache/arrow/go/v7/arrow/bitutil"
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/taskstate"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/arrowutil"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// IncrementalState is the state of a transformation that is persisted
+// between runs of a query. It is used by transformations that can
+// continue from the result of a previous run, such as running totals
+// and aggregates, so that each run only processes new rows.
+//
+// Rows are identified as new by the watermark of their group, which is
+// the latest time that was processed for the group by a previous run.
+// Rows at or before the watermark are removed by Filter. Rows that arrive
+// late for the watermark of their group are not processed.
+//
+// The state of each group is restored by its group key, which keeps the
+// _start and _stop of the run that saved it. Groups that are not within
+// the current bounds, such as old windows or a range that has moved forward,
+// are discarded so their rows are processed again for the new group.
+// The _stop of a group that spans the bounds of the previous run is
+// extended to the current bounds when the start of the bounds has not
+// changed so the state of a range continues as its stop moves forward.
+type IncrementalState struct {
+	session *taskstate.Session
+	key     string
+	bounds  *Bounds
+	timeCol string
+
+	restored   *RandomAccessGroupLookup
+	groups     *RandomAccessGroupLookup
+	watermarks *RandomAccessGroupLookup
+}
+
+// incrementalWatermark is the watermark of a group.
+type incrementalWatermark struct {
+	saved    Time
+	hasSaved bool
+	max      Time
+	hasMax   bool
+}
+
+// latest returns the latest time processed for the group
+// by this run or a previous run.
+func (w *incrementalWatermark) latest() (Time, bool) {
+	if w.hasMax && (!w.hasSaved || w.max > w.saved) {
+		return w.max, true
+	}
+	return w.saved, w.hasSaved
+}
+
+// incrementalAdministration is implemented by an Administration
+// that identifies the node in the plan that a transformation is
+// created for.
+type incrementalAdministration interface {
+	incrementalKey() string
+	incrementalReadStart() (Time, bool)
+}
+
+// IncrementalProcedureSpec is implemented by the procedure specs
+// of transformations that use an IncrementalState.
+type IncrementalProcedureSpec interface {
+	// IncrementalTimeColumn returns the column
+	// that is compared with the watermark.
+	IncrementalTimeColumn() string
+}
+
+// IncrementalReadStart returns the time that a source should start
+// reading from in an incremental run. The rows before it are at or before
+// the watermark of every group of every successor of the source and
+// would not be processed.
+//
+// The second return value is false if the source must read every row,
+// such as when the query is not run incrementally, a successor of the
+// source is not an incremental transformation on the _time column,
+// a successor has not been run before or the start of the bounds has
+// changed since the previous run. Rows of a group that did not exist
+// in the previous run are only read from the returned time.
+//
+// A source that reads from the returned time must still report
+// the _start of its bounds so the group keys match the saved state.
+func IncrementalReadStart(a Administration) (Time, bool) {
+	ia, ok := a.(incrementalAdministration)
+	if !ok {
+		return 0, false
+	}
+	return ia.incrementalReadStart()
+}
+
+// incrementalReadStart returns the earliest time after the watermark of
+// each group of each successor of the source node. See IncrementalReadStart.
+func incrementalReadStart(ctx context.Context, node plan.Node, bounds *Bounds) (Time, bool, error) {
+	session := taskstate.GetSession(ctx)
+	if session == nil || bounds == nil || len(node.Predecessors()) > 0 || len(node.Successors()) == 0 {
+		return 0, false, nil
+	}
+
+	var start Time
+	for i, succ := range node.Successors() {
+		spec, ok := succ.ProcedureSpec().(IncrementalProcedureSpec)
+		if !ok || spec.IncrementalTimeColumn() != DefaultTimeColLabel {
+			return 0, false, nil
+		}
+		// Each copy of a parallel node has its own watermark.
+		if ppn, ok := succ.(*plan.PhysicalPlanNode); !ok || plan.ParallelRunFactor(ppn) > 1 {
+			return 0, false, nil
+		}
+
+		data, ok, err := session.Load(ctx, string(succ.ID()))
+		if err != nil {
+			return 0, false, err
+		} else if !ok {
+			return 0, false, nil
+		}
+		var snapshot incrementalSnapshot
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&snapshot); err != nil {
+			return 0, false, errors.Wrapf(err, codes.Internal, "failed to decode incremental state for %s", succ.ID())
+		}
+		// The groups of a previous run with a different start
+		// are discarded so their rows must be read again.
+		if !snapshot.HasBounds || Time(snapshot.Start) != bounds.Start || len(snapshot.Groups) == 0 {
+			return 0, false, nil
+		}
+		for j, g := range snapshot.Groups {
+			if !g.HasWatermark {
+				return 0, false, nil
+			}
+			if t := Time(g.Watermark) + 1; (i == 0 && j == 0) || t < start {
+				start = t
+			}
+		}
+	}
+	return start, true, nil
+}
+
+// NewIncrementalState loads the state of the transformation from the
+// previous run of the query. The time column is used to determine
+// which rows are newer than the previous run.
+//
+// If the query is not run incrementally, this returns nil and
+// the transformation should process every row.
+func NewIncrementalState(a Administration, timeCol string) (*IncrementalState, error) {
+	session := taskstate.GetSession(a.Context())
+	if session == nil {
+		return nil, nil
+	}
+	ia, ok := a.(incrementalAdministration)
+	if !ok {
+		return nil, nil
+	}
+
+	s := &IncrementalState{
+		session:    session,
+		key:        ia.incrementalKey(),
+		bounds:     a.StreamContext().Bounds(),
+		timeCol:    timeCol,
+		restored:   NewRandomAccessGroupLookup(),
+		groups:     NewRandomAccessGroupLookup(),
+		watermarks: NewRandomAccessGroupLookup(),
+	}
+	data, ok, err := session.Load(a.Context(), s.key)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		if err := s.restore(data); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// incrementalSnapshot is the encoded form of the state of a transformation.
+type incrementalSnapshot struct {
+	Start, Stop int64
+	HasBounds   bool
+	Groups      []incrementalGroup
+}
+
+type incrementalGroup struct {
+	Cols         []flux.ColMeta
+	Values       []stateValue
+	Data         []byte
+	Watermark    int64
+	HasWatermark bool
+}
+
+func (s *IncrementalState) restore(data []byte) error {
+	var snapshot incrementalSnapshot
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&snapshot); err != nil {
+		return errors.Wrapf(err, codes.Internal, "failed to decode incremental state for %s", s.key)
+	}
+
+	for _, g := range snapshot.Groups {
+		vs := make([]values.Value, len(g.Values))
+		for j, sv := range g.Values {
+			col := g.Cols[j]
+			if sv.Null {
+				vs[j] = values.NewNull(flux.SemanticType(col.Type))
+				continue
+			}
+			v, err := sv.value()
+			if err != nil {
+				return err
+			}
+			vs[j] = v
+		}
+		key := NewGroupKey(g.Cols, vs)
+		if s.bounds != nil && snapshot.HasBounds {
+			key = s.extendStop(key, Time(snapshot.Start), Time(snapshot.Stop))
+			if !s.inBounds(key) {
+				continue
+			}
+		}
+		s.restored.Set(key, g.Data)
+		s.watermarks.Set(key, &incrementalWatermark{
+			saved:    Time(g.Watermark),
+			hasSaved: g.HasWatermark,
+		})
+	}
+	return nil
+}
+
+// extendStop replaces the _stop of a group that spans the bounds of the
+// previous run with the stop of the current bounds if the start of the
+// bounds has not changed.
+func (s *IncrementalState) extendStop(key flux.GroupKey, start, stop Time) flux.GroupKey {
+	if start != s.bounds.Start || stop == s.bounds.Stop {
+		return key
+	}
+	startIdx := ColIdx(DefaultStartColLabel, key.Cols())
+	stopIdx := ColIdx(DefaultStopColLabel, key.Cols())
+	if startIdx < 0 || stopIdx < 0 ||
+		key.Cols()[startIdx].Type != flux.TTime || key.Cols()[stopIdx].Type != flux.TTime ||
+		key.IsNull(startIdx) || key.IsNull(stopIdx) ||
+		key.ValueTime(startIdx) != start || key.ValueTime(stopIdx) != stop {
+		return key
+	}
+	vs := make([]values.Value, len(key.Cols()))
+	copy(vs, key.Values())
+	vs[stopIdx] = values.NewTime(s.bounds.Stop)
+	return NewGroupKey(key.Cols(), vs)
+}
+
+// inBounds reports whether the _start and _stop of
+// the group key are within the current bounds.
+func (s *IncrementalState) inBounds(key flux.GroupKey) bool {
+	timeValue := func(label string) (Time, bool) {
+		v := key.LabelValue(label)
+		if v == nil || v.IsNull() || v.Type().Nature() != semantic.Time {
+			return 0, false
+		}
+		return v.Time(), true
+	}
+	if start, ok := timeValue(DefaultStartColLabel); ok && start < s.bounds.Start {
+		return false
+	}
+	if stop, ok := timeValue(DefaultStopColLabel); ok && (stop <= s.bounds.Start || stop > s.bounds.Stop) {
+		return false
+	}
+	return true
+}
+
+// Watermark returns the latest time that was processed for
+// the group by the previous run. The second return value is
+// false if the previous run did not process the group.
+func (s *IncrementalState) Watermark(key flux.GroupKey) (Time, bool) {
+	v, ok := s.watermarks.Lookup(key)
+	if !ok {
+		return 0, false
+	}
+	w := v.(*incrementalWatermark)
+	return w.saved, w.hasSaved
+}
+
+// Filter returns a buffer with the rows of the column reader
+// that are newer than the watermark of its group. Rows with a
+// null time are never processed since they cannot be compared
+// with the watermark.
+//
+// The returned buffer must be released.
+func (s *IncrementalState) Filter(cr flux.ColReader, mem memory.Allocator) (*arrow.TableBuffer, error) {
+	timeIdx := ColIdx(s.timeCol, cr.Cols())
+	if timeIdx < 0 {
+		return nil, errors.Newf(codes.FailedPrecondition, "incremental execution requires the %q column", s.timeCol)
+	} else if typ := cr.Cols()[timeIdx].Type; typ != flux.TTime {
+		return nil, errors.Newf(codes.FailedPrecondition, "incremental execution requires the %q column to be a time, got %s", s.timeCol, typ)
+	}
+
+	key := cr.Key()
+	var w *incrementalWatermark
+	if v, ok := s.watermarks.Lookup(key); ok {
+		w = v.(*incrementalWatermark)
+	} else {
+		w = new(incrementalWatermark)
+		s.watermarks.Set(key, w)
+	}
+
+	n := cr.Len()
+	ts := cr.Times(timeIdx)
+	bitset := memory.NewResizableBuffer(mem)
+	bitset.Resize(n)
+	defer bitset.Release()
+
+	count := 0
+	for i := 0; i < n; i++ {
+		keep := false
+		if ts.IsValid(i) {
+			t := Time(ts.Value(i))
+			keep = !w.hasSaved || t > w.saved
+			if keep && (!w.hasMax || t > w.max) {
+				w.max, w.hasMax = t, true
+			}
+		}
+		if keep {
+			count++
+		}
+		bitutil.SetBitTo(bitset.Buf(), i, keep)
+	}
+
+	buffer := &arrow.TableBuffer{
+		GroupKey: key,
+		Columns:  cr.Cols(),
+		Values:   make([]array.Array, len(cr.Cols())),
+	}
+	for j, col := range cr.Cols() {
+		arr := table.Values(cr, j)
+		switch {
+		case count == n:
+			arr.Retain()
+			buffer.Values[j] = arr
+		case key.HasCol(col.Label):
+			buffer.Values[j] = arrow.Slice(arr, 0, int64(count))
+		default:
+			buffer.Values[j] = arrowutil.Filter(arr, bitset.Bytes(), mem)
+		}
+	}
+	return buffer, nil
+}
+
+// Restore returns the state that the previous run saved for the group.
+// The second return value is false if there is no state for the group.
+func (s *IncrementalState) Restore(key flux.GroupKey) ([]byte, bool) {
+	data, ok := s.restored.Delete(key)
+	if !ok {
+		return nil, false
+	}
+	return data.([]byte), true
+}
+
+// Remaining calls the function with the state of each group
+// that was saved by the previous run and has not been restored.
+// The groups are removed from the restored state.
+func (s *IncrementalState) Remaining(f func(key flux.GroupKey, data []byte) error) error {
+	var keys []flux.GroupKey
+	if err := s.restored.Range(func(key flux.GroupKey, value interface{}) error {
+		keys = append(keys, key)
+		return f(key, value.([]byte))
+	}); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		s.restored.Delete(key)
+	}
+	return nil
+}
+
+// Set records the state of the group to save for the next run.
+func (s *IncrementalState) Set(key flux.GroupKey, data []byte) {
+	s.groups.Set(key, data)
+}
+
+// Save stages the state for the next run. The groups that were restored
+// from the previous run and were not restored in this run are saved again.
+// The state is only stored if the query completes successfully.
+func (s *IncrementalState) Save() error {
+	var snapshot incrementalSnapshot
+	if s.bounds != nil {
+		snapshot.Start, snapshot.Stop = int64(s.bounds.Start), int64(s.bounds.Stop)
+		snapshot.HasBounds = true
+	}
+
+	appendGroup := func(key flux.GroupKey, value interface{}) error {
+		g := incrementalGroup{
+			Cols:   key.Cols(),
+			Values: make([]stateValue, len(key.Cols())),
+			Data:   value.([]byte),
+		}
+		if w, ok := s.watermarks.Lookup(key); ok {
+			t, ok := w.(*incrementalWatermark).latest()
+			g.Watermark, g.HasWatermark = int64(t), ok
+		}
+		for j, v := range key.Values() {
+			sv, err := newStateValue(v)
+			if err != nil {
+				return err
+			}
+			g.Values[j] = sv
+		}
+		snapshot.Groups = append(snapshot.Groups, g)
+		return nil
+	}
+	if err := s.restored.Range(appendGroup); err != nil {
+		return err
+	}
+	if err := s.groups.Range(appendGroup); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshot); err != nil {
+		return errors.Wrapf(err, codes.Internal, "failed to encode incremental state for %s", s.key)
+	}
+	s.session.Stage(s.key, buf.Bytes())
+	return nil
+}
+
+// EncodeState encodes the fields of the state of a group so it can
+// be saved with IncrementalState.Set. A field may be any value that
+// can be encoded by encoding/gob or a values.Value with a basic type
+// or a record of basic types.
+func EncodeState(fields ...interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	for _, field := range fields {
+		if v, ok := field.(values.Value); ok {
+			sv, err := newStateValue(v)
+			if err != nil {
+				return nil, err
+			}
+			field = sv
+		}
+		if err := enc.Encode(field); err != nil {
+			return nil, errors.Wrap(err, codes.Internal, "failed to encode incremental state")
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeState decodes state that was encoded with EncodeState
+// into the fields, which must be pointers to the same types
+// that were encoded.
+func DecodeState(data []byte, fields ...interface{}) error {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	for _, field := range fields {
+		if p, ok := field.(*values.Value); ok {
+			var sv stateValue
+			if err := dec.Decode(&sv); err != nil {
+				return errors.Wrap(err, codes.Internal, "failed to decode incremental state")
+			}
+			v, err := sv.value()
+			if err != nil {
+				return err
+			}
+			*p = v
+			continue
+		}
+		if err := dec.Decode(field); err != nil {
+			return errors.Wrap(err, codes.Internal, "failed to decode incremental state")
+		}
+	}
+	return nil
+}
+
+// stateValue is the encoded form of a values.Value.
+type stateValue struct {
+	Nature semantic.Nature
+	Null   bool
+	Int    int64
+	UInt   uint64
+	Float  float64
+	Str    string
+	Bool   bool
+	Labels []string
+	Values []stateValue
+}
+
+func newStateValue(v values.Value) (stateValue, error) {
+	sv := stateValue{Nature: v.Type().Nature()}
+	if v.IsNull() {
+		sv.Null = true
+		return sv, nil
+	}
+	switch sv.Nature {
+	case semantic.Int:
+		sv.Int = v.Int()
+	case semantic.UInt:
+		sv.UInt = v.UInt()
+	case semantic.Float:
+		sv.Float = v.Float()
+	case semantic.String:
+		sv.Str = v.Str()
+	case semantic.Bool:
+		sv.Bool = v.Bool()
+	case semantic.Time:
+		sv.Int = int64(v.Time())
+	case semantic.Object:
+		var err error
+		v.Object().Range(func(name string, v values.Value) {
+			if err != nil {
+				return
+			}
+			var fv stateValue
+			fv, err = newStateValue(v)
+			sv.Labels = append(sv.Labels, name)
+			sv.Values = append(sv.Values, fv)
+		})
+		if err != nil {
+			return stateValue{}, err
+		}
+	default:
+		return stateValue{}, errors.Newf(codes.Unimplemented, "incremental state does not support values of type %v", v.Type())
+	}
+	return sv, nil
+}
+
+func (sv stateValue) value() (values.Value, error) {
+	switch sv.Nature {
+	case semantic.Int, semantic.UInt, semantic.Float, semantic.String, semantic.Bool, semantic.Time:
+	case semantic.Object:
+		// The fields are set in their original order so that
+		// the record has the same type as the saved record.
+		return values.BuildObjectWithSize(len(sv.Labels), func(set values.ObjectSetter) error {
+			for i, label := range sv.Labels {
+				v, err := sv.Values[i].value()
+				if err != nil {
+					return err
+				}
+				set(label, v)
+			}
+			return nil
+		})
+	default:
+		return nil, errors.Newf(codes.Internal, "incremental state has a value with unsupported kind %v", sv.Nature)
+	}
+
+	if sv.Null {
+		return values.NewNull(basicType(sv.Nature)), nil
+	}
+	switch sv.Nature {
+	case semantic.Int:
+		return values.NewInt(sv.Int), nil
+	case semantic.UInt:
+		return values.NewUInt(sv.UInt), nil
+	case semantic.Float:
+		return values.NewFloat(sv.Float), nil
+	case semantic.String:
+		return values.NewString(sv.Str), nil
+	case semantic.Bool:
+		return values.NewBool(sv.Bool), nil
+	default:
+		return values.NewTime(values.Time(sv.Int)), nil
+	}
+}
+
+func basicType(n semantic.Nature) semantic.MonoType {
+	switch n {
+	case semantic.Int:
+		return semantic.BasicInt
+	case semantic.UInt:
+		return semantic.BasicUint
+	case semantic.Float:
+		return semantic.BasicFloat
+	case semantic.String:
+		return semantic.BasicString
+	case semantic.Bool:
+		return semantic.BasicBool
+	case semantic.Time:
+		return semantic.BasicTime
+	default:
+		panic(fmt.Sprintf("unexpected basic type %v", n))
+	}
+}
@@ -0,0 +1,263 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/dependencies/taskstate"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/values"
+)
+
+type incrementalTestSpec struct {
+	plan.DefaultCost
+	timeCol string
+}
+
+func (s *incrementalTestSpec) Kind() plan.ProcedureKind { return "incrementalTest" }
+func (s *incrementalTestSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+func (s *incrementalTestSpec) IncrementalTimeColumn() string { return s.timeCol }
+
+// incrementalTestKey returns a group key with the _start, _stop and host columns.
+func incrementalTestKey(start, stop Time, host string) flux.GroupKey {
+	return NewGroupKey(
+		[]flux.ColMeta{
+			{Label: DefaultStartColLabel, Type: flux.TTime},
+			{Label: DefaultStopColLabel, Type: flux.TTime},
+			{Label: "host", Type: flux.TString},
+		},
+		[]values.Value{
+			values.NewTime(start),
+			values.NewTime(stop),
+			values.NewString(host),
+		},
+	)
+}
+
+// encodeTestSnapshot encodes a snapshot with the bounds and
+// a group for each key with its watermark.
+func encodeTestSnapshot(t *testing.T, start, stop Time, keys []flux.GroupKey, watermarks []int64) []byte {
+	t.Helper()
+	snapshot := incrementalSnapshot{
+		Start:     int64(start),
+		Stop:      int64(stop),
+		HasBounds: true,
+	}
+	for i, key := range keys {
+		g := incrementalGroup{
+			Cols:         key.Cols(),
+			Values:       make([]stateValue, len(key.Cols())),
+			Data:         []byte{byte(i)},
+			Watermark:    watermarks[i],
+			HasWatermark: true,
+		}
+		for j, v := range key.Values() {
+			sv, err := newStateValue(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			g.Values[j] = sv
+		}
+		snapshot.Groups = append(snapshot.Groups, g)
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newTestIncrementalState(t *testing.T, bounds *Bounds, data []byte) *IncrementalState {
+	t.Helper()
+	s := &IncrementalState{
+		key:        "test",
+		bounds:     bounds,
+		timeCol:    DefaultTimeColLabel,
+		restored:   NewRandomAccessGroupLookup(),
+		groups:     NewRandomAccessGroupLookup(),
+		watermarks: NewRandomAccessGroupLookup(),
+	}
+	if err := s.restore(data); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestIncrementalState_Restore(t *testing.T) {
+	keys := []flux.GroupKey{
+		// A range that spans the previous bounds.
+		incrementalTestKey(0, 100, "a"),
+		// A window within the previous bounds.
+		incrementalTestKey(0, 50, "a"),
+		// A window that ends before the current bounds.
+		incrementalTestKey(0, 10, "b"),
+	}
+	data := encodeTestSnapshot(t, 0, 100, keys, []int64{90, 40, 5})
+
+	t.Run("same start", func(t *testing.T) {
+		s := newTestIncrementalState(t, &Bounds{Start: 0, Stop: 200}, data)
+		// The range follows the stop of the bounds.
+		if _, ok := s.Restore(incrementalTestKey(0, 200, "a")); !ok {
+			t.Error("expected state for the range")
+		}
+		if w, ok := s.Watermark(incrementalTestKey(0, 200, "a")); !ok || w != 90 {
+			t.Errorf("unexpected watermark for the range: %v %v", w, ok)
+		}
+		// The window keeps its own bounds.
+		if _, ok := s.Restore(incrementalTestKey(0, 50, "a")); !ok {
+			t.Error("expected state for the window")
+		}
+		if w, ok := s.Watermark(incrementalTestKey(0, 50, "a")); !ok || w != 40 {
+			t.Errorf("unexpected watermark for the window: %v %v", w, ok)
+		}
+	})
+
+	t.Run("moved start", func(t *testing.T) {
+		s := newTestIncrementalState(t, &Bounds{Start: 20, Stop: 120}, data)
+		// None of the groups start within the current bounds.
+		for _, key := range []flux.GroupKey{
+			incrementalTestKey(0, 100, "a"),
+			incrementalTestKey(20, 120, "a"),
+			incrementalTestKey(0, 50, "a"),
+		} {
+			if _, ok := s.Restore(key); ok {
+				t.Errorf("unexpected state for %v", key)
+			}
+			if _, ok := s.Watermark(key); ok {
+				t.Errorf("unexpected watermark for %v", key)
+			}
+		}
+	})
+}
+
+func TestIncrementalState_FilterByGroup(t *testing.T) {
+	keyA, keyB := incrementalTestKey(0, 100, "a"), incrementalTestKey(0, 100, "b")
+	data := encodeTestSnapshot(t, 0, 100, []flux.GroupKey{keyA, keyB}, []int64{50, 20})
+	s := newTestIncrementalState(t, &Bounds{Start: 0, Stop: 100}, data)
+
+	filter := func(key flux.GroupKey, times ...int64) int {
+		t.Helper()
+		cr := &arrow.TableBuffer{
+			GroupKey: key,
+			Columns:  []flux.ColMeta{{Label: DefaultTimeColLabel, Type: flux.TTime}},
+		}
+		cr.Values = append(cr.Values, arrow.NewInt(times, memory.DefaultAllocator))
+		defer cr.Release()
+		buf, err := s.Filter(cr, memory.DefaultAllocator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer buf.Release()
+		return buf.Len()
+	}
+
+	// A late row for b is after the watermark of b even
+	// though it is before the watermark of a.
+	if got := filter(keyA, 30, 60); got != 1 {
+		t.Errorf("unexpected rows for a: want 1, got %d", got)
+	}
+	if got := filter(keyB, 10, 30); got != 1 {
+		t.Errorf("unexpected rows for b: want 1, got %d", got)
+	}
+	// A new group processes every row.
+	if got := filter(incrementalTestKey(0, 100, "c"), 10, 30); got != 2 {
+		t.Errorf("unexpected rows for c: want 2, got %d", got)
+	}
+}
+
+func TestIncrementalReadStart(t *testing.T) {
+	bounds := &Bounds{Start: 0, Stop: 200}
+	saveGroups := func(store *taskstate.MemoryStore, key string, start Time, watermarks ...int64) {
+		keys := make([]flux.GroupKey, len(watermarks))
+		for i := range keys {
+			keys[i] = incrementalTestKey(start, 100, string(rune('a'+i)))
+		}
+		data := encodeTestSnapshot(t, start, 100, keys, watermarks)
+		if err := store.Save(context.Background(), key, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		timeCol string
+		start   Time
+		saved   map[string][]int64
+		want    Time
+		wantOK  bool
+	}{
+		{
+			name:    "first run",
+			timeCol: DefaultTimeColLabel,
+		},
+		{
+			name:    "after watermark",
+			timeCol: DefaultTimeColLabel,
+			saved:   map[string][]int64{"a": {10, 8}, "b": {5}},
+			want:    6,
+			wantOK:  true,
+		},
+		{
+			name:    "one successor not run",
+			timeCol: DefaultTimeColLabel,
+			saved:   map[string][]int64{"a": {10}},
+		},
+		{
+			name:    "other time column",
+			timeCol: "time",
+			saved:   map[string][]int64{"a": {10}, "b": {5}},
+		},
+		{
+			name:    "moved start",
+			timeCol: DefaultTimeColLabel,
+			start:   -50,
+			saved:   map[string][]int64{"a": {10}, "b": {5}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			store := taskstate.NewMemoryStore()
+			for key, watermarks := range tc.saved {
+				saveGroups(store, key, tc.start, watermarks...)
+			}
+			ctx := taskstate.WithSession(context.Background(), taskstate.NewSession(store))
+
+			from := plan.CreatePhysicalNode("from", &incrementalTestSpec{})
+			for _, id := range []plan.NodeID{"a", "b"} {
+				succ := plan.CreatePhysicalNode(id, &incrementalTestSpec{timeCol: tc.timeCol})
+				from.AddSuccessors(succ)
+				succ.AddPredecessors(from)
+			}
+
+			got, ok, err := incrementalReadStart(ctx, from, bounds)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tc.wantOK || got != tc.want {
+				t.Fatalf("unexpected read start: want %v (%v), got %v (%v)", tc.want, tc.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestIncrementalReadStart_NoSession(t *testing.T) {
+	from := plan.CreatePhysicalNode("from", &incrementalTestSpec{})
+	succ := plan.CreatePhysicalNode("a", &incrementalTestSpec{timeCol: DefaultTimeColLabel})
+	from.AddSuccessors(succ)
+	succ.AddPredecessors(from)
+
+	if _, ok, err := incrementalReadStart(context.Background(), from, &Bounds{Start: 0, Stop: 200}); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("expected no read start without a session")
+	}
+}
@@ -0,0 +1,60 @@
+package execute_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+func TestEncodeState(t *testing.T) {
+	record := values.NewObjectWithValues(map[string]values.Value{
+		"count": values.NewInt(4),
+		"sum":   values.NewFloat(10.5),
+		"last":  values.NewString("a"),
+		"seen":  values.NewBool(true),
+		"max":   values.NewNull(semantic.BasicUint),
+	})
+
+	data, err := execute.EncodeState(int64(3), true, record, values.NewTime(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		count int64
+		ok    bool
+		acc   values.Value
+		ts    values.Value
+	)
+	if err := execute.DecodeState(data, &count, &ok, &acc, &ts); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 || !ok {
+		t.Fatalf("unexpected fields: %d %v", count, ok)
+	}
+	if got, want := acc.Type().String(), record.Type().String(); got != want {
+		t.Fatalf("unexpected record type: want %s, got %s", want, got)
+	}
+	record.Range(func(name string, want values.Value) {
+		got, _ := acc.Object().Get(name)
+		if want.IsNull() {
+			if !got.IsNull() {
+				t.Errorf("expected %s to be null, got %v", name, got)
+			}
+		} else if !got.Equal(want) {
+			t.Errorf("unexpected value for %s: want %v, got %v", name, want, got)
+		}
+	})
+	if want := values.NewTime(5); !ts.Equal(want) {
+		t.Fatalf("unexpected time: want %v, got %v", want, ts)
+	}
+}
+
+func TestEncodeState_UnsupportedValue(t *testing.T) {
+	arr := values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicInt), []values.Value{values.NewInt(1)})
+	if _, err := execute.EncodeState(arr); err == nil {
+		t.Fatal("expected error for array state")
+	}
+}
@@ -117,6 +117,44 @@ func TestExecuteOptions(t *testing.T) {
 				concurrencyQuota: 2,
 			},
 		},
+		{
+			// Use the default execute options. The plan contains nodes
+			// that run in parallel, so the concurrency quota is raised to
+			// the parallel factor so the copies can run at the same time.
+			name: "defaults-parallel",
+			spec: &planspec.PlanSpec{
+				Nodes: []plan.Node{
+					planspec.CreatePhysicalMockNode("0"),
+					plan.CreatePhysicalNode("partition", planspec.MockProcedureSpec{
+						OutputAttributesFn: func() plan.PhysicalAttributes {
+							return plan.PhysicalAttributes{
+								plan.ParallelGroupRunKey: plan.ParallelGroupRunAttribute{Factor: 4},
+							}
+						},
+					}),
+					plan.CreatePhysicalNode("merge", planspec.MockProcedureSpec{
+						OutputAttributesFn: func() plan.PhysicalAttributes {
+							return plan.PhysicalAttributes{
+								plan.ParallelMergeKey: plan.ParallelMergeAttribute{Factor: 4},
+							}
+						},
+						RequiredAttributesFn: func() []plan.PhysicalAttributes {
+							return []plan.PhysicalAttributes{{
+								plan.ParallelGroupRunKey: plan.ParallelGroupRunAttribute{Factor: 4},
+							}}
+						},
+					}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			want: runWith{
+				memoryBytesQuota: math.MaxInt64,
+				concurrencyQuota: 4,
+			},
+		},
 		{
 			// Set the execute options. The memory limit passes in verbatim.
 			// The concurrency limit is 16 and the new behaviour of choosing
@@ -246,6 +246,116 @@ func TestParallel_Execute(t *testing.T) {
 				},
 			},
 		},
+		{
+			// The tables are partitioned by group key, the filter is executed
+			// on each partition in parallel, then the data is merged.
+			name: `from-partition-groups-filter-merge`,
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plantest.CreatePhysicalNode("from-test", executetest.NewFromProcedureSpec(
+						[]*executetest.Table{
+							{
+								KeyCols: []string{"host"},
+								ColMeta: []flux.ColMeta{
+									{Label: "host", Type: flux.TString},
+									{Label: "_time", Type: flux.TTime},
+									{Label: "_value", Type: flux.TFloat},
+								},
+								Data: [][]interface{}{
+									{"a", execute.Time(0), 1.0},
+									{"a", execute.Time(1), 8.0},
+									{"a", execute.Time(2), 3.0},
+								},
+							},
+							{
+								KeyCols: []string{"host"},
+								ColMeta: []flux.ColMeta{
+									{Label: "host", Type: flux.TString},
+									{Label: "_time", Type: flux.TTime},
+									{Label: "_value", Type: flux.TFloat},
+								},
+								Data: [][]interface{}{
+									{"b", execute.Time(0), 9.0},
+									{"b", execute.Time(1), 5.0},
+								},
+							},
+							{
+								KeyCols: []string{"host"},
+								ColMeta: []flux.ColMeta{
+									{Label: "host", Type: flux.TString},
+									{Label: "_time", Type: flux.TTime},
+									{Label: "_value", Type: flux.TFloat},
+								},
+								Data: [][]interface{}{
+									{"c", execute.Time(0), 7.0},
+									{"c", execute.Time(1), 8.5},
+									{"c", execute.Time(2), 2.0},
+								},
+							},
+						},
+					)),
+					plantest.CreatePhysicalNode("partition", &universe.PartitionGroupsProcedureSpec{Factor: 2}),
+					plantest.CreatePhysicalNode("filter",
+						&universe.FilterProcedureSpec{
+							Fn: interpreter.ResolvedFunction{
+								Scope: runtime.Prelude(),
+								Fn:    executetest.FunctionExpression(t, "(r) => r._value < 7.5"),
+							},
+						},
+					),
+					plantest.CreatePhysicalNode("merge", &universe.PartitionMergeProcedureSpec{
+						Factor:             2,
+						PartitionedByGroup: true,
+					}),
+					plantest.CreatePhysicalNode("yield", executetest.NewYieldProcedureSpec("_result")),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{2, 3},
+					{3, 4},
+				},
+			},
+			want: map[string][]*executetest.Table{
+				"_result": {
+					{
+						KeyCols: []string{"host"},
+						ColMeta: []flux.ColMeta{
+							{Label: "host", Type: flux.TString},
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{"a", execute.Time(0), 1.0},
+							{"a", execute.Time(2), 3.0},
+						},
+					},
+					{
+						KeyCols: []string{"host"},
+						ColMeta: []flux.ColMeta{
+							{Label: "host", Type: flux.TString},
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{"b", execute.Time(1), 5.0},
+						},
+					},
+					{
+						KeyCols: []string{"host"},
+						ColMeta: []flux.ColMeta{
+							{Label: "host", Type: flux.TString},
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{"c", execute.Time(0), 7.0},
+							{"c", execute.Time(2), 2.0},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: `parallel-from-merge-no-successor`,
 			spec: &plantest.PlanSpec{
@@ -3,6 +3,7 @@ package execute
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/memory"
@@ -119,6 +120,22 @@ func (o *OperatorProfiler) getTableBuilder(stats flux.Statistics, alloc memory.A
 			Label: "MeanDuration",
 			Type:  flux.TFloat,
 		},
+		{
+			Label: "TableCount",
+			Type:  flux.TInt,
+		},
+		{
+			Label: "RowCount",
+			Type:  flux.TInt,
+		},
+		{
+			Label: "MaxAllocated",
+			Type:  flux.TInt,
+		},
+		{
+			Label: "TotalAllocated",
+			Type:  flux.TInt,
+		},
 	}
 	for _, col := range colMeta {
 		if _, err := b.AddCol(col); err != nil {
@@ -135,6 +152,10 @@ func (o *OperatorProfiler) getTableBuilder(stats flux.Statistics, alloc memory.A
 		b.AppendInt(5, profile.Max)
 		b.AppendInt(6, profile.Sum)
 		b.AppendFloat(7, profile.Mean)
+		b.AppendInt(8, profile.Tables)
+		b.AppendInt(9, profile.Rows)
+		b.AppendInt(10, profile.MaxAllocated)
+		b.AppendInt(11, profile.TotalAllocated)
 	}
 	return b, nil
 }
@@ -283,3 +304,71 @@ func (s *QueryProfiler) getTableBuilder(q flux.Query, alloc memory.Allocator) (*
 	}
 	return b, nil
 }
+
+// profileAllocator allocates memory from the query allocator and
+// records the memory used by a single node for the operator profiler.
+// The query allocator accounts for the memory too so the memory limit
+// of the query still applies.
+type profileAllocator struct {
+	// The following variables are accessed with atomic operations
+	// and should be at the beginning of the struct to ensure byte
+	// alignment is correct.
+	// https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	bytesAllocated int64
+	maxAllocated   int64
+	totalAllocated int64
+
+	mem memory.Allocator
+}
+
+func (a *profileAllocator) Allocate(size int) []byte {
+	b := a.mem.Allocate(size)
+	a.count(size)
+	return b
+}
+
+func (a *profileAllocator) Reallocate(size int, b []byte) []byte {
+	sizediff := size - cap(b)
+	bs := a.mem.Reallocate(size, b)
+	a.count(sizediff)
+	return bs
+}
+
+func (a *profileAllocator) Free(b []byte) {
+	a.mem.Free(b)
+	atomic.AddInt64(&a.bytesAllocated, int64(-len(b)))
+}
+
+func (a *profileAllocator) Account(size int) error {
+	if err := a.mem.Account(size); err != nil {
+		return err
+	}
+	a.count(size)
+	return nil
+}
+
+// MaxAllocated reports the maximum amount of memory
+// allocated by the node at any point in the query.
+func (a *profileAllocator) MaxAllocated() int64 {
+	return atomic.LoadInt64(&a.maxAllocated)
+}
+
+// TotalAllocated reports the total amount of memory allocated
+// by the node, including memory that was released.
+func (a *profileAllocator) TotalAllocated() int64 {
+	return atomic.LoadInt64(&a.totalAllocated)
+}
+
+func (a *profileAllocator) count(size int) {
+	allocated := atomic.AddInt64(&a.bytesAllocated, int64(size))
+	if size <= 0 {
+		return
+	}
+	atomic.AddInt64(&a.totalAllocated, int64(size))
+	for {
+		max := atomic.LoadInt64(&a.maxAllocated)
+		if allocated <= max || atomic.CompareAndSwapInt64(&a.maxAllocated, max, allocated) {
+			return
+		}
+	}
+}
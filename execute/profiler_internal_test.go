@@ -0,0 +1,45 @@
+package execute
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+)
+
+func TestProfileAllocator(t *testing.T) {
+	limit := int64(64)
+	query := &memory.ResourceAllocator{Limit: &limit}
+	node := &profileAllocator{mem: query}
+
+	b := node.Allocate(32)
+	if err := node.Account(16); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int64(48), query.Allocated(); want != got {
+		t.Fatalf("unexpected query allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+
+	// The limit of the query applies to the node.
+	if err := node.Account(32); err == nil {
+		t.Fatal("expected error")
+	} else if want, got := codes.ResourceExhausted, errors.Code(err); want != got {
+		t.Fatalf("unexpected error code -want/+got\n\t- %v\n\t+ %v", want, got)
+	}
+
+	b = node.Reallocate(40, b)
+	if err := node.Account(-16); err != nil {
+		t.Fatal(err)
+	}
+	node.Free(b)
+	if want, got := int64(0), query.Allocated(); want != got {
+		t.Fatalf("unexpected query allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(56), node.MaxAllocated(); want != got {
+		t.Fatalf("unexpected max allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(56), node.TotalAllocated(); want != got {
+		t.Fatalf("unexpected total allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+}
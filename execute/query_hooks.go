@@ -0,0 +1,79 @@
+package execute
+
+import (
+	"context"
+	"sync"
+)
+
+const queryHooksKey key = executionDependenciesKey + 1
+
+// QueryFinishFunc is called once a query has finished. The error is nil
+// when the query completed and every result was read without an error.
+type QueryFinishFunc func(ctx context.Context, err error) error
+
+// QueryHooks holds the functions to call once a query has finished.
+//
+// Sources use them to defer work that must only happen after the whole
+// query has succeeded, such as acknowledging the messages that were read
+// from a queue, and to release resources when the query fails.
+type QueryHooks struct {
+	mu       sync.Mutex
+	fns      []QueryFinishFunc
+	finished bool
+	err      error
+}
+
+// WithQueryHooks associates the QueryHooks with the context.
+func WithQueryHooks(ctx context.Context, h *QueryHooks) context.Context {
+	return context.WithValue(ctx, queryHooksKey, h)
+}
+
+// OnQueryFinish registers fn to be called once the query has finished.
+// It returns false if the query does not support finish hooks and fn
+// will never be called.
+//
+// If the query has already finished, fn is called immediately with
+// the error of the query.
+func OnQueryFinish(ctx context.Context, fn QueryFinishFunc) bool {
+	h, _ := ctx.Value(queryHooksKey).(*QueryHooks)
+	if h == nil {
+		return false
+	}
+
+	h.mu.Lock()
+	if !h.finished {
+		h.fns = append(h.fns, fn)
+		h.mu.Unlock()
+		return true
+	}
+	err := h.err
+	h.mu.Unlock()
+
+	_ = fn(ctx, err)
+	return true
+}
+
+// Finish calls every registered function with the error of the query.
+// The functions are called in the order they were registered and only
+// for the first call to Finish. It returns the first error returned
+// by a function.
+func (h *QueryHooks) Finish(ctx context.Context, err error) error {
+	h.mu.Lock()
+	if h.finished {
+		h.mu.Unlock()
+		return nil
+	}
+	h.finished = true
+	h.err = err
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+
+	var ferr error
+	for _, fn := range fns {
+		if e := fn(ctx, err); e != nil && ferr == nil {
+			ferr = e
+		}
+	}
+	return ferr
+}
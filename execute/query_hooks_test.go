@@ -0,0 +1,45 @@
+package execute_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/influxdata/flux/execute"
+)
+
+func TestQueryHooks(t *testing.T) {
+	if execute.OnQueryFinish(context.Background(), func(ctx context.Context, err error) error {
+		return nil
+	}) {
+		t.Fatal("expected a context without hooks to not register the function")
+	}
+
+	hooks := new(execute.QueryHooks)
+	ctx := execute.WithQueryHooks(context.Background(), hooks)
+
+	var got []error
+	record := func(ctx context.Context, err error) error {
+		got = append(got, err)
+		return nil
+	}
+	if !execute.OnQueryFinish(ctx, record) {
+		t.Fatal("expected the function to be registered")
+	}
+
+	want := errors.New("expected error")
+	if err := hooks.Finish(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	// Only the first call to Finish calls the functions.
+	if err := hooks.Finish(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	// A function registered after the query has finished
+	// is called immediately with the error of the query.
+	execute.OnQueryFinish(ctx, record)
+
+	if len(got) != 2 || got[0] != want || got[1] != want {
+		t.Fatalf("unexpected errors: %v", got)
+	}
+}
@@ -27,6 +27,13 @@ var DefaultSelectorConfig = SelectorConfig{
 	Column: DefaultValueColLabel,
 }
 
+// PassThroughAttribute implements plan.PassThroughAttributer.
+// Selectors select rows from each table independently and keep its group key,
+// so they can run on partitions of the group keys.
+func (c SelectorConfig) PassThroughAttribute(attrKey string) bool {
+	return attrKey == plan.ParallelGroupRunKey
+}
+
 func (c *SelectorConfig) ReadArgs(args flux.Arguments) error {
 	if col, ok, err := args.GetString("column"); err != nil {
 		return err
@@ -46,6 +46,11 @@ var _ Transformation = (*consecutiveTransport)(nil)
 
 // consecutiveTransport implements Transport by transporting data consecutively to the downstream Transformation.
 type consecutiveTransport struct {
+	// rows is accessed with atomic operations and should be at the
+	// beginning of the struct to ensure byte alignment is correct.
+	// https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	rows int64
+
 	ctx        context.Context
 	dispatcher Dispatcher
 	logger     *zap.Logger
@@ -127,7 +132,9 @@ func (t *consecutiveTransport) Finished() <-chan struct{} {
 }
 
 func (t *consecutiveTransport) TransportProfile() flux.TransportProfile {
-	return t.profile
+	profile := t.profile
+	profile.Rows = atomic.LoadInt64(&t.rows)
+	return profile
 }
 
 func (t *consecutiveTransport) RetractTable(id DatasetID, key flux.GroupKey) error {
@@ -294,6 +301,17 @@ func (t *consecutiveTransport) processMessage(m Message) (finished bool, err err
 	span := t.profile.StartSpan()
 	defer span.Finish()
 
+	// Count the tables and rows that are received. The rows for
+	// a table are counted when the table is read.
+	switch m := m.(type) {
+	case ProcessMsg:
+		t.profile.Tables++
+	case ProcessChunkMsg:
+		atomic.AddInt64(&t.rows, int64(m.TableChunk().Len()))
+	case FlushKeyMsg:
+		t.profile.Tables++
+	}
+
 	if err := t.t.ProcessMessage(m); err != nil {
 		return false, err
 	}
@@ -552,6 +570,7 @@ func (t *consecutiveTransportTable) Cols() []flux.ColMeta {
 
 func (t *consecutiveTransportTable) Do(f func(flux.ColReader) error) error {
 	return t.tbl.Do(func(cr flux.ColReader) error {
+		atomic.AddInt64(&t.transport.rows, int64(cr.Len()))
 		if err := t.validate(cr); err != nil {
 			fields := []zap.Field{
 				zap.String("source", t.transport.sourceInfo()),
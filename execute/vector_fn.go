@@ -3,7 +3,9 @@ package execute
 import (
 	"context"
 
+	"github.com/apache/arrow/go/v7/arrow/memory"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/compiler"
 	"github.com/influxdata/flux/execute/table"
@@ -42,11 +44,74 @@ func (f *VectorMapPreparedFn) Type() semantic.MonoType {
 	return f.fn.Type()
 }
 
+type VectorPredicateFn struct {
+	dynamicFn
+}
+
+func NewVectorPredicateFn(fn *semantic.FunctionExpression, scope compiler.Scope) *VectorPredicateFn {
+	return &VectorPredicateFn{
+		dynamicFn: newDynamicFn(fn, scope),
+	}
+}
+
+func (f *VectorPredicateFn) Prepare(cols []flux.ColMeta) (*VectorPredicatePreparedFn, error) {
+	fn, err := f.prepare(cols, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	typ := fn.returnType()
+	if typ.Nature() != semantic.Vector {
+		return nil, errors.New(codes.Invalid, "vector predicate function does not evaluate to a vector of booleans")
+	}
+	if elemType, err := typ.ElemType(); err != nil {
+		return nil, err
+	} else if elemType.Nature() != semantic.Bool {
+		return nil, errors.New(codes.Invalid, "vector predicate function does not evaluate to a vector of booleans")
+	}
+	return &VectorPredicatePreparedFn{
+		vectorFn: vectorFn{preparedFn: fn},
+	}, nil
+}
+
+type VectorPredicatePreparedFn struct {
+	vectorFn
+}
+
+// Eval evaluates the predicate for every row of the chunk and returns
+// the results as a boolean mask. A null result does not match the row.
+func (f *VectorPredicatePreparedFn) Eval(ctx context.Context, chunk table.Chunk, mem memory.Allocator) (*array.Boolean, error) {
+	res, err := f.eval(ctx, chunk)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Release()
+
+	if res.IsNull() {
+		return array.BooleanRepeat(false, true, chunk.Len(), mem), nil
+	}
+	vec := res.Vector()
+	if vec.IsRepeat() {
+		v := vec.(*values.VectorRepeatValue).Value()
+		return array.BooleanRepeat(!v.IsNull() && v.Bool(), false, chunk.Len(), mem), nil
+	}
+	mask := vec.Arr().(*array.Boolean)
+	mask.Retain()
+	return mask, nil
+}
+
 type vectorFn struct {
 	preparedFn
 }
 
 func (f *vectorFn) Eval(ctx context.Context, chunk table.Chunk) (values.Object, error) {
+	res, err := f.eval(ctx, chunk)
+	if err != nil {
+		return nil, err
+	}
+	return res.Object(), nil
+}
+
+func (f *vectorFn) eval(ctx context.Context, chunk table.Chunk) (values.Value, error) {
 	for j, col := range chunk.Cols() {
 		arr := chunk.Values(j)
 		arr.Retain()
@@ -55,9 +120,5 @@ func (f *vectorFn) Eval(ctx context.Context, chunk table.Chunk) (values.Object,
 	}
 	defer f.arg0.Release()
 
-	res, err := f.fn.Eval(ctx, f.args)
-	if err != nil {
-		return nil, err
-	}
-	return res.Object(), nil
+	return f.fn.Eval(ctx, f.args)
 }
@@ -0,0 +1,250 @@
+package arrowio_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	stdarrow "github.com/apache/arrow/go/v7/arrow"
+	"github.com/apache/arrow/go/v7/arrow/ipc"
+	"github.com/apache/arrow/go/v7/parquet/file"
+	"github.com/apache/arrow/go/v7/parquet/pqarrow"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/internal/arrowio"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+)
+
+// bufferWriter writes to a buffer that does not need to be discarded.
+type bufferWriter struct {
+	arrowio.RecordWriter
+}
+
+func (w bufferWriter) Abort() error {
+	return w.Close()
+}
+
+func TestRoundTrip(t *testing.T) {
+	// The input tables are consumed when they are processed
+	// so a new copy is created for the input and for the output.
+	data := func() []*executetest.Table {
+		return []*executetest.Table{
+			{
+				KeyCols: []string{"_measurement", "host"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{"cpu", "a", execute.Time(1), 2.0},
+					{"cpu", "a", execute.Time(2), nil},
+					{"cpu", "a", execute.Time(3), 4.0},
+				},
+			},
+			{
+				KeyCols: []string{"_measurement", "host"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+					{Label: "ok", Type: flux.TBool},
+				},
+				Data: [][]interface{}{
+					{"cpu", "b", execute.Time(1), 5.0, true},
+					{"cpu", "b", execute.Time(2), 6.0, false},
+				},
+			},
+			{
+				KeyCols: []string{"_measurement"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_time", Type: flux.TTime},
+					{Label: "n", Type: flux.TInt},
+					{Label: "u", Type: flux.TUInt},
+				},
+				Data: [][]interface{}{
+					{"mem", execute.Time(1), int64(-7), uint64(7)},
+				},
+			},
+		}
+	}
+
+	cols := []flux.ColMeta{
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "host", Type: flux.TString},
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "ok", Type: flux.TBool},
+		{Label: "n", Type: flux.TInt},
+		{Label: "u", Type: flux.TUInt},
+	}
+	want := []*executetest.Table{
+		{
+			KeyCols: []string{"_measurement", "host"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{"cpu", "a", execute.Time(1), 2.0, nil, nil, nil},
+				{"cpu", "a", execute.Time(2), nil, nil, nil, nil},
+				{"cpu", "a", execute.Time(3), 4.0, nil, nil, nil},
+			},
+		},
+		{
+			KeyCols: []string{"_measurement", "host"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{"cpu", "b", execute.Time(1), 5.0, true, nil, nil},
+				{"cpu", "b", execute.Time(2), 6.0, false, nil, nil},
+			},
+		},
+		{
+			// The host column is part of the group key so a table
+			// without it is restored with a null host value.
+			KeyCols: []string{"_measurement", "host"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{"mem", nil, execute.Time(1), nil, nil, int64(-7), uint64(7)},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		create func(buf *bytes.Buffer) arrowio.CreateWriterFunc
+		read   func(t *testing.T, data []byte, mem memory.Allocator) arrowio.Reader
+	}{
+		{
+			name: "ipc",
+			create: func(buf *bytes.Buffer) arrowio.CreateWriterFunc {
+				return func(schema *stdarrow.Schema) (arrowio.Writer, error) {
+					return bufferWriter{ipc.NewWriter(buf, ipc.WithSchema(schema))}, nil
+				}
+			},
+			read: func(t *testing.T, data []byte, mem memory.Allocator) arrowio.Reader {
+				r, err := ipc.NewReader(bytes.NewReader(data), ipc.WithAllocator(mem))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return r
+			},
+		},
+		{
+			name: "parquet",
+			create: func(buf *bytes.Buffer) arrowio.CreateWriterFunc {
+				return func(schema *stdarrow.Schema) (arrowio.Writer, error) {
+					w, err := pqarrow.NewFileWriter(schema, buf, nil, pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()))
+					if err != nil {
+						return nil, err
+					}
+					return bufferWriter{w}, nil
+				}
+			},
+			read: func(t *testing.T, data []byte, mem memory.Allocator) arrowio.Reader {
+				pr, err := file.NewParquetReader(bytes.NewReader(data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				fr, err := pqarrow.NewFileReader(pr, pqarrow.ArrowReadProperties{BatchSize: 2}, mem)
+				if err != nil {
+					t.Fatal(err)
+				}
+				rr, err := fr.GetRecordReader(nil, nil, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return rr
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var tables []flux.Table
+			for _, tbl := range data() {
+				tables = append(tables, tbl)
+			}
+			executetest.ProcessTestHelper2(
+				t,
+				tables,
+				data(),
+				nil,
+				func(id execute.DatasetID, alloc memory.Allocator) (execute.Transformation, execute.Dataset) {
+					tx, d, err := arrowio.NewWriteTransformation(context.Background(), id, tc.create(&buf), alloc)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return tx, d
+				},
+			)
+
+			mem := &memory.ResourceAllocator{}
+			var got []*executetest.Table
+			r := tc.read(t, buf.Bytes(), mem)
+			if err := arrowio.ReadTables(r, mem, func(tbl flux.Table) error {
+				et, err := executetest.ConvertTable(tbl)
+				if err != nil {
+					return err
+				}
+				got = append(got, et)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			executetest.NormalizeTables(got)
+			executetest.NormalizeTables(want)
+			if !cmp.Equal(want, got) {
+				t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestWriteTransformation_Error(t *testing.T) {
+	wantErr := errors.New(codes.Internal, "expected error")
+	data := []flux.Table{
+		&executetest.Table{
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), 2.0},
+			},
+		},
+		&executetest.Table{
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Err: wantErr,
+		},
+	}
+
+	created := false
+	create := func(schema *stdarrow.Schema) (arrowio.Writer, error) {
+		created = true
+		return nil, errors.New(codes.Internal, "writer should not be created")
+	}
+	executetest.ProcessTestHelper2(
+		t,
+		data,
+		nil,
+		wantErr,
+		func(id execute.DatasetID, alloc memory.Allocator) (execute.Transformation, execute.Dataset) {
+			tx, d, err := arrowio.NewWriteTransformation(context.Background(), id, create, alloc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return tx, d
+		},
+	)
+	if created {
+		t.Fatal("expected the writer to not be created after an error")
+	}
+}
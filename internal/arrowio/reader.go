@@ -0,0 +1,173 @@
+// Package arrowio converts between flux tables and sequences of
+// arrow records so that columnar file formats can be read and
+// written without converting each row.
+package arrowio
+
+import (
+	"io"
+
+	stdarrow "github.com/apache/arrow/go/v7/arrow"
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/values"
+)
+
+// Reader reads a sequence of arrow records that share a schema.
+type Reader interface {
+	// Schema returns the schema shared by every record.
+	Schema() *stdarrow.Schema
+
+	// Read returns the next record or io.EOF when there are
+	// no more records. The record is owned by the Reader and
+	// is only valid until the next call to Read.
+	Read() (stdarrow.Record, error)
+}
+
+// ReadTables reads every record from the Reader and passes
+// the resulting tables to the function.
+//
+// Columns marked as part of the group key in the schema
+// determine which table a row belongs to. Rows with the same
+// group key are combined into a single table even when they
+// are spread across multiple records. If no column is marked
+// as part of the group key, a single table is produced.
+func ReadTables(r Reader, mem memory.Allocator, f func(tbl flux.Table) error) error {
+	cols, isKey, err := arrow.Columns(r.Schema())
+	if err != nil {
+		return err
+	}
+
+	var keyCols []flux.ColMeta
+	var keyIdx []int
+	for j, col := range cols {
+		if isKey[j] {
+			keyCols = append(keyCols, col)
+			keyIdx = append(keyIdx, j)
+		}
+	}
+
+	found := false
+	builders := execute.NewRandomAccessGroupLookup()
+	defer builders.Range(func(key flux.GroupKey, value interface{}) error {
+		value.(*table.BufferedBuilder).Release()
+		return nil
+	})
+
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		vs, err := arrow.RecordValues(rec, cols, mem)
+		if err != nil {
+			return err
+		}
+		buffer := &arrow.TableBuffer{
+			Columns: cols,
+			Values:  vs,
+		}
+		err = splitByKey(buffer, keyCols, keyIdx, func(cr *arrow.TableBuffer) error {
+			found = true
+			builder := builders.LookupOrCreate(cr.GroupKey, func() interface{} {
+				b := table.NewBufferedBuilder(cr.GroupKey, mem)
+				// Start with the full schema so that tables
+				// with no rows still report every column.
+				b.Columns = cols
+				return b
+			}).(*table.BufferedBuilder)
+			return builder.AppendBuffer(cr)
+		})
+		buffer.Release()
+		if err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		// There were no records, but the schema is still known
+		// so produce an empty table with the correct columns.
+		key := execute.NewGroupKey(nil, nil)
+		buffer := arrow.EmptyBuffer(key, cols)
+		return f(table.FromBuffer(&buffer))
+	}
+
+	return builders.Range(func(key flux.GroupKey, value interface{}) error {
+		builders.Delete(key)
+		b := value.(*table.BufferedBuilder)
+		tbl, err := b.Table()
+		if err != nil {
+			return err
+		}
+		return f(tbl)
+	})
+}
+
+// splitByKey partitions a buffer into runs of consecutive rows
+// that share the same group key and invokes the function with
+// each run.
+func splitByKey(buffer *arrow.TableBuffer, keyCols []flux.ColMeta, keyIdx []int, f func(cr *arrow.TableBuffer) error) error {
+	if buffer.Len() == 0 {
+		return nil
+	} else if len(keyIdx) == 0 {
+		buffer.GroupKey = execute.NewGroupKey(nil, nil)
+		return f(buffer)
+	}
+
+	start, n := 0, buffer.Len()
+	prev := keyValues(buffer, keyIdx, 0)
+	for i := 1; i <= n; i++ {
+		var next []values.Value
+		if i < n {
+			next = keyValues(buffer, keyIdx, i)
+			if valuesEqual(prev, next) {
+				continue
+			}
+		}
+
+		run := &arrow.TableBuffer{
+			GroupKey: execute.NewGroupKey(keyCols, prev),
+			Columns:  buffer.Columns,
+			Values:   make([]array.Array, len(buffer.Values)),
+		}
+		for j, vs := range buffer.Values {
+			run.Values[j] = arrow.Slice(vs, int64(start), int64(i))
+		}
+		err := f(run)
+		run.Release()
+		if err != nil {
+			return err
+		}
+		start, prev = i, next
+	}
+	return nil
+}
+
+func keyValues(cr flux.ColReader, keyIdx []int, i int) []values.Value {
+	vs := make([]values.Value, len(keyIdx))
+	for k, j := range keyIdx {
+		vs[k] = execute.ValueForRow(cr, i, j)
+	}
+	return vs
+}
+
+func valuesEqual(a, b []values.Value) bool {
+	for i := range a {
+		if a[i].IsNull() || b[i].IsNull() {
+			if a[i].IsNull() != b[i].IsNull() {
+				return false
+			}
+			continue
+		}
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,239 @@
+package arrowio
+
+import (
+	"context"
+	"io"
+
+	stdarrow "github.com/apache/arrow/go/v7/arrow"
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/dependencies/spill"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// RecordWriter writes arrow records to an output.
+type RecordWriter interface {
+	// Write writes the record. The record must match
+	// the schema the RecordWriter was created with.
+	Write(rec stdarrow.Record) error
+
+	// Close flushes any buffered data and closes the RecordWriter.
+	Close() error
+}
+
+// Writer is a RecordWriter whose output can be discarded.
+type Writer interface {
+	RecordWriter
+
+	// Abort closes the Writer and discards its output.
+	// It is called instead of Close when writing fails.
+	Abort() error
+}
+
+// CreateWriterFunc creates a Writer for records with the given schema.
+type CreateWriterFunc func(schema *stdarrow.Schema) (Writer, error)
+
+// NewFileWriter returns a Writer that writes records with w to the
+// file. The file is committed when the Writer is closed and aborted
+// when the Writer is aborted.
+func NewFileWriter(w RecordWriter, f *filesystem.AtomicFile) Writer {
+	return &fileWriter{w: w, f: f}
+}
+
+type fileWriter struct {
+	w RecordWriter
+	f *filesystem.AtomicFile
+}
+
+func (w *fileWriter) Write(rec stdarrow.Record) error {
+	return w.w.Write(rec)
+}
+
+func (w *fileWriter) Close() error {
+	if err := w.w.Close(); err != nil {
+		_ = w.f.Abort()
+		return err
+	}
+	return w.f.Close()
+}
+
+func (w *fileWriter) Abort() error {
+	_ = w.w.Close()
+	return w.f.Abort()
+}
+
+// NewWriteTransformation constructs a transformation that passes
+// its input through unchanged and writes every table to the
+// Writer returned by the CreateWriterFunc.
+//
+// A file only has a single schema so it cannot be written until the
+// input is finished. The tables are streamed to a temporary spill file
+// as they arrive instead of being held in memory. The schema is the
+// union of the columns from every table and each table is written as
+// its own record. Columns that are not present in a table are filled
+// with null values.
+//
+// The Writer is only created once the input finishes successfully.
+// If the input fails, nothing is written.
+func NewWriteTransformation(ctx context.Context, id execute.DatasetID, create CreateWriterFunc, mem memory.Allocator) (execute.Transformation, execute.Dataset, error) {
+	// Use the spill directory when one is configured.
+	c, _ := spill.Get(ctx)
+	t := &writeTransformation{
+		d:      execute.NewTransportDataset(id, mem),
+		create: create,
+		dir:    c.Dir,
+		mem:    mem,
+	}
+	return execute.NewTransformationFromTransport(t), t.d, nil
+}
+
+type writeTransformation struct {
+	d       *execute.TransportDataset
+	create  CreateWriterFunc
+	dir     string
+	mem     memory.Allocator
+	cols    []flux.ColMeta
+	keyCols []flux.ColMeta
+	file    *spill.File
+}
+
+func (t *writeTransformation) ProcessMessage(m execute.Message) error {
+	defer m.Ack()
+
+	switch m := m.(type) {
+	case execute.FinishMsg:
+		t.Finish(m.SrcDatasetID(), m.Error())
+		return nil
+	case execute.ProcessChunkMsg:
+		return t.processChunk(m.TableChunk())
+	case execute.FlushKeyMsg:
+		return t.d.FlushKey(m.Key())
+	case execute.ProcessMsg:
+		panic("unreachable")
+	}
+	return nil
+}
+
+// Finish is implemented to remain compatible with legacy upstreams.
+func (t *writeTransformation) Finish(id execute.DatasetID, err error) {
+	if err == nil {
+		err = t.write()
+	}
+	if t.file != nil {
+		if rerr := t.file.Remove(); err == nil {
+			err = rerr
+		}
+		t.file = nil
+	}
+	t.d.Finish(err)
+}
+
+func (t *writeTransformation) processChunk(chunk table.Chunk) error {
+	if err := t.mergeSchema(chunk.Key().Cols(), &t.keyCols); err != nil {
+		return err
+	}
+	if err := t.mergeSchema(chunk.Cols(), &t.cols); err != nil {
+		return err
+	}
+
+	cols := make([]flux.ColMeta, len(chunk.Cols()))
+	copy(cols, chunk.Cols())
+	if t.file == nil {
+		f, err := spill.NewFile(t.dir, cols, t.mem)
+		if err != nil {
+			return err
+		}
+		t.file = f
+	} else if !equalCols(t.file.Cols(), cols) {
+		if err := t.file.SetCols(cols); err != nil {
+			return err
+		}
+	}
+	buf := chunk.Buffer()
+	if err := t.file.Write(&buf); err != nil {
+		return err
+	}
+
+	chunk.Retain()
+	return t.d.Process(chunk)
+}
+
+// mergeSchema adds any new columns to the list of columns and
+// verifies that existing columns have the same type.
+func (t *writeTransformation) mergeSchema(cols []flux.ColMeta, into *[]flux.ColMeta) error {
+	for _, c := range cols {
+		idx := execute.ColIdx(c.Label, *into)
+		if idx < 0 {
+			*into = append(*into, c)
+		} else if ec := (*into)[idx]; ec.Type != c.Type {
+			return errors.Newf(codes.FailedPrecondition, "schema collision detected: column \"%s\" is both of type %s and %s", c.Label, c.Type, ec.Type)
+		}
+	}
+	return nil
+}
+
+// write creates the Writer and writes the tables from the spill file.
+// The output of the Writer is discarded if any write fails.
+func (t *writeTransformation) write() error {
+	schema, err := arrow.Schema(t.cols, t.keyCols)
+	if err != nil {
+		return err
+	}
+
+	w, err := t.create(schema)
+	if err != nil {
+		return err
+	}
+	if err := t.writeRecords(w, schema); err != nil {
+		_ = w.Abort()
+		return err
+	}
+	return w.Close()
+}
+
+func (t *writeTransformation) writeRecords(w Writer, schema *stdarrow.Schema) error {
+	if t.file == nil {
+		return nil
+	}
+	r, err := t.file.Open(execute.NewGroupKey(nil, nil))
+	if err != nil {
+		return err
+	}
+	defer r.Release()
+
+	for {
+		buf, err := r.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		rec, err := arrow.NewRecord(schema, buf, t.mem)
+		buf.Release()
+		if err != nil {
+			return err
+		}
+		err = w.Write(rec)
+		rec.Release()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func equalCols(a, b []flux.ColMeta) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+// Package deadletter implements the error modes of the functions
+// that write data to an external system.
+//
+// By default a sink aborts the query when it fails to write. In the
+// dead letter mode, a sink writes every row that it can and outputs
+// the rows that it failed to write instead of its input. Each failed
+// row has an additional column with the error so the failures can
+// be inspected or written somewhere else with yield.
+package deadletter
+
+import (
+	"sort"
+
+	"github.com/apache/arrow/go/v7/arrow/bitutil"
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/arrowutil"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+const (
+	// ModeAbort aborts the query on the first error.
+	ModeAbort = "abort"
+	// ModeDeadLetter outputs the rows that failed to be written.
+	ModeDeadLetter = "deadLetter"
+
+	// ErrorColLabel is the label of the column with the error
+	// for each row that failed to be written.
+	ErrorColLabel = "_error"
+)
+
+// ValidateMode returns an error if the mode is not a valid error mode.
+// An empty mode is valid and is the same as ModeAbort.
+func ValidateMode(mode string) error {
+	switch mode {
+	case "", ModeAbort, ModeDeadLetter:
+		return nil
+	default:
+		return errors.Newf(codes.Invalid, "invalid error mode %q, must be %q or %q", mode, ModeAbort, ModeDeadLetter)
+	}
+}
+
+// Cols returns the columns of the output for the columns of the input.
+func Cols(cols []flux.ColMeta) []flux.ColMeta {
+	out := make([]flux.ColMeta, 0, len(cols)+1)
+	out = append(out, cols...)
+	return append(out, flux.ColMeta{Label: ErrorColLabel, Type: flux.TString})
+}
+
+// Rows records the rows of a chunk of data that failed to be written.
+// Rows are identified by their index from the start of the data.
+type Rows struct {
+	rows []int
+	errs []error
+	all  error
+}
+
+// Add records that the row failed with the error.
+func (r *Rows) Add(row int, err error) {
+	r.rows = append(r.rows, row)
+	r.errs = append(r.errs, err)
+}
+
+// AddRange records that the rows from start up to stop failed with the error.
+func (r *Rows) AddRange(start, stop int, err error) {
+	for i := start; i < stop; i++ {
+		r.Add(i, err)
+	}
+}
+
+// AddAll records that every row failed with the error.
+// It is used when the data could not be written at all.
+func (r *Rows) AddAll(err error) {
+	r.all = err
+}
+
+// Empty reports whether no rows have failed.
+func (r *Rows) Empty() bool {
+	return r.all == nil && len(r.rows) == 0
+}
+
+// Reset forgets the failed rows so the value can be reused.
+func (r *Rows) Reset() {
+	r.rows, r.errs, r.all = r.rows[:0], r.errs[:0], nil
+}
+
+// errorAt returns the error for the row if it failed.
+// The rows must have been sorted.
+func (r *Rows) errorAt(row int) error {
+	if r.all != nil {
+		return r.all
+	}
+	i := sort.SearchInts(r.rows, row)
+	if i < len(r.rows) && r.rows[i] == row {
+		return r.errs[i]
+	}
+	return nil
+}
+
+func (r *Rows) sort() {
+	sort.Stable(byRow{r})
+}
+
+type byRow struct{ *Rows }
+
+func (r byRow) Len() int           { return len(r.rows) }
+func (r byRow) Less(i, j int) bool { return r.rows[i] < r.rows[j] }
+func (r byRow) Swap(i, j int) {
+	r.rows[i], r.rows[j] = r.rows[j], r.rows[i]
+	r.errs[i], r.errs[j] = r.errs[j], r.errs[i]
+}
+
+// AppendTo appends the failed rows of the column reader to the builder
+// with their error. The first row of the column reader has the index
+// offset. The builder must have the columns returned by Cols.
+func (r *Rows) AppendTo(cr flux.ColReader, offset int, b execute.TableBuilder) error {
+	r.sort()
+	errIdx := len(cr.Cols())
+	for i, n := 0, cr.Len(); i < n; i++ {
+		err := r.errorAt(offset + i)
+		if err == nil {
+			continue
+		}
+		for j := 0; j < errIdx; j++ {
+			if err := b.AppendValue(j, execute.ValueForRow(cr, i, j)); err != nil {
+				return err
+			}
+		}
+		if err := b.AppendString(errIdx, err.Error()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Chunk returns a chunk with the failed rows of the chunk and their error.
+// The first row of the chunk has the index offset.
+func (r *Rows) Chunk(chunk table.Chunk, offset int, mem memory.Allocator) table.Chunk {
+	r.sort()
+	n := chunk.Len()
+	bitset := memory.NewResizableBuffer(mem)
+	bitset.Resize(n)
+	defer bitset.Release()
+
+	errs := array.NewStringBuilder(mem)
+	for i := 0; i < n; i++ {
+		err := r.errorAt(offset + i)
+		bitutil.SetBitTo(bitset.Buf(), i, err != nil)
+		if err != nil {
+			errs.Append(err.Error())
+		}
+	}
+
+	buffer := arrow.TableBuffer{
+		GroupKey: chunk.Key(),
+		Columns:  Cols(chunk.Cols()),
+		Values:   make([]array.Array, 0, chunk.NCols()+1),
+	}
+	for j := 0; j < chunk.NCols(); j++ {
+		buffer.Values = append(buffer.Values, arrowutil.Filter(chunk.Values(j), bitset.Bytes(), mem))
+	}
+	buffer.Values = append(buffer.Values, errs.NewArray())
+	return table.ChunkFromBuffer(buffer)
+}
@@ -0,0 +1,89 @@
+package deadletter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/execute/table/static"
+	"github.com/influxdata/flux/internal/execute/deadletter"
+	"github.com/influxdata/flux/memory"
+)
+
+func input(t *testing.T) flux.Table {
+	t.Helper()
+	return static.Table{
+		static.StringKey("_measurement", "m0"),
+		static.Ints("_value", 1, 2, 3, 4),
+	}.Table(memory.DefaultAllocator)
+}
+
+func TestValidateMode(t *testing.T) {
+	for _, mode := range []string{"", deadletter.ModeAbort, deadletter.ModeDeadLetter} {
+		if err := deadletter.ValidateMode(mode); err != nil {
+			t.Errorf("unexpected error for mode %q: %s", mode, err)
+		}
+	}
+	if err := deadletter.ValidateMode("ignore"); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+}
+
+func TestRows_Chunk(t *testing.T) {
+	var rows deadletter.Rows
+	// Rows may be added out of order.
+	rows.Add(3, errors.New("bad row"))
+	rows.AddRange(0, 2, errors.New("bad batch"))
+
+	var got table.Iterator
+	if err := input(t).Do(func(cr flux.ColReader) error {
+		chunk := rows.Chunk(table.ChunkFromReader(cr), 0, memory.DefaultAllocator)
+		buf := chunk.Buffer()
+		got = append(got, table.FromBuffer(&buf))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := static.Table{
+		static.StringKey("_measurement", "m0"),
+		static.Ints("_value", 1, 2, 4),
+		static.Strings("_error", "bad batch", "bad batch", "bad row"),
+	}
+	if diff := table.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected diff -want/+got:\n%s", diff)
+	}
+}
+
+func TestRows_AppendTo(t *testing.T) {
+	var rows deadletter.Rows
+	rows.AddAll(errors.New("bad table"))
+
+	tbl := input(t)
+	builder := execute.NewColListTableBuilder(tbl.Key(), memory.DefaultAllocator)
+	for _, col := range deadletter.Cols(tbl.Cols()) {
+		if _, err := builder.AddCol(col); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		return rows.AppendTo(cr, 0, builder)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := builder.Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := static.Table{
+		static.StringKey("_measurement", "m0"),
+		static.Ints("_value", 1, 2, 3, 4),
+		static.Strings("_error", "bad table", "bad table", "bad table", "bad table"),
+	}
+	if diff := table.Diff(want, table.Iterator{got}); diff != "" {
+		t.Fatalf("unexpected diff -want/+got:\n%s", diff)
+	}
+}
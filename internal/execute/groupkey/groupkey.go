@@ -23,6 +23,16 @@ func New(cols []flux.ColMeta, values []values.Value) flux.GroupKey {
 	return newGroupKey(cols, values)
 }
 
+// Hash returns a hash of the group key.
+// Group keys that are equal have the same hash.
+func Hash(key flux.GroupKey) uint64 {
+	k, ok := key.(*groupKey)
+	if !ok {
+		k = newGroupKey(key.Cols(), key.Values())
+	}
+	return k.hash64()
+}
+
 func newGroupKey(cols []flux.ColMeta, values []values.Value) *groupKey {
 	sorted := make([]int, len(cols))
 	for i := range cols {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/execute/groupkey"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
 )
@@ -342,3 +343,34 @@ func TestGroupKey_Less(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupKey_Hash(t *testing.T) {
+	cols := []flux.ColMeta{
+		{Label: "a", Type: flux.TString},
+		{Label: "b", Type: flux.TString},
+	}
+	key := groupkey.New(cols, []values.Value{
+		values.NewString("b"),
+		values.NewString("c"),
+	})
+
+	// The hash does not depend on the order of the columns.
+	reordered := execute.NewGroupKey(
+		[]flux.ColMeta{cols[1], cols[0]},
+		[]values.Value{
+			values.NewString("c"),
+			values.NewString("b"),
+		},
+	)
+	if want, got := groupkey.Hash(key), groupkey.Hash(reordered); want != got {
+		t.Fatalf("expected equal group keys to have the same hash: %d != %d", want, got)
+	}
+
+	other := groupkey.New(cols, []values.Value{
+		values.NewString("b"),
+		values.Null,
+	})
+	if groupkey.Hash(key) == groupkey.Hash(other) {
+		t.Fatal("expected different group keys to have different hashes")
+	}
+}
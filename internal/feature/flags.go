@@ -197,6 +197,30 @@ func ExperimentalTestingDiff() BoolFlag {
 	return experimentalTestingDiff
 }
 
+var vectorizedFilter = feature.MakeBoolFlag(
+	"Vectorized Filter",
+	"vectorizedFilter",
+	"agent",
+	false,
+)
+
+// VectorizedFilter - Enables vectorized evaluation of filter predicates
+func VectorizedFilter() BoolFlag {
+	return vectorizedFilter
+}
+
+var queryParallelism = feature.MakeIntFlag(
+	"Query Parallelism",
+	"queryParallelism",
+	"agent",
+	0,
+)
+
+// QueryParallelism - Sets the maximum number of group key partitions the planner executes in parallel
+func QueryParallelism() IntFlag {
+	return queryParallelism
+}
+
 // Inject will inject the Flagger into the context.
 func Inject(ctx context.Context, flagger Flagger) context.Context {
 	return feature.Inject(ctx, flagger)
@@ -218,6 +242,8 @@ var all = []Flag{
 	unusedSymbolWarnings,
 	vectorizedConst,
 	experimentalTestingDiff,
+	vectorizedFilter,
+	queryParallelism,
 }
 
 var byKey = map[string]Flag{
@@ -236,6 +262,8 @@ var byKey = map[string]Flag{
 	"unusedSymbolWarnings":             unusedSymbolWarnings,
 	"vectorizedConst":                  vectorizedConst,
 	"experimentalTestingDiff":          experimentalTestingDiff,
+	"vectorizedFilter":                 vectorizedFilter,
+	"queryParallelism":                 queryParallelism,
 }
 
 // Flags returns all feature flags.
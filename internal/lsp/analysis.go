@@ -0,0 +1,432 @@
+package lsp
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/complete"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// document is a Flux script that is open in the client.
+type document struct {
+	uri     string
+	version int
+	text    string
+
+	// pkg is the semantic graph of the current text.
+	// It is nil if the text has errors.
+	pkg *semantic.Package
+
+	// defs are the definitions in the last text that was
+	// analyzed without errors. They are used for completion
+	// while the text is being edited and does not analyze.
+	defs []definition
+}
+
+// update analyzes the text of the document and returns the diagnostics.
+func (d *document) update(ctx context.Context, version int, text string) []Diagnostic {
+	d.version, d.text = version, text
+	pkg, err := runtime.AnalyzeSource(ctx, text)
+	if err != nil {
+		d.pkg = nil
+		return diagnostics(err)
+	}
+	d.pkg = pkg
+	d.defs = definitions(pkg)
+	return []Diagnostic{}
+}
+
+// errorPattern matches an error from libflux with its location.
+var errorPattern = regexp.MustCompile(`^error @(\d+):(\d+)-(\d+):(\d+): ((?s).*)$`)
+
+// diagnostics converts the errors from analyzing a script into diagnostics.
+// The errors are separated by blank lines and each one starts with its location.
+// An error without a location is reported at the start of the document.
+func diagnostics(err error) []Diagnostic {
+	var diags []Diagnostic
+	for _, msg := range strings.Split(err.Error(), "\n\n") {
+		msg = strings.TrimSpace(msg)
+		if msg == "" {
+			continue
+		}
+		diag := Diagnostic{
+			Severity: SeverityError,
+			Source:   "flux",
+			Message:  msg,
+		}
+		if m := errorPattern.FindStringSubmatch(msg); m != nil {
+			diag.Range = Range{
+				Start: Position{Line: atoi(m[1]) - 1, Character: atoi(m[2]) - 1},
+				End:   Position{Line: atoi(m[3]) - 1, Character: atoi(m[4]) - 1},
+			}
+			diag.Message = m[5]
+		}
+		diags = append(diags, diag)
+	}
+	return diags
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// toRange converts a source location, which has one-based
+// lines and columns, into a range in the document.
+func toRange(loc ast.SourceLocation) Range {
+	return Range{
+		Start: Position{Line: loc.Start.Line - 1, Character: loc.Start.Column - 1},
+		End:   Position{Line: loc.End.Line - 1, Character: loc.End.Column - 1},
+	}
+}
+
+// contains reports whether the position is within the source location.
+// The end of the location is included so that a position directly after
+// an identifier refers to that identifier.
+func contains(loc ast.SourceLocation, pos Position) bool {
+	line, col := pos.Line+1, pos.Character+1
+	if line < loc.Start.Line || (line == loc.Start.Line && col < loc.Start.Column) {
+		return false
+	}
+	if line > loc.End.Line || (line == loc.End.Line && col > loc.End.Column) {
+		return false
+	}
+	return true
+}
+
+// before reports whether position a comes before position b.
+func before(a, b ast.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+}
+
+// size orders source locations so the innermost node at a position can be found.
+func size(loc ast.SourceLocation) (int, int) {
+	return loc.End.Line - loc.Start.Line, loc.End.Column - loc.Start.Column
+}
+
+func smaller(a, b ast.SourceLocation) bool {
+	al, ac := size(a)
+	bl, bc := size(b)
+	return al < bl || (al == bl && ac < bc)
+}
+
+// definition is an identifier that is bound to a value in a script.
+type definition struct {
+	name string
+	loc  ast.SourceLocation
+	// scope is the location of the function that the identifier
+	// is defined in. It is the zero location at the top level.
+	scope ast.SourceLocation
+	// param is true for function parameters which are
+	// visible in the entire function.
+	param bool
+	// imported is the package path of an import.
+	imported string
+	typ      string
+}
+
+func (d definition) inScope(pos Position) bool {
+	return d.scope == (ast.SourceLocation{}) || contains(d.scope, pos)
+}
+
+// definitionVisitor collects the definitions in a semantic graph.
+type definitionVisitor struct {
+	defs  *[]definition
+	scope ast.SourceLocation
+}
+
+func (v definitionVisitor) Visit(node semantic.Node) semantic.Visitor {
+	switch n := node.(type) {
+	case *semantic.FunctionExpression:
+		return definitionVisitor{defs: v.defs, scope: n.Location()}
+	case *semantic.NativeVariableAssignment:
+		def := definition{
+			name:  n.Identifier.Name.Name(),
+			loc:   n.Identifier.Location(),
+			scope: v.scope,
+		}
+		if !n.Typ.IsNil() {
+			def.typ = n.Typ.CanonicalString()
+		} else if n.Init != nil {
+			def.typ = typeString(n.Init.TypeOf())
+		}
+		*v.defs = append(*v.defs, def)
+	case *semantic.FunctionParameter:
+		*v.defs = append(*v.defs, definition{
+			name:  n.Key.Name.Name(),
+			loc:   n.Key.Location(),
+			scope: v.scope,
+			param: true,
+		})
+	case *semantic.ImportDeclaration:
+		def := definition{
+			name:     path.Base(n.Path.Value),
+			loc:      n.Location(),
+			imported: n.Path.Value,
+		}
+		if n.As != nil && n.As.Name.Name() != "" {
+			def.name = n.As.Name.Name()
+		}
+		*v.defs = append(*v.defs, def)
+	}
+	return v
+}
+
+func (v definitionVisitor) Done(node semantic.Node) {}
+
+func definitions(pkg *semantic.Package) []definition {
+	var defs []definition
+	semantic.Walk(definitionVisitor{defs: &defs}, pkg)
+	return defs
+}
+
+// resolve returns the definition that the name refers to at the position.
+// Variables are visible after they are defined and parameters are visible
+// in the entire function. The innermost definition wins.
+func resolve(defs []definition, name string, pos ast.Position) (definition, bool) {
+	var (
+		found definition
+		ok    bool
+	)
+	lpos := Position{Line: pos.Line - 1, Character: pos.Column - 1}
+	for _, def := range defs {
+		if def.name != name || !def.inScope(lpos) {
+			continue
+		}
+		if !def.param && !before(def.loc.Start, pos) {
+			continue
+		}
+		if !ok || innermost(def, found) {
+			found, ok = def, true
+		}
+	}
+	return found, ok
+}
+
+// innermost reports whether the definition shadows the other definition.
+// Both definitions must be in scope at the same position.
+func innermost(def, other definition) bool {
+	if def.scope == other.scope {
+		return before(other.loc.Start, def.loc.Start)
+	} else if def.scope == (ast.SourceLocation{}) {
+		return false
+	} else if other.scope == (ast.SourceLocation{}) {
+		return true
+	}
+	return smaller(def.scope, other.scope)
+}
+
+// nodeAt returns the innermost node at the position that has a type
+// or refers to a definition.
+func nodeAt(pkg *semantic.Package, pos Position) semantic.Node {
+	var found semantic.Node
+	semantic.Walk(semantic.CreateVisitor(func(node semantic.Node) {
+		switch node.(type) {
+		case *semantic.IdentifierExpression, *semantic.MemberExpression, *semantic.Identifier:
+		default:
+			return
+		}
+		loc := node.Location()
+		if !contains(loc, pos) {
+			return
+		}
+		if found == nil || smaller(loc, found.Location()) {
+			found = node
+		}
+	}), pkg)
+	return found
+}
+
+// hover describes the type of the identifier at the position.
+func (d *document) hover(pos Position) *Hover {
+	if d.pkg == nil {
+		return nil
+	}
+	var name, typ string
+	switch n := nodeAt(d.pkg, pos).(type) {
+	case *semantic.IdentifierExpression:
+		name, typ = n.Name.Name(), typeString(n.TypeOf())
+	case *semantic.MemberExpression:
+		name, typ = n.Property.Name(), typeString(n.TypeOf())
+	case *semantic.Identifier:
+		for _, def := range d.defs {
+			if def.loc == n.Location() {
+				name, typ = def.name, def.typ
+				break
+			}
+		}
+	default:
+		return nil
+	}
+	if typ == "" {
+		return nil
+	}
+	return &Hover{
+		Contents: MarkupContent{
+			Kind:  "markdown",
+			Value: "```flux\n" + name + ": " + typ + "\n```",
+		},
+	}
+}
+
+// definition returns the location where the identifier at the position is defined.
+func (d *document) definition(pos Position) *Location {
+	if d.pkg == nil {
+		return nil
+	}
+	var (
+		name string
+		at   ast.Position
+	)
+	switch n := nodeAt(d.pkg, pos).(type) {
+	case *semantic.IdentifierExpression:
+		name, at = n.Name.Name(), n.Location().Start
+	case *semantic.MemberExpression:
+		// The property of a package is defined in the standard library,
+		// so go to the import of the package instead.
+		id, ok := n.Object.(*semantic.IdentifierExpression)
+		if !ok {
+			return nil
+		}
+		name, at = id.Name.Name(), id.Location().Start
+	default:
+		return nil
+	}
+	def, ok := resolve(d.defs, name, at)
+	if !ok {
+		return nil
+	}
+	return &Location{URI: d.uri, Range: toRange(def.loc)}
+}
+
+// identPrefix returns the identifier being typed before the position and,
+// if it follows a period, the identifier before the period.
+func identPrefix(text string, pos Position) (object, prefix string) {
+	lines := strings.Split(text, "\n")
+	if pos.Line < 0 || pos.Line >= len(lines) {
+		return "", ""
+	}
+	line := lines[pos.Line]
+	if pos.Character < len(line) {
+		line = line[:pos.Character]
+	}
+	start := identStart(line, len(line))
+	prefix = line[start:]
+	if start > 0 && line[start-1] == '.' {
+		ostart := identStart(line, start-1)
+		object = line[ostart : start-1]
+	}
+	return object, prefix
+}
+
+func identStart(line string, end int) int {
+	i := end
+	for i > 0 && isIdentChar(line[i-1]) {
+		i--
+	}
+	return i
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// importPattern matches an import in a script that may not analyze.
+var importPattern = regexp.MustCompile(`(?m)^\s*import\s+(?:([A-Za-z_][A-Za-z0-9_]*)\s+)?"([^"]+)"`)
+
+// imports returns the packages imported by the text by their name in the script.
+func imports(text string) map[string]string {
+	pkgs := make(map[string]string)
+	for _, m := range importPattern.FindAllStringSubmatch(text, -1) {
+		name := m[1]
+		if name == "" {
+			name = path.Base(m[2])
+		}
+		pkgs[name] = m[2]
+	}
+	return pkgs
+}
+
+// completion suggests the identifiers that can be used at the position.
+// After the name of an imported package, the members of the package are
+// suggested. Otherwise the suggestions are the identifiers defined in the
+// script, the imported packages and the values in the prelude.
+func (d *document) completion(pos Position) *CompletionList {
+	object, prefix := identPrefix(d.text, pos)
+	var items []CompletionItem
+	seen := make(map[string]bool)
+	add := func(item CompletionItem) {
+		if seen[item.Label] || !strings.HasPrefix(item.Label, prefix) {
+			return
+		}
+		seen[item.Label] = true
+		items = append(items, item)
+	}
+
+	if object != "" {
+		if pkgpath, ok := imports(d.text)[object]; ok {
+			if pkg, err := runtime.StdLib().ImportPackageObject(pkgpath); err == nil {
+				pkg.Range(func(name string, v values.Value) {
+					add(valueItem(name, v))
+				})
+			}
+		}
+	} else {
+		for _, def := range d.defs {
+			if def.imported != "" {
+				continue
+			}
+			if d.pkg != nil && !def.inScope(pos) {
+				continue
+			}
+			add(CompletionItem{Label: def.name, Kind: VariableCompletion, Detail: def.typ})
+		}
+		for name, pkgpath := range imports(d.text) {
+			add(CompletionItem{Label: name, Kind: ModuleCompletion, Detail: pkgpath})
+		}
+		c := complete.NewCompleter(runtime.Prelude())
+		for _, name := range c.Names() {
+			if v, err := c.Value(name); err == nil {
+				add(valueItem(name, v))
+			}
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+	if items == nil {
+		items = []CompletionItem{}
+	}
+	return &CompletionList{Items: items}
+}
+
+func valueItem(name string, v values.Value) CompletionItem {
+	item := CompletionItem{
+		Label:  name,
+		Kind:   VariableCompletion,
+		Detail: typeString(v.Type()),
+	}
+	if v.Type().Nature() == semantic.Function {
+		item.Kind = FunctionCompletion
+	}
+	return item
+}
+
+func typeString(typ semantic.MonoType) string {
+	if typ.Kind() == semantic.Unknown {
+		return ""
+	}
+	return typ.CanonicalString()
+}
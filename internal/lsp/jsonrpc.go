@@ -0,0 +1,133 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// JSON-RPC error codes used by the server.
+const (
+	codeParseError           = -32700
+	codeInvalidRequest       = -32600
+	codeMethodNotFound       = -32601
+	codeInvalidParams        = -32602
+	codeInternalError        = -32603
+	codeServerNotInitialized = -32002
+)
+
+// message is a JSON-RPC 2.0 request, response or notification.
+// A request has an ID and a method, a notification has only a method
+// and a response has only an ID.
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *responseError) Error() string {
+	return e.Message
+}
+
+// conn reads and writes messages with the base protocol of the
+// Language Server Protocol where each message has a header with
+// the length of the content.
+type conn struct {
+	r *bufio.Reader
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		r: bufio.NewReader(r),
+		w: w,
+	}
+}
+
+// read returns the next message. It returns io.EOF
+// when the input is closed between messages.
+func (c *conn) read() (*message, error) {
+	header, err := textproto.NewReader(c.r).ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, errors.Wrap(err, codes.Invalid, "invalid message header")
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(header.Get("Content-Length")))
+	if err != nil || length < 0 {
+		return nil, errors.Newf(codes.Invalid, "invalid Content-Length header %q", header.Get("Content-Length"))
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(c.r, content); err != nil {
+		return nil, err
+	}
+	var msg message
+	if err := json.Unmarshal(content, &msg); err != nil {
+		return nil, &responseError{Code: codeParseError, Message: err.Error()}
+	}
+	return &msg, nil
+}
+
+// write sends the message to the client.
+func (c *conn) write(msg *message) error {
+	msg.JSONRPC = "2.0"
+	content, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(content)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(content)
+	return err
+}
+
+// reply sends the response to the request with the id.
+func (c *conn) reply(id *json.RawMessage, result interface{}, err error) error {
+	msg := &message{ID: id}
+	if err != nil {
+		rerr, ok := err.(*responseError)
+		if !ok {
+			rerr = &responseError{Code: codeInternalError, Message: err.Error()}
+		}
+		msg.Error = rerr
+	} else if result == nil {
+		// A successful response must have a result even if it is null.
+		msg.Result = json.RawMessage("null")
+	} else {
+		msg.Result = result
+	}
+	return c.write(msg)
+}
+
+// notify sends a notification to the client.
+func (c *conn) notify(method string, params interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.write(&message{Method: method, Params: data})
+}
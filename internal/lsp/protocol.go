@@ -0,0 +1,160 @@
+package lsp
+
+// The types in this file are the subset of the Language Server
+// Protocol that the server implements. Field names follow the
+// specification so the types encode to the expected JSON.
+
+// Position is a zero-based line and character offset in a document.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a span of a document between two positions.
+// The end position is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a range within a document.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// DiagnosticSeverity is the severity of a diagnostic.
+type DiagnosticSeverity int
+
+const (
+	SeverityError   DiagnosticSeverity = 1
+	SeverityWarning DiagnosticSeverity = 2
+)
+
+// Diagnostic is a problem that was found in a document.
+type Diagnostic struct {
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Source   string             `json:"source"`
+	Message  string             `json:"message"`
+}
+
+// PublishDiagnosticsParams are sent with the
+// textDocument/publishDiagnostics notification.
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Version     int          `json:"version,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// TextDocumentSyncKind defines how the client sends document changes.
+type TextDocumentSyncKind int
+
+// SyncFull means the client sends the full text of the document on each change.
+const SyncFull TextDocumentSyncKind = 1
+
+// InitializeResult is the response to the initialize request.
+type InitializeResult struct {
+	Capabilities ServerCapabilities `json:"capabilities"`
+	ServerInfo   ServerInfo         `json:"serverInfo"`
+}
+
+// ServerCapabilities are the features that the server provides.
+type ServerCapabilities struct {
+	TextDocumentSync   TextDocumentSyncKind `json:"textDocumentSync"`
+	CompletionProvider *CompletionOptions   `json:"completionProvider,omitempty"`
+	HoverProvider      bool                 `json:"hoverProvider"`
+	DefinitionProvider bool                 `json:"definitionProvider"`
+}
+
+// CompletionOptions describes how completion is triggered.
+type CompletionOptions struct {
+	TriggerCharacters []string `json:"triggerCharacters,omitempty"`
+}
+
+// ServerInfo identifies the server to the client.
+type ServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// TextDocumentIdentifier identifies a document by its URI.
+type TextDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+// TextDocumentItem is a document that was opened by the client.
+type TextDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+// VersionedTextDocumentIdentifier identifies a version of a document.
+type VersionedTextDocumentIdentifier struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+}
+
+// DidOpenTextDocumentParams are sent with the textDocument/didOpen notification.
+type DidOpenTextDocumentParams struct {
+	TextDocument TextDocumentItem `json:"textDocument"`
+}
+
+// DidChangeTextDocumentParams are sent with the textDocument/didChange notification.
+type DidChangeTextDocumentParams struct {
+	TextDocument   VersionedTextDocumentIdentifier  `json:"textDocument"`
+	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
+}
+
+// TextDocumentContentChangeEvent is a change to a document.
+// Only full document changes are supported.
+type TextDocumentContentChangeEvent struct {
+	Text string `json:"text"`
+}
+
+// DidCloseTextDocumentParams are sent with the textDocument/didClose notification.
+type DidCloseTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// TextDocumentPositionParams identify a position in a document.
+type TextDocumentPositionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// CompletionItemKind is the kind of a completion item.
+type CompletionItemKind int
+
+const (
+	FunctionCompletion CompletionItemKind = 3
+	VariableCompletion CompletionItemKind = 6
+	ModuleCompletion   CompletionItemKind = 9
+)
+
+// CompletionItem is a suggestion for completion.
+type CompletionItem struct {
+	Label  string             `json:"label"`
+	Kind   CompletionItemKind `json:"kind,omitempty"`
+	Detail string             `json:"detail,omitempty"`
+}
+
+// CompletionList is the response to the textDocument/completion request.
+type CompletionList struct {
+	IsIncomplete bool             `json:"isIncomplete"`
+	Items        []CompletionItem `json:"items"`
+}
+
+// MarkupContent is text that the client renders in a hover.
+type MarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// Hover is the response to the textDocument/hover request.
+type Hover struct {
+	Contents MarkupContent `json:"contents"`
+	Range    *Range        `json:"range,omitempty"`
+}
@@ -0,0 +1,209 @@
+// Package lsp implements a Language Server Protocol server for Flux.
+//
+// The server reads requests from the client and writes responses on
+// a stream, which is normally the standard input and output of the
+// process started by the editor. Documents are analyzed with libflux
+// to publish diagnostics and the semantic graph is used for hover
+// and go to definition.
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// Serve runs the server on the stream until the client
+// sends the exit notification or closes the stream.
+//
+// The Flux runtime must be initialized before the server is run.
+func Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s := &server{
+		ctx:  ctx,
+		conn: newConn(r, w),
+		docs: make(map[string]*document),
+	}
+	return s.serve()
+}
+
+type server struct {
+	ctx  context.Context
+	conn *conn
+	docs map[string]*document
+
+	initialized bool
+	shutdown    bool
+}
+
+func (s *server) serve() error {
+	for {
+		msg, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		} else if rerr, ok := err.(*responseError); ok {
+			// The content could not be decoded so the id of the request is unknown.
+			if err := s.conn.reply(nil, nil, rerr); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New(codes.Aborted, "client exited without a shutdown request")
+			}
+			return nil
+		}
+
+		result, err := s.handle(msg)
+		if msg.ID == nil {
+			// Notifications do not have a response.
+			continue
+		}
+		if err := s.conn.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches the message to the handler for its method.
+func (s *server) handle(msg *message) (interface{}, error) {
+	switch {
+	case msg.Method == "initialize":
+		s.initialized = true
+		return s.initialize(), nil
+	case !s.initialized:
+		return nil, &responseError{Code: codeServerNotInitialized, Message: "server is not initialized"}
+	case s.shutdown:
+		return nil, &responseError{Code: codeInvalidRequest, Message: "server is shut down"}
+	}
+
+	switch msg.Method {
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didOpen(params)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didChange(params)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didClose(params)
+	case "textDocument/completion":
+		doc, pos, err := s.position(msg)
+		if err != nil {
+			return nil, err
+		}
+		return doc.completion(pos), nil
+	case "textDocument/hover":
+		doc, pos, err := s.position(msg)
+		if err != nil {
+			return nil, err
+		}
+		if h := doc.hover(pos); h != nil {
+			return h, nil
+		}
+		return nil, nil
+	case "textDocument/definition":
+		doc, pos, err := s.position(msg)
+		if err != nil {
+			return nil, err
+		}
+		if loc := doc.definition(pos); loc != nil {
+			return loc, nil
+		}
+		return nil, nil
+	default:
+		return nil, &responseError{Code: codeMethodNotFound, Message: "method not supported: " + msg.Method}
+	}
+}
+
+func decodeParams(msg *message, params interface{}) error {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *server) initialize() *InitializeResult {
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: SyncFull,
+			CompletionProvider: &CompletionOptions{
+				TriggerCharacters: []string{"."},
+			},
+			HoverProvider:      true,
+			DefinitionProvider: true,
+		},
+		ServerInfo: ServerInfo{
+			Name: "flux",
+		},
+	}
+}
+
+func (s *server) didOpen(params DidOpenTextDocumentParams) error {
+	doc := &document{uri: params.TextDocument.URI}
+	s.docs[doc.uri] = doc
+	return s.publish(doc, doc.update(s.ctx, params.TextDocument.Version, params.TextDocument.Text))
+}
+
+func (s *server) didChange(params DidChangeTextDocumentParams) error {
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return &responseError{Code: codeInvalidParams, Message: "document is not open: " + params.TextDocument.URI}
+	} else if len(params.ContentChanges) == 0 {
+		return nil
+	}
+	// The server only supports full synchronization so
+	// the last change has the entire text of the document.
+	text := params.ContentChanges[len(params.ContentChanges)-1].Text
+	return s.publish(doc, doc.update(s.ctx, params.TextDocument.Version, text))
+}
+
+func (s *server) didClose(params DidCloseTextDocumentParams) error {
+	delete(s.docs, params.TextDocument.URI)
+	// Clear the diagnostics of the closed document.
+	return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         params.TextDocument.URI,
+		Diagnostics: []Diagnostic{},
+	})
+}
+
+func (s *server) publish(doc *document, diags []Diagnostic) error {
+	return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     doc.version,
+		Diagnostics: diags,
+	})
+}
+
+// position decodes the document and position of a request.
+func (s *server) position(msg *message) (*document, Position, error) {
+	var params TextDocumentPositionParams
+	if err := decodeParams(msg, &params); err != nil {
+		return nil, Position{}, err
+	}
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return nil, Position{}, &responseError{Code: codeInvalidParams, Message: "document is not open: " + params.TextDocument.URI}
+	}
+	return doc, params.Position, nil
+}
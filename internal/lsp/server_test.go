@@ -0,0 +1,257 @@
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	_ "github.com/influxdata/flux/fluxinit/static"
+	"github.com/influxdata/flux/internal/lsp"
+)
+
+// session writes requests for the server and
+// reads the messages that the server wrote.
+type session struct {
+	in bytes.Buffer
+	id int
+}
+
+func (s *session) request(method string, params interface{}) int {
+	s.id++
+	s.write(map[string]interface{}{"jsonrpc": "2.0", "id": s.id, "method": method, "params": params})
+	return s.id
+}
+
+func (s *session) notify(method string, params interface{}) {
+	s.write(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (s *session) write(msg interface{}) {
+	data, _ := json.Marshal(msg)
+	fmt.Fprintf(&s.in, "Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+type response struct {
+	ID     int             `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+// run serves the requests and returns the messages from the server.
+func (s *session) run(t *testing.T) []response {
+	t.Helper()
+	var out bytes.Buffer
+	if err := lsp.Serve(context.Background(), &s.in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var msgs []response
+	r := bufio.NewReader(&out)
+	for {
+		header, err := textproto.NewReader(r).ReadMIMEHeader()
+		if err == io.EOF {
+			return msgs
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		n, _ := strconv.Atoi(header.Get("Content-Length"))
+		data := make([]byte, n)
+		if _, err := io.ReadFull(r, data); err != nil {
+			t.Fatal(err)
+		}
+		var msg response
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+func find(t *testing.T, msgs []response, id int) response {
+	t.Helper()
+	for _, msg := range msgs {
+		if msg.Method == "" && msg.ID == id {
+			return msg
+		}
+	}
+	t.Fatalf("no response for request %d", id)
+	return response{}
+}
+
+func diagnostics(t *testing.T, msgs []response) [][]lsp.Diagnostic {
+	t.Helper()
+	var diags [][]lsp.Diagnostic
+	for _, msg := range msgs {
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var params lsp.PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			t.Fatal(err)
+		}
+		diags = append(diags, params.Diagnostics)
+	}
+	return diags
+}
+
+const uri = "file:///query.flux"
+
+func open(s *session, text string) {
+	s.request("initialize", map[string]interface{}{})
+	s.notify("initialized", map[string]interface{}{})
+	s.notify("textDocument/didOpen", lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri, LanguageID: "flux", Version: 1, Text: text},
+	})
+}
+
+func position(line, character int) lsp.TextDocumentPositionParams {
+	return lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		Position:     lsp.Position{Line: line, Character: character},
+	}
+}
+
+func shutdown(s *session) {
+	s.request("shutdown", nil)
+	s.notify("exit", nil)
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	var s session
+	open(&s, "x = 10 + \"foo\"\n")
+	s.notify("textDocument/didChange", lsp.DidChangeTextDocumentParams{
+		TextDocument:   lsp.VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+		ContentChanges: []lsp.TextDocumentContentChangeEvent{{Text: "x = 1 + 2\n"}},
+	})
+	shutdown(&s)
+
+	diags := diagnostics(t, s.run(t))
+	if len(diags) != 2 {
+		t.Fatalf("unexpected number of diagnostics notifications: %d", len(diags))
+	}
+	if len(diags[0]) != 1 {
+		t.Fatalf("expected one diagnostic, got %v", diags[0])
+	}
+	if want, got := (lsp.Range{
+		Start: lsp.Position{Line: 0, Character: 9},
+		End:   lsp.Position{Line: 0, Character: 14},
+	}), diags[0][0].Range; !cmp.Equal(want, got) {
+		t.Errorf("unexpected diagnostic range -want/+got:\n%s", cmp.Diff(want, got))
+	}
+	if len(diags[1]) != 0 {
+		t.Errorf("expected the diagnostics to be cleared, got %v", diags[1])
+	}
+}
+
+func TestServer_HoverAndDefinition(t *testing.T) {
+	script := `f = (v) => v * 2
+y = f(v: 21)
+z = y + 1
+`
+	var s session
+	open(&s, script)
+	hover := s.request("textDocument/hover", position(2, 4))
+	def := s.request("textDocument/definition", position(2, 4))
+	param := s.request("textDocument/definition", position(0, 11))
+	shutdown(&s)
+	msgs := s.run(t)
+
+	var h lsp.Hover
+	if err := json.Unmarshal(find(t, msgs, hover).Result, &h); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(h.Contents.Value, "y: int") {
+		t.Errorf("unexpected hover: %q", h.Contents.Value)
+	}
+
+	var loc lsp.Location
+	if err := json.Unmarshal(find(t, msgs, def).Result, &loc); err != nil {
+		t.Fatal(err)
+	}
+	if want := (lsp.Location{
+		URI: uri,
+		Range: lsp.Range{
+			Start: lsp.Position{Line: 1, Character: 0},
+			End:   lsp.Position{Line: 1, Character: 1},
+		},
+	}); !cmp.Equal(want, loc) {
+		t.Errorf("unexpected definition -want/+got:\n%s", cmp.Diff(want, loc))
+	}
+
+	if err := json.Unmarshal(find(t, msgs, param).Result, &loc); err != nil {
+		t.Fatal(err)
+	}
+	if want := (lsp.Range{
+		Start: lsp.Position{Line: 0, Character: 5},
+		End:   lsp.Position{Line: 0, Character: 6},
+	}); !cmp.Equal(want, loc.Range) {
+		t.Errorf("unexpected parameter definition -want/+got:\n%s", cmp.Diff(want, loc.Range))
+	}
+}
+
+func TestServer_Completion(t *testing.T) {
+	script := `import "strings"
+
+total = 1
+strings.toU
+`
+	var s session
+	open(&s, script)
+	members := s.request("textDocument/completion", position(3, 11))
+	names := s.request("textDocument/completion", position(2, 2))
+	shutdown(&s)
+	msgs := s.run(t)
+
+	labels := func(id int) []string {
+		var list lsp.CompletionList
+		if err := json.Unmarshal(find(t, msgs, id).Result, &list); err != nil {
+			t.Fatal(err)
+		}
+		var labels []string
+		for _, item := range list.Items {
+			labels = append(labels, item.Label)
+		}
+		return labels
+	}
+
+	if want, got := []string{"toUpper"}, labels(members); !cmp.Equal(want, got) {
+		t.Errorf("unexpected package members -want/+got:\n%s", cmp.Diff(want, got))
+	}
+	// The script does not analyze, so the prelude is used for completion.
+	if got := labels(names); !contains(got, "toBool") || !contains(got, "today") {
+		t.Errorf("expected prelude functions in completion, got %v", got)
+	}
+}
+
+func TestServer_NotInitialized(t *testing.T) {
+	var s session
+	id := s.request("textDocument/hover", position(0, 0))
+	s.request("initialize", map[string]interface{}{})
+	shutdown(&s)
+
+	msg := find(t, s.run(t), id)
+	if msg.Error == nil || msg.Error.Code != -32002 {
+		t.Errorf("expected a server not initialized error, got %+v", msg)
+	}
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+// Package pubsub decodes the messages that are consumed from a
+// message broker, such as Kafka or MQTT, into tables.
+package pubsub
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	// DecoderJSON decodes each message as a JSON object
+	// or an array of JSON objects with a row for each object.
+	DecoderJSON = "json"
+	// DecoderLineProtocol decodes each message as line protocol
+	// with a table for each series and field.
+	DecoderLineProtocol = "lineProtocol"
+)
+
+// Message is a message that was consumed from a message broker.
+type Message struct {
+	// Value is the payload of the message.
+	Value []byte
+	// Time is the time of the message. It is used for the
+	// rows that do not have a time of their own.
+	Time time.Time
+}
+
+// ValidateDecoder returns an error if the decoder is not supported.
+func ValidateDecoder(decoder string) error {
+	switch decoder {
+	case DecoderJSON, DecoderLineProtocol:
+		return nil
+	default:
+		return errors.Newf(codes.Invalid, "invalid decoder %q, must be %q or %q", decoder, DecoderJSON, DecoderLineProtocol)
+	}
+}
+
+// Decode decodes the messages into tables with the decoder.
+func Decode(decoder string, msgs []Message, alloc memory.Allocator) (flux.TableIterator, error) {
+	switch decoder {
+	case DecoderJSON:
+		return decodeJSON(msgs, alloc)
+	case DecoderLineProtocol:
+		payloads := make([][]byte, len(msgs))
+		times := make([]time.Time, len(msgs))
+		for i, m := range msgs {
+			payloads[i], times[i] = m.Value, m.Time
+		}
+		dec := &line.ProtocolDecoder{Allocator: alloc}
+		res, err := dec.DecodeMessages(payloads, times)
+		if err != nil {
+			return nil, err
+		}
+		return res.Tables(), nil
+	default:
+		return nil, ValidateDecoder(decoder)
+	}
+}
+
+// decodeJSON decodes the messages into a single table with a column
+// for each key of the objects. Numbers are floats, nested objects and
+// arrays are strings with their JSON and a _time column is added with
+// the time of the message unless the objects have a _time key with an
+// RFC3339 timestamp.
+func decodeJSON(msgs []Message, alloc memory.Allocator) (flux.TableIterator, error) {
+	type row struct {
+		values map[string]interface{}
+		time   time.Time
+	}
+	var (
+		rows  []row
+		types = map[string]flux.ColType{
+			execute.DefaultTimeColLabel: flux.TTime,
+		}
+	)
+	for _, m := range msgs {
+		var v interface{}
+		if err := json.Unmarshal(m.Value, &v); err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "failed to decode json message")
+		}
+		var objects []interface{}
+		if arr, ok := v.([]interface{}); ok {
+			objects = arr
+		} else {
+			objects = []interface{}{v}
+		}
+		for _, o := range objects {
+			obj, ok := o.(map[string]interface{})
+			if !ok {
+				return nil, errors.New(codes.Invalid, "json message must be an object or an array of objects")
+			}
+			for k, v := range obj {
+				typ := jsonColType(v)
+				if k == execute.DefaultTimeColLabel {
+					if typ != flux.TString && typ != flux.TInvalid {
+						return nil, errors.Newf(codes.Invalid, "json key %q must be an RFC3339 timestamp", k)
+					}
+					continue
+				}
+				if typ == flux.TInvalid {
+					continue
+				} else if t, ok := types[k]; ok && t != typ {
+					return nil, errors.Newf(codes.Invalid, "json key %q has values of type %v and %v", k, t, typ)
+				}
+				types[k] = typ
+			}
+			rows = append(rows, row{values: obj, time: m.Time})
+		}
+	}
+	if len(rows) == 0 {
+		return table.Iterator{}, nil
+	}
+
+	cols := make([]flux.ColMeta, 0, len(types))
+	for label, typ := range types {
+		cols = append(cols, flux.ColMeta{Label: label, Type: typ})
+	}
+	sort.Slice(cols, func(i, j int) bool {
+		return cols[i].Label < cols[j].Label
+	})
+
+	b := execute.NewColListTableBuilder(execute.NewGroupKey(nil, nil), alloc)
+	for _, col := range cols {
+		if _, err := b.AddCol(col); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range rows {
+		for j, col := range cols {
+			v, err := jsonValue(col, r.values[col.Label], r.time)
+			if err != nil {
+				b.Release()
+				return nil, err
+			}
+			if err := b.AppendValue(j, v); err != nil {
+				b.Release()
+				return nil, err
+			}
+		}
+	}
+	tbl, err := b.Table()
+	if err != nil {
+		return nil, err
+	}
+	return table.Iterator{tbl}, nil
+}
+
+// jsonColType returns the column type for a decoded JSON value.
+// It returns TInvalid for null.
+func jsonColType(v interface{}) flux.ColType {
+	switch v.(type) {
+	case nil:
+		return flux.TInvalid
+	case float64:
+		return flux.TFloat
+	case bool:
+		return flux.TBool
+	default:
+		return flux.TString
+	}
+}
+
+// jsonValue converts a decoded JSON value to the value for the column.
+func jsonValue(col flux.ColMeta, v interface{}, t time.Time) (values.Value, error) {
+	if col.Label == execute.DefaultTimeColLabel {
+		if v == nil {
+			return values.NewTime(values.ConvertTime(t)), nil
+		}
+		ts, err := time.Parse(time.RFC3339Nano, v.(string))
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "json key %q must be an RFC3339 timestamp", col.Label)
+		}
+		return values.NewTime(values.ConvertTime(ts)), nil
+	}
+	switch v := v.(type) {
+	case nil:
+		return values.NewNull(flux.SemanticType(col.Type)), nil
+	case float64:
+		return values.NewFloat(v), nil
+	case bool:
+		return values.NewBool(v), nil
+	case string:
+		return values.NewString(v), nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "failed to encode json value")
+		}
+		return values.NewString(string(b)), nil
+	}
+}
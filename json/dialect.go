@@ -0,0 +1,58 @@
+package json
+
+import (
+	"net/http"
+
+	"github.com/influxdata/flux"
+)
+
+const (
+	DialectType       = "json"
+	NDJSONDialectType = "ndjson"
+)
+
+// AddDialectMappings adds the json and ndjson dialect mappings.
+func AddDialectMappings(mappings flux.DialectMappings) error {
+	if err := mappings.Add(DialectType, func() flux.Dialect {
+		return &Dialect{
+			ResultEncoderConfig: DefaultEncoderConfig(),
+		}
+	}); err != nil {
+		return err
+	}
+	return mappings.Add(NDJSONDialectType, func() flux.Dialect {
+		return &Dialect{
+			ResultEncoderConfig: ResultEncoderConfig{Format: NDJSON},
+		}
+	})
+}
+
+// Dialect describes the output format of queries in JSON.
+type Dialect struct {
+	ResultEncoderConfig
+}
+
+func (d Dialect) SetHeaders(w http.ResponseWriter) {
+	if d.Format == NDJSON {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	} else {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	w.Header().Set("Transfer-Encoding", "chunked")
+}
+
+func (d Dialect) Encoder() flux.MultiResultEncoder {
+	return NewMultiResultEncoder(d.ResultEncoderConfig)
+}
+func (d Dialect) DialectType() flux.DialectType {
+	if d.Format == NDJSON {
+		return NDJSONDialectType
+	}
+	return DialectType
+}
+
+func DefaultDialect() *Dialect {
+	return &Dialect{
+		ResultEncoderConfig: DefaultEncoderConfig(),
+	}
+}
@@ -0,0 +1,914 @@
+// Package json contains the JSON and NDJSON result encoders and decoders.
+//
+// A JSON document contains every result and the tables within them:
+//
+//	{"results":[{"name":"_result","tables":[{"group_key":{...},"columns":[...],"rows":[{...}]}]}]}
+//
+// With the Columns format, the rows are omitted and each column
+// holds a list of its values instead. If the query fails after the
+// encoding has started, the document ends with an "error" property.
+//
+// With the NDJSON format, each line is a JSON object. A table starts
+// with a line containing its group key and columns, and each row of
+// the table is written on a separate line after it:
+//
+//	{"result":"_result","table":0,"group_key":{...},"columns":[...]}
+//	{"result":"_result","table":0,"row":{...}}
+//
+// A query error is written as a line with only an "error" property.
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+)
+
+// Format is the layout of the encoded results.
+type Format int
+
+const (
+	// Rows encodes each table as a list of row objects.
+	Rows Format = iota
+	// Columns encodes each table as a list of columns with their values.
+	Columns
+	// NDJSON encodes each table and row as a separate line.
+	NDJSON
+)
+
+func (f Format) String() string {
+	switch f {
+	case Rows:
+		return "rows"
+	case Columns:
+		return "columns"
+	case NDJSON:
+		return "ndjson"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseFormat returns the Format with the given name.
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "rows":
+		return Rows, nil
+	case "columns":
+		return Columns, nil
+	case "ndjson":
+		return NDJSON, nil
+	default:
+		return 0, errors.Newf(codes.Invalid, "unknown json format %q", s)
+	}
+}
+
+// ResultEncoderConfig are options that can be specified on the ResultEncoder.
+type ResultEncoderConfig struct {
+	// Format is the layout of the encoded results.
+	Format Format
+}
+
+func (c ResultEncoderConfig) MarshalJSON() ([]byte, error) {
+	request := struct {
+		Format string `json:"format"`
+	}{
+		Format: c.Format.String(),
+	}
+	return json.Marshal(request)
+}
+
+func (c *ResultEncoderConfig) UnmarshalJSON(b []byte) error {
+	request := &struct {
+		Format string `json:"format"`
+	}{}
+	if err := json.Unmarshal(b, request); err != nil {
+		return err
+	}
+
+	c.Format = Rows
+	if request.Format != "" {
+		f, err := ParseFormat(request.Format)
+		if err != nil {
+			return err
+		}
+		c.Format = f
+	}
+	return nil
+}
+
+func DefaultEncoderConfig() ResultEncoderConfig {
+	return ResultEncoderConfig{
+		Format: Rows,
+	}
+}
+
+// column is the JSON representation of a column.
+// The values are only present with the Columns format.
+type column struct {
+	Label  string            `json:"label"`
+	Type   string            `json:"type"`
+	Group  bool              `json:"group"`
+	Values []json.RawMessage `json:"values,omitempty"`
+}
+
+type tableJSON struct {
+	GroupKey map[string]json.RawMessage   `json:"group_key"`
+	Columns  []column                     `json:"columns"`
+	Rows     []map[string]json.RawMessage `json:"rows,omitempty"`
+}
+
+type resultJSON struct {
+	Name   string      `json:"name"`
+	Tables []tableJSON `json:"tables"`
+}
+
+type document struct {
+	Results []resultJSON `json:"results"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// line is a single line of NDJSON.
+type line struct {
+	Result   string                     `json:"result"`
+	Table    int                        `json:"table"`
+	GroupKey map[string]json.RawMessage `json:"group_key"`
+	Columns  []column                   `json:"columns"`
+	Row      map[string]json.RawMessage `json:"row"`
+	Error    string                     `json:"error"`
+}
+
+// ResultEncoder encodes a single result as JSON.
+type ResultEncoder struct {
+	c ResultEncoderConfig
+}
+
+// NewResultEncoder creates a new encoder with the provided configuration.
+func NewResultEncoder(c ResultEncoderConfig) *ResultEncoder {
+	return &ResultEncoder{
+		c: c,
+	}
+}
+
+type jsonEncoderError struct {
+	err error
+}
+
+func (e *jsonEncoderError) Error() string {
+	return fmt.Sprintf("json encoder error: %s", e.err.Error())
+}
+
+func (e *jsonEncoderError) IsEncoderError() bool {
+	return true
+}
+
+func (e *jsonEncoderError) Unwrap() error {
+	return e.err
+}
+
+func newJSONEncoderError(err error) *jsonEncoderError {
+	return &jsonEncoderError{err: err}
+}
+
+func isEncoderError(err error) bool {
+	encErr, ok := err.(flux.EncoderError)
+	return ok && encErr.IsEncoderError()
+}
+
+func write(w io.Writer, buf []byte) error {
+	if _, err := w.Write(buf); err != nil {
+		return newJSONEncoderError(err)
+	}
+	return nil
+}
+
+// Encode writes the result to w. With the Rows and Columns formats,
+// the result is written as a single JSON object. With the NDJSON format,
+// the tables and rows of the result are written as separate lines.
+func (e *ResultEncoder) Encode(w io.Writer, result flux.Result) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+	var err error
+	if e.c.Format == NDJSON {
+		err = e.encodeLines(wc, result)
+	} else {
+		err = e.encodeObject(wc, result)
+	}
+	return wc.Count(), err
+}
+
+func (e *ResultEncoder) encodeObject(w io.Writer, result flux.Result) error {
+	buf := append([]byte(`{"name":`), appendString(nil, result.Name())...)
+	buf = append(buf, `,"tables":[`...)
+	if err := write(w, buf); err != nil {
+		return err
+	}
+
+	n := 0
+	err := result.Tables().Do(func(tbl flux.Table) error {
+		if n > 0 {
+			if err := write(w, []byte{','}); err != nil {
+				return err
+			}
+		}
+		n++
+		if e.c.Format == Columns {
+			return encodeColumns(w, tbl)
+		}
+		return encodeRows(w, tbl)
+	})
+	if isEncoderError(err) {
+		return err
+	}
+	// Close the result even if the query failed
+	// so that the document remains valid.
+	if werr := write(w, []byte("]}")); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// encodeRows writes the table with a row object for each row.
+// The table object is closed even if reading the table fails.
+func encodeRows(w io.Writer, tbl flux.Table) error {
+	buf := appendTableHeader(nil, tbl.Key(), tbl.Cols())
+	buf = append(buf, `],"rows":[`...)
+	if err := write(w, buf); err != nil {
+		return err
+	}
+
+	n := 0
+	err := tbl.Do(func(cr flux.ColReader) error {
+		buf = buf[:0]
+		for i := 0; i < cr.Len(); i++ {
+			if n > 0 {
+				buf = append(buf, ',')
+			}
+			n++
+			buf = appendRow(buf, cr, i)
+		}
+		return write(w, buf)
+	})
+	if isEncoderError(err) {
+		return err
+	}
+	if werr := write(w, []byte("]}")); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// encodeColumns writes the table with the values of each column.
+// The values are buffered until the entire table has been read.
+func encodeColumns(w io.Writer, tbl flux.Table) error {
+	cols := tbl.Cols()
+	vs := make([][]byte, len(cols))
+	err := tbl.Do(func(cr flux.ColReader) error {
+		for j := range cols {
+			for i := 0; i < cr.Len(); i++ {
+				if len(vs[j]) > 0 {
+					vs[j] = append(vs[j], ',')
+				}
+				vs[j] = appendColValue(vs[j], cr, j, i)
+			}
+		}
+		return nil
+	})
+
+	buf := appendGroupKey([]byte(`{"group_key":`), tbl.Key())
+	buf = append(buf, `,"columns":[`...)
+	for j, col := range cols {
+		if j > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendColumn(buf, col, tbl.Key().HasCol(col.Label))
+		buf = append(buf, `,"values":[`...)
+		buf = append(buf, vs[j]...)
+		buf = append(buf, "]}"...)
+	}
+	buf = append(buf, "]}"...)
+	if werr := write(w, buf); werr != nil {
+		return werr
+	}
+	return err
+}
+
+func (e *ResultEncoder) encodeLines(w io.Writer, result flux.Result) error {
+	name := appendString(nil, result.Name())
+	id := 0
+	return result.Tables().Do(func(tbl flux.Table) error {
+		prefix := append([]byte(`{"result":`), name...)
+		prefix = append(prefix, `,"table":`...)
+		prefix = strconv.AppendInt(prefix, int64(id), 10)
+		id++
+
+		buf := appendTableHeader(append([]byte(nil), prefix...), tbl.Key(), tbl.Cols())
+		buf = append(buf, "]}\n"...)
+		if err := write(w, buf); err != nil {
+			return err
+		}
+
+		return tbl.Do(func(cr flux.ColReader) error {
+			buf = buf[:0]
+			for i := 0; i < cr.Len(); i++ {
+				buf = append(buf, prefix...)
+				buf = append(buf, `,"row":`...)
+				buf = appendRow(buf, cr, i)
+				buf = append(buf, "}\n"...)
+			}
+			return write(w, buf)
+		})
+	})
+}
+
+// EncodeError writes the error as a JSON object with an "error" property.
+func (e *ResultEncoder) EncodeError(w io.Writer, err error) error {
+	buf := appendString([]byte(`{"error":`), err.Error())
+	buf = append(buf, "}\n"...)
+	return write(w, buf)
+}
+
+// appendTableHeader appends the group key and columns of a table.
+// The columns list is left open. When buf is not empty, the header
+// continues the object that buf already contains.
+func appendTableHeader(buf []byte, key flux.GroupKey, cols []flux.ColMeta) []byte {
+	if len(buf) == 0 {
+		buf = append(buf, '{')
+	} else {
+		buf = append(buf, ',')
+	}
+	buf = append(buf, `"group_key":`...)
+	buf = appendGroupKey(buf, key)
+	buf = append(buf, `,"columns":[`...)
+	for j, col := range cols {
+		if j > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendColumn(buf, col, key.HasCol(col.Label))
+		buf = append(buf, '}')
+	}
+	return buf
+}
+
+// appendColumn appends the metadata of a column
+// without closing the column object.
+func appendColumn(buf []byte, col flux.ColMeta, group bool) []byte {
+	buf = append(buf, `{"label":`...)
+	buf = appendString(buf, col.Label)
+	buf = append(buf, `,"type":`...)
+	buf = appendString(buf, col.Type.String())
+	buf = append(buf, `,"group":`...)
+	return strconv.AppendBool(buf, group)
+}
+
+func appendGroupKey(buf []byte, key flux.GroupKey) []byte {
+	buf = append(buf, '{')
+	for j, col := range key.Cols() {
+		if j > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendString(buf, col.Label)
+		buf = append(buf, ':')
+		buf = appendValue(buf, key.Value(j))
+	}
+	return append(buf, '}')
+}
+
+func appendRow(buf []byte, cr flux.ColReader, i int) []byte {
+	buf = append(buf, '{')
+	for j, col := range cr.Cols() {
+		if j > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendString(buf, col.Label)
+		buf = append(buf, ':')
+		buf = appendColValue(buf, cr, j, i)
+	}
+	return append(buf, '}')
+}
+
+func appendColValue(buf []byte, cr flux.ColReader, j, i int) []byte {
+	switch typ := cr.Cols()[j].Type; typ {
+	case flux.TBool:
+		vs := cr.Bools(j)
+		if vs.IsNull(i) {
+			break
+		}
+		return strconv.AppendBool(buf, vs.Value(i))
+	case flux.TInt:
+		vs := cr.Ints(j)
+		if vs.IsNull(i) {
+			break
+		}
+		return strconv.AppendInt(buf, vs.Value(i), 10)
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		if vs.IsNull(i) {
+			break
+		}
+		return strconv.AppendUint(buf, vs.Value(i), 10)
+	case flux.TFloat:
+		vs := cr.Floats(j)
+		if vs.IsNull(i) {
+			break
+		}
+		return appendFloat(buf, vs.Value(i))
+	case flux.TString:
+		vs := cr.Strings(j)
+		if vs.IsNull(i) {
+			break
+		}
+		return appendString(buf, vs.Value(i))
+	case flux.TTime:
+		vs := cr.Times(j)
+		if vs.IsNull(i) {
+			break
+		}
+		return appendTime(buf, values.Time(vs.Value(i)))
+	}
+	return append(buf, "null"...)
+}
+
+func appendValue(buf []byte, v values.Value) []byte {
+	if v.IsNull() {
+		return append(buf, "null"...)
+	}
+	switch flux.ColumnType(v.Type()) {
+	case flux.TBool:
+		return strconv.AppendBool(buf, v.Bool())
+	case flux.TInt:
+		return strconv.AppendInt(buf, v.Int(), 10)
+	case flux.TUInt:
+		return strconv.AppendUint(buf, v.UInt(), 10)
+	case flux.TFloat:
+		return appendFloat(buf, v.Float())
+	case flux.TString:
+		return appendString(buf, v.Str())
+	case flux.TTime:
+		return appendTime(buf, v.Time())
+	default:
+		return append(buf, "null"...)
+	}
+}
+
+// appendFloat appends a float as a JSON number. JSON cannot represent
+// NaN or infinity so those values are written as the strings
+// "NaN", "+Inf" and "-Inf".
+func appendFloat(buf []byte, f float64) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(buf, `"NaN"`...)
+	case math.IsInf(f, 1):
+		return append(buf, `"+Inf"`...)
+	case math.IsInf(f, -1):
+		return append(buf, `"-Inf"`...)
+	}
+	return strconv.AppendFloat(buf, f, 'g', -1, 64)
+}
+
+func appendTime(buf []byte, t values.Time) []byte {
+	buf = append(buf, '"')
+	buf = t.Time().UTC().AppendFormat(buf, time.RFC3339Nano)
+	return append(buf, '"')
+}
+
+const hex = "0123456789abcdef"
+
+// appendString appends s as a quoted JSON string.
+// Invalid UTF-8 is replaced with the Unicode replacement character.
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			switch c {
+			case '"', '\\':
+				buf = append(buf, '\\', c)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				if c < 0x20 {
+					buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+				} else {
+					buf = append(buf, c)
+				}
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, `�`...)
+		} else {
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(buf, '"')
+}
+
+type flusher interface {
+	Flush()
+}
+
+// MultiResultEncoder encodes multiple results as a single JSON document.
+//
+// If an error is encountered after the document has been started and the
+// error is not an encoder error, the error is written in the "error"
+// property of the document.
+type MultiResultEncoder struct {
+	Encoder *ResultEncoder
+}
+
+// NewMultiResultEncoder creates an encoder for multiple results. The NDJSON
+// format writes the lines of each result one after the other.
+func NewMultiResultEncoder(c ResultEncoderConfig) flux.MultiResultEncoder {
+	if c.Format == NDJSON {
+		return &flux.DelimitedMultiResultEncoder{
+			Encoder: NewResultEncoder(c),
+		}
+	}
+	return &MultiResultEncoder{
+		Encoder: NewResultEncoder(c),
+	}
+}
+
+func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+
+	n := 0
+	var err error
+	for err == nil && results.More() {
+		sep := []byte{','}
+		if n == 0 {
+			sep = []byte(`{"results":[`)
+		}
+		if err := write(wc, sep); err != nil {
+			return wc.Count(), err
+		}
+		n++
+		_, err = e.Encoder.Encode(wc, results.Next())
+		if f, ok := w.(flusher); ok {
+			f.Flush()
+		}
+	}
+	results.Release()
+	if err == nil {
+		err = results.Err()
+	}
+
+	if err != nil && (isEncoderError(err) || wc.Count() == 0) {
+		return wc.Count(), err
+	}
+
+	buf := []byte(`]`)
+	if n == 0 {
+		buf = []byte(`{"results":[]`)
+	}
+	if err != nil {
+		buf = append(buf, `,"error":`...)
+		buf = appendString(buf, err.Error())
+	}
+	buf = append(buf, "}\n"...)
+	return wc.Count(), write(wc, buf)
+}
+
+// ResultDecoderConfig are options that can be specified on the MultiResultDecoder.
+type ResultDecoderConfig struct {
+	// Format is the layout of the encoded results.
+	Format Format
+	// Allocator is the memory allocator that will be used during decoding.
+	// The default is to use an unlimited allocator when this is not set.
+	Allocator memory.Allocator
+}
+
+// MultiResultDecoder decodes results that were encoded
+// by the MultiResultEncoder with the same format.
+//
+// The results are read into memory before they are returned.
+type MultiResultDecoder struct {
+	c ResultDecoderConfig
+}
+
+// NewMultiResultDecoder creates a new MultiResultDecoder.
+func NewMultiResultDecoder(c ResultDecoderConfig) *MultiResultDecoder {
+	return &MultiResultDecoder{
+		c: c,
+	}
+}
+
+func (d *MultiResultDecoder) Decode(r io.ReadCloser) (flux.ResultIterator, error) {
+	defer func() { _ = r.Close() }()
+
+	alloc := d.c.Allocator
+	if alloc == nil {
+		alloc = memory.DefaultAllocator
+	}
+
+	var (
+		results []flux.Result
+		err     error
+	)
+	if d.c.Format == NDJSON {
+		results, err = decodeLines(r, alloc)
+	} else {
+		results, err = decodeDocument(r, alloc)
+	}
+	if err != nil && len(results) == 0 {
+		return nil, err
+	}
+	return &resultIterator{
+		ResultIterator: flux.NewSliceResultIterator(results),
+		err:            err,
+	}, nil
+}
+
+// resultIterator reports the error that was
+// encoded with the results after the results.
+type resultIterator struct {
+	flux.ResultIterator
+	err error
+}
+
+func (r *resultIterator) Err() error {
+	return r.err
+}
+
+type result struct {
+	name   string
+	tables table.Iterator
+}
+
+func (r *result) Name() string {
+	return r.name
+}
+
+func (r *result) Tables() flux.TableIterator {
+	return r.tables
+}
+
+func decodeDocument(r io.Reader, alloc memory.Allocator) ([]flux.Result, error) {
+	var doc document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to decode json results")
+	}
+
+	results := make([]flux.Result, 0, len(doc.Results))
+	for _, res := range doc.Results {
+		tables := make(table.Iterator, 0, len(res.Tables))
+		for _, t := range res.Tables {
+			b, err := newTableBuilder(t.GroupKey, t.Columns, alloc)
+			if err != nil {
+				return nil, err
+			}
+			if len(t.Rows) > 0 {
+				for _, row := range t.Rows {
+					if err := appendRawRow(b, row); err != nil {
+						return nil, err
+					}
+				}
+			} else {
+				for j, col := range t.Columns {
+					for _, raw := range col.Values {
+						if err := appendRaw(b, j, raw); err != nil {
+							return nil, err
+						}
+					}
+				}
+			}
+			tbl, err := b.Table()
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, tbl)
+		}
+		results = append(results, &result{
+			name:   res.Name,
+			tables: tables,
+		})
+	}
+
+	if doc.Error != "" {
+		return results, errors.New(codes.Internal, doc.Error)
+	}
+	return results, nil
+}
+
+func decodeLines(r io.Reader, alloc memory.Allocator) ([]flux.Result, error) {
+	type tableKey struct {
+		result string
+		table  int
+	}
+	var (
+		results  []*result
+		builders = make(map[tableKey]*execute.ColListTableBuilder)
+		order    = make(map[string][]*execute.ColListTableBuilder)
+	)
+
+	dec := json.NewDecoder(r)
+	for {
+		var l line
+		if err := dec.Decode(&l); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "failed to decode ndjson results")
+		}
+		if l.Error != "" {
+			out, err := buildResults(results, order)
+			if err != nil {
+				return nil, err
+			}
+			return out, errors.New(codes.Internal, l.Error)
+		}
+
+		key := tableKey{result: l.Result, table: l.Table}
+		if l.Columns != nil {
+			b, err := newTableBuilder(l.GroupKey, l.Columns, alloc)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := order[l.Result]; !ok {
+				results = append(results, &result{name: l.Result})
+			}
+			builders[key] = b
+			order[l.Result] = append(order[l.Result], b)
+			continue
+		}
+
+		b, ok := builders[key]
+		if !ok {
+			return nil, errors.Newf(codes.Invalid, "row for table %d of result %q appears before the table columns", l.Table, l.Result)
+		}
+		if err := appendRawRow(b, l.Row); err != nil {
+			return nil, err
+		}
+	}
+	return buildResults(results, order)
+}
+
+func buildResults(results []*result, order map[string][]*execute.ColListTableBuilder) ([]flux.Result, error) {
+	out := make([]flux.Result, 0, len(results))
+	for _, res := range results {
+		for _, b := range order[res.name] {
+			tbl, err := b.Table()
+			if err != nil {
+				return nil, err
+			}
+			res.tables = append(res.tables, tbl)
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+func newTableBuilder(groupKey map[string]json.RawMessage, cols []column, alloc memory.Allocator) (*execute.ColListTableBuilder, error) {
+	meta := make([]flux.ColMeta, len(cols))
+	var (
+		keyCols   []flux.ColMeta
+		keyValues []values.Value
+	)
+	for j, col := range cols {
+		typ, err := parseType(col.Type)
+		if err != nil {
+			return nil, err
+		}
+		meta[j] = flux.ColMeta{Label: col.Label, Type: typ}
+		if !col.Group {
+			continue
+		}
+		v, err := parseValue(groupKey[col.Label], typ)
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Inherit, "invalid group key value for column %q", col.Label)
+		}
+		keyCols = append(keyCols, meta[j])
+		keyValues = append(keyValues, v)
+	}
+
+	b := execute.NewColListTableBuilder(execute.NewGroupKey(keyCols, keyValues), alloc)
+	for _, col := range meta {
+		if _, err := b.AddCol(col); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func appendRawRow(b *execute.ColListTableBuilder, row map[string]json.RawMessage) error {
+	for j, col := range b.Cols() {
+		if err := appendRaw(b, j, row[col.Label]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendRaw(b *execute.ColListTableBuilder, j int, raw json.RawMessage) error {
+	col := b.Cols()[j]
+	v, err := parseValue(raw, col.Type)
+	if err != nil {
+		return errors.Wrapf(err, codes.Inherit, "invalid value for column %q", col.Label)
+	}
+	return b.AppendValue(j, v)
+}
+
+func parseType(typ string) (flux.ColType, error) {
+	switch typ {
+	case "bool":
+		return flux.TBool, nil
+	case "int":
+		return flux.TInt, nil
+	case "uint":
+		return flux.TUInt, nil
+	case "float":
+		return flux.TFloat, nil
+	case "string":
+		return flux.TString, nil
+	case "time":
+		return flux.TTime, nil
+	default:
+		return flux.TInvalid, errors.Newf(codes.Invalid, "unsupported data type %q", typ)
+	}
+}
+
+// parseValue parses a JSON value as the given column type.
+// A missing value is treated as null.
+func parseValue(raw json.RawMessage, typ flux.ColType) (values.Value, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return values.NewNull(flux.SemanticType(typ)), nil
+	}
+
+	switch typ {
+	case flux.TBool:
+		var v bool
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "expected a bool")
+		}
+		return values.NewBool(v), nil
+	case flux.TInt:
+		v, err := strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "expected an int")
+		}
+		return values.NewInt(v), nil
+	case flux.TUInt:
+		v, err := strconv.ParseUint(string(raw), 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "expected a uint")
+		}
+		return values.NewUInt(v), nil
+	case flux.TFloat:
+		if raw[0] == '"' {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, errors.Wrap(err, codes.Invalid, "expected a float")
+			}
+			switch s {
+			case "NaN":
+				return values.NewFloat(math.NaN()), nil
+			case "+Inf":
+				return values.NewFloat(math.Inf(1)), nil
+			case "-Inf":
+				return values.NewFloat(math.Inf(-1)), nil
+			}
+			return nil, errors.Newf(codes.Invalid, "expected a float, got %q", s)
+		}
+		v, err := strconv.ParseFloat(string(raw), 64)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "expected a float")
+		}
+		return values.NewFloat(v), nil
+	case flux.TString:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "expected a string")
+		}
+		return values.NewString(v), nil
+	case flux.TTime:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "expected a time")
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "expected a time")
+		}
+		return values.NewTime(values.ConvertTime(t)), nil
+	default:
+		return nil, errors.Newf(codes.Internal, "unsupported data type %v", typ)
+	}
+}
@@ -0,0 +1,215 @@
+package json_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/execute/table/static"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/json"
+)
+
+type result struct {
+	name   string
+	tables flux.TableIterator
+}
+
+func (r *result) Name() string               { return r.name }
+func (r *result) Tables() flux.TableIterator { return r.tables }
+
+// errorIterator produces the error after its results.
+type errorIterator struct {
+	flux.ResultIterator
+	err error
+}
+
+func (r *errorIterator) Err() error { return r.err }
+
+var tables = static.TableGroup{
+	static.StringKey("_measurement", "m0"),
+	static.TimeKey("_start", "2020-01-01T00:00:00Z"),
+	static.Table{
+		static.StringKey("_field", "f0"),
+		static.Times("_time", "2020-01-01T00:00:00Z", 10),
+		static.Floats("_value", 1.5, nil),
+		static.Strings("host", "a\"b", "c\nd"),
+	},
+	static.Table{
+		static.StringKey("_field", "f1"),
+		static.Times("_time", "2020-01-01T00:00:20Z"),
+		static.Floats("_value", math.Inf(-1)),
+		static.Strings("host", "e"),
+	},
+}
+
+func TestResultEncoder(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		format json.Format
+		want   string
+	}{
+		{
+			name:   "Rows",
+			format: json.Rows,
+			want: `{"results":[{"name":"_result","tables":[` +
+				`{"group_key":{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f0"},` +
+				`"columns":[{"label":"_measurement","type":"string","group":true},{"label":"_start","type":"time","group":true},` +
+				`{"label":"_field","type":"string","group":true},{"label":"_time","type":"time","group":false},` +
+				`{"label":"_value","type":"float","group":false},{"label":"host","type":"string","group":false}],` +
+				`"rows":[{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f0","_time":"2020-01-01T00:00:00Z","_value":1.5,"host":"a\"b"},` +
+				`{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f0","_time":"2020-01-01T00:00:10Z","_value":null,"host":"c\nd"}]},` +
+				`{"group_key":{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f1"},` +
+				`"columns":[{"label":"_measurement","type":"string","group":true},{"label":"_start","type":"time","group":true},` +
+				`{"label":"_field","type":"string","group":true},{"label":"_time","type":"time","group":false},` +
+				`{"label":"_value","type":"float","group":false},{"label":"host","type":"string","group":false}],` +
+				`"rows":[{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f1","_time":"2020-01-01T00:00:20Z","_value":"-Inf","host":"e"}]}` +
+				`]}]}` + "\n",
+		},
+		{
+			name:   "Columns",
+			format: json.Columns,
+			want: `{"results":[{"name":"_result","tables":[` +
+				`{"group_key":{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f0"},` +
+				`"columns":[{"label":"_measurement","type":"string","group":true,"values":["m0","m0"]},` +
+				`{"label":"_start","type":"time","group":true,"values":["2020-01-01T00:00:00Z","2020-01-01T00:00:00Z"]},` +
+				`{"label":"_field","type":"string","group":true,"values":["f0","f0"]},` +
+				`{"label":"_time","type":"time","group":false,"values":["2020-01-01T00:00:00Z","2020-01-01T00:00:10Z"]},` +
+				`{"label":"_value","type":"float","group":false,"values":[1.5,null]},` +
+				`{"label":"host","type":"string","group":false,"values":["a\"b","c\nd"]}]},` +
+				`{"group_key":{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f1"},` +
+				`"columns":[{"label":"_measurement","type":"string","group":true,"values":["m0"]},` +
+				`{"label":"_start","type":"time","group":true,"values":["2020-01-01T00:00:00Z"]},` +
+				`{"label":"_field","type":"string","group":true,"values":["f1"]},` +
+				`{"label":"_time","type":"time","group":false,"values":["2020-01-01T00:00:20Z"]},` +
+				`{"label":"_value","type":"float","group":false,"values":["-Inf"]},` +
+				`{"label":"host","type":"string","group":false,"values":["e"]}]}` +
+				`]}]}` + "\n",
+		},
+		{
+			name:   "NDJSON",
+			format: json.NDJSON,
+			want: `{"result":"_result","table":0,"group_key":{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f0"},` +
+				`"columns":[{"label":"_measurement","type":"string","group":true},{"label":"_start","type":"time","group":true},` +
+				`{"label":"_field","type":"string","group":true},{"label":"_time","type":"time","group":false},` +
+				`{"label":"_value","type":"float","group":false},{"label":"host","type":"string","group":false}]}` + "\n" +
+				`{"result":"_result","table":0,"row":{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f0","_time":"2020-01-01T00:00:00Z","_value":1.5,"host":"a\"b"}}` + "\n" +
+				`{"result":"_result","table":0,"row":{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f0","_time":"2020-01-01T00:00:10Z","_value":null,"host":"c\nd"}}` + "\n" +
+				`{"result":"_result","table":1,"group_key":{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f1"},` +
+				`"columns":[{"label":"_measurement","type":"string","group":true},{"label":"_start","type":"time","group":true},` +
+				`{"label":"_field","type":"string","group":true},{"label":"_time","type":"time","group":false},` +
+				`{"label":"_value","type":"float","group":false},{"label":"host","type":"string","group":false}]}` + "\n" +
+				`{"result":"_result","table":1,"row":{"_measurement":"m0","_start":"2020-01-01T00:00:00Z","_field":"f1","_time":"2020-01-01T00:00:20Z","_value":"-Inf","host":"e"}}` + "\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			results := flux.NewSliceResultIterator([]flux.Result{
+				&result{name: "_result", tables: tables},
+			})
+			var buf bytes.Buffer
+			enc := json.NewMultiResultEncoder(json.ResultEncoderConfig{Format: tt.format})
+			if _, err := enc.Encode(&buf, results); err != nil {
+				t.Fatal(err)
+			}
+			if want, got := tt.want, buf.String(); want != got {
+				t.Fatalf("unexpected output -want/+got:\n%s", cmp.Diff(want, got))
+			}
+
+			dec := json.NewMultiResultDecoder(json.ResultDecoderConfig{Format: tt.format})
+			decoded, err := dec.Decode(ioutil.NopCloser(&buf))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer decoded.Release()
+
+			if !decoded.More() {
+				t.Fatal("expected a result")
+			}
+			res := decoded.Next()
+			if want, got := "_result", res.Name(); want != got {
+				t.Fatalf("unexpected result name -want/+got:\n\t- %q\n\t+ %q", want, got)
+			}
+			if diff := table.Diff(tables, res.Tables()); diff != "" {
+				t.Fatalf("unexpected tables -want/+got:\n%s", diff)
+			}
+			if decoded.More() {
+				t.Fatal("unexpected result")
+			}
+			if err := decoded.Err(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestMultiResultEncoder_Error(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		format json.Format
+		want   string
+	}{
+		{
+			name:   "Rows",
+			format: json.Rows,
+			want: `{"results":[{"name":"_result","tables":[` +
+				`{"group_key":{"_measurement":"m0"},"columns":[{"label":"_measurement","type":"string","group":true},` +
+				`{"label":"_value","type":"int","group":false}],"rows":[{"_measurement":"m0","_value":1}]}` +
+				`]}],"error":"expected failure"}` + "\n",
+		},
+		{
+			name:   "NDJSON",
+			format: json.NDJSON,
+			want: `{"result":"_result","table":0,"group_key":{"_measurement":"m0"},"columns":[{"label":"_measurement","type":"string","group":true},` +
+				`{"label":"_value","type":"int","group":false}]}` + "\n" +
+				`{"result":"_result","table":0,"row":{"_measurement":"m0","_value":1}}` + "\n" +
+				`{"error":"expected failure"}` + "\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			results := &errorIterator{
+				ResultIterator: flux.NewSliceResultIterator([]flux.Result{
+					&result{
+						name: "_result",
+						tables: static.Table{
+							static.StringKey("_measurement", "m0"),
+							static.Ints("_value", 1),
+						},
+					},
+				}),
+				err: errors.New(codes.Internal, "expected failure"),
+			}
+			var buf bytes.Buffer
+			enc := json.NewMultiResultEncoder(json.ResultEncoderConfig{Format: tt.format})
+			if _, err := enc.Encode(&buf, results); err != nil {
+				t.Fatal(err)
+			}
+			if want, got := tt.want, buf.String(); want != got {
+				t.Fatalf("unexpected output -want/+got:\n%s", cmp.Diff(want, got))
+			}
+
+			dec := json.NewMultiResultDecoder(json.ResultDecoderConfig{Format: tt.format})
+			decoded, err := dec.Decode(ioutil.NopCloser(&buf))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for decoded.More() {
+				if err := decoded.Next().Tables().Do(func(tbl flux.Table) error {
+					tbl.Done()
+					return nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			decoded.Release()
+			if err := decoded.Err(); err == nil || !strings.Contains(err.Error(), "expected failure") {
+				t.Fatalf("expected decoded error, got %v", err)
+			}
+		})
+	}
+}
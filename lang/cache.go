@@ -0,0 +1,381 @@
+package lang
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"io"
+	"sync"
+
+	stdarrow "github.com/apache/arrow/go/v7/arrow"
+	"github.com/apache/arrow/go/v7/arrow/ipc"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/cache"
+	"github.com/influxdata/flux/dependencies/taskstate"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	cacheKeyPrefix   = "flux-query/"
+	cacheMetadataKey = "flux/query-cache"
+)
+
+// cacheKey returns the key that the results of the plan are stored under.
+// The second return value is false if the results cannot be cached.
+func cacheKey(ctx context.Context, p *plan.Spec) (cache.Cache, string, bool) {
+	c := cache.GetCache(ctx)
+	if c == nil {
+		return nil, "", false
+	}
+	// The results of an incremental run depend on the state
+	// saved by the previous run and not only on the plan.
+	if taskstate.GetSession(ctx) != nil {
+		return nil, "", false
+	}
+	fp, ok := plan.Fingerprint(p)
+	if !ok {
+		return nil, "", false
+	}
+	return c, cacheKeyPrefix + fp, true
+}
+
+// cachedResults is the encoded form of the results of a query.
+type cachedResults struct {
+	Results []cachedResult
+}
+
+type cachedResult struct {
+	Name   string
+	Tables []cachedTable
+}
+
+type cachedTable struct {
+	Key []cachedKeyValue
+	// Data is an arrow IPC stream with the columns and rows of the table.
+	Data []byte
+}
+
+type cachedKeyValue struct {
+	Label string
+	Type  flux.ColType
+	Null  bool
+	Value interface{}
+}
+
+// decodeCachedResults decodes the results stored in the cache.
+func decodeCachedResults(data []byte, mem memory.Allocator) (map[string]flux.Result, error) {
+	var cr cachedResults
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cr); err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "failed to decode cached results")
+	}
+
+	results := make(map[string]flux.Result, len(cr.Results))
+	for _, res := range cr.Results {
+		tables := make(table.Iterator, 0, len(res.Tables))
+		for _, t := range res.Tables {
+			tbl, err := decodeCachedTable(t, mem)
+			if err != nil {
+				for _, tbl := range tables {
+					tbl.Done()
+				}
+				return nil, err
+			}
+			tables = append(tables, tbl)
+		}
+		results[res.Name] = &cacheResult{
+			name:   res.Name,
+			tables: tables,
+		}
+	}
+	return results, nil
+}
+
+func decodeCachedTable(t cachedTable, mem memory.Allocator) (flux.Table, error) {
+	keyCols := make([]flux.ColMeta, len(t.Key))
+	keyValues := make([]values.Value, len(t.Key))
+	for i, kv := range t.Key {
+		keyCols[i] = flux.ColMeta{Label: kv.Label, Type: kv.Type}
+		v, err := decodeKeyValue(kv)
+		if err != nil {
+			return nil, err
+		}
+		keyValues[i] = v
+	}
+	key := execute.NewGroupKey(keyCols, keyValues)
+
+	r, err := ipc.NewReader(bytes.NewReader(t.Data), ipc.WithAllocator(mem))
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "failed to decode cached results")
+	}
+	defer r.Release()
+
+	cols, _, err := arrow.Columns(r.Schema())
+	if err != nil {
+		return nil, err
+	}
+	b := table.NewBufferedBuilder(key, mem)
+	b.Columns = cols
+	for r.Next() {
+		vs, err := arrow.RecordValues(r.Record(), cols, mem)
+		if err != nil {
+			b.Release()
+			return nil, err
+		}
+		buffer := &arrow.TableBuffer{
+			GroupKey: key,
+			Columns:  cols,
+			Values:   vs,
+		}
+		err = b.AppendBuffer(buffer)
+		buffer.Release()
+		if err != nil {
+			b.Release()
+			return nil, err
+		}
+	}
+	if err := r.Err(); err != nil && err != io.EOF {
+		b.Release()
+		return nil, errors.Wrap(err, codes.Internal, "failed to decode cached results")
+	}
+	return b.Table()
+}
+
+func encodeKeyValue(col flux.ColMeta, v values.Value) cachedKeyValue {
+	kv := cachedKeyValue{Label: col.Label, Type: col.Type}
+	if v.IsNull() {
+		kv.Null = true
+		return kv
+	}
+	switch col.Type {
+	case flux.TInt:
+		kv.Value = v.Int()
+	case flux.TUInt:
+		kv.Value = v.UInt()
+	case flux.TFloat:
+		kv.Value = v.Float()
+	case flux.TString:
+		kv.Value = v.Str()
+	case flux.TBool:
+		kv.Value = v.Bool()
+	case flux.TTime:
+		kv.Value = int64(v.Time())
+	}
+	return kv
+}
+
+func decodeKeyValue(kv cachedKeyValue) (values.Value, error) {
+	if kv.Null {
+		return values.NewNull(flux.SemanticType(kv.Type)), nil
+	}
+	switch v := kv.Value.(type) {
+	case int64:
+		if kv.Type == flux.TTime {
+			return values.NewTime(values.Time(v)), nil
+		}
+		return values.NewInt(v), nil
+	case uint64:
+		return values.NewUInt(v), nil
+	case float64:
+		return values.NewFloat(v), nil
+	case string:
+		return values.NewString(v), nil
+	case bool:
+		return values.NewBool(v), nil
+	default:
+		return nil, errors.Newf(codes.Internal, "unexpected cached group key value %T for column %q", kv.Value, kv.Label)
+	}
+}
+
+type cacheResult struct {
+	name   string
+	tables flux.TableIterator
+}
+
+func (r *cacheResult) Name() string {
+	return r.name
+}
+
+func (r *cacheResult) Tables() flux.TableIterator {
+	return r.tables
+}
+
+// resultRecorder copies the tables of the results as they are read
+// and stores them in the cache once every result has been read
+// without an error.
+//
+// Storing the results is best effort. Results that are not read
+// completely are not stored and errors from the cache are ignored.
+type resultRecorder struct {
+	ctx   context.Context
+	cache cache.Cache
+	key   string
+
+	mu      sync.Mutex
+	failed  bool
+	pending int
+	results []cachedResult
+}
+
+// recordResults wraps the results so that they
+// are stored in the cache after they are read.
+func recordResults(ctx context.Context, c cache.Cache, key string, results map[string]flux.Result) map[string]flux.Result {
+	r := &resultRecorder{
+		ctx:     ctx,
+		cache:   c,
+		key:     key,
+		pending: len(results),
+	}
+	wrapped := make(map[string]flux.Result, len(results))
+	for name, res := range results {
+		wrapped[name] = &cacheResult{
+			name: res.Name(),
+			tables: &recordingTableIterator{
+				TableIterator: res.Tables(),
+				recorder:      r,
+				name:          res.Name(),
+			},
+		}
+	}
+	return wrapped
+}
+
+// finish records the tables of one result. When the last result
+// is finished, the results are encoded and stored in the cache.
+func (r *resultRecorder) finish(res cachedResult, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failed {
+		return
+	}
+	if err != nil {
+		r.failed = true
+		r.results = nil
+		return
+	}
+	r.results = append(r.results, res)
+	r.pending--
+	if r.pending > 0 {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(cachedResults{Results: r.results}); err != nil {
+		r.failed = true
+		return
+	}
+	r.results = nil
+	_ = r.cache.Set(r.ctx, r.key, buf.Bytes())
+}
+
+type recordingTableIterator struct {
+	flux.TableIterator
+	recorder *resultRecorder
+	name     string
+}
+
+func (t *recordingTableIterator) Do(f func(flux.Table) error) error {
+	var tables []*recordingTable
+	if err := t.TableIterator.Do(func(tbl flux.Table) error {
+		rt := &recordingTable{Table: tbl}
+		tables = append(tables, rt)
+		return f(rt)
+	}); err != nil {
+		t.recorder.finish(cachedResult{}, err)
+		return err
+	}
+
+	// A failure to record the tables is not reported
+	// to the caller since the tables were read successfully.
+	res := cachedResult{Name: t.name}
+	var err error
+	for _, rt := range tables {
+		if rt.err != nil {
+			err = rt.err
+			break
+		} else if !rt.complete {
+			err = errors.New(codes.Canceled, "table was not read")
+			break
+		}
+		res.Tables = append(res.Tables, cachedTable{
+			Key:  rt.key,
+			Data: rt.data.Bytes(),
+		})
+	}
+	t.recorder.finish(res, err)
+	return nil
+}
+
+// recordingTable writes each buffer of the table
+// to an arrow IPC stream as it is read.
+type recordingTable struct {
+	flux.Table
+	key      []cachedKeyValue
+	data     bytes.Buffer
+	complete bool
+	err      error
+}
+
+func (t *recordingTable) Do(f func(flux.ColReader) error) error {
+	t.recordKey()
+	schema, err := arrow.Schema(t.Cols(), nil)
+	if err != nil {
+		t.err = err
+		return t.Table.Do(f)
+	}
+	w := ipc.NewWriter(&t.data, ipc.WithSchema(schema), ipc.WithAllocator(memory.DefaultAllocator))
+	if err := t.Table.Do(func(cr flux.ColReader) error {
+		if t.err == nil {
+			t.err = t.write(w, schema, cr)
+		}
+		return f(cr)
+	}); err != nil {
+		t.err = err
+		return err
+	}
+	if err := w.Close(); err != nil && t.err == nil {
+		t.err = err
+	}
+	t.complete = true
+	return nil
+}
+
+func (t *recordingTable) write(w *ipc.Writer, schema *stdarrow.Schema, cr flux.ColReader) error {
+	rec, err := arrow.NewRecord(schema, cr, memory.DefaultAllocator)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+	return w.Write(rec)
+}
+
+func (t *recordingTable) Done() {
+	if !t.complete && t.err == nil && t.Table.Empty() {
+		// An empty table has nothing to read so the
+		// schema is all that needs to be recorded.
+		t.recordKey()
+		if schema, err := arrow.Schema(t.Cols(), nil); err != nil {
+			t.err = err
+		} else if err := ipc.NewWriter(&t.data, ipc.WithSchema(schema)).Close(); err != nil {
+			t.err = err
+		} else {
+			t.complete = true
+		}
+	}
+	t.Table.Done()
+}
+
+func (t *recordingTable) recordKey() {
+	key := t.Key()
+	t.key = make([]cachedKeyValue, len(key.Cols()))
+	for j, col := range key.Cols() {
+		t.key[j] = encodeKeyValue(col, key.Value(j))
+	}
+}
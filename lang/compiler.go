@@ -55,6 +55,8 @@ type compileOptions struct {
 
 	extern flux.ASTHandle
 
+	profilers []string
+
 	planOptions struct {
 		logical  []plan.LogicalOption
 		physical []plan.PhysicalOption
@@ -77,6 +79,14 @@ func WithExtern(extern flux.ASTHandle) CompileOption {
 	}
 }
 
+// WithProfilers enables the named profilers in addition to
+// any profilers that the script enables with the profiler package.
+func WithProfilers(names ...string) CompileOption {
+	return func(o *compileOptions) {
+		o.profilers = append(o.profilers, names...)
+	}
+}
+
 func defaultOptions() *compileOptions {
 	o := new(compileOptions)
 	return o
@@ -290,11 +300,15 @@ func (p *Program) Start(ctx context.Context, alloc memory.Allocator) (flux.Query
 	}
 
 	ctx = memory.WithAllocator(ctx, resourceAlloc)
+	hooks := new(execute.QueryHooks)
+	ctx = execute.WithQueryHooks(ctx, hooks)
+	ctx = incrementalSession(ctx)
 
 	q := &query{
 		ctx:     ctx,
 		results: results,
 		alloc:   resourceAlloc,
+		hooks:   hooks,
 		span:    s,
 		cancel:  cancel,
 		stats: flux.Statistics{
@@ -315,12 +329,30 @@ func (p *Program) Start(ctx context.Context, alloc memory.Allocator) (flux.Query
 	q.stats.Metadata.Add("flux/query-plan",
 		fmt.Sprintf("%v", plan.Formatted(p.PlanSpec, plan.WithDetails())))
 
+	c, key, cacheable := cacheKey(ctx, p.PlanSpec)
+	if cacheable {
+		if data, ok, err := c.Get(ctx, key); err == nil && ok {
+			if resultMap, err := decodeCachedResults(data, q.alloc); err == nil {
+				q.stats.Metadata.Add(cacheMetadataKey, "hit")
+				q.wg.Add(1)
+				go p.processResults(ctx, q, resultMap)
+				return q, nil
+			}
+		}
+		q.stats.Metadata.Add(cacheMetadataKey, "miss")
+	}
+
 	e := execute.NewExecutor(p.Logger)
 	resultMap, statsCh, err := e.Execute(ctx, p.PlanSpec, q.alloc)
 	if err != nil {
+		_ = hooks.Finish(ctx, err)
 		s.Finish()
 		return nil, err
 	}
+	if cacheable {
+		resultMap = recordResults(ctx, c, key, resultMap)
+	}
+	resultMap = finishResults(ctx, hooks, resultMap)
 
 	// There was no error so send the results downstream.
 	q.wg.Add(1)
@@ -511,6 +543,13 @@ func (p *AstProgram) Start(ctx context.Context, alloc memory.Allocator) (flux.Qu
 	if err := p.updateOpts(scope); err != nil {
 		return nil, errors.Wrap(err, codes.Inherit, "error in reading options while starting program")
 	}
+	if len(p.opts.profilers) > 0 {
+		names := p.opts.profilers
+		for _, profiler := range deps.ExecutionOptions.Profilers {
+			names = append(names, profiler.Name())
+		}
+		(&ExecOptsConfig{}).ConfigureProfiler(ctx, names)
+	}
 	if err := p.updateProfilers(ctx, scope); err != nil {
 		return nil, errors.Wrap(err, codes.Inherit, "error in reading profiler settings while starting program")
 	}
@@ -535,6 +574,41 @@ func (p *AstProgram) Start(ctx context.Context, alloc memory.Allocator) (flux.Qu
 	}, nil
 }
 
+// Plan evaluates the program and returns its logical and physical plans
+// without executing it.
+func (p *AstProgram) Plan(ctx context.Context, alloc memory.Allocator) (logical, physical *plan.Spec, err error) {
+	deps := execute.NewExecutionDependencies(alloc, &p.Now, p.Logger)
+	ctx, span := dependency.Inject(ctx, deps)
+	defer span.Finish()
+	ctx = context.WithValue(ctx, plan.NextPlanNodeIDKey, new(int))
+
+	sp, scope, err := p.getSpec(ctx, alloc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := p.updateOpts(scope); err != nil {
+		return nil, nil, errors.Wrap(err, codes.Inherit, "error in reading options while planning program")
+	}
+
+	// The physical planner modifies the nodes of the logical plan
+	// so the logical plan is created separately.
+	lp := plan.NewLogicalPlanner(p.opts.planOptions.logical...)
+	initial, err := lp.CreateInitialPlan(sp)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, codes.Inherit, "error in building logical plan")
+	}
+	logical, err = lp.Plan(ctx, initial)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, codes.Inherit, "error in building logical plan")
+	}
+
+	physical, err = buildPlan(ctx, sp, p.opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, codes.Inherit, "error in building plan")
+	}
+	return logical, physical, nil
+}
+
 func (p *AstProgram) updateProfilers(ctx context.Context, scope values.Scope) error {
 	if execute.HaveExecutionDependencies(ctx) {
 		deps := execute.GetExecutionDependencies(ctx)
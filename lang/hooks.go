@@ -0,0 +1,77 @@
+package lang
+
+import (
+	"context"
+	"sync"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+var errResultsNotRead = errors.New(codes.Canceled, "query finished before its results were read")
+
+// resultsFinisher finishes the query hooks once every result
+// has been read. The hooks receive the first error from reading
+// a result or nil if every result was read successfully.
+type resultsFinisher struct {
+	ctx   context.Context
+	hooks *execute.QueryHooks
+
+	mu      sync.Mutex
+	pending int
+}
+
+// finishResults wraps the results so that the query hooks
+// are finished after the results are read.
+func finishResults(ctx context.Context, hooks *execute.QueryHooks, results map[string]flux.Result) map[string]flux.Result {
+	c := &resultsFinisher{
+		ctx:     ctx,
+		hooks:   hooks,
+		pending: len(results),
+	}
+	wrapped := make(map[string]flux.Result, len(results))
+	for name, res := range results {
+		wrapped[name] = &cacheResult{
+			name: res.Name(),
+			tables: &finishingTableIterator{
+				TableIterator: res.Tables(),
+				finisher:      c,
+			},
+		}
+	}
+	return wrapped
+}
+
+// finish records that one result was read. The hooks are
+// finished when the last result is read successfully or when
+// a result fails, and the error from the hooks is returned.
+func (c *resultsFinisher) finish(err error) error {
+	if err != nil {
+		_ = c.hooks.Finish(c.ctx, err)
+		return nil
+	}
+
+	c.mu.Lock()
+	c.pending--
+	pending := c.pending
+	c.mu.Unlock()
+	if pending > 0 {
+		return nil
+	}
+	return c.hooks.Finish(c.ctx, nil)
+}
+
+type finishingTableIterator struct {
+	flux.TableIterator
+	finisher *resultsFinisher
+}
+
+func (t *finishingTableIterator) Do(f func(flux.Table) error) error {
+	if err := t.TableIterator.Do(f); err != nil {
+		_ = t.finisher.finish(err)
+		return err
+	}
+	return t.finisher.finish(nil)
+}
@@ -0,0 +1,28 @@
+package lang
+
+import (
+	"context"
+
+	"github.com/influxdata/flux/dependencies/taskstate"
+	"github.com/influxdata/flux/execute"
+)
+
+// incrementalSession starts a session for the task state store
+// in the context. The session is committed once the query has
+// completed successfully. A run that fails or is not read
+// completely does not advance the state so the next run
+// processes the same rows again.
+func incrementalSession(ctx context.Context) context.Context {
+	store := taskstate.GetStore(ctx)
+	if store == nil {
+		return ctx
+	}
+	session := taskstate.NewSession(store)
+	execute.OnQueryFinish(ctx, func(ctx context.Context, err error) error {
+		if err != nil {
+			return nil
+		}
+		return session.Commit(ctx)
+	})
+	return taskstate.WithSession(ctx, session)
+}
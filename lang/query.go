@@ -6,6 +6,7 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/dependencies/testing"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/memory"
 	"github.com/opentracing/opentracing-go"
 )
@@ -16,6 +17,7 @@ type query struct {
 	results chan flux.Result
 	stats   flux.Statistics
 	alloc   *memory.ResourceAllocator
+	hooks   *execute.QueryHooks
 	span    opentracing.Span
 	cancel  func()
 	err     error
@@ -29,6 +31,16 @@ func (q *query) Results() <-chan flux.Result {
 func (q *query) Done() {
 	q.cancel()
 	q.wg.Wait()
+
+	// Release anything held for the query hooks if the
+	// results were not read completely.
+	if q.hooks != nil {
+		err := q.err
+		if err == nil {
+			err = errResultsNotRead
+		}
+		_ = q.hooks.Finish(q.ctx, err)
+	}
 	q.stats.MaxAllocated = q.alloc.MaxAllocated()
 	q.stats.TotalAllocated = q.alloc.TotalAllocated()
 	if q.span != nil {
@@ -137,16 +137,38 @@ fn vectorize_subtraction_operator() -> anyhow::Result<()> {
 }
 
 #[test]
-fn vectorize_gt_operator_not_implemented() -> anyhow::Result<()> {
+fn vectorize_gt_operator() -> anyhow::Result<()> {
+    let pkg = vectorize(r#"(r) => ({ x: r.a > r.b })"#)?;
+
+    let function = get_vectorized_function(&pkg);
+
+    expect_test::expect![[r#"{x: v[bool]}"#]].assert_eq(&return_type(function));
+
+    Ok(())
+}
+
+#[test]
+fn vectorize_regexp_match_operator_not_implemented() -> anyhow::Result<()> {
     // N.b. there are many operators that are not currently implemented.
-    // This one just popped up in the acceptance tests when vec repeat was
-    // implemented for #4622.
-    let mut pkg = vectorize(r#"(r) => ({ x: r.a > r.b })"#).unwrap();
+    let mut pkg = vectorize(r#"(r) => ({ x: r.a =~ r.b })"#).unwrap();
 
     let err = semantic::vectorize::vectorize(&analyzer_config(), &mut pkg).unwrap_err();
 
-    expect_test::expect![[r#"error @1:14-1:23: can't vectorize function: unsupported operator >"#]]
-        .assert_eq(&err.to_string());
+    expect_test::expect![[
+        r#"error @1:14-1:24: can't vectorize function: unsupported operator =~"#
+    ]]
+    .assert_eq(&err.to_string());
+    Ok(())
+}
+
+#[test]
+fn vectorize_predicate() -> anyhow::Result<()> {
+    let pkg = vectorize(r#"(r) => r.a > 1.0 and r.b == "x""#)?;
+
+    let function = get_vectorized_function(&pkg);
+
+    expect_test::expect![[r#"v[bool]"#]].assert_eq(&return_type(function));
+
     Ok(())
 }
 
@@ -282,3 +304,94 @@ fn vectorize_with_construction_using_literal_time() -> anyhow::Result<()> {
 
     Ok(())
 }
+
+fn return_type(function: &FunctionExpr) -> String {
+    match &function.typ {
+        MonoType::Fun(f) => f.retn.to_string(),
+        typ => panic!("expected a function type, got `{}`", typ),
+    }
+}
+
+#[test]
+fn vectorize_conditional() -> anyhow::Result<()> {
+    let pkg = vectorize(r#"(r) => ({ x: if r.a then r.b + 1.0 else 0.0 })"#)?;
+
+    let function = get_vectorized_function(&pkg);
+
+    expect_test::expect![[r#"{x: v[float]}"#]].assert_eq(&return_type(function));
+
+    Ok(())
+}
+
+#[test]
+fn vectorize_nested_field_access() -> anyhow::Result<()> {
+    let pkg = vectorize(r#"(r) => ({ x: r.a.b * 2.0 })"#)?;
+
+    let function = get_vectorized_function(&pkg);
+
+    expect_test::expect![[r#"{x: v[float]}"#]].assert_eq(&return_type(function));
+
+    Ok(())
+}
+
+#[test]
+fn vectorize_unsupported_call_not_implemented() -> anyhow::Result<()> {
+    let mut pkg = vectorize(r#"(r) => ({ x: r.f(v: r.a) })"#)?;
+
+    let err = semantic::vectorize::vectorize(&analyzer_config(), &mut pkg).unwrap_err();
+
+    expect_test::expect![[
+        r#"error @1:14-1:25: can't vectorize function: unsupported function call"#
+    ]]
+    .assert_eq(&err.to_string());
+    Ok(())
+}
+
+fn vectorize_with_strings(src: &str) -> anyhow::Result<Package> {
+    let strings = vec![
+        ("toUpper", "(v: string) => string"),
+        ("split", "(v: string, t: string) => [string]"),
+    ]
+    .into_iter()
+    .collect();
+    let (_, pkg) = infer_types(
+        src,
+        HashMap::default(),
+        vec![("strings", strings)].into_iter().collect(),
+        None,
+        analyzer_config(),
+    )?;
+    Ok(pkg)
+}
+
+#[test]
+fn vectorize_package_call_not_implemented() -> anyhow::Result<()> {
+    let mut pkg = vectorize_with_strings(
+        r#"
+            import "strings"
+
+            (r) => ({ x: strings.toUpper(v: r.a) })
+        "#,
+    )?;
+
+    let err = semantic::vectorize::vectorize(&analyzer_config(), &mut pkg).unwrap_err();
+
+    expect_test::expect![[
+        r#"error @4:26-4:49: can't vectorize function: unsupported function call"#
+    ]]
+    .assert_eq(&err.to_string());
+    Ok(())
+}
+
+#[test]
+fn vectorize_conditional_with_division_not_implemented() -> anyhow::Result<()> {
+    let mut pkg = vectorize(r#"(r) => ({ x: if r.b != 0 then r.a / r.b else 0 })"#)?;
+
+    let err = semantic::vectorize::vectorize(&analyzer_config(), &mut pkg).unwrap_err();
+
+    expect_test::expect![[
+        r#"error @1:31-1:40: can't vectorize function: unsupported conditional branch that can fail"#
+    ]]
+    .assert_eq(&err.to_string());
+    Ok(())
+}
@@ -5,9 +5,9 @@ use crate::{
     errors::{located, Errors},
     semantic::{
         nodes::{
-            BinaryExpr, Block, CallExpr, Error, ErrorKind, Expression, FunctionExpr, Identifier,
-            IdentifierExpr, LogicalExpr, MemberExpr, ObjectExpr, Package, Property, Result,
-            ReturnStmt,
+            BinaryExpr, Block, CallExpr, ConditionalExpr, Error, ErrorKind, Expression,
+            FunctionExpr, Identifier, IdentifierExpr, LogicalExpr, MemberExpr, ObjectExpr, Package,
+            Property, Result, ReturnStmt,
         },
         types::{self, Function, Label, MonoType},
         AnalyzerConfig, Feature, Symbol,
@@ -123,6 +123,40 @@ impl Expression {
                     right,
                 }))
             }
+            Expression::Conditional(expr) => {
+                // Both branches are evaluated for every row and the result is
+                // selected by the test afterwards. A branch that can fail would
+                // fail for rows that never select it, so those conditionals are
+                // left to the row by row evaluation.
+                for branch in [&expr.consequent, &expr.alternate] {
+                    if can_fail(branch) {
+                        return Err(located(
+                            branch.loc().clone(),
+                            ErrorKind::UnableToVectorize(
+                                "unsupported conditional branch that can fail".into(),
+                            ),
+                        ));
+                    }
+                }
+                let test = expr.test.vectorize(env)?;
+                let consequent = expr.consequent.vectorize(env)?;
+                let alternate = expr.alternate.vectorize(env)?;
+                Expression::Conditional(Box::new(ConditionalExpr {
+                    loc: expr.loc.clone(),
+                    typ: MonoType::vector(expr.typ.clone()),
+                    test,
+                    consequent,
+                    alternate,
+                }))
+            }
+            // Functions are called with a single row at a time, so a call
+            // can't be evaluated over a whole vector.
+            Expression::Call(call) => {
+                return Err(located(
+                    call.loc.clone(),
+                    ErrorKind::UnableToVectorize("unsupported function call".into()),
+                ));
+            }
             expr @ Expression::Integer(_)
                 if env.config.features.contains(&Feature::VectorizedConst) =>
             {
@@ -166,9 +200,36 @@ fn op_is_vectorizable(op: &Operator) -> bool {
             | Operator::DivisionOperator
             | Operator::ModuloOperator
             | Operator::PowerOperator
+            | Operator::EqualOperator
+            | Operator::NotEqualOperator
+            | Operator::LessThanOperator
+            | Operator::LessThanEqualOperator
+            | Operator::GreaterThanOperator
+            | Operator::GreaterThanEqualOperator
     )
 }
 
+/// Returns whether evaluating the expression can fail at runtime,
+/// such as an integer division by zero.
+fn can_fail(expr: &Expression) -> bool {
+    match expr {
+        Expression::Binary(e) => {
+            matches!(
+                e.operator,
+                Operator::DivisionOperator | Operator::ModuloOperator
+            ) || can_fail(&e.left)
+                || can_fail(&e.right)
+        }
+        Expression::Logical(e) => can_fail(&e.left) || can_fail(&e.right),
+        Expression::Conditional(e) => {
+            can_fail(&e.test) || can_fail(&e.consequent) || can_fail(&e.alternate)
+        }
+        Expression::Member(e) => can_fail(&e.object),
+        Expression::Call(_) => true,
+        _ => false,
+    }
+}
+
 fn wrap_vec_repeat(expr: Expression) -> Expression {
     // The call expression is just a way to trigger a rewrite in Go during evaluation.
     // The only details that matter are the parameter `v` (the original expression) and
@@ -222,6 +283,8 @@ impl IdentifierExpr {
 impl FunctionExpr {
     fn vectorize(&self, config: &AnalyzerConfig) -> Result<Self> {
         if self.params.len() == 1 && self.params[0].key.name == "r" {
+            // Fields of nested records are vectorized as well so that
+            // members of the nested records can be accessed.
             fn vectorize_fields(record: &MonoType) -> MonoType {
                 use crate::semantic::types::Record;
                 match record {
@@ -230,7 +293,10 @@ impl FunctionExpr {
                         Record::Extension { head, tail } => Record::Extension {
                             head: types::Property {
                                 k: head.k.clone(),
-                                v: MonoType::vector(head.v.clone()),
+                                v: match &head.v {
+                                    MonoType::Record(_) => vectorize_fields(&head.v),
+                                    v => MonoType::vector(v.clone()),
+                                },
                             },
                             tail: vectorize_fields(tail),
                         },
@@ -259,12 +325,9 @@ impl FunctionExpr {
                         ErrorKind::UnableToVectorize("Unable to vectorize statements".into()),
                     ));
                 }
-                // XXX: sean (January 14 2022) - The only type of function expression
-                // currently supported for vectorization is one whose body contains only
-                // a single object expression, the fields of which only reference members of
-                // `r` and do not include any kind of operation, literal, or logical expression.
-                //
-                // We may support other expression types in the future.
+                // The only type of function expression currently supported for vectorization
+                // is one whose body contains only a single return statement, which returns
+                // either a record (`map`) or a single vectorizable expression (`filter`).
                 Block::Return(e) => {
                     let argument = match &e.argument {
                         Expression::Object(e) => {
@@ -299,14 +362,8 @@ impl FunctionExpr {
                                 properties,
                             }))
                         }
-                        _ => {
-                            return Err(located(
-                                e.argument.loc().clone(),
-                                ErrorKind::UnableToVectorize(
-                                    "Vectorization only supports returning a record".into(),
-                                ),
-                            ));
-                        }
+                        // Predicates, such as the functions of `filter`, return a vector of booleans.
+                        argument => argument.vectorize(&env)?,
                     };
                     Block::Return(ReturnStmt {
                         loc: e.loc.clone(),
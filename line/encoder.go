@@ -0,0 +1,284 @@
+package line
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/line-protocol/v2/lineprotocol"
+)
+
+const (
+	DefaultMeasurementColLabel = "_measurement"
+	DefaultFieldColLabel       = "_field"
+)
+
+// ResultEncoderConfig are options that can be specified on the ResultEncoder.
+type ResultEncoderConfig struct {
+	// MeasurementColumn is the column that contains the measurement name.
+	// If empty, _measurement is used.
+	MeasurementColumn string
+
+	// TimeColumn is the column that contains the timestamp.
+	// If empty, _time is used.
+	TimeColumn string
+
+	// TagColumns are the columns that are written as tags.
+	// If nil, every string column other than the measurement,
+	// _field and _value columns is a tag.
+	TagColumns []string
+
+	// Precision is the precision of the written timestamps.
+	// The default is nanoseconds.
+	Precision lineprotocol.Precision
+}
+
+func DefaultEncoderConfig() ResultEncoderConfig {
+	return ResultEncoderConfig{
+		MeasurementColumn: DefaultMeasurementColLabel,
+		TimeColumn:        execute.DefaultTimeColLabel,
+		Precision:         lineprotocol.Nanosecond,
+	}
+}
+
+// ResultEncoder encodes a result as line protocol.
+//
+// It follows the same conventions as influxdb.to(). When a table
+// has a _field column, each row is written as a line with the
+// value of the _field column as the field key and the _value column
+// as the field value. Otherwise, every column that is not the
+// measurement, time or a tag, and is not a time column itself,
+// is written as a field of the line.
+//
+// Rows with a null timestamp are skipped, as are null field values
+// and float values that are NaN or infinite. Tags with an empty value
+// are omitted. A row without any field values is not written.
+type ResultEncoder struct {
+	c ResultEncoderConfig
+}
+
+// NewResultEncoder creates a new encoder with the provided configuration.
+func NewResultEncoder(c ResultEncoderConfig) *ResultEncoder {
+	if c.MeasurementColumn == "" {
+		c.MeasurementColumn = DefaultMeasurementColLabel
+	}
+	if c.TimeColumn == "" {
+		c.TimeColumn = execute.DefaultTimeColLabel
+	}
+	return &ResultEncoder{
+		c: c,
+	}
+}
+
+// NewMultiResultEncoder creates an encoder that
+// writes the lines of each result one after the other.
+func NewMultiResultEncoder(c ResultEncoderConfig) flux.MultiResultEncoder {
+	return &flux.DelimitedMultiResultEncoder{
+		Encoder: NewResultEncoder(c),
+	}
+}
+
+type lineEncoderError struct {
+	err error
+}
+
+func (e *lineEncoderError) Error() string {
+	return fmt.Sprintf("line protocol encoder error: %s", e.err.Error())
+}
+
+func (e *lineEncoderError) IsEncoderError() bool {
+	return true
+}
+
+func (e *lineEncoderError) Unwrap() error {
+	return e.err
+}
+
+func (e *ResultEncoder) Encode(w io.Writer, result flux.Result) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+	var enc lineprotocol.Encoder
+	enc.SetPrecision(e.c.Precision)
+	err := result.Tables().Do(func(tbl flux.Table) error {
+		return tbl.Do(func(cr flux.ColReader) error {
+			enc.Reset()
+			if err := e.encodeBuffer(&enc, cr); err != nil {
+				return err
+			}
+			if _, err := wc.Write(enc.Bytes()); err != nil {
+				return &lineEncoderError{err: err}
+			}
+			return nil
+		})
+	})
+	return wc.Count(), err
+}
+
+// EncodeError writes the error as a line protocol comment.
+func (e *ResultEncoder) EncodeError(w io.Writer, err error) error {
+	_, werr := fmt.Fprintf(w, "# error: %s\n", err)
+	return werr
+}
+
+// column is a column of the buffer with the role it has in a line.
+type column struct {
+	idx   int
+	label string
+}
+
+func (e *ResultEncoder) encodeBuffer(enc *lineprotocol.Encoder, cr flux.ColReader) error {
+	cols := cr.Cols()
+	measurementIdx := execute.ColIdx(e.c.MeasurementColumn, cols)
+	if measurementIdx < 0 {
+		return &lineEncoderError{err: errors.Newf(codes.Invalid, "no column with label %s exists", e.c.MeasurementColumn)}
+	} else if cols[measurementIdx].Type != flux.TString {
+		return &lineEncoderError{err: errors.Newf(codes.Invalid, "column %s of type %s is not of type %s", e.c.MeasurementColumn, cols[measurementIdx].Type, flux.TString)}
+	}
+	timeIdx := execute.ColIdx(e.c.TimeColumn, cols)
+	if timeIdx < 0 {
+		return &lineEncoderError{err: errors.New(codes.Invalid, "no time column detected")}
+	} else if cols[timeIdx].Type != flux.TTime {
+		return &lineEncoderError{err: errors.Newf(codes.Invalid, "column %s of type %s is not of type %s", e.c.TimeColumn, cols[timeIdx].Type, flux.TTime)}
+	}
+	fieldIdx := execute.ColIdx(DefaultFieldColLabel, cols)
+	valueIdx := execute.ColIdx(execute.DefaultValueColLabel, cols)
+	if fieldIdx >= 0 {
+		if cols[fieldIdx].Type != flux.TString {
+			return &lineEncoderError{err: errors.Newf(codes.Invalid, "column %s of type %s is not of type %s", DefaultFieldColLabel, cols[fieldIdx].Type, flux.TString)}
+		} else if valueIdx < 0 {
+			return &lineEncoderError{err: errors.New(codes.Invalid, "table has no _value column")}
+		}
+	}
+
+	tags, err := e.tagColumns(cols, measurementIdx, fieldIdx, valueIdx)
+	if err != nil {
+		return err
+	}
+
+	// Without a _field column, the remaining columns are the fields.
+	var fields []column
+	if fieldIdx < 0 {
+		isTag := make(map[int]bool, len(tags))
+		for _, tag := range tags {
+			isTag[tag.idx] = true
+		}
+		for j, col := range cols {
+			if j == measurementIdx || j == timeIdx || isTag[j] || col.Type == flux.TTime {
+				continue
+			}
+			fields = append(fields, column{idx: j, label: col.Label})
+		}
+	}
+
+	measurements, times := cr.Strings(measurementIdx), cr.Times(timeIdx)
+	for i := 0; i < cr.Len(); i++ {
+		if times.IsNull(i) || measurements.IsNull(i) {
+			continue
+		}
+
+		// Determine the field values before the line is started
+		// so that rows without any fields are not written.
+		var (
+			keys []string
+			vals []lineprotocol.Value
+		)
+		if fieldIdx >= 0 {
+			if v, ok := fieldValue(cr, valueIdx, i); ok && cr.Strings(fieldIdx).IsValid(i) {
+				keys = append(keys, cr.Strings(fieldIdx).Value(i))
+				vals = append(vals, v)
+			}
+		} else {
+			for _, field := range fields {
+				if v, ok := fieldValue(cr, field.idx, i); ok {
+					keys = append(keys, field.label)
+					vals = append(vals, v)
+				}
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		enc.StartLine(measurements.Value(i))
+		for _, tag := range tags {
+			vs := cr.Strings(tag.idx)
+			if vs.IsNull(i) || vs.Value(i) == "" {
+				continue
+			}
+			enc.AddTag(tag.label, vs.Value(i))
+		}
+		for k := range keys {
+			enc.AddField(keys[k], vals[k])
+		}
+		enc.EndLine(values.Time(times.Value(i)).Time())
+		if err := enc.Err(); err != nil {
+			return &lineEncoderError{err: errors.Wrap(err, codes.Invalid, "invalid line")}
+		}
+	}
+	return nil
+}
+
+// tagColumns returns the tag columns of the buffer sorted by label.
+func (e *ResultEncoder) tagColumns(cols []flux.ColMeta, measurementIdx, fieldIdx, valueIdx int) ([]column, error) {
+	var tags []column
+	if e.c.TagColumns == nil {
+		for j, col := range cols {
+			if j == measurementIdx || j == fieldIdx || j == valueIdx || col.Type != flux.TString {
+				continue
+			}
+			tags = append(tags, column{idx: j, label: col.Label})
+		}
+	} else {
+		for _, label := range e.c.TagColumns {
+			j := execute.ColIdx(label, cols)
+			if j < 0 {
+				continue
+			} else if cols[j].Type != flux.TString {
+				return nil, &lineEncoderError{err: errors.New(codes.Invalid, "invalid type for tag column")}
+			}
+			tags = append(tags, column{idx: j, label: label})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].label < tags[j].label
+	})
+	return tags, nil
+}
+
+// fieldValue returns the line protocol value of the column in the row.
+// It returns false if the value is null or cannot be written.
+// Time values are written as integers.
+func fieldValue(cr flux.ColReader, j, i int) (lineprotocol.Value, bool) {
+	switch cr.Cols()[j].Type {
+	case flux.TBool:
+		if vs := cr.Bools(j); vs.IsValid(i) {
+			return lineprotocol.BoolValue(vs.Value(i)), true
+		}
+	case flux.TInt:
+		if vs := cr.Ints(j); vs.IsValid(i) {
+			return lineprotocol.IntValue(vs.Value(i)), true
+		}
+	case flux.TUInt:
+		if vs := cr.UInts(j); vs.IsValid(i) {
+			return lineprotocol.UintValue(vs.Value(i)), true
+		}
+	case flux.TFloat:
+		if vs := cr.Floats(j); vs.IsValid(i) {
+			return lineprotocol.FloatValue(vs.Value(i))
+		}
+	case flux.TString:
+		if vs := cr.Strings(j); vs.IsValid(i) {
+			return lineprotocol.StringValue(vs.Value(i))
+		}
+	case flux.TTime:
+		if vs := cr.Times(j); vs.IsValid(i) {
+			return lineprotocol.IntValue(vs.Value(i)), true
+		}
+	}
+	return lineprotocol.Value{}, false
+}
@@ -0,0 +1,99 @@
+package line_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/table/static"
+	"github.com/influxdata/flux/line"
+	"github.com/influxdata/line-protocol/v2/lineprotocol"
+)
+
+type result struct {
+	tables flux.TableIterator
+}
+
+func (r *result) Name() string               { return "_result" }
+func (r *result) Tables() flux.TableIterator { return r.tables }
+
+func TestResultEncoder(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		config line.ResultEncoderConfig
+		in     flux.TableIterator
+		want   string
+	}{
+		{
+			name: "FieldValue",
+			in: static.TableGroup{
+				static.StringKey("_measurement", "cpu"),
+				static.StringKey("host", "a"),
+				static.TimeKey("_start", "2020-01-01T00:00:00Z"),
+				static.Table{
+					static.StringKey("_field", "usage"),
+					static.Times("_time", "2020-01-01T00:00:00Z", 10, 20),
+					static.Floats("_value", 1.5, math.NaN(), nil),
+				},
+				static.Table{
+					static.StringKey("_field", "count"),
+					static.Times("_time", "2020-01-01T00:00:00Z", nil),
+					static.Ints("_value", 4, 8),
+				},
+			},
+			want: "cpu,host=a usage=1.5 1577836800000000000\n" +
+				"cpu,host=a count=4i 1577836800000000000\n",
+		},
+		{
+			name: "Wide",
+			in: static.Table{
+				static.StringKey("_measurement", "m 0"),
+				static.Strings("region", "west", ""),
+				static.Strings("dc", "b", "c"),
+				static.Times("_time", "2020-01-01T00:00:00Z", 1),
+				static.Ints("a", 1, nil),
+				static.Booleans("b", true, nil),
+			},
+			want: "m\\ 0,dc=b,region=west a=1i,b=true 1577836800000000000\n",
+		},
+		{
+			name: "TagColumnsAndPrecision",
+			config: line.ResultEncoderConfig{
+				TagColumns: []string{"dc"},
+				Precision:  lineprotocol.Second,
+			},
+			in: static.Table{
+				static.StringKey("_measurement", "m0"),
+				static.Strings("region", "west"),
+				static.Strings("dc", "b"),
+				static.Times("_time", "2020-01-01T00:00:00Z"),
+				static.Floats("f", 2),
+			},
+			want: "m0,dc=b region=\"west\",f=2 1577836800\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			enc := line.NewResultEncoder(tt.config)
+			if _, err := enc.Encode(&buf, &result{tables: tt.in}); err != nil {
+				t.Fatal(err)
+			}
+			if want, got := tt.want, buf.String(); want != got {
+				t.Fatalf("unexpected output -want/+got:\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestResultEncoder_MissingMeasurement(t *testing.T) {
+	in := static.Table{
+		static.Times("_time", "2020-01-01T00:00:00Z"),
+		static.Floats("_value", 2),
+	}
+	enc := line.NewResultEncoder(line.DefaultEncoderConfig())
+	if _, err := enc.Encode(&bytes.Buffer{}, &result{tables: in}); err == nil {
+		t.Fatal("expected error")
+	}
+}
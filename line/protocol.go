@@ -0,0 +1,254 @@
+package line
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/line-protocol/v2/lineprotocol"
+)
+
+// ProtocolDecoder decodes line protocol into tables.
+//
+// The tables have the same layout as the tables returned by
+// influxdb.from(). There is one table for each series and field
+// with the _time, _value, _field and _measurement columns followed
+// by a column for each tag. Every column except _time and _value
+// is part of the group key. Rows are sorted by time.
+//
+// If a field has values of different types, there is a table
+// for each type. All of the data is read into memory.
+type ProtocolDecoder struct {
+	// Precision is the precision of the timestamps.
+	// The default is nanoseconds.
+	Precision lineprotocol.Precision
+
+	// Now is the timestamp used for lines without a timestamp.
+	Now time.Time
+
+	// Allocator is the memory allocator that will be used during decoding.
+	// The default is to use an unlimited allocator when this is not set.
+	Allocator memory.Allocator
+}
+
+type protocolResult struct {
+	tables table.Iterator
+}
+
+func (r *protocolResult) Name() string {
+	return "_result"
+}
+
+func (r *protocolResult) Tables() flux.TableIterator {
+	return r.tables
+}
+
+// Decode decodes the line protocol from r into a result.
+func (d *ProtocolDecoder) Decode(r io.Reader) (flux.Result, error) {
+	t := d.newSeriesTables()
+	if err := t.decode(r, d.Now); err != nil {
+		return nil, err
+	}
+	return t.result()
+}
+
+// DecodeMessages decodes the line protocol in each of the messages
+// into a single result. Lines without a timestamp use the time of
+// their message instead of Now.
+func (d *ProtocolDecoder) DecodeMessages(messages [][]byte, times []time.Time) (flux.Result, error) {
+	t := d.newSeriesTables()
+	for i, m := range messages {
+		if err := t.decode(bytes.NewReader(m), times[i]); err != nil {
+			return nil, err
+		}
+	}
+	return t.result()
+}
+
+// seriesTables builds a table for each series and field
+// of the decoded line protocol.
+type seriesTables struct {
+	precision lineprotocol.Precision
+	alloc     memory.Allocator
+	builders  map[string]*execute.ColListTableBuilder
+	order     []*execute.ColListTableBuilder
+	tags      []tag
+	sb        strings.Builder
+}
+
+// tag is a tag key and value of a line.
+type tag struct {
+	key, value string
+}
+
+func (d *ProtocolDecoder) newSeriesTables() *seriesTables {
+	alloc := d.Allocator
+	if alloc == nil {
+		alloc = memory.DefaultAllocator
+	}
+	return &seriesTables{
+		precision: d.Precision,
+		alloc:     alloc,
+		builders:  make(map[string]*execute.ColListTableBuilder),
+	}
+}
+
+// decode appends the points in the line protocol from r to the tables.
+func (t *seriesTables) decode(r io.Reader, now time.Time) error {
+	dec := lineprotocol.NewDecoder(r)
+	for dec.Next() {
+		m, err := dec.Measurement()
+		if err != nil {
+			return errors.Wrap(err, codes.Invalid, "failed to decode line protocol")
+		}
+		measurement := string(m)
+
+		t.tags = t.tags[:0]
+		for {
+			key, value, err := dec.NextTag()
+			if err != nil {
+				return errors.Wrap(err, codes.Invalid, "failed to decode line protocol")
+			} else if key == nil {
+				break
+			}
+			t.tags = append(t.tags, tag{key: string(key), value: string(value)})
+		}
+		// Tags are normally sorted, but the line protocol does not
+		// require it. They are sorted so that lines of the same series
+		// have the same series key whatever the order of their tags.
+		sort.Slice(t.tags, func(i, j int) bool {
+			return t.tags[i].key < t.tags[j].key
+		})
+
+		type field struct {
+			key   string
+			value values.Value
+		}
+		var fields []field
+		for {
+			key, value, err := dec.NextField()
+			if err != nil {
+				return errors.Wrap(err, codes.Invalid, "failed to decode line protocol")
+			} else if key == nil {
+				break
+			}
+			fields = append(fields, field{key: string(key), value: fieldToValue(value)})
+		}
+
+		ts, err := dec.Time(t.precision, now)
+		if err != nil {
+			return errors.Wrap(err, codes.Invalid, "failed to decode line protocol")
+		}
+
+		for _, f := range fields {
+			// The series key identifies the table
+			// for the field and its value type.
+			t.sb.Reset()
+			t.sb.WriteString(measurement)
+			for _, tag := range t.tags {
+				t.sb.WriteByte(0)
+				t.sb.WriteString(tag.key)
+				t.sb.WriteByte(0)
+				t.sb.WriteString(tag.value)
+			}
+			t.sb.WriteByte(0)
+			t.sb.WriteString(f.key)
+			t.sb.WriteByte(0)
+			t.sb.WriteString(f.value.Type().Nature().String())
+			seriesKey := t.sb.String()
+
+			b, ok := t.builders[seriesKey]
+			if !ok {
+				b, err = newSeriesBuilder(measurement, t.tags, f.key, f.value, t.alloc)
+				if err != nil {
+					return err
+				}
+				t.builders[seriesKey] = b
+				t.order = append(t.order, b)
+			}
+			if err := b.AppendTime(0, values.ConvertTime(ts)); err != nil {
+				return err
+			}
+			if err := b.AppendValue(1, f.value); err != nil {
+				return err
+			}
+			for j := 2; j < b.NCols(); j++ {
+				if err := b.AppendValue(j, b.Key().LabelValue(b.Cols()[j].Label)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if err := dec.Err(); err != nil {
+		return errors.Wrap(err, codes.Invalid, "failed to decode line protocol")
+	}
+	return nil
+}
+
+// result returns the tables sorted by time.
+func (t *seriesTables) result() (flux.Result, error) {
+	tables := make(table.Iterator, 0, len(t.order))
+	for _, b := range t.order {
+		b.Sort([]string{execute.DefaultTimeColLabel}, false)
+		tbl, err := b.Table()
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, tbl)
+	}
+	return &protocolResult{tables: tables}, nil
+}
+
+// newSeriesBuilder creates a table builder for a field of a series.
+// The tags must be sorted by key.
+func newSeriesBuilder(measurement string, tags []tag, field string, v values.Value, alloc memory.Allocator) (*execute.ColListTableBuilder, error) {
+	keyCols := []flux.ColMeta{
+		{Label: DefaultFieldColLabel, Type: flux.TString},
+		{Label: DefaultMeasurementColLabel, Type: flux.TString},
+	}
+	keyValues := []values.Value{
+		values.NewString(field),
+		values.NewString(measurement),
+	}
+
+	for _, t := range tags {
+		keyCols = append(keyCols, flux.ColMeta{Label: t.key, Type: flux.TString})
+		keyValues = append(keyValues, values.NewString(t.value))
+	}
+
+	b := execute.NewColListTableBuilder(execute.NewGroupKey(keyCols, keyValues), alloc)
+	cols := append([]flux.ColMeta{
+		{Label: execute.DefaultTimeColLabel, Type: flux.TTime},
+		{Label: execute.DefaultValueColLabel, Type: flux.ColumnType(v.Type())},
+	}, keyCols...)
+	for _, col := range cols {
+		if _, err := b.AddCol(col); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func fieldToValue(v lineprotocol.Value) values.Value {
+	switch v.Kind() {
+	case lineprotocol.Int:
+		return values.NewInt(v.IntV())
+	case lineprotocol.Uint:
+		return values.NewUInt(v.UintV())
+	case lineprotocol.Float:
+		return values.NewFloat(v.FloatV())
+	case lineprotocol.Bool:
+		return values.NewBool(v.BoolV())
+	default:
+		return values.NewString(v.StringV())
+	}
+}
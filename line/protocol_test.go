@@ -0,0 +1,120 @@
+package line_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/execute/table/static"
+	"github.com/influxdata/flux/line"
+)
+
+func TestProtocolDecoder(t *testing.T) {
+	input := `cpu,host=a usage=1.5,count=4i 1577836810000000000
+cpu,host=a usage=2.5 1577836800000000000
+mem,region=west,host=b free=10u,ok=true,msg="hi there"
+cpu,host=a count=5 1577836820000000000
+`
+	now := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	dec := &line.ProtocolDecoder{Now: now}
+	res, err := dec.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := static.TableGroup{
+		static.Table{
+			static.StringKey("_field", "usage"),
+			static.StringKey("_measurement", "cpu"),
+			static.StringKey("host", "a"),
+			static.Times("_time", "2020-01-01T00:00:00Z", 10),
+			static.Floats("_value", 2.5, 1.5),
+		},
+		static.Table{
+			static.StringKey("_field", "count"),
+			static.StringKey("_measurement", "cpu"),
+			static.StringKey("host", "a"),
+			static.Times("_time", "2020-01-01T00:00:10Z"),
+			static.Ints("_value", 4),
+		},
+		static.TableGroup{
+			static.StringKey("_measurement", "mem"),
+			static.StringKey("host", "b"),
+			static.StringKey("region", "west"),
+			static.Times("_time", "2020-01-02T00:00:00Z"),
+			static.Table{
+				static.StringKey("_field", "free"),
+				static.Uints("_value", 10),
+			},
+			static.Table{
+				static.StringKey("_field", "ok"),
+				static.Booleans("_value", true),
+			},
+			static.Table{
+				static.StringKey("_field", "msg"),
+				static.Strings("_value", "hi there"),
+			},
+		},
+		static.Table{
+			static.StringKey("_field", "count"),
+			static.StringKey("_measurement", "cpu"),
+			static.StringKey("host", "a"),
+			static.Times("_time", "2020-01-01T00:00:20Z"),
+			static.Floats("_value", 5),
+		},
+	}
+	if diff := table.Diff(want, res.Tables()); diff != "" {
+		t.Fatalf("unexpected tables -want/+got:\n%s", diff)
+	}
+}
+
+func TestProtocolDecoder_UnsortedTags(t *testing.T) {
+	// Both lines are in the same series even though
+	// their tags are in a different order.
+	input := `cpu,region=west,host=a usage=1.5 1577836810000000000
+cpu,host=a,region=west usage=2.5 1577836800000000000
+`
+	dec := &line.ProtocolDecoder{}
+	res, err := dec.Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := static.Table{
+		static.StringKey("_field", "usage"),
+		static.StringKey("_measurement", "cpu"),
+		static.StringKey("host", "a"),
+		static.StringKey("region", "west"),
+		static.Times("_time", "2020-01-01T00:00:00Z", 10),
+		static.Floats("_value", 2.5, 1.5),
+	}
+	if diff := table.Diff(want, res.Tables()); diff != "" {
+		t.Fatalf("unexpected tables -want/+got:\n%s", diff)
+	}
+}
+
+func TestProtocolDecoder_RoundTrip(t *testing.T) {
+	in := static.TableGroup{
+		static.StringKey("_measurement", "cpu"),
+		static.StringKey("host", "a"),
+		static.StringKey("_field", "usage"),
+		static.Table{
+			static.Times("_time", "2020-01-01T00:00:00Z", 10, 20),
+			static.Floats("_value", 1.5, 2, 3.25),
+		},
+	}
+	var buf bytes.Buffer
+	if _, err := line.NewResultEncoder(line.DefaultEncoderConfig()).Encode(&buf, &result{tables: in}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := (&line.ProtocolDecoder{}).Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := table.Diff(in, res.Tables()); diff != "" {
+		t.Fatalf("unexpected tables -want/+got:\n%s", diff)
+	}
+}
@@ -141,6 +141,19 @@ func (a *ResourceAllocator) Allocated() int64 {
 	return atomic.LoadInt64(&a.bytesAllocated)
 }
 
+// CurrentLimit returns the current limit on the amount of memory that
+// this allocator can assign. The limit may grow over time if a Manager
+// grants additional memory. The second return value is false if this
+// allocator has no limit.
+func (a *ResourceAllocator) CurrentLimit() (int64, bool) {
+	if a.Limit == nil {
+		return 0, false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return *a.Limit, true
+}
+
 // MaxAllocated reports the maximum amount of allocated memory at any point in the query.
 func (a *ResourceAllocator) MaxAllocated() int64 {
 	return atomic.LoadInt64(&a.maxAllocated)
@@ -8,8 +8,10 @@ import (
 )
 
 type Transformation struct {
-	ProcessFn func(id execute.DatasetID, tbl flux.Table) error
-	FinishFn  func(id execute.DatasetID, err error)
+	ProcessFn              func(id execute.DatasetID, tbl flux.Table) error
+	UpdateWatermarkFn      func(id execute.DatasetID, ts execute.Time) error
+	UpdateProcessingTimeFn func(id execute.DatasetID, ts execute.Time) error
+	FinishFn               func(id execute.DatasetID, err error)
 }
 
 func (t *Transformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
@@ -21,11 +23,17 @@ func (t *Transformation) Process(id execute.DatasetID, tbl flux.Table) error {
 }
 
 func (t *Transformation) UpdateWatermark(id execute.DatasetID, ts execute.Time) error {
-	return nil
+	if t.UpdateWatermarkFn == nil {
+		return nil
+	}
+	return t.UpdateWatermarkFn(id, ts)
 }
 
 func (t *Transformation) UpdateProcessingTime(id execute.DatasetID, ts execute.Time) error {
-	return nil
+	if t.UpdateProcessingTimeFn == nil {
+		return nil
+	}
+	return t.UpdateProcessingTimeFn(id, ts)
 }
 
 func (t *Transformation) Finish(id execute.DatasetID, err error) {
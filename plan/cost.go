@@ -1,10 +1,22 @@
 package plan
 
+import (
+	"context"
+	"math"
+)
+
+// Statistics describes the estimated output of a plan node.
+// A zero Cardinality means that the number of rows is not known.
 type Statistics struct {
 	Cardinality      int64
 	GroupCardinality int64
 }
 
+// Known reports whether the statistics contain a cardinality estimate.
+func (s Statistics) Known() bool {
+	return s.Cardinality > 0
+}
+
 // Cost stores various dimensions of the cost of a query plan
 type Cost struct {
 	Disk int64
@@ -25,9 +37,157 @@ func Add(a Cost, b Cost) Cost {
 	}
 }
 
+// DefaultCost is the cost of an operation that does a constant
+// amount of work per row and does not add rows to its input.
+// The statistics of the inputs are reported as the output statistics,
+// which is an upper bound for operations that remove rows.
 type DefaultCost struct {
 }
 
 func (c DefaultCost) Cost(inStats []Statistics) (Cost, Statistics) {
-	return Cost{}, Statistics{}
+	return Cost{}, MergeStatistics(inStats)
+}
+
+// MergeStatistics combines the statistics of multiple inputs
+// into the statistics of a single stream containing all of them.
+// The result is unknown when any of the inputs is unknown.
+func MergeStatistics(stats []Statistics) Statistics {
+	if len(stats) == 0 {
+		return Statistics{}
+	}
+	var out Statistics
+	for _, s := range stats {
+		if !s.Known() {
+			return Statistics{}
+		}
+		out.Cardinality += s.Cardinality
+		out.GroupCardinality += s.GroupCardinality
+	}
+	return out
+}
+
+// StatisticsEstimator is implemented by procedure specs that need the
+// dependencies in the context to estimate the statistics of their output,
+// such as a source that asks its storage for the number of rows it will read.
+//
+// When a procedure spec implements this interface, it is used instead
+// of the output statistics reported by its Cost method.
+type StatisticsEstimator interface {
+	EstimateStatistics(ctx context.Context, inStats []Statistics) Statistics
+}
+
+// EstimateStatistics estimates the statistics of the output of the node
+// by estimating the statistics of its predecessors and passing them
+// to the procedure spec of the node.
+//
+// Logical nodes whose procedure spec does not report a cost pass the
+// statistics of their inputs through unchanged.
+func EstimateStatistics(ctx context.Context, node Node) Statistics {
+	return getEstimates(ctx).statistics(ctx, node)
+}
+
+// EstimateCost estimates the total cost of producing the output of the node,
+// which is the cost of the node itself added to the costs of its predecessors.
+func EstimateCost(ctx context.Context, node Node) Cost {
+	return getEstimates(ctx).cost(ctx, node)
+}
+
+type estimatesKey struct{}
+
+// estimates remembers the statistics and costs estimated for each node
+// so a node that is the input of several others, or whose estimate is
+// requested by several rules, is only estimated once. A source may ask its
+// storage for its estimate, so this also avoids repeating those requests.
+//
+// The estimates are kept for one pass of a planner and are reset
+// whenever a rule changes the plan.
+type estimates struct {
+	stats map[Node]Statistics
+	costs map[Node]Cost
+}
+
+func newEstimates() *estimates {
+	return &estimates{
+		stats: make(map[Node]Statistics),
+		costs: make(map[Node]Cost),
+	}
+}
+
+// withEstimates returns a context that remembers the estimates
+// made with it until they are reset.
+func withEstimates(ctx context.Context) context.Context {
+	return context.WithValue(ctx, estimatesKey{}, newEstimates())
+}
+
+// getEstimates returns the estimates remembered by the context.
+// When the context does not remember estimates, the returned
+// estimates are only kept for a single call.
+func getEstimates(ctx context.Context) *estimates {
+	if e, ok := ctx.Value(estimatesKey{}).(*estimates); ok {
+		return e
+	}
+	return newEstimates()
+}
+
+// resetEstimates forgets the estimates remembered by the context.
+func resetEstimates(ctx context.Context) {
+	if e, ok := ctx.Value(estimatesKey{}).(*estimates); ok {
+		*e = *newEstimates()
+	}
+}
+
+func (e *estimates) statistics(ctx context.Context, node Node) Statistics {
+	if stats, ok := e.stats[node]; ok {
+		return stats
+	}
+
+	preds := node.Predecessors()
+	inStats := make([]Statistics, len(preds))
+	for i, pred := range preds {
+		inStats[i] = e.statistics(ctx, pred)
+	}
+
+	var stats Statistics
+	switch spec := node.ProcedureSpec().(type) {
+	case StatisticsEstimator:
+		stats = spec.EstimateStatistics(ctx, inStats)
+	case PhysicalProcedureSpec:
+		_, stats = spec.Cost(inStats)
+	default:
+		stats = MergeStatistics(inStats)
+	}
+	e.stats[node] = stats
+	return stats
+}
+
+func (e *estimates) cost(ctx context.Context, node Node) Cost {
+	if cost, ok := e.costs[node]; ok {
+		return cost
+	}
+
+	var total Cost
+	preds := node.Predecessors()
+	inStats := make([]Statistics, len(preds))
+	for i, pred := range preds {
+		total = Add(total, e.cost(ctx, pred))
+		inStats[i] = e.statistics(ctx, pred)
+	}
+	if spec, ok := node.ProcedureSpec().(PhysicalProcedureSpec); ok {
+		cost, _ := spec.Cost(inStats)
+		total = Add(total, cost)
+	}
+	e.costs[node] = total
+	return total
+}
+
+// SortCost returns the cost of sorting the rows described by the statistics.
+func SortCost(stats Statistics) Cost {
+	if !stats.Known() {
+		return Cost{}
+	}
+	n := float64(stats.Cardinality)
+	return Cost{
+		CPU: int64(n * math.Log2(n+1)),
+		MEM: stats.Cardinality,
+	}
 }
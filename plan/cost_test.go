@@ -0,0 +1,280 @@
+package plan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+)
+
+const mockSourceKind = "mock-source"
+
+// mockSourceSpec is a source that reports a fixed cardinality.
+type mockSourceSpec struct {
+	cardinality int64
+}
+
+func (s mockSourceSpec) Kind() plan.ProcedureKind {
+	return mockSourceKind
+}
+
+func (s mockSourceSpec) Copy() plan.ProcedureSpec {
+	return s
+}
+
+func (s mockSourceSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	return plan.Cost{Disk: s.cardinality}, plan.Statistics{Cardinality: s.cardinality}
+}
+
+// mockEstimatorSpec estimates its cardinality from the context.
+type mockEstimatorSpec struct {
+	mockSourceSpec
+}
+
+type cardinalityKey struct{}
+
+func (s mockEstimatorSpec) EstimateStatistics(ctx context.Context, inStats []plan.Statistics) plan.Statistics {
+	n, _ := ctx.Value(cardinalityKey{}).(int64)
+	return plan.Statistics{Cardinality: n}
+}
+
+func TestEstimateStatistics(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec *plantest.PlanSpec
+		want plan.Statistics
+	}{
+		{
+			name: "source",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("source", mockSourceSpec{cardinality: 100}),
+				},
+			},
+			want: plan.Statistics{Cardinality: 100},
+		},
+		{
+			name: "pass through",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("source", mockSourceSpec{cardinality: 100}),
+					plantest.CreatePhysicalMockNode("0"),
+					plantest.CreateLogicalMockNode("1"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			want: plan.Statistics{Cardinality: 100},
+		},
+		{
+			name: "merged inputs",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("a", mockSourceSpec{cardinality: 100}),
+					plan.CreatePhysicalNode("b", mockSourceSpec{cardinality: 50}),
+					plantest.CreatePhysicalMockNode("union"),
+				},
+				Edges: [][2]int{
+					{0, 2},
+					{1, 2},
+				},
+			},
+			want: plan.Statistics{Cardinality: 150},
+		},
+		{
+			name: "unknown input",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("a", mockSourceSpec{cardinality: 100}),
+					plantest.CreatePhysicalMockNode("b"),
+					plantest.CreatePhysicalMockNode("union"),
+				},
+				Edges: [][2]int{
+					{0, 2},
+					{1, 2},
+				},
+			},
+			want: plan.Statistics{},
+		},
+		{
+			name: "estimator",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("source", mockEstimatorSpec{}),
+					plantest.CreatePhysicalMockNode("0"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+			},
+			want: plan.Statistics{Cardinality: 42},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), cardinalityKey{}, int64(42))
+			ps := plantest.CreatePlanSpec(tc.spec)
+			for root := range ps.Roots {
+				if got := plan.EstimateStatistics(ctx, root); got != tc.want {
+					t.Fatalf("unexpected statistics: want %+v, got %+v", tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	ps := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plan.CreatePhysicalNode("a", mockSourceSpec{cardinality: 100}),
+			plan.CreatePhysicalNode("b", mockSourceSpec{cardinality: 50}),
+			plantest.CreatePhysicalMockNode("union"),
+		},
+		Edges: [][2]int{
+			{0, 2},
+			{1, 2},
+		},
+	})
+	for root := range ps.Roots {
+		if want, got := (plan.Cost{Disk: 150}), plan.EstimateCost(context.Background(), root); got != want {
+			t.Fatalf("unexpected cost: want %+v, got %+v", want, got)
+		}
+	}
+}
+
+func TestParallelFactor(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		cardinality int64
+		max         int
+		want        int
+	}{
+		{
+			name: "unknown",
+			max:  8,
+			want: 1,
+		},
+		{
+			name:        "small",
+			cardinality: 10,
+			max:         8,
+			want:        1,
+		},
+		{
+			name:        "partial partition",
+			cardinality: 2*plan.RowsPerParallelPartition + 1,
+			max:         8,
+			want:        3,
+		},
+		{
+			name:        "large",
+			cardinality: 100 * plan.RowsPerParallelPartition,
+			max:         8,
+			want:        8,
+		},
+		{
+			name:        "no parallelism",
+			cardinality: 100 * plan.RowsPerParallelPartition,
+			max:         1,
+			want:        1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			node := plan.CreatePhysicalNode("source", mockSourceSpec{cardinality: tc.cardinality})
+			if got := plan.ParallelFactor(context.Background(), node, tc.max); got != tc.want {
+				t.Fatalf("unexpected factor: want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCostBasedRules(t *testing.T) {
+	ps := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plantest.CreateLogicalMockNode("0"),
+			plantest.CreateLogicalMockNode("1"),
+		},
+		Edges: [][2]int{
+			{0, 1},
+		},
+	})
+
+	var visited int
+	planner := plan.NewPhysicalPlanner(
+		plan.OnlyPhysicalRules(),
+		plan.AddCostBasedRules(&plantest.FunctionRule{
+			RewriteFn: func(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+				// The cost-based rules run after the
+				// nodes have been converted to physical nodes.
+				if _, ok := node.(*plan.PhysicalPlanNode); !ok {
+					t.Errorf("node %s is not a physical node", node.ID())
+				}
+				visited++
+				return node, false, nil
+			},
+		}),
+		plan.DisableValidation(),
+	)
+	if _, err := planner.Plan(context.Background(), ps); err != nil {
+		t.Fatal(err)
+	}
+	if visited != 2 {
+		t.Fatalf("expected the cost-based rule to visit 2 nodes, visited %d", visited)
+	}
+}
+
+// countingEstimatorSpec counts the number of times its statistics are estimated.
+type countingEstimatorSpec struct {
+	mockSourceSpec
+	calls *int
+}
+
+func (s countingEstimatorSpec) EstimateStatistics(ctx context.Context, inStats []plan.Statistics) plan.Statistics {
+	*s.calls++
+	return plan.Statistics{Cardinality: s.cardinality}
+}
+
+func TestEstimates_Memoized(t *testing.T) {
+	var calls int
+	ps := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plan.CreatePhysicalNode("source", countingEstimatorSpec{
+				mockSourceSpec: mockSourceSpec{cardinality: 100},
+				calls:          &calls,
+			}),
+			plantest.CreatePhysicalMockNode("a"),
+			plantest.CreatePhysicalMockNode("b"),
+			plantest.CreatePhysicalMockNode("union"),
+			plantest.CreatePhysicalMockNode("yield"),
+		},
+		Edges: [][2]int{
+			{0, 1},
+			{0, 2},
+			{1, 3},
+			{2, 3},
+			{3, 4},
+		},
+	})
+
+	planner := plan.NewPhysicalPlanner(
+		plan.OnlyPhysicalRules(),
+		plan.AddCostBasedRules(&plantest.FunctionRule{
+			RewriteFn: func(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+				plan.EstimateStatistics(ctx, node)
+				plan.EstimateCost(ctx, node)
+				return node, false, nil
+			},
+		}),
+		plan.DisableValidation(),
+	)
+	if _, err := planner.Plan(context.Background(), ps); err != nil {
+		t.Fatal(err)
+	}
+	// The physical pass does not estimate the plan and the source
+	// is estimated once by the cost-based pass.
+	if calls != 1 {
+		t.Fatalf("expected the source to be estimated once, estimated %d times", calls)
+	}
+}
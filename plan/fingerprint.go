@@ -0,0 +1,162 @@
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+var errNotCacheable = errors.New(codes.Unimplemented, "plan cannot be fingerprinted")
+
+// FingerprintProcedureSpec is implemented by procedure specs
+// whose results can be cached.
+type FingerprintProcedureSpec interface {
+	// Fingerprint writes every field of the spec that changes its
+	// results to the Fingerprinter. It returns false if the results
+	// of the spec cannot be cached, such as when a source reads data
+	// that can change between queries.
+	Fingerprint(f *Fingerprinter) bool
+}
+
+// Fingerprint returns a hash of the plan that includes the procedure spec
+// and the time bounds of every node. Plans with the same fingerprint
+// produce the same results.
+//
+// The second return value is false if the results of the plan cannot be
+// identified by a fingerprint. This happens when a procedure is not a
+// FingerprintProcedureSpec, when its Fingerprint method returns false,
+// or when a procedure has side effects.
+func Fingerprint(p *Spec) (string, bool) {
+	h := sha256.New()
+	f := &Fingerprinter{w: h}
+	if err := p.BottomUpWalk(func(node Node) error {
+		spec := node.ProcedureSpec()
+		fs, ok := spec.(FingerprintProcedureSpec)
+		if !ok || HasSideEffect(spec) {
+			return errNotCacheable
+		}
+
+		_, _ = fmt.Fprintf(h, "node %s %s\n", node.ID(), spec.Kind())
+		for _, pred := range node.Predecessors() {
+			_, _ = fmt.Fprintf(h, "pred %s\n", pred.ID())
+		}
+		if b := node.Bounds(); b != nil {
+			_, _ = fmt.Fprintf(h, "bounds %d %d\n", b.Start, b.Stop)
+		}
+		if !fs.Fingerprint(f) {
+			return errNotCacheable
+		}
+		return nil
+	}); err != nil {
+		return "", false
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// Fingerprinter writes the fields of a procedure spec to the
+// fingerprint of a plan. Each value is written with a terminator
+// so that adjacent values cannot be confused with each other.
+type Fingerprinter struct {
+	w io.Writer
+}
+
+func (f *Fingerprinter) write(s string) {
+	_, _ = io.WriteString(f.w, s)
+}
+
+// String writes a string to the fingerprint.
+func (f *Fingerprinter) String(s string) {
+	f.write(strconv.Quote(s) + ";")
+}
+
+// Strings writes a list of strings to the fingerprint.
+func (f *Fingerprinter) Strings(ss []string) {
+	f.write("[" + strconv.Itoa(len(ss)) + ":")
+	for _, s := range ss {
+		f.String(s)
+	}
+	f.write("]")
+}
+
+// Int writes an integer to the fingerprint.
+func (f *Fingerprinter) Int(i int64) {
+	f.write(strconv.FormatInt(i, 10) + ";")
+}
+
+// Bool writes a boolean to the fingerprint.
+func (f *Fingerprinter) Bool(b bool) {
+	f.write(strconv.FormatBool(b) + ";")
+}
+
+// Value writes a value to the fingerprint. It returns false for
+// values that cannot be written, such as functions.
+func (f *Fingerprinter) Value(v values.Value) bool {
+	if v == nil || v.IsNull() {
+		f.write("null;")
+		return true
+	}
+	switch v.Type().Nature() {
+	case semantic.String:
+		f.write("string:")
+		f.String(v.Str())
+	case semantic.Int:
+		f.write("int:")
+		f.Int(v.Int())
+	case semantic.UInt:
+		f.write("uint:" + strconv.FormatUint(v.UInt(), 10) + ";")
+	case semantic.Float:
+		f.write("float:" + strconv.FormatFloat(v.Float(), 'g', -1, 64) + ";")
+	case semantic.Bool:
+		f.write("bool:")
+		f.Bool(v.Bool())
+	case semantic.Time:
+		f.write("time:")
+		f.Int(int64(v.Time()))
+	case semantic.Duration:
+		f.write("duration:")
+		f.String(v.Duration().String())
+	case semantic.Regexp:
+		f.write("regexp:")
+		f.String(v.Regexp().String())
+	case semantic.Bytes:
+		f.write("bytes:")
+		f.String(string(v.Bytes()))
+	case semantic.Array:
+		arr := v.Array()
+		f.write("array[" + strconv.Itoa(arr.Len()) + ":")
+		ok := true
+		arr.Range(func(i int, v values.Value) {
+			ok = ok && f.Value(v)
+		})
+		if !ok {
+			return false
+		}
+		f.write("]")
+	case semantic.Object:
+		obj := v.Object()
+		f.write("record{" + strconv.Itoa(obj.Len()) + ":")
+		ok := true
+		obj.Range(func(name string, v values.Value) {
+			if ok {
+				f.String(name)
+				ok = f.Value(v)
+			}
+		})
+		if !ok {
+			return false
+		}
+		f.write("}")
+	default:
+		// Functions, dictionaries and other values do not have a
+		// representation that is known to identify their contents.
+		return false
+	}
+	return true
+}
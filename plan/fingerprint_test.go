@@ -0,0 +1,120 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+type fingerprintSpec struct {
+	plan.DefaultCost
+	File      string
+	cacheable bool
+}
+
+func (s *fingerprintSpec) Kind() plan.ProcedureKind { return "fingerprint" }
+func (s *fingerprintSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+func (s *fingerprintSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.String(s.File)
+	return s.cacheable
+}
+
+func TestFingerprint(t *testing.T) {
+	newPlan := func(file string, cacheable bool, bounds *plan.Bounds) *plan.Spec {
+		source := plantest.CreatePhysicalNode("source", &fingerprintSpec{
+			File:      file,
+			cacheable: cacheable,
+		})
+		source.SetBounds(bounds)
+		return plantest.CreatePlanSpec(&plantest.PlanSpec{
+			Nodes: []plan.Node{
+				source,
+				plantest.CreatePhysicalNode("yield", &fingerprintSpec{cacheable: true}),
+			},
+			Edges: [][2]int{{0, 1}},
+		})
+	}
+	bounds := func(start, stop values.Time) *plan.Bounds {
+		return &plan.Bounds{Start: start, Stop: stop}
+	}
+
+	want, ok := plan.Fingerprint(newPlan("a.csv", true, bounds(0, 10)))
+	if !ok {
+		t.Fatal("expected plan to have a fingerprint")
+	}
+	if got, _ := plan.Fingerprint(newPlan("a.csv", true, bounds(0, 10))); got != want {
+		t.Errorf("expected identical plans to have the same fingerprint: %s != %s", want, got)
+	}
+	if got, _ := plan.Fingerprint(newPlan("b.csv", true, bounds(0, 10))); got == want {
+		t.Error("expected a different spec to change the fingerprint")
+	}
+	if got, _ := plan.Fingerprint(newPlan("a.csv", true, bounds(0, 20))); got == want {
+		t.Error("expected different bounds to change the fingerprint")
+	}
+	if _, ok := plan.Fingerprint(newPlan("a.csv", false, bounds(0, 10))); ok {
+		t.Error("expected a plan with a source that cannot be cached to have no fingerprint")
+	}
+
+	// A procedure that does not implement Fingerprint cannot be cached.
+	source := plantest.CreatePhysicalNode("source", &fingerprintSpec{File: "a.csv", cacheable: true})
+	p := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			source,
+			plantest.CreatePhysicalMockNode("filter"),
+		},
+		Edges: [][2]int{{0, 1}},
+	})
+	if _, ok := plan.Fingerprint(p); ok {
+		t.Error("expected a plan with a procedure without a fingerprint to have no fingerprint")
+	}
+}
+
+func TestFingerprinter_Value(t *testing.T) {
+	fingerprint := func(v values.Value) (string, bool) {
+		source := plantest.CreatePhysicalNode("source", &valueSpec{v: v})
+		return plan.Fingerprint(plantest.CreatePlanSpec(&plantest.PlanSpec{
+			Nodes: []plan.Node{source},
+		}))
+	}
+	row := func(v values.Value) values.Value {
+		return values.NewObjectWithValues(map[string]values.Value{"_value": v})
+	}
+
+	want, ok := fingerprint(row(values.NewInt(1)))
+	if !ok {
+		t.Fatal("expected a record of basic values to have a fingerprint")
+	}
+	if got, _ := fingerprint(row(values.NewInt(1))); got != want {
+		t.Errorf("expected identical values to have the same fingerprint: %s != %s", want, got)
+	}
+	if got, _ := fingerprint(row(values.NewUInt(1))); got == want {
+		t.Error("expected a value of a different type to change the fingerprint")
+	}
+	if got, _ := fingerprint(row(values.NewString("1"))); got == want {
+		t.Error("expected a value of a different type to change the fingerprint")
+	}
+	fn := values.NewFunction("fn", semantic.NewFunctionType(semantic.BasicInt, nil), nil, false)
+	if _, ok := fingerprint(row(fn)); ok {
+		t.Error("expected a function to have no fingerprint")
+	}
+}
+
+type valueSpec struct {
+	plan.DefaultCost
+	v values.Value
+}
+
+func (s *valueSpec) Kind() plan.ProcedureKind { return "value" }
+func (s *valueSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+func (s *valueSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	return f.Value(s.v)
+}
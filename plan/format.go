@@ -3,6 +3,7 @@ package plan
 import (
 	"fmt"
 	"runtime/debug"
+	"sort"
 	"strings"
 )
 
@@ -29,6 +30,14 @@ func WithDetails() FormatOption {
 	}
 }
 
+// WithPhysicalAttributes returns a FormatOption that can be used to include
+// the output attributes of each physical node in a formatted plan.
+func WithPhysicalAttributes() FormatOption {
+	return func(f *formatter) {
+		f.withAttributes = true
+	}
+}
+
 // Detailer provides an optional interface that ProcedureSpecs can implement.
 // Implementors of this interface will have their details appear in the
 // formatted output for a plan if the WithDetails() option is set.
@@ -37,8 +46,9 @@ type Detailer interface {
 }
 
 type formatter struct {
-	withDetails bool
-	p           *Spec
+	withDetails    bool
+	withAttributes bool
+	p              *Spec
 }
 
 func (f formatter) Format(fs fmt.State, c rune) {
@@ -61,7 +71,8 @@ func (f formatter) Format(fs fmt.State, c rune) {
 				details += d.PlanDetails() + "\n"
 			}
 
-			if ppn, ok := pn.(*PhysicalPlanNode); ok {
+			// Attributes are listed separately when they are included.
+			if ppn, ok := pn.(*PhysicalPlanNode); ok && !f.withAttributes {
 				for _, attr := range ppn.outputAttrs() {
 					if d, ok := attr.(Detailer); ok {
 						details += d.PlanDetails() + "\n"
@@ -77,6 +88,17 @@ func (f formatter) Format(fs fmt.State, c rune) {
 				}
 			}
 		}
+		if ppn, ok := pn.(*PhysicalPlanNode); ok && f.withAttributes {
+			attrs := ppn.outputAttrs()
+			keys := make([]string, 0, len(attrs))
+			for key := range attrs {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				_, _ = fmt.Fprintf(fs, "  // %v\n", attrs[key])
+			}
+		}
 		for _, pred := range pn.Predecessors() {
 			edges = append(edges, fmt.Sprintf("  %v -> %v", pred.ID(), pn.ID()))
 		}
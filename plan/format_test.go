@@ -29,6 +29,7 @@ func TestFormatted(t *testing.T) {
 	type testcase struct {
 		name string
 		plan *plantest.PlanSpec
+		opts []plan.FormatOption
 		want string
 	}
 
@@ -90,6 +91,49 @@ func TestFormatted(t *testing.T) {
 
   source -> merge
 }
+`,
+		},
+		{
+			name: "physical attributes",
+			plan: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plantest.CreatePhysicalNode("source", spec.MockProcedureSpec{
+						OutputAttributesFn: func() plan.PhysicalAttributes {
+							return plan.PhysicalAttributes{plan.ParallelRunKey: plan.ParallelRunAttribute{Factor: 8}}
+						},
+					}),
+					plantest.CreatePhysicalNode("merge", spec.MockProcedureSpec{
+						RequiredAttributesFn: func() []plan.PhysicalAttributes {
+							return []plan.PhysicalAttributes{
+								{plan.ParallelRunKey: plan.ParallelRunAttribute{Factor: 8}},
+							}
+						},
+						OutputAttributesFn: func() plan.PhysicalAttributes {
+							return plan.PhysicalAttributes{
+								plan.CollationKey:     &plan.CollationAttr{Columns: []string{"_time"}},
+								plan.ParallelMergeKey: plan.ParallelMergeAttribute{Factor: 8},
+							}
+						},
+						PlanDetailsFn: func() string {
+							return "*** spec details ***"
+						},
+					}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+			},
+			opts: []plan.FormatOption{plan.WithDetails(), plan.WithPhysicalAttributes()},
+			want: `digraph {
+  source
+  // parallel-run{Factor: 8}
+  merge
+  // *** spec details ***
+  // collation{Columns: [_time], Desc: false}
+  // parallel-merge{Factor: 8}
+
+  source -> merge
+}
 `,
 		},
 	}
@@ -98,7 +142,11 @@ func TestFormatted(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			ps := plantest.CreatePlanSpec(tc.plan)
-			got := fmt.Sprintf("%v", plan.Formatted(ps, plan.WithDetails()))
+			opts := tc.opts
+			if opts == nil {
+				opts = []plan.FormatOption{plan.WithDetails()}
+			}
+			got := fmt.Sprintf("%v", plan.Formatted(ps, opts...))
 			if tc.want != got {
 				t.Fatalf("unexpected output: -want/+got:\n%v", diff.LineDiff(tc.want, got))
 			}
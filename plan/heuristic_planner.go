@@ -51,6 +51,9 @@ func applyRule(ctx context.Context, rule Rule, node Node) (Node, bool, error) {
 			return nil, false, errors.Newf(codes.Internal, "rule %q returned a nil plan node even though it seems to have changed the plan", rule.Name())
 		}
 		testing.MarkInvokedPlannerRule(ctx, rule.Name())
+		// The rewrite may change the inputs of any node
+		// so the estimates made before it are forgotten.
+		resetEstimates(ctx)
 		return newNode, true, nil
 	}
 
@@ -102,6 +105,7 @@ func (p *heuristicPlanner) matchRules(ctx context.Context, node Node) (Node, boo
 // Plan may change its argument and/or return a new instance of Spec, so the correct way to call Plan is:
 //     plan, err = plan.Plan(plan)
 func (p *heuristicPlanner) Plan(ctx context.Context, inputPlan *Spec) (*Spec, error) {
+	ctx = withEstimates(ctx)
 	for anyChanged := true; anyChanged; {
 		visited := make(map[Node]struct{})
 		visitedSuccessors := make(map[Node]int)
@@ -1,6 +1,7 @@
 package plan
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -37,6 +38,88 @@ func (a ParallelRunAttribute) String() string {
 	return fmt.Sprintf("%v{Factor: %d}", ParallelRunKey, a.Factor)
 }
 
+const ParallelGroupRunKey = "parallel-group-run"
+
+// ParallelGroupRunAttribute means the node executes in parallel on partitions of the group keys.
+// Unlike the partitions of ParallelRunAttribute, every table with a given group key is in the
+// same partition, so operations that process each group independently can pass it through.
+type ParallelGroupRunAttribute struct {
+	Factor int
+}
+
+var _ PhysicalAttr = ParallelGroupRunAttribute{}
+
+func (ParallelGroupRunAttribute) Key() string { return ParallelGroupRunKey }
+
+// SuccessorsMustRequire implements the PhysicalAttribute interface.
+// Like the parallel-run attribute, partitions of the group keys
+// must be merged by a successor.
+func (ParallelGroupRunAttribute) SuccessorsMustRequire() bool {
+	return true
+}
+
+func (a ParallelGroupRunAttribute) SatisfiedBy(attr PhysicalAttr) bool {
+	other, ok := attr.(ParallelGroupRunAttribute)
+	if !ok {
+		return false
+	}
+	return a == other
+}
+
+func (a ParallelGroupRunAttribute) String() string {
+	return fmt.Sprintf("%v{Factor: %d}", ParallelGroupRunKey, a.Factor)
+}
+
+// ParallelRouter is implemented by procedure specs that produce parallel data
+// from a single copy of the node. The node sends each table to one of the
+// parallel copies of its successors instead of every copy reading all of the tables.
+type ParallelRouter interface {
+	ParallelRouter()
+}
+
+// ParallelRunFactor returns the number of copies of the node that run in parallel.
+// It is one unless the node produces parallel data and is not a ParallelRouter.
+func ParallelRunFactor(node *PhysicalPlanNode) int {
+	if _, ok := node.ProcedureSpec().(ParallelRouter); ok {
+		return 1
+	}
+	if attr := GetOutputAttribute(node, ParallelRunKey); attr != nil {
+		return attr.(ParallelRunAttribute).Factor
+	}
+	if attr := GetOutputAttribute(node, ParallelGroupRunKey); attr != nil {
+		return attr.(ParallelGroupRunAttribute).Factor
+	}
+	return 1
+}
+
+// RowsPerParallelPartition is the estimated number of rows each
+// partition of a parallel read should produce. Small inputs are
+// read with fewer partitions because the cost of starting and merging
+// a partition outweighs the time saved by reading it in parallel.
+const RowsPerParallelPartition = 100_000
+
+// ParallelFactor returns the factor of the ParallelRunAttribute to use
+// for reading the output of the node in parallel, up to max partitions.
+//
+// The factor is chosen from the estimated cardinality of the node so that
+// each partition reads about RowsPerParallelPartition rows.
+// When the cardinality is not known, the node is not read in parallel
+// and one is returned.
+func ParallelFactor(ctx context.Context, node Node, max int) int {
+	if max <= 1 {
+		return 1
+	}
+	stats := EstimateStatistics(ctx, node)
+	if !stats.Known() {
+		return 1
+	}
+	factor := (stats.Cardinality + RowsPerParallelPartition - 1) / RowsPerParallelPartition
+	if factor > int64(max) {
+		return max
+	}
+	return int(factor)
+}
+
 const ParallelMergeKey = "parallel-merge"
 
 // ParallelMergeAttribute means that the node accepts parallel data, merges the streams, and produces non-parallel
@@ -22,6 +22,7 @@ type PhysicalPlanner interface {
 func NewPhysicalPlanner(options ...PhysicalOption) PhysicalPlanner {
 	pp := &physicalPlanner{
 		heuristicPlannerPhysical: newHeuristicPlanner(),
+		heuristicPlannerCost:     newHeuristicPlanner(),
 		heuristicPlannerParallel: newHeuristicPlanner(),
 		defaultMemoryLimit:       math.MaxInt64,
 	}
@@ -33,6 +34,13 @@ func NewPhysicalPlanner(options ...PhysicalOption) PhysicalPlanner {
 		i++
 	}
 
+	rulesCost := make([]Rule, len(ruleNameToCostBasedRule))
+	i = 0
+	for _, v := range ruleNameToCostBasedRule {
+		rulesCost[i] = v
+		i++
+	}
+
 	rulesParallel := make([]Rule, len(ruleNameToParallelizeRules))
 	i = 0
 	for _, v := range ruleNameToParallelizeRules {
@@ -44,6 +52,8 @@ func NewPhysicalPlanner(options ...PhysicalOption) PhysicalPlanner {
 
 	pp.heuristicPlannerPhysical.addRules(physicalConverterRule{})
 
+	pp.heuristicPlannerCost.addRules(rulesCost...)
+
 	pp.heuristicPlannerParallel.addRules(rulesParallel...)
 
 	// Options may add or remove rules, so process them after we've
@@ -61,6 +71,13 @@ func (pp *physicalPlanner) Plan(ctx context.Context, spec *Spec) (*Spec, error)
 		return nil, err
 	}
 
+	// The cost-based rules run once the physical operations are known
+	// so they can compare the estimated costs of the physical plan.
+	intermediateSpec, err = pp.heuristicPlannerCost.Plan(ctx, intermediateSpec)
+	if err != nil {
+		return nil, err
+	}
+
 	transformedSpec, err := pp.heuristicPlannerParallel.Plan(ctx, intermediateSpec)
 	if err != nil {
 		return nil, err
@@ -126,6 +143,7 @@ func ValidatePhysicalPlan(plan *Spec) error {
 
 type physicalPlanner struct {
 	heuristicPlannerPhysical *heuristicPlanner
+	heuristicPlannerCost     *heuristicPlanner
 	heuristicPlannerParallel *heuristicPlanner
 	defaultMemoryLimit       int64
 	disableValidation        bool
@@ -154,6 +172,7 @@ func WithDefaultMemoryLimit(memBytes int64) PhysicalOption {
 func OnlyPhysicalRules(rules ...Rule) PhysicalOption {
 	return physicalOption(func(pp *physicalPlanner) {
 		pp.heuristicPlannerPhysical.clearRules()
+		pp.heuristicPlannerCost.clearRules()
 		pp.heuristicPlannerParallel.clearRules()
 		// Always add physicalConverterRule. It doesn't change the plan but only convert nodes to physical.
 		// This is required for some pieces to work on the physical plan (e.g. SetTriggerSpec).
@@ -168,9 +187,17 @@ func AddParallelRules(rules ...Rule) PhysicalOption {
 	})
 }
 
+// AddCostBasedRules adds rules to the cost-based pass of the physical plan.
+func AddCostBasedRules(rules ...Rule) PhysicalOption {
+	return physicalOption(func(pp *physicalPlanner) {
+		pp.heuristicPlannerCost.addRules(rules...)
+	})
+}
+
 func RemovePhysicalRules(rules ...string) PhysicalOption {
 	return physicalOption(func(pp *physicalPlanner) {
 		pp.heuristicPlannerPhysical.removeRules(rules...)
+		pp.heuristicPlannerCost.removeRules(rules...)
 		pp.heuristicPlannerParallel.removeRules(rules...)
 	})
 }
@@ -73,6 +73,7 @@ func HasSideEffect(spec ProcedureSpec) bool {
 var ruleNameToLogicalRule = make(map[string]Rule)
 var ruleNameToPhysicalRule = make(map[string]Rule)
 var ruleNameToParallelizeRules = make(map[string]Rule)
+var ruleNameToCostBasedRule = make(map[string]Rule)
 
 // RegisterLogicalRules registers the rule created by createFn with the logical plan.
 func RegisterLogicalRules(rules ...Rule) {
@@ -89,6 +90,19 @@ func RegisterParallelizeRules(rules ...Rule) {
 	registerRule(ruleNameToParallelizeRules, rules...)
 }
 
+// RegisterCostBasedRules registers rules with the cost-based pass of the physical plan.
+// These rules run after the physical rules and before the parallelize rules
+// and may use EstimateStatistics and EstimateCost to decide how to rewrite a node.
+//
+// The estimates currently decide whether a group is pushed down into sql.from
+// and the factor of the parallelize rules. The order of joins is not chosen
+// by cost because the joins are binary and swapping their inputs changes
+// their result. A sort is not pushed down into a source because the databases
+// order strings and nulls differently than flux does.
+func RegisterCostBasedRules(rules ...Rule) {
+	registerRule(ruleNameToCostBasedRule, rules...)
+}
+
 func registerRule(ruleMap map[string]Rule, rules ...Rule) {
 	for _, rule := range rules {
 		name := rule.Name()
@@ -103,4 +117,5 @@ func ClearRegisteredRules() {
 	ruleNameToLogicalRule = make(map[string]Rule)
 	ruleNameToPhysicalRule = make(map[string]Rule)
 	ruleNameToParallelizeRules = make(map[string]Rule)
+	ruleNameToCostBasedRule = make(map[string]Rule)
 }
@@ -301,6 +301,14 @@ func (t *Transpiler) transpileBinaryExpr(b *promql.BinaryExpr) (ast.Expression,
 						}),
 					)
 				}
+				// Comparison filters return the sample value of the left-hand side.
+				opCalls = append(
+					opCalls,
+					call("duplicate", map[string]ast.Expression{
+						"column": &ast.StringLiteral{Value: "_value_lhs"},
+						"as":     &ast.StringLiteral{Value: "_value"},
+					}),
+				)
 				dropField = false
 			}
 		} else {
@@ -309,6 +317,10 @@ func (t *Transpiler) transpileBinaryExpr(b *promql.BinaryExpr) (ast.Expression,
 
 		onCols := append(b.VectorMatching.MatchingLabels, "_start", "_stop")
 
+		if card := b.VectorMatching.Card; card == promql.CardManyToOne || card == promql.CardOneToMany {
+			return t.transpileGroupedBinaryExpr(lhs, rhs, card, onCols, b.VectorMatching.Include, opCalls, dropField), nil
+		}
+
 		outputColTransformCalls := []*ast.CallExpression{
 			call("keep", map[string]ast.Expression{
 				"columns": columnList(append(append(onCols, "_value"), b.VectorMatching.Include...)...),
@@ -351,3 +363,96 @@ func (t *Transpiler) transpileBinaryExpr(b *promql.BinaryExpr) (ast.Expression,
 		), nil
 	}
 }
+
+// transpileGroupedBinaryExpr transpiles a binary operation with a
+// group_left() or group_right() modifier. Each series of the "many" side
+// is matched with the one series of the "one" side that has the same on()
+// labels. The result keeps the labels of the "many" side and the labels of
+// the "one" side that are listed in the modifier.
+func (t *Transpiler) transpileGroupedBinaryExpr(lhs, rhs ast.Expression, card promql.VectorMatchCardinality, onCols, include []string, opCalls []*ast.CallExpression, dropField bool) ast.Expression {
+	manySide, oneSide := "lhs", "rhs"
+	if card == promql.CardOneToMany {
+		manySide, oneSide = oneSide, manySide
+	}
+
+	// Only keep the matching and included labels of the "one" side
+	// so that they are the only ones that can conflict in the join.
+	keepOneCall := call("keep", map[string]ast.Expression{
+		"columns": columnList(append(append(onCols, "_value"), include...)...),
+	})
+	if oneSide == "lhs" {
+		lhs = buildPipeline(lhs, keepOneCall)
+	} else {
+		rhs = buildPipeline(rhs, keepOneCall)
+	}
+
+	// An included label that also exists on the "many" side is suffixed
+	// by the join. The value of the "one" side replaces it.
+	dropCols := []string{"_value_lhs", "_value_rhs"}
+	for _, l := range include {
+		dropCols = append(dropCols, l+"_"+manySide)
+	}
+	postJoinCalls := append(opCalls,
+		call("drop", map[string]ast.Expression{"columns": columnList(dropCols...)}),
+	)
+	if len(include) > 0 {
+		postJoinCalls = append(postJoinCalls,
+			call("rename", map[string]ast.Expression{"fn": stripSuffixFn("_"+oneSide, include)}),
+		)
+	}
+	if dropField {
+		postJoinCalls = append(postJoinCalls, dropFieldAndTimeCall)
+	}
+	postJoinCalls = append(postJoinCalls,
+		call("group", map[string]ast.Expression{
+			"columns": columnList("_time", "_value"),
+			"mode":    &ast.StringLiteral{Value: "except"},
+		}),
+	)
+
+	return buildPipeline(
+		call("join", map[string]ast.Expression{
+			"tables": &ast.ObjectExpression{
+				Properties: []*ast.Property{
+					{
+						Key:   &ast.Identifier{Name: "lhs"},
+						Value: lhs,
+					},
+					{
+						Key:   &ast.Identifier{Name: "rhs"},
+						Value: rhs,
+					},
+				},
+			},
+			"on": columnList(onCols...),
+		}),
+		postJoinCalls...,
+	)
+}
+
+// Function to strip a suffix from the columns that are the given labels with the suffix.
+func stripSuffixFn(suffix string, labels []string) *ast.FunctionExpression {
+	// (column) => if column == "<l1><suffix>" then "<l1>" else if ... else column
+	var body ast.Expression = &ast.Identifier{Name: "column"}
+	for i := len(labels) - 1; i >= 0; i-- {
+		body = &ast.ConditionalExpression{
+			Test: &ast.BinaryExpression{
+				Operator: ast.EqualOperator,
+				Left:     &ast.Identifier{Name: "column"},
+				Right:    &ast.StringLiteral{Value: labels[i] + suffix},
+			},
+			Consequent: &ast.StringLiteral{Value: labels[i]},
+			Alternate:  body,
+		}
+	}
+	return &ast.FunctionExpression{
+		Params: []*ast.Property{
+			{
+				Key: &ast.Identifier{
+					Name: "column",
+				},
+			},
+		},
+		Body: body,
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/promql/v2"
+	"github.com/influxdata/promql/v2/pkg/labels"
 	"github.com/prometheus/common/model"
 )
 
@@ -181,16 +182,32 @@ func (t *Transpiler) transpileAggregateOverTimeFunc(fn string, inArgs []ast.Expr
 }
 
 func labelJoinFn(srcLabels []*ast.StringLiteral, dst *ast.StringLiteral, sep *ast.StringLiteral) *ast.FunctionExpression {
-	// TODO: Deal with empty source labels! Use Flux conditionals to check for existence?
-	var dstLabelValue ast.Expression = member("r", srcLabels[0].Value)
-	for _, srcLabel := range srcLabels[1:] {
+	// A missing source label joins as an empty string, like in PromQL.
+	labelValue := func(l *ast.StringLiteral) ast.Expression {
+		v := &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: &ast.StringLiteral{Value: l.Value},
+		}
+		return &ast.ConditionalExpression{
+			Test:       &ast.UnaryExpression{Operator: ast.ExistsOperator, Argument: v},
+			Consequent: v,
+			Alternate:  &ast.StringLiteral{Value: ""},
+		}
+	}
+
+	var dstLabelValue ast.Expression = &ast.StringLiteral{Value: ""}
+	for i, srcLabel := range srcLabels {
+		if i == 0 {
+			dstLabelValue = labelValue(srcLabel)
+			continue
+		}
 		dstLabelValue = &ast.BinaryExpression{
 			Operator: ast.AdditionOperator,
 			Left:     dstLabelValue,
 			Right: &ast.BinaryExpression{
 				Operator: ast.AdditionOperator,
 				Left:     sep,
-				Right:    member("r", srcLabel.Value),
+				Right:    labelValue(srcLabel),
 			},
 		}
 	}
@@ -253,6 +270,64 @@ func (t *Transpiler) generateZeroWindows() *ast.PipeExpression {
 	)
 }
 
+// transpileAbsent returns a series with the value 1 at each resolution step
+// at which the vector v has no samples. Its labels are the ones of the
+// equality matchers of v if it is a selector.
+func (t *Transpiler) transpileAbsent(expr promql.Expr, v ast.Expression) *ast.PipeExpression {
+	keepStopAndValueCall := call("keep", map[string]ast.Expression{
+		"columns": columnList("_stop", "_value"),
+	})
+	calls := []*ast.CallExpression{
+		// Each step gets the value 1 unless there is a sample that sets it to 0.
+		call("group", map[string]ast.Expression{
+			"columns": columnList("_stop"),
+		}),
+		call("min", nil),
+		call("filter", map[string]ast.Expression{
+			"fn": scalarCompBinaryOpFn(ast.EqualOperator, &ast.FloatLiteral{Value: 1}, false),
+		}),
+	}
+
+	if vs, ok := expr.(*promql.VectorSelector); ok {
+		// A label that has several equality matchers is not added, like in PromQL.
+		count := make(map[string]int)
+		for _, lm := range vs.LabelMatchers {
+			if lm.Type == labels.MatchEqual {
+				count[lm.Name]++
+			}
+		}
+		for _, lm := range vs.LabelMatchers {
+			if lm.Type != labels.MatchEqual || lm.Name == "_field" || count[lm.Name] > 1 {
+				continue
+			}
+			calls = append(calls, call("set", map[string]ast.Expression{
+				"key":   &ast.StringLiteral{Value: lm.Name},
+				"value": &ast.StringLiteral{Value: lm.Value},
+			}))
+		}
+	}
+
+	return buildPipeline(
+		call("union", map[string]ast.Expression{
+			"tables": &ast.ArrayExpression{
+				Elements: []ast.Expression{
+					buildPipeline(
+						t.generateZeroWindows(),
+						call("map", map[string]ast.Expression{"fn": setConstValueFn(&ast.FloatLiteral{Value: 1})}),
+						keepStopAndValueCall,
+					),
+					buildPipeline(
+						v,
+						keepStopAndValueCall,
+						call("map", map[string]ast.Expression{"fn": setConstValueFn(&ast.FloatLiteral{Value: 0})}),
+					),
+				},
+			},
+		}),
+		calls...,
+	)
+}
+
 func (t *Transpiler) timeFn() *ast.PipeExpression {
 	return buildPipeline(
 		t.generateZeroWindows(),
@@ -495,6 +570,8 @@ func (t *Transpiler) transpileCall(c *promql.Call) (ast.Expression, error) {
 				"columns": columnList("_stop", "_value"),
 			}),
 		), nil
+	case "absent":
+		return t.transpileAbsent(c.Args[0], args[0]), nil
 	case "histogram_quantile":
 		if yieldsTable(c.Args[0]) {
 			return nil, fmt.Errorf("non-const scalar expressions not supported yet")
@@ -0,0 +1,221 @@
+package promql
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/promql/v2"
+	"github.com/influxdata/promql/v2/pkg/labels"
+)
+
+// atLabel is the reserved label of the matcher that ParseExpr adds to a
+// selector to pass its @ modifier through the PromQL parser, which does
+// not support the modifier.
+const atLabel = "__at__"
+
+var (
+	// atValuePattern matches the evaluation time that follows an @.
+	atValuePattern = regexp.MustCompile(`^\s*([+-]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+)(?:[eE][+-]?[0-9]+)?|start\s*\(\s*\)|end\s*\(\s*\))`)
+	// offsetPattern matches an offset modifier at the end of the input.
+	offsetPattern = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_:])offset\s+[0-9a-zA-Z]+$`)
+)
+
+// ParseExpr parses a PromQL expression. Unlike the PromQL parser it also
+// accepts the @ modifier of vector and matrix selectors, for example
+// "rate(http_requests_total[5m] @ 1609746000)" or "up @ end()".
+func ParseExpr(input string) (promql.Expr, error) {
+	if strings.Contains(input, atLabel) {
+		return nil, fmt.Errorf("label name %q is reserved", atLabel)
+	}
+	input, err := rewriteAtModifiers(input)
+	if err != nil {
+		return nil, err
+	}
+	return promql.ParseExpr(input)
+}
+
+// edit replaces the input between start and end with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// rewriteAtModifiers replaces each @ modifier of the input with a label
+// matcher of the selector that it modifies.
+func rewriteAtModifiers(input string) (string, error) {
+	quoted := quotedPositions(input)
+
+	var edits []edit
+	for i := 0; i < len(input); i++ {
+		if quoted[i] || input[i] != '@' {
+			continue
+		}
+		loc := atValuePattern.FindStringSubmatchIndex(input[i+1:])
+		if loc == nil {
+			return "", fmt.Errorf("invalid @ modifier at position %d: must be followed by a timestamp, start() or end()", i)
+		}
+		value := strings.Join(strings.Fields(input[i+1+loc[2]:i+1+loc[3]]), "")
+
+		matcher, err := atMatcherEdit(input, quoted, i, value)
+		if err != nil {
+			return "", err
+		}
+		// The @ modifier is removed with the spaces in front of it. The removal
+		// comes first so that it is applied before an insertion at the same position.
+		edits = append(edits, edit{start: lastNonSpace(input, i) + 1, end: i + 1 + loc[1]}, matcher)
+		i += loc[1]
+	}
+
+	// Apply the edits from the end of the input so that
+	// the positions of the remaining edits stay valid.
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	for _, e := range edits {
+		input = input[:e.start] + e.text + input[e.end:]
+	}
+	return input, nil
+}
+
+// atMatcherEdit returns the edit that adds the matcher of the @ modifier
+// at position pos to the selector in front of it. The selector may be
+// followed by a range and an offset modifier.
+func atMatcherEdit(input string, quoted []bool, pos int, value string) (edit, error) {
+	i := lastNonSpace(input, pos)
+	if loc := offsetPattern.FindStringIndex(input[:i+1]); loc != nil && !quoted[i] {
+		i = lastNonSpace(input, strings.LastIndex(input[:i+1], "offset"))
+	}
+	if i >= 0 && input[i] == ']' && !quoted[i] {
+		open := strings.LastIndexByte(input[:i], '[')
+		if open < 0 {
+			return edit{}, fmt.Errorf("invalid @ modifier at position %d: unbalanced brackets", pos)
+		}
+		if strings.Contains(input[open:i], ":") {
+			return edit{}, fmt.Errorf("invalid @ modifier at position %d: @ is not supported for subqueries", pos)
+		}
+		i = lastNonSpace(input, open)
+	}
+
+	matcher := fmt.Sprintf("%s=%q", atLabel, value)
+	switch {
+	case i >= 0 && input[i] == '}' && !quoted[i]:
+		if j := lastNonSpace(input, i); j >= 0 && (input[j] == '{' || input[j] == ',') {
+			return edit{start: i, end: i, text: matcher}, nil
+		}
+		return edit{start: i, end: i, text: ", " + matcher}, nil
+	case i >= 0 && isMetricNameChar(input[i]) && !quoted[i]:
+		return edit{start: i + 1, end: i + 1, text: "{" + matcher + "}"}, nil
+	default:
+		return edit{}, fmt.Errorf("invalid @ modifier at position %d: @ is only supported for vector and matrix selectors", pos)
+	}
+}
+
+// quotedPositions reports for each position of the input
+// whether it is part of a string or a comment.
+func quotedPositions(input string) []bool {
+	quoted := make([]bool, len(input))
+	for i := 0; i < len(input); i++ {
+		var end int
+		switch c := input[i]; c {
+		case '"', '\'':
+			end = i + 1
+			for end < len(input) && input[end] != c {
+				if input[end] == '\\' {
+					end++
+				}
+				end++
+			}
+		case '`':
+			end = i + 1
+			for end < len(input) && input[end] != '`' {
+				end++
+			}
+		case '#':
+			end = i + 1
+			for end < len(input) && input[end] != '\n' {
+				end++
+			}
+		default:
+			continue
+		}
+		if end >= len(input) {
+			end = len(input) - 1
+		}
+		for j := i; j <= end; j++ {
+			quoted[j] = true
+		}
+		i = end
+	}
+	return quoted
+}
+
+// lastNonSpace returns the position of the last
+// character before pos that is not a space.
+func lastNonSpace(input string, pos int) int {
+	i := pos - 1
+	for i >= 0 && strings.IndexByte(" \t\r\n", input[i]) >= 0 {
+		i--
+	}
+	return i
+}
+
+func isMetricNameChar(c byte) bool {
+	return c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// atExtractor removes the matchers that ParseExpr added for the @ modifiers
+// and records the evaluation time of each modified selector in the transpiler.
+type atExtractor struct {
+	t *Transpiler
+}
+
+func (a atExtractor) Visit(node promql.Node, path []promql.Node) (promql.Visitor, error) {
+	var err error
+	switch n := node.(type) {
+	case *promql.MatrixSelector:
+		n.LabelMatchers, err = a.extract(n, n.LabelMatchers)
+	case *promql.VectorSelector:
+		n.LabelMatchers, err = a.extract(n, n.LabelMatchers)
+	}
+	return a, err
+}
+
+func (a atExtractor) extract(n promql.Node, lms []*labels.Matcher) ([]*labels.Matcher, error) {
+	out := make([]*labels.Matcher, 0, len(lms))
+	for _, lm := range lms {
+		if lm.Name != atLabel {
+			out = append(out, lm)
+			continue
+		}
+		if lm.Type != labels.MatchEqual {
+			return nil, fmt.Errorf("label name %q is reserved", atLabel)
+		}
+		at, err := a.t.atTime(lm.Value)
+		if err != nil {
+			return nil, err
+		}
+		a.t.at[n] = at
+	}
+	return out, nil
+}
+
+// atTime returns the time of the value of an @ modifier,
+// which is a Unix timestamp in seconds, start() or end().
+func (t *Transpiler) atTime(v string) (time.Time, error) {
+	switch v {
+	case "start()":
+		return t.Start, nil
+	case "end()":
+		return t.End, nil
+	}
+	sec, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid @ modifier timestamp %q: %s", v, err)
+	}
+	return time.Unix(0, int64(math.Round(sec*1e9))).UTC(), nil
+}
@@ -0,0 +1,89 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestRewriteAtModifiers(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			input: `foo @ 1609746000`,
+			want:  `foo{__at__="1609746000"}`,
+		},
+		{
+			input: `foo{job="api"} @ 1609746000.5`,
+			want:  `foo{job="api", __at__="1609746000.5"}`,
+		},
+		{
+			input: `foo{job="api",} @ start()`,
+			want:  `foo{job="api",__at__="start()"}`,
+		},
+		{
+			input: `{__name__="foo"}@end( )`,
+			want:  `{__name__="foo", __at__="end()"}`,
+		},
+		{
+			input: `rate(foo[5m] @ 100)`,
+			want:  `rate(foo{__at__="100"}[5m])`,
+		},
+		{
+			input: `foo offset 5m @ 100`,
+			want:  `foo{__at__="100"} offset 5m`,
+		},
+		{
+			input: `foo @ 100 offset 5m`,
+			want:  `foo{__at__="100"} offset 5m`,
+		},
+		{
+			input: `foo @ 100 / bar{a="@ 1"} @ 200 # comment with @`,
+			want:  `foo{__at__="100"} / bar{a="@ 1", __at__="200"} # comment with @`,
+		},
+		{
+			input:   `rate(foo[5m])[30m:1m] @ 100`,
+			wantErr: true,
+		},
+		{
+			input:   `sum(foo) @ 100`,
+			wantErr: true,
+		},
+		{
+			input:   `foo @ now()`,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		got, err := rewriteAtModifiers(tc.input)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("expected error for %q, got %q", tc.input, got)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("unexpected error for %q: %s", tc.input, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("unexpected rewrite of %q: want %q, got %q", tc.input, tc.want, got)
+		}
+	}
+}
+
+func TestParseExpr(t *testing.T) {
+	for _, expr := range []string{
+		`foo @ 1609746000`,
+		`rate(foo{job="api"}[5m] @ end() offset 1m)`,
+		`foo / on(job) group_left(instance) bar @ start()`,
+	} {
+		if _, err := ParseExpr(expr); err != nil {
+			t.Errorf("unexpected error for %q: %s", expr, err)
+		}
+	}
+
+	if _, err := ParseExpr(`foo{__at__="1"}`); err == nil {
+		t.Error("expected error for reserved label")
+	}
+}
@@ -74,6 +74,10 @@ var dropMeasurementCall = call(
 )
 
 func (t *Transpiler) transpileInstantVectorSelector(v *promql.VectorSelector) *ast.PipeExpression {
+	if at, ok := t.at[v]; ok {
+		return t.transpileAtSelector(at, v.Offset, 5*time.Minute, v.LabelMatchers, true)
+	}
+
 	var windowCall *ast.CallExpression
 	var windowFilterCall *ast.CallExpression
 	if t.Resolution > 0 {
@@ -112,6 +116,10 @@ func (t *Transpiler) transpileInstantVectorSelector(v *promql.VectorSelector) *a
 }
 
 func (t *Transpiler) transpileRangeVectorSelector(v *promql.MatrixSelector) *ast.PipeExpression {
+	if at, ok := t.at[v]; ok {
+		return t.transpileAtSelector(at, v.Offset, v.Range, v.LabelMatchers, false)
+	}
+
 	var windowCall *ast.CallExpression
 	var windowFilterCall *ast.CallExpression
 	if t.Resolution > 0 {
@@ -120,7 +128,7 @@ func (t *Transpiler) transpileRangeVectorSelector(v *promql.MatrixSelector) *ast
 		windowCall = call("window", map[string]ast.Expression{
 			"every":  &ast.DurationLiteral{Values: []ast.Duration{{Magnitude: t.Resolution.Nanoseconds(), Unit: "ns"}}},
 			"period": &ast.DurationLiteral{Values: []ast.Duration{{Magnitude: v.Range.Nanoseconds(), Unit: "ns"}}},
-			"offset": &ast.DurationLiteral{Values: []ast.Duration{{Magnitude: t.Start.UnixNano() % t.Resolution.Nanoseconds(), Unit: "ns"}}},
+			"offset": &ast.DurationLiteral{Values: []ast.Duration{{Magnitude: t.Start.Add(-v.Offset).UnixNano() % t.Resolution.Nanoseconds(), Unit: "ns"}}},
 		})
 
 		// Remove any windows smaller than the specified range at the edges of the graph range.
@@ -146,3 +154,136 @@ func (t *Transpiler) transpileRangeVectorSelector(v *promql.MatrixSelector) *ast
 		dropMeasurementCall,
 	)
 }
+
+// transpileAtSelector selects the data of a selector with an @ modifier,
+// which is evaluated at the fixed time at instead of at each resolution
+// step, and repeats the result for each step.
+func (t *Transpiler) transpileAtSelector(at time.Time, offset, lookback time.Duration, lms []*labels.Matcher, instant bool) *ast.PipeExpression {
+	var lastCall *ast.CallExpression
+	if instant {
+		lastCall = call("last", nil)
+	}
+	series := buildPipeline(
+		call("from", map[string]ast.Expression{"bucket": &ast.StringLiteral{Value: t.Bucket}}),
+		// Query only the data before the fixed evaluation time.
+		call("range", map[string]ast.Expression{
+			"start": &ast.DateTimeLiteral{Value: at.Add(-lookback - offset)},
+			"stop":  &ast.DateTimeLiteral{Value: at.Add(-offset)},
+		}),
+		call("filter", map[string]ast.Expression{"fn": transpileLabelMatchersFn(lms)}),
+		lastCall,
+		call("timeShift", map[string]ast.Expression{
+			"duration": &ast.DurationLiteral{Values: []ast.Duration{{Magnitude: offset.Nanoseconds(), Unit: "ns"}}},
+		}),
+		// The windows of the resolution steps replace the range of the data.
+		call("drop", map[string]ast.Expression{
+			"columns": columnList("_measurement", "_start", "_stop"),
+		}),
+		call("set", map[string]ast.Expression{
+			"key":   &ast.StringLiteral{Value: "~at"},
+			"value": &ast.StringLiteral{Value: ""},
+		}),
+	)
+	steps := buildPipeline(
+		t.generateZeroWindows(),
+		call("set", map[string]ast.Expression{
+			"key":   &ast.StringLiteral{Value: "~at"},
+			"value": &ast.StringLiteral{Value: ""},
+		}),
+	)
+
+	var shiftCall *ast.CallExpression
+	if !instant {
+		// Move the samples of a range into the window of each step so that
+		// range functions see the same data relative to the end of the window.
+		shiftCall = call("map", map[string]ast.Expression{"fn": shiftRangeToStepFn(at, lookback)})
+	}
+
+	return buildPipeline(
+		// Every row of the series is joined with every step.
+		call("join", map[string]ast.Expression{
+			"tables": &ast.ObjectExpression{
+				Properties: []*ast.Property{
+					{
+						Key:   &ast.Identifier{Name: "at"},
+						Value: series,
+					},
+					{
+						Key:   &ast.Identifier{Name: "step"},
+						Value: steps,
+					},
+				},
+			},
+			"on": columnList("~at"),
+		}),
+		call("rename", map[string]ast.Expression{
+			"columns": &ast.ObjectExpression{
+				Properties: []*ast.Property{
+					{
+						Key:   &ast.Identifier{Name: "_value_at"},
+						Value: &ast.StringLiteral{Value: "_value"},
+					},
+				},
+			},
+		}),
+		call("drop", map[string]ast.Expression{
+			"columns": columnList("_value_step", "~at"),
+		}),
+		shiftCall,
+		call("group", map[string]ast.Expression{
+			"columns": columnList("_time", "_value"),
+			"mode":    &ast.StringLiteral{Value: "except"},
+		}),
+	)
+}
+
+// Function to move the samples of a range that ends at the time at into
+// the window of a step, which starts rng before the step.
+func shiftRangeToStepFn(at time.Time, rng time.Duration) *ast.FunctionExpression {
+	toInt := func(e ast.Expression) ast.Expression {
+		return call("int", map[string]ast.Expression{"v": e})
+	}
+
+	// (r) => ({r with
+	//     _time: time(v: int(v: r._time) + int(v: r._stop) - <at>),
+	//     _start: time(v: int(v: r._stop) - <rng>),
+	// })
+	return &ast.FunctionExpression{
+		Params: []*ast.Property{
+			{
+				Key: &ast.Identifier{
+					Name: "r",
+				},
+			},
+		},
+		Body: &ast.ObjectExpression{
+			With: &ast.Identifier{Name: "r"},
+			Properties: []*ast.Property{
+				{
+					Key: &ast.Identifier{Name: "_time"},
+					Value: call("time", map[string]ast.Expression{
+						"v": &ast.BinaryExpression{
+							Operator: ast.SubtractionOperator,
+							Left: &ast.BinaryExpression{
+								Operator: ast.AdditionOperator,
+								Left:     toInt(member("r", "_time")),
+								Right:    toInt(member("r", "_stop")),
+							},
+							Right: &ast.IntegerLiteral{Value: at.UnixNano()},
+						},
+					}),
+				},
+				{
+					Key: &ast.Identifier{Name: "_start"},
+					Value: call("time", map[string]ast.Expression{
+						"v": &ast.BinaryExpression{
+							Operator: ast.SubtractionOperator,
+							Left:     toInt(member("r", "_stop")),
+							Right:    &ast.IntegerLiteral{Value: rng.Nanoseconds()},
+						},
+					}),
+				},
+			},
+		},
+	}
+}
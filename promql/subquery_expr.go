@@ -12,6 +12,7 @@ func (t *Transpiler) transpileSubqueryExpr(sq *promql.SubqueryExpr) (ast.Express
 		Start:      t.Start.Add(-sq.Range - sq.Offset),
 		End:        t.End.Add(-sq.Offset),
 		Resolution: sq.Step,
+		at:         t.at,
 	}
 
 	// 2. Transpile subexpression with that transpiler.
@@ -29,7 +30,7 @@ func (t *Transpiler) transpileSubqueryExpr(sq *promql.SubqueryExpr) (ast.Express
 		windowCall = call("window", map[string]ast.Expression{
 			"every":  &ast.DurationLiteral{Values: []ast.Duration{{Magnitude: t.Resolution.Nanoseconds(), Unit: "ns"}}},
 			"period": &ast.DurationLiteral{Values: []ast.Duration{{Magnitude: sq.Range.Nanoseconds(), Unit: "ns"}}},
-			"offset": &ast.DurationLiteral{Values: []ast.Duration{{Magnitude: t.Start.UnixNano() % t.Resolution.Nanoseconds(), Unit: "ns"}}},
+			"offset": &ast.DurationLiteral{Values: []ast.Duration{{Magnitude: t.Start.Add(-sq.Offset).UnixNano() % t.Resolution.Nanoseconds(), Unit: "ns"}}},
 		})
 
 		// Remove any windows smaller than the specified range at the edges of the graph range.
@@ -2,6 +2,7 @@ package promql
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -51,11 +52,18 @@ func call(fn string, args map[string]ast.Expression) *ast.CallExpression {
 		Callee: callee,
 	}
 	if len(args) > 0 {
+		// Sort the arguments by name so that the generated Flux is deterministic.
+		keys := make([]string, 0, len(args))
+		for k := range args {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
 		props := make([]*ast.Property, 0, len(args))
-		for k, v := range args {
+		for _, k := range keys {
 			props = append(props, &ast.Property{
 				Key:   &ast.Identifier{Name: k},
-				Value: v,
+				Value: args[k],
 			})
 		}
 
@@ -188,13 +196,16 @@ type Transpiler struct {
 	Start      time.Time
 	End        time.Time
 	Resolution time.Duration
+
+	// at holds the evaluation time of the selectors with an @ modifier.
+	at map[promql.Node]time.Time
 }
 
 // Transpile converts a PromQL expression with the time ranges set in the transpiler
 // into a Flux file. The resulting Flux file can be executed and the result needs to
 // be transformed using FluxResultToPromQLValue() (implemented in the InfluxDB repo)
 // to get a result value that is fully equivalent to the result of a native PromQL
-// execution.
+// execution. Expressions with @ modifiers have to be parsed with ParseExpr.
 //
 // During the transpilation, the transpiler recursively translates the PromQL AST into
 // equivalent Flux nodes. Each PromQL node translates into one or more Flux
@@ -214,6 +225,10 @@ type Transpiler struct {
 // - Tables should be grouped by all columns except for "_time" and "_value". Each Flux
 //   table represents one PromQL series, with potentially multiple samples over time.
 func (t *Transpiler) Transpile(expr promql.Expr) (*ast.File, error) {
+	t.at = make(map[promql.Node]time.Time)
+	if err := promql.Walk(atExtractor{t: t}, expr, nil); err != nil {
+		return nil, fmt.Errorf("error transpiling expression: %s", err)
+	}
 	promql.Walk(labelNameEscaper{}, expr, nil)
 
 	fluxNode, err := t.transpileExpr(expr)
@@ -0,0 +1,192 @@
+package promql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+)
+
+// callNames returns the names of the functions that are called
+// by the file in the order of the pipelines.
+func callNames(file *ast.File) []string {
+	var names []string
+	ast.Visit(file, func(node ast.Node) {
+		c, ok := node.(*ast.CallExpression)
+		if !ok {
+			return
+		}
+		switch callee := c.Callee.(type) {
+		case *ast.Identifier:
+			names = append(names, callee.Name)
+		case *ast.MemberExpression:
+			names = append(names, callee.Object.(*ast.Identifier).Name+"."+callee.Property.(*ast.Identifier).Name)
+		}
+	})
+	return names
+}
+
+func TestTranspile(t *testing.T) {
+	testCases := []struct {
+		name    string
+		expr    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "histogram_quantile",
+			expr: `histogram_quantile(0.9, rate(foo_bucket[5m]))`,
+			want: []string{
+				"from", "range", "filter", "window", "filter", "timeShift", "drop",
+				"promql.extrapolatedRate", "drop",
+				"group", "promql.promHistogramQuantile", "drop",
+				"duplicate",
+			},
+		},
+		{
+			name: "label_join",
+			expr: `label_join(foo, "dst", ",", "a", "b")`,
+			want: []string{
+				"from", "range", "filter", "window", "filter", "last", "timeShift", "drop",
+				"map",
+				"duplicate",
+			},
+		},
+		{
+			name: "label_join without source labels",
+			expr: `label_join(foo, "dst", ",")`,
+			want: []string{
+				"from", "range", "filter", "window", "filter", "last", "timeShift", "drop",
+				"map",
+				"duplicate",
+			},
+		},
+		{
+			name: "absent",
+			expr: `absent(foo{job="api"})`,
+			want: []string{
+				"union",
+				"promql.emptyTable", "range", "window", "sum", "filter", "map", "keep",
+				"from", "range", "filter", "window", "filter", "last", "timeShift", "drop", "keep", "map",
+				"group", "min", "filter", "set",
+				"duplicate",
+			},
+		},
+		{
+			name: "at modifier",
+			expr: `foo @ 1609746000`,
+			want: []string{
+				"join",
+				"from", "range", "filter", "last", "timeShift", "drop", "set",
+				"promql.emptyTable", "range", "window", "sum", "filter", "set",
+				"rename", "drop", "group",
+				"duplicate",
+			},
+		},
+		{
+			name: "at modifier of range",
+			expr: `rate(foo[5m] @ end())`,
+			want: []string{
+				"join",
+				"from", "range", "filter", "timeShift", "drop", "set",
+				"promql.emptyTable", "range", "window", "sum", "filter", "set",
+				"rename", "drop", "map", "time", "int", "int", "time", "int", "group",
+				"promql.extrapolatedRate", "drop",
+				"duplicate",
+			},
+		},
+		{
+			name: "group_left",
+			expr: `foo * on(job) group_left(instance) bar`,
+			want: []string{
+				"join",
+				"from", "range", "filter", "window", "filter", "last", "timeShift", "drop",
+				"from", "range", "filter", "window", "filter", "last", "timeShift", "drop", "keep",
+				"map", "drop", "rename", "drop", "group",
+				"duplicate",
+			},
+		},
+		{
+			name: "group_right filter",
+			expr: `foo > on(job) group_right bar`,
+			want: []string{
+				"join",
+				"from", "range", "filter", "window", "filter", "last", "timeShift", "drop", "keep",
+				"from", "range", "filter", "window", "filter", "last", "timeShift", "drop",
+				"filter", "duplicate", "drop", "group",
+				"duplicate",
+			},
+		},
+		{
+			name:    "group_left without on",
+			expr:    `foo * ignoring(job) group_left bar`,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := ParseExpr(tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			end := time.Date(2021, 1, 4, 8, 0, 0, 0, time.UTC)
+			tr := &Transpiler{
+				Bucket:     "prometheus",
+				Start:      end.Add(-time.Hour),
+				End:        end,
+				Resolution: time.Minute,
+			}
+			file, err := tr.Transpile(expr)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got := callNames(file); !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected calls -want/+got\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestTranspile_Offset(t *testing.T) {
+	expr, err := ParseExpr(`rate(foo[5m] offset 90s)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := &Transpiler{
+		Bucket:     "prometheus",
+		Start:      time.Unix(3600, 0),
+		End:        time.Unix(7200, 0),
+		Resolution: time.Minute,
+	}
+	file, err := tr.Transpile(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The windows of the steps are aligned to the start minus the offset.
+	var offset int64 = -1
+	ast.Visit(file, func(node ast.Node) {
+		c, ok := node.(*ast.CallExpression)
+		if !ok {
+			return
+		}
+		if id, ok := c.Callee.(*ast.Identifier); !ok || id.Name != "window" {
+			return
+		}
+		for _, p := range c.Arguments[0].(*ast.ObjectExpression).Properties {
+			if p.Key.Key() == "offset" {
+				offset = p.Value.(*ast.DurationLiteral).Values[0].Magnitude
+			}
+		}
+	})
+	if want := (30 * time.Second).Nanoseconds(); offset != want {
+		t.Errorf("unexpected window offset: want %d, got %d", want, offset)
+	}
+}
@@ -132,6 +132,21 @@ type TransportProfile struct {
 
 	// Mean is the mean span time of this profile.
 	Mean float64 `json:"mean"`
+
+	// Tables holds the number of tables received by the transport.
+	Tables int64 `json:"tables"`
+
+	// Rows holds the number of rows received by the transport.
+	Rows int64 `json:"rows"`
+
+	// MaxAllocated holds the maximum number of bytes allocated
+	// by the operation at any point.
+	MaxAllocated int64 `json:"max_allocated"`
+
+	// TotalAllocated holds the total number of bytes allocated
+	// by the operation. The number includes memory that was
+	// freed and then used again.
+	TotalAllocated int64 `json:"total_allocated"`
 }
 
 // StartSpan will start a profile span to be recorded.
@@ -0,0 +1,129 @@
+package repl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/libflux/go/libflux"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+)
+
+// commandPrefix starts a line that is a command for the REPL
+// instead of Flux source.
+const commandPrefix = ":"
+
+// command is a meta-command that controls the REPL.
+type command struct {
+	name  string
+	args  string
+	usage string
+	run   func(r *REPL, args string) (*libflux.FluxError, error)
+}
+
+var commands []command
+
+func init() {
+	// The commands are assigned in init since :help refers to the list.
+	commands = []command{
+		{name: "type", args: "<expr>", usage: "Show the inferred type of an expression", run: (*REPL).typeCommand},
+		{name: "plan", usage: "Show the plan of the last query", run: (*REPL).planCommand},
+		{name: "load", args: "<file>", usage: "Evaluate the Flux script in a file", run: (*REPL).loadCommand},
+		{name: "time", usage: "Toggle printing how long each query takes", run: (*REPL).timeCommand},
+		{name: "format", args: "<table|csv|json|ndjson|lp>", usage: "Set the format of query results", run: (*REPL).formatCommand},
+		{name: "help", usage: "Show the available commands", run: (*REPL).helpCommand},
+	}
+}
+
+// executeCommand runs a line that starts with the command prefix.
+func (r *REPL) executeCommand(line string) (*libflux.FluxError, error) {
+	line = strings.TrimSpace(strings.TrimPrefix(line, commandPrefix))
+	name, args := line, ""
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		name, args = line[:i], strings.TrimSpace(line[i+1:])
+	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(r, args)
+		}
+	}
+	return nil, errors.Newf(codes.Invalid, "unknown command %q, use :help to list the commands", name)
+}
+
+func (r *REPL) typeCommand(expr string) (*libflux.FluxError, error) {
+	if expr == "" {
+		return nil, errors.New(codes.Invalid, "usage: :type <expr>")
+	}
+	pkg, fluxError, err := r.analyzeLine(expr)
+	if err != nil {
+		return fluxError, err
+	}
+
+	var typ semantic.MonoType
+	found := false
+	for _, file := range pkg.Files {
+		if len(file.Body) == 0 {
+			continue
+		}
+		if stmt, ok := file.Body[len(file.Body)-1].(*semantic.ExpressionStatement); ok {
+			typ, found = stmt.Expression.TypeOf(), true
+		}
+	}
+	if !found {
+		return nil, errors.New(codes.Invalid, ":type requires an expression")
+	}
+	fmt.Println(typ.CanonicalString())
+	return nil, nil
+}
+
+func (r *REPL) planCommand(args string) (*libflux.FluxError, error) {
+	if r.lastPlan == nil {
+		return nil, errors.New(codes.Invalid, "no query has been run")
+	}
+	fmt.Printf("%v\n", plan.Formatted(r.lastPlan, plan.WithDetails()))
+	return nil, nil
+}
+
+func (r *REPL) loadCommand(path string) (*libflux.FluxError, error) {
+	if path == "" {
+		return nil, errors.New(codes.Invalid, "usage: :load <file>")
+	}
+	return r.executeLine("@" + path)
+}
+
+func (r *REPL) timeCommand(args string) (*libflux.FluxError, error) {
+	r.timing = !r.timing
+	if r.timing {
+		fmt.Println("Timing is on")
+	} else {
+		fmt.Println("Timing is off")
+	}
+	return nil, nil
+}
+
+func (r *REPL) formatCommand(format string) (*libflux.FluxError, error) {
+	switch format {
+	case "":
+		fmt.Println(r.format)
+	case "table", "csv", "json", "ndjson", "lp":
+		r.format = format
+	default:
+		return nil, errors.Newf(codes.Invalid, "unknown result format %q", format)
+	}
+	return nil, nil
+}
+
+func (r *REPL) helpCommand(args string) (*libflux.FluxError, error) {
+	for _, cmd := range commands {
+		usage := commandPrefix + cmd.name
+		if cmd.args != "" {
+			usage += " " + cmd.args
+		}
+		fmt.Printf("  %-36s %s\n", usage, cmd.usage)
+	}
+	fmt.Println("Lines that end with a pipe or leave a bracket open continue on the next line.")
+	fmt.Println("An empty line evaluates the lines that were entered so far.")
+	return nil, nil
+}
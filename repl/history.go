@@ -0,0 +1,56 @@
+package repl
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// maxHistory is the number of lines that are loaded from the history file.
+const maxHistory = 1000
+
+// loadHistory reads the most recent lines from the history file.
+// A missing history file is not an error.
+func loadHistory(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) > 2*maxHistory {
+			lines = append(lines[:0], lines[len(lines)-maxHistory:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) > maxHistory {
+		lines = lines[len(lines)-maxHistory:]
+	}
+	return lines, nil
+}
+
+// appendHistory adds the line to the end of the history file.
+func appendHistory(path, line string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,106 @@
+package repl
+
+import (
+	"strings"
+)
+
+// continuationSuffixes are the tokens that cannot end a statement
+// so a line that ends with one of them continues on the next line.
+var continuationSuffixes = []string{"|>", "=>", ",", "=", "+", "-", "*", "and", "or", "with"}
+
+// isIncomplete reports whether the source needs more lines before it
+// can be evaluated. The source is incomplete if it has unbalanced
+// parentheses, brackets or braces, an unterminated string, or if the
+// last line ends with a pipe or an operator.
+func isIncomplete(src string) bool {
+	var (
+		depth    int
+		inString bool
+		escaped  bool
+	)
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '/':
+			if i+1 < len(src) && src[i+1] == '/' {
+				// Skip the comment to the end of the line.
+				for i < len(src) && src[i] != '\n' {
+					i++
+				}
+			}
+		}
+	}
+	if inString || depth > 0 {
+		return true
+	}
+
+	last := lastLine(src)
+	for _, suffix := range continuationSuffixes {
+		if !strings.HasSuffix(last, suffix) {
+			continue
+		}
+		// Keywords must be a complete word.
+		if isLetter(suffix[0]) {
+			if n := len(last) - len(suffix); n > 0 && isIdentChar(last[n-1]) {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// lastLine returns the last line of the source without
+// a trailing comment or whitespace.
+func lastLine(src string) string {
+	src = strings.TrimRight(src, " \t\r\n")
+	if i := strings.LastIndexByte(src, '\n'); i >= 0 {
+		src = src[i+1:]
+	}
+	return strings.TrimSpace(stripComment(src))
+}
+
+// stripComment removes a comment from the end of a line.
+func stripComment(line string) string {
+	inString, escaped := false, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case !inString && c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentChar(c byte) bool {
+	return isLetter(c) || c == '_' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,29 @@
+package repl
+
+import "testing"
+
+func TestIsIncomplete(t *testing.T) {
+	for _, tc := range []struct {
+		src  string
+		want bool
+	}{
+		{src: `x = 1`, want: false},
+		{src: `from(bucket: "db")`, want: false},
+		{src: `from(bucket: "db") |>`, want: true},
+		{src: "from(bucket: \"db\")\n  |> range(start: -1h) // last hour", want: false},
+		{src: `f = (r) => {`, want: true},
+		{src: "f = (r) => {\n  return r\n}", want: false},
+		{src: `x = [1, 2,`, want: true},
+		{src: `x = "a (b"`, want: false},
+		{src: `x = "unterminated`, want: true},
+		{src: `x = "escaped \" quote"`, want: false},
+		{src: `a = true and`, want: true},
+		{src: `brand = "x" // and`, want: false},
+		{src: `x = {r with`, want: true},
+		{src: `color`, want: false},
+	} {
+		if got := isIncomplete(tc.src); got != tc.want {
+			t.Errorf("isIncomplete(%q): want %v, got %v", tc.src, tc.want, got)
+		}
+	}
+}
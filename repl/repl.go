@@ -12,16 +12,21 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/c-bata/go-prompt"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
 	"github.com/influxdata/flux/dependency"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/spec"
 	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/json"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/libflux/go/libflux"
+	"github.com/influxdata/flux/line"
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
@@ -39,6 +44,16 @@ type REPL struct {
 	cancelFunc context.CancelFunc
 
 	enableSuggestions bool
+	historyFile       string
+	memoryLimit       int64
+
+	// pending holds the lines of a statement that continues
+	// on the next line.
+	pending []string
+
+	lastPlan *plan.Spec
+	timing   bool
+	format   string
 }
 
 type Option interface {
@@ -67,6 +82,7 @@ func New(ctx context.Context, opts ...Option) *REPL {
 		itrp:     interpreter.NewInterpreter(nil, &lang.ExecOptsConfig{}),
 		analyzer: analyzer,
 		importer: importer,
+		format:   "table",
 	}
 	for _, opt := range opts {
 		opt.applyOption(repl)
@@ -75,11 +91,22 @@ func New(ctx context.Context, opts ...Option) *REPL {
 }
 
 func (r *REPL) Run() {
+	opts := []prompt.Option{
+		prompt.OptionPrefix("> "),
+		prompt.OptionLivePrefix(r.livePrefix),
+		prompt.OptionTitle("flux"),
+	}
+	if r.historyFile != "" {
+		history, err := loadHistory(r.historyFile)
+		if err != nil {
+			fmt.Println("Warning: failed to load history:", err)
+		}
+		opts = append(opts, prompt.OptionHistory(history))
+	}
 	p := prompt.New(
 		r.input,
 		r.completer,
-		prompt.OptionPrefix("> "),
-		prompt.OptionTitle("flux"),
+		opts...,
 	)
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT)
@@ -111,6 +138,14 @@ func (r *REPL) clearCancel() {
 
 func (r *REPL) completer(d prompt.Document) []prompt.Suggest {
 	if r.enableSuggestions {
+		if strings.HasPrefix(d.Text, commandPrefix) {
+			s := make([]prompt.Suggest, 0, len(commands))
+			for _, cmd := range commands {
+				s = append(s, prompt.Suggest{Text: commandPrefix + cmd.name, Description: cmd.usage})
+			}
+			return prompt.FilterHasPrefix(s, d.Text, true)
+		}
+
 		names := make([]string, 0, r.scope.Size())
 		r.scope.Range(func(k string, v values.Value) {
 			names = append(names, k)
@@ -149,9 +184,49 @@ func (r *REPL) Input(t string) (*libflux.FluxError, error) {
 	return r.executeLine(t)
 }
 
+// livePrefix changes the prompt while a statement continues on the next line.
+func (r *REPL) livePrefix() (string, bool) {
+	if len(r.pending) > 0 {
+		return ". ", true
+	}
+	return "", false
+}
+
 // input processes a line of input and prints the result.
+// Lines are collected until the statement is complete
+// and then the statement is evaluated.
 func (r *REPL) input(t string) {
-	if fluxError, err := r.executeLine(t); err != nil {
+	if r.historyFile != "" && strings.TrimSpace(t) != "" {
+		if err := appendHistory(r.historyFile, t); err != nil {
+			fmt.Println("Warning: failed to save history:", err)
+			r.historyFile = ""
+		}
+	}
+
+	var (
+		fluxError *libflux.FluxError
+		err       error
+	)
+	switch {
+	case len(r.pending) > 0:
+		if strings.TrimSpace(t) != "" {
+			r.pending = append(r.pending, t)
+			if isIncomplete(strings.Join(r.pending, "\n")) {
+				return
+			}
+		}
+		src := strings.Join(r.pending, "\n")
+		r.pending = nil
+		fluxError, err = r.executeLine(src)
+	case strings.HasPrefix(strings.TrimSpace(t), commandPrefix):
+		fluxError, err = r.executeCommand(t)
+	case t != "" && t[0] != '@' && isIncomplete(t):
+		r.pending = append(r.pending, t)
+		return
+	default:
+		fluxError, err = r.executeLine(t)
+	}
+	if err != nil {
 		if fluxError != nil {
 			fluxError.Print()
 		} else {
@@ -255,27 +330,67 @@ func (r *REPL) doQuery(ctx context.Context, spec *flux.Spec) error {
 		return err
 	}
 	alloc := &memory.ResourceAllocator{}
+	if r.memoryLimit > 0 {
+		limit := r.memoryLimit
+		alloc.Limit = &limit
+	}
+
+	if p, ok := program.(*lang.Program); ok {
+		r.lastPlan = p.PlanSpec
+	}
 
+	start := time.Now()
 	qry, err := program.Start(ctx, alloc)
 	if err != nil {
 		return err
 	}
 	defer qry.Done()
 
-	for result := range qry.Results() {
-		tables := result.Tables()
-		fmt.Println("Result:", result.Name())
-		if err := tables.Do(func(tbl flux.Table) error {
-			_, err := execute.NewFormatter(tbl, nil).WriteTo(os.Stdout)
-			return err
-		}); err != nil {
-			return err
-		}
+	if err := r.writeResults(flux.NewResultIteratorFromQuery(qry)); err != nil {
+		return err
 	}
 	qry.Done()
+	if r.timing {
+		fmt.Println("Elapsed:", time.Since(start))
+	}
 	return qry.Err()
 }
 
+// writeResults prints the results in the format chosen with :format.
+func (r *REPL) writeResults(results flux.ResultIterator) error {
+	defer results.Release()
+
+	var err error
+	switch r.format {
+	case "csv":
+		_, err = csv.NewMultiResultEncoder(csv.DefaultEncoderConfig()).Encode(os.Stdout, results)
+	case "json", "ndjson":
+		config := json.DefaultEncoderConfig()
+		if r.format == "ndjson" {
+			config.Format = json.NDJSON
+		}
+		_, err = json.NewMultiResultEncoder(config).Encode(os.Stdout, results)
+	case "lp":
+		_, err = line.NewMultiResultEncoder(line.DefaultEncoderConfig()).Encode(os.Stdout, results)
+	default:
+		for results.More() {
+			result := results.Next()
+			fmt.Println("Result:", result.Name())
+			if err := result.Tables().Do(func(tbl flux.Table) error {
+				_, err := execute.NewFormatter(tbl, nil).WriteTo(os.Stdout)
+				return err
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	results.Release()
+	return results.Err()
+}
+
 func getFluxFiles(path string) ([]string, error) {
 	return filepath.Glob(path + "*.flux")
 }
@@ -331,3 +446,19 @@ func EnableSuggestions() Option {
 		r.enableSuggestions = true
 	})
 }
+
+// HistoryFile saves the lines that are entered to the file
+// and loads the previous lines from it when the REPL starts.
+func HistoryFile(path string) Option {
+	return option(func(r *REPL) {
+		r.historyFile = path
+	})
+}
+
+// MemoryLimit limits the memory that each query may allocate to the
+// given number of bytes. A limit of zero or less means there is no limit.
+func MemoryLimit(n int64) Option {
+	return option(func(r *REPL) {
+		r.memoryLimit = n
+	})
+}
@@ -79,6 +79,11 @@ func (s *FromProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Fingerprint writes the rows since they are part of the spec.
+func (s *FromProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	return f.Value(s.Rows)
+}
+
 func createFromSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	spec := ps.(*FromProcedureSpec)
 	return &tableSource{
@@ -22,6 +22,7 @@ const FromCSVKind = "fromCSV"
 type FromCSVOpSpec struct {
 	CSV  string `json:"csv"`
 	File string `json:"file"`
+	Glob string `json:"glob"`
 	Mode string `json:"mode"`
 }
 
@@ -53,12 +54,22 @@ func createFromCSVOpSpec(args flux.Arguments, a *flux.Administration) (flux.Oper
 		spec.File = file
 	}
 
-	if spec.CSV == "" && spec.File == "" {
-		return nil, errors.New(codes.Invalid, "must provide csv raw text or filename")
+	if glob, ok, err := args.GetString("glob"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Glob = glob
 	}
 
-	if spec.CSV != "" && spec.File != "" {
-		return nil, errors.New(codes.Invalid, "must provide exactly one of the parameters csv or file")
+	n := 0
+	for _, v := range []string{spec.CSV, spec.File, spec.Glob} {
+		if v != "" {
+			n++
+		}
+	}
+	if n == 0 {
+		return nil, errors.New(codes.Invalid, "must provide csv raw text, filename or glob pattern")
+	} else if n > 1 {
+		return nil, errors.New(codes.Invalid, "must provide exactly one of the parameters csv, file or glob")
 	}
 
 	if mode, ok, err := args.GetString("mode"); err != nil {
@@ -85,6 +96,7 @@ type FromCSVProcedureSpec struct {
 	plan.DefaultCost
 	CSV  string
 	File string
+	Glob string
 	Mode string
 }
 
@@ -97,6 +109,7 @@ func newFromCSVProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.Pr
 	return &FromCSVProcedureSpec{
 		CSV:  spec.CSV,
 		File: spec.File,
+		Glob: spec.Glob,
 		Mode: spec.Mode,
 	}, nil
 }
@@ -109,10 +122,75 @@ func (s *FromCSVProcedureSpec) Copy() plan.ProcedureSpec {
 	ns := new(FromCSVProcedureSpec)
 	ns.CSV = s.CSV
 	ns.File = s.File
+	ns.Glob = s.Glob
 	ns.Mode = s.Mode
 	return ns
 }
 
+// Fingerprint writes the inline csv data. A file can change between
+// queries without changing the spec so reads from files are never cached.
+func (s *FromCSVProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	if s.File != "" || s.Glob != "" {
+		return false
+	}
+	f.String(s.CSV)
+	f.String(s.Mode)
+	return true
+}
+
+// estimatedBytesPerRow is the assumed size of a row in a csv file.
+// It is used to estimate the number of rows in a file from its size.
+const estimatedBytesPerRow = 64
+
+// EstimateStatistics estimates the number of rows read by the source.
+// Rows of a csv string are counted. The number of rows in a file
+// is estimated from its size.
+func (s *FromCSVProcedureSpec) EstimateStatistics(ctx context.Context, inStats []plan.Statistics) plan.Statistics {
+	var files []string
+	switch {
+	case s.File != "":
+		files = []string{s.File}
+	case s.Glob != "":
+		matches, err := filesystem.Glob(ctx, s.Glob)
+		if err != nil {
+			return plan.Statistics{}
+		}
+		files = matches
+	default:
+		return plan.Statistics{Cardinality: countCSVRows(s.CSV)}
+	}
+
+	var size int64
+	for _, file := range files {
+		info, err := filesystem.Stat(ctx, file)
+		if err != nil {
+			return plan.Statistics{}
+		}
+		size += info.Size()
+	}
+	return plan.Statistics{Cardinality: (size + estimatedBytesPerRow - 1) / estimatedBytesPerRow}
+}
+
+// countCSVRows counts the data rows of a csv string.
+// The first row after the annotations of each table
+// or after an empty line is the header and is not counted.
+func countCSVRows(data string) int64 {
+	var n int64
+	header := true
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			header = true
+		case header:
+			header = false
+		default:
+			n++
+		}
+	}
+	return n
+}
+
 func createFromCSVSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	spec, ok := prSpec.(*FromCSVProcedureSpec)
 	if !ok {
@@ -122,37 +200,61 @@ func createFromCSVSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a ex
 }
 
 func CreateSource(spec *FromCSVProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
-	var getDataStream func() (io.ReadCloser, error)
-	if spec.File != "" {
-		getDataStream = func() (io.ReadCloser, error) {
-			f, err := filesystem.OpenFile(a.Context(), spec.File)
+	var getDataStreams func() ([]dataStream, error)
+	switch {
+	case spec.File != "":
+		getDataStreams = func() ([]dataStream, error) {
+			return []dataStream{openFile(a.Context(), spec.File)}, nil
+		}
+	case spec.Glob != "":
+		getDataStreams = func() ([]dataStream, error) {
+			files, err := filesystem.Glob(a.Context(), spec.Glob)
 			if err != nil {
-				return nil, errors.Wrap(err, codes.Inherit, "csv.from() failed to read file")
+				return nil, errors.Wrapf(err, codes.Inherit, "csv.from() failed to match files with pattern %q", spec.Glob)
 			}
-			return f, nil
+			streams := make([]dataStream, len(files))
+			for i, file := range files {
+				streams[i] = openFile(a.Context(), file)
+			}
+			return streams, nil
 		}
-	} else { // if spec.File is empty then spec.CSV is not empty
-		getDataStream = func() (io.ReadCloser, error) {
-			return ioutil.NopCloser(strings.NewReader(spec.CSV)), nil
+	default: // if spec.File and spec.Glob are empty then spec.CSV is not empty
+		getDataStreams = func() ([]dataStream, error) {
+			return []dataStream{func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(strings.NewReader(spec.CSV)), nil
+			}}, nil
 		}
 	}
 	csvSource := CSVSource{
-		id:            dsid,
-		getDataStream: getDataStream,
-		alloc:         a.Allocator(),
-		mode:          spec.Mode,
+		id:             dsid,
+		getDataStreams: getDataStreams,
+		alloc:          a.Allocator(),
+		mode:           spec.Mode,
 	}
 
 	return &csvSource, nil
 }
 
+// dataStream opens one of the inputs of the source.
+type dataStream func() (io.ReadCloser, error)
+
+func openFile(ctx context.Context, file string) dataStream {
+	return func() (io.ReadCloser, error) {
+		f, err := filesystem.OpenFile(ctx, file)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Inherit, "csv.from() failed to read file")
+		}
+		return f, nil
+	}
+}
+
 type CSVSource struct {
 	execute.ExecutionNode
-	id            execute.DatasetID
-	getDataStream func() (io.ReadCloser, error)
-	ts            []execute.Transformation
-	alloc         memory.Allocator
-	mode          string
+	id             execute.DatasetID
+	getDataStreams func() ([]dataStream, error)
+	ts             []execute.Transformation
+	alloc          memory.Allocator
+	mode           string
 }
 
 func (c *CSVSource) AddTransformation(t execute.Transformation) {
@@ -160,71 +262,25 @@ func (c *CSVSource) AddTransformation(t execute.Transformation) {
 }
 
 func (c *CSVSource) Run(ctx context.Context) {
-	var err error
-	var max execute.Time
-	maxSet := false
+	var (
+		max    execute.Time
+		maxSet bool
+	)
+
+	// The files are matched once so every transformation
+	// reads the same set of files.
+	streams, err := c.getDataStreams()
+	if err != nil {
+		goto FINISH
+	}
 
 	for _, t := range c.ts {
-		// For each downstream transformation, instantiate a new result
-		// decoder. This way a table instance goes to one and only one
-		// transformation. Unlike other sources, tables from csv sources
-		// are not read-only. They contain mutable state and therefore
-		// cannot be shared among goroutines.
-		config := csv.ResultDecoderConfig{
-			Allocator: c.alloc,
-			Context:   ctx,
-		}
-		switch c.mode {
-		case rawMode:
-			config.NoAnnotations = true
-		default:
-		}
-		decoder := csv.NewMultiResultDecoder(config)
-		var data io.ReadCloser
-		data, err = c.getDataStream()
-		if err != nil {
-			goto FINISH
-		}
-		// Many applications will add a UTF BOM (byte order mark) to the beginning of csv files
-		// We expect UTF8 encoded data so the byte order does not matter.
-		// Therefore we skip the BOM if it exists.
-		// See http://www.unicode.org/faq/utf_bom.html#BOM
-		rc := newSkipBOMReader(data)
-		results, decodeErr := decoder.Decode(rc)
-		defer results.Release()
-		if decodeErr != nil {
-			err = decodeErr
-			goto FINISH
-		}
-
-		if !results.More() {
-			err = results.Err()
-			goto FINISH
-		}
-		result := results.Next()
-
-		err = result.Tables().Do(func(tbl flux.Table) error {
-			err := t.Process(c.id, tbl)
-			if err != nil {
-				return err
-			}
-			if idx := execute.ColIdx(execute.DefaultStopColLabel, tbl.Key().Cols()); idx >= 0 {
-				if stop := tbl.Key().ValueTime(idx); !maxSet || stop > max {
-					max = stop
-					maxSet = true
-				}
+		// The streams are read in order and the tables from
+		// every stream are sent to the transformation.
+		for _, stream := range streams {
+			if err = c.process(ctx, t, stream, &max, &maxSet); err != nil {
+				goto FINISH
 			}
-			return nil
-		})
-		if err != nil {
-			goto FINISH
-		}
-		if results.More() {
-			err = errors.New(
-				codes.FailedPrecondition,
-				"csv.from() can only parse 1 result",
-			)
-			goto FINISH
 		}
 	}
 
@@ -246,6 +302,67 @@ FINISH:
 	}
 }
 
+// process decodes the stream and sends the tables to the transformation.
+func (c *CSVSource) process(ctx context.Context, t execute.Transformation, stream dataStream, max *execute.Time, maxSet *bool) error {
+	// For each downstream transformation, instantiate a new result
+	// decoder. This way a table instance goes to one and only one
+	// transformation. Unlike other sources, tables from csv sources
+	// are not read-only. They contain mutable state and therefore
+	// cannot be shared among goroutines.
+	config := csv.ResultDecoderConfig{
+		Allocator: c.alloc,
+		Context:   ctx,
+	}
+	switch c.mode {
+	case rawMode:
+		config.NoAnnotations = true
+	default:
+	}
+	decoder := csv.NewMultiResultDecoder(config)
+	data, err := stream()
+	if err != nil {
+		return err
+	}
+	// Many applications will add a UTF BOM (byte order mark) to the beginning of csv files
+	// We expect UTF8 encoded data so the byte order does not matter.
+	// Therefore we skip the BOM if it exists.
+	// See http://www.unicode.org/faq/utf_bom.html#BOM
+	rc := newSkipBOMReader(data)
+	results, err := decoder.Decode(rc)
+	if err != nil {
+		return err
+	}
+	defer results.Release()
+
+	if !results.More() {
+		return results.Err()
+	}
+	result := results.Next()
+
+	if err := result.Tables().Do(func(tbl flux.Table) error {
+		err := t.Process(c.id, tbl)
+		if err != nil {
+			return err
+		}
+		if idx := execute.ColIdx(execute.DefaultStopColLabel, tbl.Key().Cols()); idx >= 0 {
+			if stop := tbl.Key().ValueTime(idx); !*maxSet || stop > *max {
+				*max = stop
+				*maxSet = true
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if results.More() {
+		return errors.New(
+			codes.FailedPrecondition,
+			"csv.from() can only parse 1 result",
+		)
+	}
+	return nil
+}
+
 // skipBOMReader wraps an io.ReadCloser and skips the BOM,
 // if it exists at the beginning of the stream.
 type skipBOMReader struct {
@@ -0,0 +1,193 @@
+package csv
+
+import (
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/dependencies/spill"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+)
+
+const ToCSVKind = "toCSV"
+
+type ToCSVOpSpec struct {
+	File string `json:"file"`
+}
+
+func init() {
+	toCSVSignature := runtime.MustLookupBuiltinType("csv", "to")
+	runtime.RegisterPackageValue("csv", "to", flux.MustValue(flux.FunctionValueWithSideEffect(ToCSVKind, createToCSVOpSpec, toCSVSignature)))
+	flux.RegisterOpSpec(ToCSVKind, newToCSVOp)
+	plan.RegisterProcedureSpecWithSideEffect(ToCSVKind, newToCSVProcedure, ToCSVKind)
+	execute.RegisterTransformation(ToCSVKind, createToCSVTransformation)
+}
+
+func createToCSVOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(ToCSVOpSpec)
+	if file, err := args.GetRequiredString("file"); err != nil {
+		return nil, err
+	} else if file == "" {
+		return nil, errors.New(codes.Invalid, "must provide a filename")
+	} else {
+		spec.File = file
+	}
+	return spec, nil
+}
+
+func newToCSVOp() flux.OperationSpec {
+	return new(ToCSVOpSpec)
+}
+
+func (s *ToCSVOpSpec) Kind() flux.OperationKind {
+	return ToCSVKind
+}
+
+type ToCSVProcedureSpec struct {
+	plan.DefaultCost
+	File string
+}
+
+func newToCSVProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*ToCSVOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &ToCSVProcedureSpec{
+		File: spec.File,
+	}, nil
+}
+
+func (s *ToCSVProcedureSpec) Kind() plan.ProcedureKind {
+	return ToCSVKind
+}
+
+func (s *ToCSVProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func createToCSVTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*ToCSVProcedureSpec)
+	if !ok {
+		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
+	}
+	// Use the spill directory when one is configured.
+	c, _ := spill.Get(a.Context())
+	spiller := spill.Always(c.Dir)
+	mem := a.Allocator()
+	t := &toCSVTransformation{
+		d:    execute.NewTransportDataset(id, mem),
+		spec: s,
+		a:    a,
+		cache: table.BuilderCache{
+			New: func(key flux.GroupKey) table.Builder {
+				return spill.NewBuilder(key, spiller, mem)
+			},
+		},
+	}
+	return execute.NewTransformationFromTransport(t), t.d, nil
+}
+
+// toCSVTransformation passes its input through unchanged and writes
+// the tables to the file once the input is finished.
+//
+// The chunks of a table may be interleaved with the chunks of other
+// tables so they are spilled to a temporary file for each group key
+// to write each table once without holding the input in memory.
+// The file is written to a temporary file that replaces it when
+// it is complete and it is not written at all if the input fails.
+type toCSVTransformation struct {
+	d     *execute.TransportDataset
+	spec  *ToCSVProcedureSpec
+	a     execute.Administration
+	cache table.BuilderCache
+}
+
+func (t *toCSVTransformation) ProcessMessage(m execute.Message) error {
+	defer m.Ack()
+
+	switch m := m.(type) {
+	case execute.FinishMsg:
+		t.Finish(m.SrcDatasetID(), m.Error())
+		return nil
+	case execute.ProcessChunkMsg:
+		return t.processChunk(m.TableChunk())
+	case execute.FlushKeyMsg:
+		return t.d.FlushKey(m.Key())
+	case execute.ProcessMsg:
+		panic("unreachable")
+	}
+	return nil
+}
+
+// Finish is implemented to remain compatible with legacy upstreams.
+func (t *toCSVTransformation) Finish(id execute.DatasetID, err error) {
+	if err == nil {
+		err = t.write()
+	}
+	_ = t.cache.ForEach(func(key flux.GroupKey, builder table.Builder) error {
+		builder.Release()
+		return nil
+	})
+	t.d.Finish(err)
+}
+
+func (t *toCSVTransformation) processChunk(chunk table.Chunk) error {
+	builder, _ := spill.GetBuilder(chunk.Key(), &t.cache)
+	buf := chunk.Buffer()
+	if err := builder.AppendBuffer(&buf); err != nil {
+		return err
+	}
+	if err := builder.Spill(); err != nil {
+		return err
+	}
+
+	chunk.Retain()
+	return t.d.Process(chunk)
+}
+
+func (t *toCSVTransformation) write() error {
+	f, err := filesystem.CreateAtomicFile(t.a.Context(), t.spec.File)
+	if err != nil {
+		return errors.Wrap(err, codes.Inherit, "csv.to() failed to create file")
+	}
+	enc := csv.NewResultEncoder(csv.DefaultEncoderConfig())
+	if _, err := enc.Encode(f, &toCSVResult{cache: &t.cache}); err != nil {
+		_ = f.Abort()
+		return errors.Wrap(err, codes.Inherit, "csv.to() failed to write file")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, codes.Inherit, "csv.to() failed to write file")
+	}
+	return nil
+}
+
+// toCSVResult reads the table for each group key
+// from the cache as the result is encoded.
+type toCSVResult struct {
+	cache *table.BuilderCache
+}
+
+func (r *toCSVResult) Name() string {
+	return "_result"
+}
+
+func (r *toCSVResult) Tables() flux.TableIterator {
+	return r
+}
+
+func (r *toCSVResult) Do(f func(flux.Table) error) error {
+	return r.cache.ForEach(func(key flux.GroupKey, builder table.Builder) error {
+		tbl, err := builder.Table()
+		if err != nil {
+			return err
+		}
+		defer tbl.Done()
+		return f(tbl)
+	})
+}
@@ -0,0 +1,132 @@
+package csv_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/dependency"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/runtime"
+)
+
+func runQuery(t *testing.T, query string) []*executetest.Table {
+	t.Helper()
+	tables, err := runQueryErr(t, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tables
+}
+
+func runQueryErr(t *testing.T, query string) ([]*executetest.Table, error) {
+	t.Helper()
+
+	ctx, deps := dependency.Inject(context.Background(), dependenciestest.Default())
+	defer deps.Finish()
+
+	c := &lang.FluxCompiler{Query: query}
+	program, err := c.Compile(ctx, runtime.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := program.Start(ctx, &memory.ResourceAllocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Done()
+
+	var tables []*executetest.Table
+	for res := range q.Results() {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			et, err := executetest.ConvertTable(tbl)
+			if err != nil {
+				return err
+			}
+			tables = append(tables, et)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	q.Done()
+
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+	executetest.NormalizeTables(tables)
+	return tables, nil
+}
+
+func TestToCSV_Glob(t *testing.T) {
+	dir := t.TempDir()
+
+	var want []*executetest.Table
+	for _, day := range []string{"2021-01-01", "2021-01-02"} {
+		want = append(want, runQuery(t, fmt.Sprintf(`
+import "array"
+import "csv"
+
+array.from(rows: [
+	{_time: %[1]sT00:00:00Z, host: "%[1]s-a", _value: 1.5, n: 1, ok: true},
+	{_time: %[1]sT00:00:10Z, host: "%[1]s-a", _value: 2.5, n: 2, ok: false},
+	{_time: %[1]sT00:00:00Z, host: "%[1]s-b", _value: 3.5, n: 3, ok: true},
+])
+	|> group(columns: ["host"])
+	|> csv.to(file: %[2]q)
+`, day, filepath.Join(dir, day+".csv")))...)
+	}
+
+	got := runQuery(t, fmt.Sprintf(`
+import "csv"
+
+csv.from(glob: %q)
+`, filepath.Join(dir, "*.csv")))
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	if got := runQuery(t, fmt.Sprintf(`
+import "csv"
+
+csv.from(glob: %q)
+`, filepath.Join(dir, "*.txt"))); len(got) != 0 {
+		t.Errorf("expected no tables, got %v", got)
+	}
+}
+
+func TestToCSV_Error(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "out.csv")
+	if err := ioutil.WriteFile(file, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runQueryErr(t, fmt.Sprintf(`
+import "array"
+import "csv"
+
+array.from(rows: [{_value: 1}, {_value: 2}])
+	|> map(fn: (r) => ({r with _value: if r._value > 1 then die(msg: "expected error") else r._value}))
+	|> csv.to(file: %q)
+`, file)); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// The file is left unchanged when the query fails.
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "old"; got != want {
+		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+}
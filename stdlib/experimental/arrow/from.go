@@ -0,0 +1,155 @@
+package arrow
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+
+	"github.com/apache/arrow/go/v7/arrow/ipc"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/arrowio"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+)
+
+const (
+	pkgpath  = "experimental/arrow"
+	FromKind = pkgpath + ".from"
+)
+
+const (
+	fileFormat   = "file"
+	streamFormat = "stream"
+)
+
+type FromOpSpec struct {
+	File   string
+	Format string
+}
+
+func init() {
+	fromSignature := runtime.MustLookupBuiltinType(pkgpath, "from")
+	runtime.RegisterPackageValue(pkgpath, "from", flux.MustValue(flux.FunctionValue(FromKind, createFromOpSpec, fromSignature)))
+	plan.RegisterProcedureSpec(FromKind, newFromProcedure, FromKind)
+	execute.RegisterSource(FromKind, createFromSource)
+}
+
+func createFromOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(FromOpSpec)
+	if f, err := args.GetRequiredString("file"); err != nil {
+		return nil, err
+	} else {
+		spec.File = f
+	}
+
+	format, err := getFormat(args)
+	if err != nil {
+		return nil, err
+	}
+	spec.Format = format
+	return spec, nil
+}
+
+func getFormat(args flux.Arguments) (string, error) {
+	format, ok, err := args.GetString("format")
+	if err != nil {
+		return "", err
+	} else if !ok {
+		return fileFormat, nil
+	}
+
+	switch format {
+	case fileFormat, streamFormat:
+		return format, nil
+	default:
+		return "", errors.Newf(codes.Invalid, "unsupported format %q, must be %q or %q", format, fileFormat, streamFormat)
+	}
+}
+
+func (s *FromOpSpec) Kind() flux.OperationKind {
+	return FromKind
+}
+
+type FromProcedureSpec struct {
+	plan.DefaultCost
+	File   string
+	Format string
+}
+
+func newFromProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &FromProcedureSpec{
+		File:   spec.File,
+		Format: spec.Format,
+	}, nil
+}
+
+func (s *FromProcedureSpec) Kind() plan.ProcedureKind {
+	return FromKind
+}
+
+func (s *FromProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func createFromSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := ps.(*FromProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", ps)
+	}
+	return execute.CreateSourceFromIterator(&fileSource{
+		file:   spec.File,
+		format: spec.Format,
+		mem:    a.Allocator(),
+	}, id)
+}
+
+type fileSource struct {
+	file   string
+	format string
+	mem    memory.Allocator
+}
+
+func (s *fileSource) Do(ctx context.Context, f func(flux.Table) error) error {
+	file, err := filesystem.OpenFile(ctx, s.file)
+	if err != nil {
+		return errors.Wrap(err, codes.Inherit, "arrow.from() failed to read file")
+	}
+	defer func() { _ = file.Close() }()
+
+	if s.format == streamFormat {
+		r, err := ipc.NewReader(file, ipc.WithAllocator(s.mem))
+		if err != nil {
+			return errors.Wrap(err, codes.Invalid, "arrow.from() failed to read file")
+		}
+		defer r.Release()
+		return arrowio.ReadTables(r, s.mem, f)
+	}
+
+	// The file format stores its footer at the end of the file
+	// so a file that cannot seek is read into memory.
+	ra, ok := file.(ipc.ReadAtSeeker)
+	if !ok {
+		data, err := ioutil.ReadAll(file)
+		if err != nil {
+			return errors.Wrap(err, codes.Inherit, "arrow.from() failed to read file")
+		}
+		ra = bytes.NewReader(data)
+	}
+
+	r, err := ipc.NewFileReader(ra, ipc.WithAllocator(s.mem))
+	if err != nil {
+		return errors.Wrap(err, codes.Invalid, "arrow.from() failed to read file")
+	}
+	defer func() { _ = r.Close() }()
+	return arrowio.ReadTables(r, s.mem, f)
+}
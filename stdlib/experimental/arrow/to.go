@@ -0,0 +1,128 @@
+package arrow
+
+import (
+	"io"
+
+	stdarrow "github.com/apache/arrow/go/v7/arrow"
+	"github.com/apache/arrow/go/v7/arrow/ipc"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/arrowio"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+)
+
+const ToKind = pkgpath + ".to"
+
+type ToOpSpec struct {
+	File   string
+	Format string
+}
+
+func init() {
+	toSignature := runtime.MustLookupBuiltinType(pkgpath, "to")
+	runtime.RegisterPackageValue(pkgpath, "to", flux.MustValue(flux.FunctionValueWithSideEffect(ToKind, createToOpSpec, toSignature)))
+	plan.RegisterProcedureSpecWithSideEffect(ToKind, newToProcedure, ToKind)
+	execute.RegisterTransformation(ToKind, createToTransformation)
+}
+
+func createToOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(ToOpSpec)
+	if f, err := args.GetRequiredString("file"); err != nil {
+		return nil, err
+	} else {
+		spec.File = f
+	}
+
+	format, err := getFormat(args)
+	if err != nil {
+		return nil, err
+	}
+	spec.Format = format
+	return spec, nil
+}
+
+func (s *ToOpSpec) Kind() flux.OperationKind {
+	return ToKind
+}
+
+type ToProcedureSpec struct {
+	plan.DefaultCost
+	File   string
+	Format string
+}
+
+func newToProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*ToOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &ToProcedureSpec{
+		File:   spec.File,
+		Format: spec.Format,
+	}, nil
+}
+
+func (s *ToProcedureSpec) Kind() plan.ProcedureKind {
+	return ToKind
+}
+
+func (s *ToProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func createToTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*ToProcedureSpec)
+	if !ok {
+		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
+	}
+
+	ctx := a.Context()
+	create := func(schema *stdarrow.Schema) (arrowio.Writer, error) {
+		f, err := filesystem.CreateAtomicFile(ctx, s.File)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Inherit, "arrow.to() failed to create file")
+		}
+
+		opts := []ipc.Option{
+			ipc.WithSchema(schema),
+			ipc.WithAllocator(a.Allocator()),
+		}
+		if s.Format == streamFormat {
+			return arrowio.NewFileWriter(ipc.NewWriter(f, opts...), f), nil
+		}
+
+		w, err := ipc.NewFileWriter(&offsetWriter{w: f}, opts...)
+		if err != nil {
+			_ = f.Abort()
+			return nil, err
+		}
+		return arrowio.NewFileWriter(w, f), nil
+	}
+	return arrowio.NewWriteTransformation(ctx, id, create, a.Allocator())
+}
+
+// offsetWriter tracks the number of bytes written so the
+// file writer can determine its position without the file
+// being seekable.
+type offsetWriter struct {
+	w      io.Writer
+	offset int64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.offset += int64(n)
+	return n, err
+}
+
+func (w *offsetWriter) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekCurrent {
+		return 0, errors.New(codes.Internal, "arrow.to() output can only report its current position")
+	}
+	return w.offset, nil
+}
@@ -0,0 +1,131 @@
+package lineprotocol
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/line-protocol/v2/lineprotocol"
+)
+
+const (
+	pkgpath  = "experimental/lineprotocol"
+	FromKind = pkgpath + ".from"
+)
+
+type FromOpSpec struct {
+	File      string
+	Precision lineprotocol.Precision
+}
+
+func init() {
+	fromSignature := runtime.MustLookupBuiltinType(pkgpath, "from")
+	runtime.RegisterPackageValue(pkgpath, "from", flux.MustValue(flux.FunctionValue(FromKind, createFromOpSpec, fromSignature)))
+	plan.RegisterProcedureSpec(FromKind, newFromProcedure, FromKind)
+	execute.RegisterSource(FromKind, createFromSource)
+}
+
+func createFromOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(FromOpSpec)
+	if f, err := args.GetRequiredString("file"); err != nil {
+		return nil, err
+	} else {
+		spec.File = f
+	}
+
+	if d, ok, err := args.GetDuration("precision"); err != nil {
+		return nil, err
+	} else if ok {
+		switch d.Duration() {
+		case time.Nanosecond:
+			spec.Precision = lineprotocol.Nanosecond
+		case time.Microsecond:
+			spec.Precision = lineprotocol.Microsecond
+		case time.Millisecond:
+			spec.Precision = lineprotocol.Millisecond
+		case time.Second:
+			spec.Precision = lineprotocol.Second
+		default:
+			return nil, errors.Newf(codes.Invalid, "unsupported precision %v, must be one of 1ns, 1us, 1ms or 1s", d)
+		}
+	}
+	return spec, nil
+}
+
+func (s *FromOpSpec) Kind() flux.OperationKind {
+	return FromKind
+}
+
+type FromProcedureSpec struct {
+	plan.DefaultCost
+	File      string
+	Precision lineprotocol.Precision
+}
+
+func newFromProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &FromProcedureSpec{
+		File:      spec.File,
+		Precision: spec.Precision,
+	}, nil
+}
+
+func (s *FromProcedureSpec) Kind() plan.ProcedureKind {
+	return FromKind
+}
+
+func (s *FromProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func createFromSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := ps.(*FromProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", ps)
+	}
+
+	now := time.Now()
+	if execute.HaveExecutionDependencies(a.Context()) {
+		if deps := execute.GetExecutionDependencies(a.Context()); deps.Now != nil {
+			now = *deps.Now
+		}
+	}
+	return execute.CreateSourceFromIterator(&fileSource{
+		file: spec.File,
+		decoder: &line.ProtocolDecoder{
+			Precision: spec.Precision,
+			Now:       now,
+			Allocator: a.Allocator(),
+		},
+	}, id)
+}
+
+type fileSource struct {
+	file    string
+	decoder *line.ProtocolDecoder
+}
+
+func (s *fileSource) Do(ctx context.Context, f func(flux.Table) error) error {
+	r, err := filesystem.OpenFile(ctx, s.file)
+	if err != nil {
+		return errors.Wrap(err, codes.Inherit, "lineprotocol.from() failed to read file")
+	}
+	defer func() { _ = r.Close() }()
+
+	res, err := s.decoder.Decode(r)
+	if err != nil {
+		return errors.Wrap(err, codes.Inherit, "lineprotocol.from() failed to read file")
+	}
+	return res.Tables().Do(f)
+}
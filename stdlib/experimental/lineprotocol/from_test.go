@@ -0,0 +1,110 @@
+package lineprotocol_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/dependency"
+	"github.com/influxdata/flux/execute/executetest"
+	_ "github.com/influxdata/flux/fluxinit/static"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/runtime"
+)
+
+func runQuery(t *testing.T, query string) ([]*executetest.Table, error) {
+	t.Helper()
+
+	ctx, deps := dependency.Inject(context.Background(), dependenciestest.Default())
+	defer deps.Finish()
+
+	c := &lang.FluxCompiler{Query: query}
+	program, err := c.Compile(ctx, runtime.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := program.Start(ctx, &memory.ResourceAllocator{})
+	if err != nil {
+		return nil, err
+	}
+	defer q.Done()
+
+	var tables []*executetest.Table
+	for res := range q.Results() {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			et, err := executetest.ConvertTable(tbl)
+			if err != nil {
+				return err
+			}
+			tables = append(tables, et)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	q.Done()
+
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+	executetest.NormalizeTables(tables)
+	return tables, nil
+}
+
+func TestFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.lp")
+	data := `cpu,host=a usage=1.5 1609459200
+cpu,host=a usage=2.5 1609459210
+cpu,host=b usage=3.5,count=2i 1609459200
+`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := runQuery(t, fmt.Sprintf(`
+import "experimental/lineprotocol"
+
+lineprotocol.from(file: %q, precision: 1s)
+`, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := runQuery(t, `
+import "array"
+
+array.from(rows: [
+	{_time: 2021-01-01T00:00:00Z, _value: 1.5, _field: "usage", _measurement: "cpu", host: "a"},
+	{_time: 2021-01-01T00:00:10Z, _value: 2.5, _field: "usage", _measurement: "cpu", host: "a"},
+	{_time: 2021-01-01T00:00:00Z, _value: 3.5, _field: "usage", _measurement: "cpu", host: "b"},
+])
+	|> group(columns: ["_field", "_measurement", "host"])
+	|> union(tables: array.from(rows: [
+		{_time: 2021-01-01T00:00:00Z, _value: 2, _field: "count", _measurement: "cpu", host: "b"},
+	]) |> group(columns: ["_field", "_measurement", "host"]))
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestFrom_InvalidPrecision(t *testing.T) {
+	if _, err := runQuery(t, `
+import "experimental/lineprotocol"
+
+lineprotocol.from(file: "data.lp", precision: 1m)
+`); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,210 @@
+package mqtt
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/mqtt"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/pubsub"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+)
+
+const FromMQTTKind = "fromMQTT"
+
+func init() {
+	fromMQTTSignature := runtime.MustLookupBuiltinType("experimental/mqtt", "from")
+
+	runtime.RegisterPackageValue("experimental/mqtt", "from", flux.MustValue(flux.FunctionValue(FromMQTTKind, createFromMQTTOpSpec, fromMQTTSignature)))
+	flux.RegisterOpSpec(FromMQTTKind, func() flux.OperationSpec { return &FromMQTTOpSpec{} })
+	plan.RegisterProcedureSpec(FromMQTTKind, newFromMQTTProcedure, FromMQTTKind)
+	execute.RegisterSource(FromMQTTKind, createFromMQTTSource)
+}
+
+// FromMQTTOpSpec subscribes to a topic and reads the messages until
+// MaxMessages messages have been received or the Stop time is reached.
+type FromMQTTOpSpec struct {
+	CommonMQTTOpSpec
+	Topic       string    `json:"topic"`
+	Decoder     string    `json:"decoder"`
+	MaxMessages int64     `json:"maxMessages"`
+	Stop        flux.Time `json:"stop"`
+}
+
+// ReadArgs loads a flux.Arguments into FromMQTTOpSpec.
+// The decoder defaults to line protocol.
+func (o *FromMQTTOpSpec) ReadArgs(args flux.Arguments) error {
+	var err error
+	var ok bool
+
+	if err = o.CommonMQTTOpSpec.ReadArgs(args); err != nil {
+		return err
+	}
+
+	if o.Topic, err = args.GetRequiredString("topic"); err != nil {
+		return err
+	}
+
+	o.Decoder, ok, err = args.GetString("decoder")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		o.Decoder = pubsub.DecoderLineProtocol
+	}
+	if err := pubsub.ValidateDecoder(o.Decoder); err != nil {
+		return err
+	}
+
+	if o.MaxMessages, _, err = args.GetInt("maxMessages"); err != nil {
+		return err
+	}
+	if o.MaxMessages < 0 {
+		return errors.New(codes.Invalid, "maxMessages must be positive")
+	}
+	if o.Stop, _, err = args.GetTime("stop"); err != nil {
+		return err
+	}
+	if o.MaxMessages == 0 && o.Stop.IsZero() {
+		return errors.New(codes.Invalid, "mqtt.from requires maxMessages or stop to bound the read")
+	}
+	return nil
+}
+
+func createFromMQTTOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	s := new(FromMQTTOpSpec)
+	if err := s.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (FromMQTTOpSpec) Kind() flux.OperationKind {
+	return FromMQTTKind
+}
+
+type FromMQTTProcedureSpec struct {
+	plan.DefaultCost
+	Spec *FromMQTTOpSpec
+}
+
+func (o *FromMQTTProcedureSpec) Kind() plan.ProcedureKind {
+	return FromMQTTKind
+}
+
+func (o *FromMQTTProcedureSpec) Copy() plan.ProcedureSpec {
+	s := *o.Spec
+	return &FromMQTTProcedureSpec{Spec: &s}
+}
+
+func newFromMQTTProcedure(qs flux.OperationSpec, a plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromMQTTOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &FromMQTTProcedureSpec{Spec: spec}, nil
+}
+
+func createFromMQTTSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := ps.(*FromMQTTProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", ps)
+	}
+	return CreateSource(spec, id, a)
+}
+
+// CreateSource creates a source that reads the messages of an mqtt topic.
+func CreateSource(spec *FromMQTTProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	var stop time.Time
+	if !spec.Spec.Stop.IsZero() {
+		now := time.Now()
+		if execute.HaveExecutionDependencies(a.Context()) {
+			if deps := execute.GetExecutionDependencies(a.Context()); deps.Now != nil {
+				now = *deps.Now
+			}
+		}
+		stop = spec.Spec.Stop.Time(now)
+	}
+	return execute.CreateSourceFromIterator(&mqttSource{
+		ctx:   a.Context(),
+		spec:  spec.Spec,
+		stop:  stop,
+		alloc: a.Allocator(),
+	}, id)
+}
+
+type mqttSource struct {
+	ctx   context.Context
+	spec  *FromMQTTOpSpec
+	stop  time.Time
+	alloc memory.Allocator
+}
+
+func (s *mqttSource) Do(ctx context.Context, f func(flux.Table) error) error {
+	client, err := dial(s.ctx, &s.spec.CommonMQTTOpSpec)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	msgs, err := s.receive(ctx, client)
+	if err != nil {
+		return err
+	}
+	tables, err := pubsub.Decode(s.spec.Decoder, msgs, s.alloc)
+	if err != nil {
+		return err
+	}
+	return tables.Do(f)
+}
+
+// receive subscribes to the topic and returns the messages
+// that are received before the read is stopped. The time of
+// each message is the time that it was received.
+func (s *mqttSource) receive(ctx context.Context, client mqtt.Client) (msgs []pubsub.Message, err error) {
+	var (
+		received = make(chan pubsub.Message, 64)
+		done     = make(chan struct{})
+	)
+	handler := func(m mqtt.Message) {
+		select {
+		case received <- pubsub.Message{Value: m.Payload, Time: time.Now()}:
+		case <-done:
+		}
+	}
+	if err := client.Subscribe(ctx, s.spec.Topic, byte(s.spec.QoS), handler); err != nil {
+		return nil, err
+	}
+	defer func() {
+		close(done)
+		if uerr := client.Unsubscribe(ctx, s.spec.Topic); uerr != nil && err == nil {
+			err = uerr
+		}
+	}()
+
+	var timeout <-chan time.Time
+	if !s.stop.IsZero() {
+		timer := time.NewTimer(time.Until(s.stop))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for s.spec.MaxMessages == 0 || int64(len(msgs)) < s.spec.MaxMessages {
+		select {
+		case m := <-received:
+			if !s.stop.IsZero() && !m.Time.Before(s.stop) {
+				return msgs, nil
+			}
+			msgs = append(msgs, m)
+		case <-timeout:
+			return msgs, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return msgs, nil
+}
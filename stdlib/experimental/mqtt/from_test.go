@@ -0,0 +1,216 @@
+package mqtt_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/mqtt"
+	"github.com/influxdata/flux/dependency"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/mock"
+	"github.com/influxdata/flux/querytest"
+	fluxmqtt "github.com/influxdata/flux/stdlib/experimental/mqtt"
+)
+
+func TestFromMQTT_NewQuery(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name: "from topic",
+			Raw: `
+import "experimental/mqtt"
+mqtt.from(broker: "tcp://iot.eclipse.org:1883", topic: "sensors/#", maxMessages: 10)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromMQTT0",
+						Spec: &fluxmqtt.FromMQTTOpSpec{
+							CommonMQTTOpSpec: fluxmqtt.CommonMQTTOpSpec{
+								Broker:   "tcp://iot.eclipse.org:1883",
+								ClientID: "flux-mqtt",
+								Timeout:  fluxmqtt.DefaultConnectMQTTTimeout,
+							},
+							Topic:       "sensors/#",
+							Decoder:     "lineProtocol",
+							MaxMessages: 10,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "unbounded",
+			Raw: `
+import "experimental/mqtt"
+mqtt.from(broker: "tcp://iot.eclipse.org:1883", topic: "sensors/#")`,
+			WantErr: true,
+		},
+		{
+			Name: "invalid decoder",
+			Raw: `
+import "experimental/mqtt"
+mqtt.from(broker: "tcp://iot.eclipse.org:1883", topic: "sensors/#", maxMessages: 10, decoder: "xml")`,
+			WantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+// fakeBroker is an mqtt.Dialer for a broker that publishes
+// the messages to each client that subscribes.
+func fakeBroker(t *testing.T, messages ...string) mqtt.Dialer {
+	return mock.MqttDialer{
+		DialFn: func(ctx context.Context, brokers []string, options mqtt.Options) (mqtt.Client, error) {
+			unsubscribed := make(chan struct{})
+			return mock.MqttClient{
+				SubscribeFn: func(ctx context.Context, topic string, qos byte, handler func(mqtt.Message)) error {
+					if want, got := "sensors/#", topic; want != got {
+						t.Errorf("unexpected topic: want %q, got %q", want, got)
+					}
+					go func() {
+						for _, m := range messages {
+							select {
+							case <-unsubscribed:
+								return
+							default:
+								handler(mqtt.Message{Topic: "sensors/a", Payload: []byte(m)})
+							}
+						}
+					}()
+					return nil
+				},
+				UnsubscribeFn: func(ctx context.Context, topic string) error {
+					close(unsubscribed)
+					return nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestFromMQTT_Run(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     *fluxmqtt.FromMQTTOpSpec
+		messages []string
+		want     []*executetest.Table
+	}{
+		{
+			name: "line protocol",
+			spec: &fluxmqtt.FromMQTTOpSpec{
+				Decoder:     "lineProtocol",
+				MaxMessages: 2,
+			},
+			messages: []string{
+				"m,host=a v=1 10\nm,host=b v=2 10",
+				"m,host=a v=3 20",
+				"m,host=a v=4 30",
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"_field", "_measurement", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(10), 1.0, "v", "m", "a"},
+						{execute.Time(20), 3.0, "v", "m", "a"},
+					},
+				},
+				{
+					KeyCols: []string{"_field", "_measurement", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(10), 2.0, "v", "m", "b"},
+					},
+				},
+			},
+		},
+		{
+			name: "json",
+			spec: &fluxmqtt.FromMQTTOpSpec{
+				Decoder:     "json",
+				MaxMessages: 2,
+			},
+			messages: []string{
+				`{"_time": "1970-01-01T00:00:00.00000001Z", "host": "a", "v": 1}`,
+				`[{"_time": "1970-01-01T00:00:00.00000002Z", "host": "b", "v": 2, "ok": true}]`,
+			},
+			want: []*executetest.Table{
+				{
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "host", Type: flux.TString},
+						{Label: "ok", Type: flux.TBool},
+						{Label: "v", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{execute.Time(10), "a", nil, 1.0},
+						{execute.Time(20), "b", true, 2.0},
+					},
+				},
+			},
+		},
+		{
+			name: "stop",
+			spec: &fluxmqtt.FromMQTTOpSpec{
+				Decoder: "lineProtocol",
+				Stop:    flux.Time{IsRelative: true, Relative: 100 * time.Millisecond},
+			},
+			messages: []string{"m v=1i 10"},
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"_field", "_measurement"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TInt},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(10), int64(1), "v", "m"},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, span := dependency.Inject(context.Background(),
+				mqtt.Dependency{Dialer: fakeBroker(t, tc.messages...)},
+			)
+			defer span.Finish()
+
+			spec := *tc.spec
+			spec.Broker = "tcp://localhost:1883"
+			spec.Topic = "sensors/#"
+			executetest.RunSourceHelper(t, tc.want, nil, func(id execute.DatasetID) execute.Source {
+				a := mock.AdministrationWithContext(ctx)
+				s, err := fluxmqtt.CreateSource(&fluxmqtt.FromMQTTProcedureSpec{Spec: &spec}, id, a)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return s
+			})
+		})
+	}
+}
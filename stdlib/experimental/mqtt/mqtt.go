@@ -89,7 +89,9 @@ func (o *CommonMQTTOpSpec) ReadArgs(args flux.Arguments) error {
 	return nil
 }
 
-func publish(ctx context.Context, topic, message string, spec *CommonMQTTOpSpec) (bool, error) {
+// dial connects a client to the broker of the spec
+// with the dialer of the dependencies in the context.
+func dial(ctx context.Context, spec *CommonMQTTOpSpec) (mqtt.Client, error) {
 	options := mqtt.Options{
 		ClientID: spec.ClientID,
 		Username: spec.Username,
@@ -97,7 +99,11 @@ func publish(ctx context.Context, topic, message string, spec *CommonMQTTOpSpec)
 		Timeout:  spec.Timeout,
 	}
 	provider := mqtt.GetDialer(ctx)
-	client, err := provider.Dial(ctx, []string{spec.Broker}, options)
+	return provider.Dial(ctx, []string{spec.Broker}, options)
+}
+
+func publish(ctx context.Context, topic, message string, spec *CommonMQTTOpSpec) (bool, error) {
+	client, err := dial(ctx, spec)
 	if err != nil {
 		return false, err
 	}
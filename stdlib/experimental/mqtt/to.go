@@ -12,7 +12,7 @@ import (
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
-	"github.com/influxdata/flux/internal/pkg/syncutil"
+	"github.com/influxdata/flux/internal/execute/deadletter"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/flux/semantic"
@@ -46,6 +46,7 @@ type ToMQTTOpSpec struct {
 	TimeColumn   string   `json:"timeColumn"`
 	TagColumns   []string `json:"tagColumns"`
 	ValueColumns []string `json:"valueColumns"`
+	ErrorMode    string   `json:"errorMode,omitempty"`
 }
 
 // ReadArgs loads a flux.Arguments into ToMQTTOpSpec.  It sets several default values.
@@ -112,7 +113,10 @@ func (o *ToMQTTOpSpec) ReadArgs(args flux.Arguments) error {
 		sort.Strings(o.ValueColumns)
 	}
 
-	return nil
+	if o.ErrorMode, _, err = args.GetString("errorMode"); err != nil {
+		return err
+	}
+	return deadletter.ValidateMode(o.ErrorMode)
 }
 
 func createToMQTTOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
@@ -173,6 +177,7 @@ func (o *ToMQTTProcedureSpec) Copy() plan.ProcedureSpec {
 			TimeColumn:   s.TimeColumn,
 			TagColumns:   append([]string(nil), s.TagColumns...),
 			ValueColumns: append([]string(nil), s.ValueColumns...),
+			ErrorMode:    s.ErrorMode,
 		},
 	}
 	return res
@@ -252,6 +257,52 @@ type idxType struct {
 }
 
 func (t *ToMQTTTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	if t.spec.Spec.ErrorMode != deadletter.ModeDeadLetter {
+		builder, isNew := t.cache.TableBuilder(tbl.Key())
+		if isNew {
+			if err := execute.AddTableCols(tbl, builder); err != nil {
+				return err
+			}
+		}
+		return t.writeTable(tbl, builder, nil)
+	}
+
+	buf, err := execute.CopyTable(tbl)
+	if err != nil {
+		return err
+	}
+	defer buf.Done()
+
+	var rows deadletter.Rows
+	if err := t.writeTable(buf.Copy(), nil, &rows); err != nil {
+		rows.AddAll(err)
+	}
+	if rows.Empty() {
+		return nil
+	}
+	builder, isNew := t.cache.TableBuilder(buf.Key())
+	if isNew {
+		for _, col := range deadletter.Cols(buf.Cols()) {
+			if _, err := builder.AddCol(col); err != nil {
+				return err
+			}
+		}
+	}
+	offset := 0
+	return buf.Copy().Do(func(cr flux.ColReader) error {
+		if err := rows.AppendTo(cr, offset, builder); err != nil {
+			return err
+		}
+		offset += cr.Len()
+		return nil
+	})
+}
+
+// writeTable publishes the rows of the table encoded as line protocol
+// in a single message. The rows are appended to the builder if it is
+// not nil. If rows is not nil, the rows that cannot be encoded or
+// published are recorded in it instead of returning an error.
+func (t *ToMQTTTransformation) writeTable(tbl flux.Table, builder execute.TableBuilder, rows *deadletter.Rows) error {
 	cols := tbl.Cols()
 	labels := make(map[string]idxType, len(cols))
 	for i, col := range cols {
@@ -284,80 +335,82 @@ func (t *ToMQTTTransformation) Process(id execute.DatasetID, tbl flux.Table) err
 		isTag[i] = tagIdx < len(t.spec.Spec.TagColumns) && t.spec.Spec.TagColumns[tagIdx] == col.Label
 	}
 
-	builder, isNew := t.cache.TableBuilder(tbl.Key())
-	if isNew {
-		if err := execute.AddTableCols(tbl, builder); err != nil {
-			return err
-		}
-	}
-
 	m := &toMqttMetric{
 		name: t.spec.Spec.Name,
 	}
 	sb := strings.Builder{}
-	e := protocol.NewEncoder(&sb)
+	line := strings.Builder{}
+	e := protocol.NewEncoder(&line)
 	e.FailOnFieldErr(true)
 	e.SetFieldSortOrder(protocol.SortFields)
 
-	// Is there a reason the actual processing needs to run in a subroutine?
-	// The whole table content is sent afterwards in a single MQTT message.
-	// Unlike kafka.to(), with very similar code but different in this regard.
-
-	var wg syncutil.WaitGroup
-	wg.Do(func() error {
-		err := tbl.Do(func(er flux.ColReader) error {
-			l := er.Len()
-			for i := 0; i < l; i++ {
-				m.truncateTagsAndFields()
-				for j, col := range er.Cols() {
-					switch {
-					case col.Label == timeColLabel:
-						m.t = values.Time(er.Times(j).Value(i)).Time()
-					case measurementNameCol != "" && measurementNameCol == col.Label:
-						if col.Type != flux.TString {
-							return errors.Newf(codes.FailedPrecondition, "invalid type for measurement column: %s", col.Type)
-						}
-						m.name = er.Strings(j).Value(i)
-					case isTag[j]:
-						if col.Type != flux.TString {
-							return errors.Newf(codes.FailedPrecondition, "invalid type for tag column: %s", col.Type)
-						}
-						m.tags = append(m.tags, &protocol.Tag{Key: col.Label, Value: er.Strings(j).Value(i)})
-
-					case isValue[j]:
-						switch col.Type {
-						case flux.TFloat:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Floats(j).Value(i)})
-						case flux.TInt:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Ints(j).Value(i)})
-						case flux.TUInt:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.UInts(j).Value(i)})
-						case flux.TString:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Strings(j).Value(i)})
-						case flux.TTime:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: values.Time(er.Times(j).Value(i))})
-						case flux.TBool:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Bools(j).Value(i)})
-						default:
-							return errors.Newf(codes.FailedPrecondition, "unsupported type %s for column %s",
-								col.Type, col.Label)
-						}
-					}
+	// encode writes row i of the column reader to line.
+	encode := func(er flux.ColReader, i int) error {
+		m.truncateTagsAndFields()
+		for j, col := range er.Cols() {
+			switch {
+			case col.Label == timeColLabel:
+				m.t = values.Time(er.Times(j).Value(i)).Time()
+			case measurementNameCol != "" && measurementNameCol == col.Label:
+				if col.Type != flux.TString {
+					return errors.Newf(codes.FailedPrecondition, "invalid type for measurement column: %s", col.Type)
 				}
-				_, err := e.Encode(m)
-				if err != nil {
+				m.name = er.Strings(j).Value(i)
+			case isTag[j]:
+				if col.Type != flux.TString {
+					return errors.Newf(codes.FailedPrecondition, "invalid type for tag column: %s", col.Type)
+				}
+				m.tags = append(m.tags, &protocol.Tag{Key: col.Label, Value: er.Strings(j).Value(i)})
+
+			case isValue[j]:
+				switch col.Type {
+				case flux.TFloat:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Floats(j).Value(i)})
+				case flux.TInt:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Ints(j).Value(i)})
+				case flux.TUInt:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.UInts(j).Value(i)})
+				case flux.TString:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Strings(j).Value(i)})
+				case flux.TTime:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: values.Time(er.Times(j).Value(i))})
+				case flux.TBool:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Bools(j).Value(i)})
+				default:
+					return errors.Newf(codes.FailedPrecondition, "unsupported type %s for column %s",
+						col.Type, col.Label)
+				}
+			}
+		}
+		line.Reset()
+		_, err := e.Encode(m)
+		return err
+	}
+
+	// encoded are the rows of the table in the message.
+	var encoded []int
+	offset := 0
+	if err := tbl.Do(func(er flux.ColReader) error {
+		l := er.Len()
+		for i := 0; i < l; i++ {
+			if err := encode(er, i); err != nil {
+				if rows == nil {
 					return err
 				}
+				rows.Add(offset+i, err)
+				continue
+			}
+			sb.WriteString(line.String())
+			encoded = append(encoded, offset+i)
+			if builder != nil {
 				if err := execute.AppendRecord(i, er, builder); err != nil {
 					return err
 				}
 			}
-			return nil
-		})
-		return err
-	})
-
-	if err := wg.Wait(); err != nil {
+		}
+		offset += l
+		return nil
+	}); err != nil {
 		return err
 	}
 
@@ -368,7 +421,14 @@ func (t *ToMQTTTransformation) Process(id execute.DatasetID, tbl flux.Table) err
 			topic = m.createTopic(message)
 		}
 		spec := &t.spec.Spec.CommonMQTTOpSpec
-		publish(t.ctx, topic, message, spec)
+		if _, err := publish(t.ctx, topic, message, spec); err != nil {
+			if rows == nil {
+				return err
+			}
+			for _, row := range encoded {
+				rows.Add(row, err)
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,140 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+
+	stdparquet "github.com/apache/arrow/go/v7/parquet"
+	"github.com/apache/arrow/go/v7/parquet/file"
+	"github.com/apache/arrow/go/v7/parquet/pqarrow"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/arrowio"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+)
+
+const (
+	pkgpath  = "experimental/parquet"
+	FromKind = pkgpath + ".from"
+)
+
+type FromOpSpec struct {
+	File string
+}
+
+func init() {
+	fromSignature := runtime.MustLookupBuiltinType(pkgpath, "from")
+	runtime.RegisterPackageValue(pkgpath, "from", flux.MustValue(flux.FunctionValue(FromKind, createFromOpSpec, fromSignature)))
+	plan.RegisterProcedureSpec(FromKind, newFromProcedure, FromKind)
+	execute.RegisterSource(FromKind, createFromSource)
+}
+
+func createFromOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(FromOpSpec)
+	if f, err := args.GetRequiredString("file"); err != nil {
+		return nil, err
+	} else {
+		spec.File = f
+	}
+	return spec, nil
+}
+
+func (s *FromOpSpec) Kind() flux.OperationKind {
+	return FromKind
+}
+
+type FromProcedureSpec struct {
+	plan.DefaultCost
+	File string
+}
+
+func newFromProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &FromProcedureSpec{File: spec.File}, nil
+}
+
+func (s *FromProcedureSpec) Kind() plan.ProcedureKind {
+	return FromKind
+}
+
+func (s *FromProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func createFromSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := ps.(*FromProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", ps)
+	}
+	return execute.CreateSourceFromIterator(&fileSource{
+		file: spec.File,
+		mem:  a.Allocator(),
+	}, id)
+}
+
+type fileSource struct {
+	file string
+	mem  memory.Allocator
+}
+
+func (s *fileSource) Do(ctx context.Context, f func(flux.Table) error) error {
+	r, err := openFile(ctx, s.file)
+	if err != nil {
+		return errors.Wrap(err, codes.Inherit, "parquet.from() failed to read file")
+	}
+
+	pr, err := file.NewParquetReader(r)
+	if err != nil {
+		if c, ok := r.(io.Closer); ok {
+			_ = c.Close()
+		}
+		return errors.Wrap(err, codes.Invalid, "parquet.from() failed to read file")
+	}
+	defer func() { _ = pr.Close() }()
+
+	fr, err := pqarrow.NewFileReader(pr, pqarrow.ArrowReadProperties{
+		BatchSize: table.BufferSize,
+	}, s.mem)
+	if err != nil {
+		return errors.Wrap(err, codes.Invalid, "parquet.from() failed to read file")
+	}
+
+	rr, err := fr.GetRecordReader(ctx, nil, nil)
+	if err != nil {
+		return errors.Wrap(err, codes.Invalid, "parquet.from() failed to read file")
+	}
+	defer rr.Release()
+	return arrowio.ReadTables(rr, s.mem, f)
+}
+
+// openFile opens the file for random access. Parquet stores its
+// metadata at the end of the file so a file that cannot seek
+// is read into memory.
+func openFile(ctx context.Context, name string) (stdparquet.ReaderAtSeeker, error) {
+	f, err := filesystem.OpenFile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := f.(stdparquet.ReaderAtSeeker); ok {
+		return r, nil
+	}
+	defer func() { _ = f.Close() }()
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
@@ -0,0 +1,101 @@
+package parquet_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/dependency"
+	"github.com/influxdata/flux/execute/executetest"
+	_ "github.com/influxdata/flux/fluxinit/static"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/runtime"
+)
+
+func runQuery(t *testing.T, query string) []*executetest.Table {
+	t.Helper()
+
+	ctx, deps := dependency.Inject(context.Background(), dependenciestest.Default())
+	defer deps.Finish()
+
+	c := &lang.FluxCompiler{Query: query}
+	program, err := c.Compile(ctx, runtime.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := program.Start(ctx, &memory.ResourceAllocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Done()
+
+	var tables []*executetest.Table
+	for res := range q.Results() {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			et, err := executetest.ConvertTable(tbl)
+			if err != nil {
+				return err
+			}
+			tables = append(tables, et)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Done()
+
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+	executetest.NormalizeTables(tables)
+	return tables
+}
+
+func TestParquet_RoundTrip(t *testing.T) {
+	for _, compression := range []string{"uncompressed", "snappy", "gzip", "brotli", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "data.parquet")
+			want := runQuery(t, fmt.Sprintf(`
+import "array"
+import "experimental/parquet"
+
+array.from(rows: [
+	{_time: 2021-01-01T00:00:00Z, host: "a", _value: 1.5, n: 1, ok: true},
+	{_time: 2021-01-01T00:00:10Z, host: "a", _value: 2.5, n: 2, ok: false},
+	{_time: 2021-01-01T00:00:00Z, host: "b", _value: 3.5, n: 3, ok: true},
+])
+	|> group(columns: ["host"])
+	|> parquet.to(file: %q, compression: %q)
+`, path, compression))
+
+			got := runQuery(t, fmt.Sprintf(`
+import "experimental/parquet"
+
+parquet.from(file: %q)
+`, path))
+
+			if !cmp.Equal(want, got) {
+				t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestParquet_InvalidCompression(t *testing.T) {
+	_, _, err := runtime.Eval(context.Background(), `
+import "experimental/parquet"
+import "array"
+
+array.from(rows: [{_value: 1}])
+	|> parquet.to(file: "data.parquet", compression: "lzo")
+`)
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+}
@@ -0,0 +1,122 @@
+package parquet
+
+import (
+	stdarrow "github.com/apache/arrow/go/v7/arrow"
+	stdparquet "github.com/apache/arrow/go/v7/parquet"
+	"github.com/apache/arrow/go/v7/parquet/compress"
+	"github.com/apache/arrow/go/v7/parquet/pqarrow"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/arrowio"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+)
+
+const ToKind = pkgpath + ".to"
+
+var compressionCodecs = map[string]compress.Compression{
+	"uncompressed": compress.Codecs.Uncompressed,
+	"snappy":       compress.Codecs.Snappy,
+	"gzip":         compress.Codecs.Gzip,
+	"brotli":       compress.Codecs.Brotli,
+	"zstd":         compress.Codecs.Zstd,
+}
+
+type ToOpSpec struct {
+	File        string
+	Compression string
+}
+
+func init() {
+	toSignature := runtime.MustLookupBuiltinType(pkgpath, "to")
+	runtime.RegisterPackageValue(pkgpath, "to", flux.MustValue(flux.FunctionValueWithSideEffect(ToKind, createToOpSpec, toSignature)))
+	plan.RegisterProcedureSpecWithSideEffect(ToKind, newToProcedure, ToKind)
+	execute.RegisterTransformation(ToKind, createToTransformation)
+}
+
+func createToOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(ToOpSpec)
+	if f, err := args.GetRequiredString("file"); err != nil {
+		return nil, err
+	} else {
+		spec.File = f
+	}
+
+	if c, ok, err := args.GetString("compression"); err != nil {
+		return nil, err
+	} else if ok {
+		if _, ok := compressionCodecs[c]; !ok {
+			return nil, errors.Newf(codes.Invalid, "unsupported compression codec %q", c)
+		}
+		spec.Compression = c
+	} else {
+		spec.Compression = "snappy"
+	}
+	return spec, nil
+}
+
+func (s *ToOpSpec) Kind() flux.OperationKind {
+	return ToKind
+}
+
+type ToProcedureSpec struct {
+	plan.DefaultCost
+	File        string
+	Compression string
+}
+
+func newToProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*ToOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &ToProcedureSpec{
+		File:        spec.File,
+		Compression: spec.Compression,
+	}, nil
+}
+
+func (s *ToProcedureSpec) Kind() plan.ProcedureKind {
+	return ToKind
+}
+
+func (s *ToProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func createToTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*ToProcedureSpec)
+	if !ok {
+		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
+	}
+
+	ctx := a.Context()
+	create := func(schema *stdarrow.Schema) (arrowio.Writer, error) {
+		f, err := filesystem.CreateAtomicFile(ctx, s.File)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Inherit, "parquet.to() failed to create file")
+		}
+
+		props := stdparquet.NewWriterProperties(
+			stdparquet.WithCompression(compressionCodecs[s.Compression]),
+			stdparquet.WithAllocator(a.Allocator()),
+		)
+		// Store the arrow schema in the file so the group key
+		// metadata is available when the file is read again.
+		arrProps := pqarrow.NewArrowWriterProperties(
+			pqarrow.WithStoreSchema(),
+			pqarrow.WithAllocator(a.Allocator()),
+		)
+		w, err := pqarrow.NewFileWriter(schema, f, props, arrProps)
+		if err != nil {
+			_ = f.Abort()
+			return nil, err
+		}
+		return arrowio.NewFileWriter(w, f), nil
+	}
+	return arrowio.NewWriteTransformation(ctx, id, create, a.Allocator())
+}
@@ -0,0 +1,358 @@
+package prometheus
+
+import (
+	"math"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The messages of the Prometheus remote read and write protocol.
+// They are encoded by hand so that the Prometheus server does not
+// need to be a dependency. The field numbers are the ones used by
+// the prompb package of Prometheus.
+
+type label struct {
+	Name  string
+	Value string
+}
+
+type sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+type timeSeries struct {
+	Labels  []label
+	Samples []sample
+}
+
+type writeRequest struct {
+	Timeseries []timeSeries
+}
+
+type matchType int32
+
+const (
+	matchEqual matchType = iota
+	matchNotEqual
+	matchRegexp
+	matchNotRegexp
+)
+
+type labelMatcher struct {
+	Type  matchType
+	Name  string
+	Value string
+}
+
+type query struct {
+	StartTimestampMs int64
+	EndTimestampMs   int64
+	Matchers         []labelMatcher
+}
+
+// readRequest is a remote read request. It does not set the accepted
+// response types so the server responds with samples.
+type readRequest struct {
+	Queries []query
+}
+
+type queryResult struct {
+	Timeseries []timeSeries
+}
+
+type readResponse struct {
+	Results []queryResult
+}
+
+func appendMessage(b []byte, num protowire.Number, m []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, m)
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendInt(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+func (l label) marshal() []byte {
+	b := appendString(nil, 1, l.Name)
+	return appendString(b, 2, l.Value)
+}
+
+func (s sample) marshal() []byte {
+	var b []byte
+	if s.Value != 0 || math.Signbit(s.Value) {
+		b = protowire.AppendTag(b, 1, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(s.Value))
+	}
+	return appendInt(b, 2, s.Timestamp)
+}
+
+func (ts timeSeries) marshal() []byte {
+	var b []byte
+	for _, l := range ts.Labels {
+		b = appendMessage(b, 1, l.marshal())
+	}
+	for _, s := range ts.Samples {
+		b = appendMessage(b, 2, s.marshal())
+	}
+	return b
+}
+
+func (r writeRequest) marshal() []byte {
+	var b []byte
+	for _, ts := range r.Timeseries {
+		b = appendMessage(b, 1, ts.marshal())
+	}
+	return b
+}
+
+func (m labelMatcher) marshal() []byte {
+	b := appendInt(nil, 1, int64(m.Type))
+	b = appendString(b, 2, m.Name)
+	return appendString(b, 3, m.Value)
+}
+
+func (q query) marshal() []byte {
+	b := appendInt(nil, 1, q.StartTimestampMs)
+	b = appendInt(b, 2, q.EndTimestampMs)
+	for _, m := range q.Matchers {
+		b = appendMessage(b, 3, m.marshal())
+	}
+	return b
+}
+
+func (r readRequest) marshal() []byte {
+	var b []byte
+	for _, q := range r.Queries {
+		b = appendMessage(b, 1, q.marshal())
+	}
+	return b
+}
+
+func (r queryResult) marshal() []byte {
+	var b []byte
+	for _, ts := range r.Timeseries {
+		b = appendMessage(b, 1, ts.marshal())
+	}
+	return b
+}
+
+func (r readResponse) marshal() []byte {
+	var b []byte
+	for _, res := range r.Results {
+		b = appendMessage(b, 1, res.marshal())
+	}
+	return b
+}
+
+// unmarshalMessage calls fn with the value of each field in b.
+// The fields that fn does not consume are skipped.
+func unmarshalMessage(b []byte, fn func(num protowire.Number, typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protoError(n)
+		}
+		b = b[n:]
+
+		n, err := fn(num, typ, b)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protoError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// consumeMessage consumes an embedded message and unmarshals it with fn.
+func consumeMessage(b []byte, fn func(b []byte) error) (int, error) {
+	v, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return 0, protoError(n)
+	}
+	return n, fn(v)
+}
+
+func protoError(n int) error {
+	return errors.Wrap(protowire.ParseError(n), codes.Invalid, "failed to decode protobuf message")
+}
+
+func (l *label) unmarshal(b []byte) error {
+	return unmarshalMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if typ != protowire.BytesType {
+			return 0, nil
+		}
+		var n int
+		switch num {
+		case 1:
+			l.Name, n = protowire.ConsumeString(b)
+		case 2:
+			l.Value, n = protowire.ConsumeString(b)
+		}
+		return n, nil
+	})
+}
+
+func (s *sample) unmarshal(b []byte) error {
+	return unmarshalMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == 1 && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			s.Value = math.Float64frombits(v)
+			return n, nil
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			s.Timestamp = int64(v)
+			return n, nil
+		}
+		return 0, nil
+	})
+}
+
+func (ts *timeSeries) unmarshal(b []byte) error {
+	return unmarshalMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if typ != protowire.BytesType {
+			return 0, nil
+		}
+		switch num {
+		case 1:
+			return consumeMessage(b, func(b []byte) error {
+				var l label
+				if err := l.unmarshal(b); err != nil {
+					return err
+				}
+				ts.Labels = append(ts.Labels, l)
+				return nil
+			})
+		case 2:
+			return consumeMessage(b, func(b []byte) error {
+				var s sample
+				if err := s.unmarshal(b); err != nil {
+					return err
+				}
+				ts.Samples = append(ts.Samples, s)
+				return nil
+			})
+		}
+		return 0, nil
+	})
+}
+
+// unmarshalTimeSeries unmarshals the repeated time series
+// with the field number num of a message.
+func unmarshalTimeSeries(b []byte, num protowire.Number) ([]timeSeries, error) {
+	var series []timeSeries
+	err := unmarshalMessage(b, func(n protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if n != num || typ != protowire.BytesType {
+			return 0, nil
+		}
+		return consumeMessage(b, func(b []byte) error {
+			var ts timeSeries
+			if err := ts.unmarshal(b); err != nil {
+				return err
+			}
+			series = append(series, ts)
+			return nil
+		})
+	})
+	return series, err
+}
+
+func (r *writeRequest) unmarshal(b []byte) (err error) {
+	r.Timeseries, err = unmarshalTimeSeries(b, 1)
+	return err
+}
+
+func (m *labelMatcher) unmarshal(b []byte) error {
+	return unmarshalMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		var n int
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(b)
+			m.Type = matchType(v)
+		case num == 2 && typ == protowire.BytesType:
+			m.Name, n = protowire.ConsumeString(b)
+		case num == 3 && typ == protowire.BytesType:
+			m.Value, n = protowire.ConsumeString(b)
+		}
+		return n, nil
+	})
+}
+
+func (q *query) unmarshal(b []byte) error {
+	return unmarshalMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			q.StartTimestampMs = int64(v)
+			return n, nil
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			q.EndTimestampMs = int64(v)
+			return n, nil
+		case num == 3 && typ == protowire.BytesType:
+			return consumeMessage(b, func(b []byte) error {
+				var m labelMatcher
+				if err := m.unmarshal(b); err != nil {
+					return err
+				}
+				q.Matchers = append(q.Matchers, m)
+				return nil
+			})
+		}
+		return 0, nil
+	})
+}
+
+func (r *readRequest) unmarshal(b []byte) error {
+	return unmarshalMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if num != 1 || typ != protowire.BytesType {
+			return 0, nil
+		}
+		return consumeMessage(b, func(b []byte) error {
+			var q query
+			if err := q.unmarshal(b); err != nil {
+				return err
+			}
+			r.Queries = append(r.Queries, q)
+			return nil
+		})
+	})
+}
+
+func (r *readResponse) unmarshal(b []byte) error {
+	return unmarshalMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if num != 1 || typ != protowire.BytesType {
+			return 0, nil
+		}
+		return consumeMessage(b, func(b []byte) error {
+			series, err := unmarshalTimeSeries(b, 1)
+			if err != nil {
+				return err
+			}
+			r.Results = append(r.Results, queryResult{Timeseries: series})
+			return nil
+		})
+	})
+}
@@ -0,0 +1,86 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+const remoteProtocolVersion = "0.1.0"
+
+// readHeaders reads the optional headers record of a remote function.
+func readHeaders(args flux.Arguments) (map[string]string, error) {
+	obj, ok, err := args.GetObject("headers")
+	if err != nil || !ok {
+		return nil, err
+	}
+	headers := make(map[string]string, obj.Len())
+	obj.Range(func(k string, v values.Value) {
+		if err != nil {
+			return
+		}
+		if v.Type().Nature() != semantic.String {
+			err = errors.Newf(codes.Invalid, "header value %q must be a string", k)
+			return
+		}
+		headers[k] = v.Str()
+	})
+	return headers, err
+}
+
+// postSnappy sends the snappy compressed protobuf message to the url with
+// the user headers and the headers of the protocol and returns the
+// decompressed body of the response.
+func postSnappy(ctx context.Context, url string, headers map[string]string, protocol http.Header, msg []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(snappy.Encode(nil, msg)))
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid prometheus remote request")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for k := range protocol {
+		req.Header.Set(k, protocol.Get(k))
+	}
+
+	client, err := flux.GetDependencies(ctx).HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Unavailable, "failed to read prometheus remote response")
+	}
+	if resp.StatusCode/100 != 2 {
+		code := codes.Invalid
+		if resp.StatusCode >= 500 {
+			code = codes.Unavailable
+		}
+		return nil, errors.Newf(code, "prometheus remote request failed with status %q: %s",
+			resp.Status, strings.TrimSpace(string(body)))
+	}
+	if resp.Header.Get("Content-Encoding") != "snappy" {
+		return body, nil
+	}
+	if body, err = snappy.Decode(nil, body); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to decompress prometheus remote response")
+	}
+	return body, nil
+}
@@ -0,0 +1,330 @@
+package prometheus
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/mock"
+)
+
+// remoteContext returns a context with dependencies
+// that can send requests to a local test server.
+func remoteContext() context.Context {
+	deps := flux.NewDefaultDependencies()
+	deps.Deps.HTTPClient = http.DefaultClient
+	return deps.Inject(context.Background())
+}
+
+// readSnappy reads and decompresses the body of a remote request.
+func readSnappy(t *testing.T, r *http.Request) []byte {
+	t.Helper()
+	if want, got := "snappy", r.Header.Get("Content-Encoding"); want != got {
+		t.Errorf("unexpected content encoding: want %q, got %q", want, got)
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, err = snappy.Decode(nil, body); err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestRemoteWrite(t *testing.T) {
+	input := func() *executetest.Table {
+		return &executetest.Table{
+			KeyCols: []string{"_field", "_measurement", "host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TInt},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+				{Label: "region", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(2e6), int64(2), "requests", "http", "a", "east"},
+				{execute.Time(1e6), int64(1), "requests", "http", "a", "east"},
+				{execute.Time(3e6), nil, "requests", "http", "a", "east"},
+				{execute.Time(3e6), int64(3), "requests", "http", "a", "west"},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		spec    *RemoteWriteOpSpec
+		want    writeRequest
+		wantErr error
+	}{
+		{
+			name: "group key labels",
+			spec: &RemoteWriteOpSpec{NameColumn: "_field"},
+			want: writeRequest{
+				Timeseries: []timeSeries{{
+					Labels: []label{
+						{Name: "__name__", Value: "requests"},
+						{Name: "host", Value: "a"},
+					},
+					Samples: []sample{
+						{Value: 1, Timestamp: 1},
+						{Value: 2, Timestamp: 2},
+						{Value: 3, Timestamp: 3},
+					},
+				}},
+			},
+		},
+		{
+			name: "label columns",
+			spec: &RemoteWriteOpSpec{
+				NameColumn:   "_measurement",
+				LabelColumns: []string{"region"},
+			},
+			want: writeRequest{
+				Timeseries: []timeSeries{
+					{
+						Labels: []label{
+							{Name: "__name__", Value: "http"},
+							{Name: "region", Value: "east"},
+						},
+						Samples: []sample{
+							{Value: 1, Timestamp: 1},
+							{Value: 2, Timestamp: 2},
+						},
+					},
+					{
+						Labels: []label{
+							{Name: "__name__", Value: "http"},
+							{Name: "region", Value: "west"},
+						},
+						Samples: []sample{
+							{Value: 3, Timestamp: 3},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "missing label column",
+			spec:    &RemoteWriteOpSpec{NameColumn: "_field", LabelColumns: []string{"zone"}},
+			wantErr: errors.New(codes.Invalid, `label column "zone" does not exist`),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got writeRequest
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if want, got := "0.1.0", r.Header.Get("X-Prometheus-Remote-Write-Version"); want != got {
+					t.Errorf("unexpected version: want %q, got %q", want, got)
+				}
+				if want, got := "secret", r.Header.Get("Authorization"); want != got {
+					t.Errorf("unexpected authorization: want %q, got %q", want, got)
+				}
+				if err := got.unmarshal(readSnappy(t, r)); err != nil {
+					t.Error(err)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer ts.Close()
+
+			spec := *tc.spec
+			spec.URL = ts.URL
+			spec.Headers = map[string]string{"Authorization": "secret"}
+			executetest.ProcessTestHelper2(t,
+				[]flux.Table{input()},
+				[]*executetest.Table{input()},
+				tc.wantErr,
+				func(id execute.DatasetID, alloc memory.Allocator) (execute.Transformation, execute.Dataset) {
+					tr, d, err := NewRemoteWriteTransformation(remoteContext(), id, &RemoteWriteProcedureSpec{Spec: &spec}, alloc)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return tr, d
+				},
+			)
+			if tc.wantErr != nil {
+				return
+			}
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected write request -want/+got\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestRemoteWrite_ServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	input := &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TFloat},
+			{Label: "_field", Type: flux.TString},
+		},
+		Data: [][]interface{}{
+			{execute.Time(1e6), 1.0, "up"},
+		},
+	}
+	spec := &RemoteWriteOpSpec{URL: ts.URL, NameColumn: "_field"}
+	executetest.ProcessTestHelper2(t,
+		[]flux.Table{input},
+		nil,
+		errors.New(codes.Invalid, `prometheus remote request failed with status "400 Bad Request": out of order sample`),
+		func(id execute.DatasetID, alloc memory.Allocator) (execute.Transformation, execute.Dataset) {
+			tr, d, err := NewRemoteWriteTransformation(remoteContext(), id, &RemoteWriteProcedureSpec{Spec: spec}, alloc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return tr, d
+		},
+	)
+}
+
+func TestRemoteRead(t *testing.T) {
+	resp := readResponse{
+		Results: []queryResult{{
+			Timeseries: []timeSeries{
+				{
+					Labels: []label{
+						{Name: "__name__", Value: "up"},
+						{Name: "job", Value: "api"},
+					},
+					Samples: []sample{
+						{Value: 1, Timestamp: 10},
+						{Value: 0, Timestamp: 20},
+						// Samples outside of the bounds are dropped.
+						{Value: 1, Timestamp: 30},
+					},
+				},
+				{
+					Labels: []label{
+						{Name: "__name__", Value: "up"},
+						{Name: "job", Value: "api-canary"},
+					},
+					Samples: []sample{
+						{Value: 1, Timestamp: 15},
+					},
+				},
+			},
+		}},
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if want, got := "0.1.0", r.Header.Get("X-Prometheus-Remote-Read-Version"); want != got {
+			t.Errorf("unexpected version: want %q, got %q", want, got)
+		}
+		var req readRequest
+		if err := req.unmarshal(readSnappy(t, r)); err != nil {
+			t.Error(err)
+		}
+		want := readRequest{
+			Queries: []query{{
+				StartTimestampMs: 10,
+				EndTimestampMs:   29,
+				Matchers: []labelMatcher{
+					{Type: matchEqual, Name: "__name__", Value: "up"},
+					{Type: matchRegexp, Name: "job", Value: "api.*"},
+				},
+			}},
+		}
+		if !cmp.Equal(want, req) {
+			t.Errorf("unexpected read request -want/+got\n%s", cmp.Diff(want, req))
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.Header().Set("Content-Encoding", "snappy")
+		_, _ = w.Write(snappy.Encode(nil, resp.marshal()))
+	}))
+	defer ts.Close()
+
+	table := func(job string, rows ...[]interface{}) *executetest.Table {
+		data := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			data[i] = append([]interface{}{execute.Time(10e6), execute.Time(30e6), "up", "prometheus", job}, row...)
+		}
+		return &executetest.Table{
+			KeyCols: []string{"_start", "_stop", "_field", "_measurement", "job"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_start", Type: flux.TTime},
+				{Label: "_stop", Type: flux.TTime},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "job", Type: flux.TString},
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: data,
+		}
+	}
+	want := []*executetest.Table{
+		table("api",
+			[]interface{}{execute.Time(10e6), 1.0},
+			[]interface{}{execute.Time(20e6), 0.0},
+		),
+		table("api-canary",
+			[]interface{}{execute.Time(15e6), 1.0},
+		),
+	}
+
+	spec := &RemoteReadOpSpec{
+		URL:      ts.URL,
+		Matchers: []string{`__name__="up"`, "job=~`api.*`"},
+		Start:    flux.Time{Absolute: time.Unix(0, 10e6)},
+		Stop:     flux.Time{Absolute: time.Unix(0, 30e6)},
+	}
+	executetest.RunSourceHelper(t, want, nil, func(id execute.DatasetID) execute.Source {
+		a := mock.AdministrationWithContext(remoteContext())
+		s, err := CreateRemoteReadSource(&RemoteReadProcedureSpec{Spec: spec}, id, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	})
+}
+
+func TestParseMatchers(t *testing.T) {
+	testCases := []struct {
+		matcher string
+		want    labelMatcher
+		wantErr bool
+	}{
+		{matcher: `job="api"`, want: labelMatcher{Type: matchEqual, Name: "job", Value: "api"}},
+		{matcher: `job != "api"`, want: labelMatcher{Type: matchNotEqual, Name: "job", Value: "api"}},
+		{matcher: `path=~"/api/\"v1\".*"`, want: labelMatcher{Type: matchRegexp, Name: "path", Value: `/api/"v1".*`}},
+		{matcher: "path!~`/api/\\d+`", want: labelMatcher{Type: matchNotRegexp, Name: "path", Value: `/api/\d+`}},
+		{matcher: `job`, wantErr: true},
+		{matcher: `job=api`, wantErr: true},
+		{matcher: `job=~"("`, wantErr: true},
+	}
+	for _, tc := range testCases {
+		got, err := parseMatchers([]string{tc.matcher})
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("expected error for matcher %q", tc.matcher)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("unexpected error for matcher %q: %s", tc.matcher, err)
+			continue
+		}
+		if !cmp.Equal(tc.want, got[0]) {
+			t.Errorf("unexpected matcher for %q -want/+got\n%s", tc.matcher, cmp.Diff(tc.want, got[0]))
+		}
+	}
+}
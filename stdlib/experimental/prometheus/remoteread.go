@@ -0,0 +1,296 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+const RemoteReadKind = "prometheusRemoteRead"
+
+func init() {
+	remoteReadSignature := runtime.MustLookupBuiltinType("experimental/prometheus", "remoteRead")
+	runtime.RegisterPackageValue("experimental/prometheus", "remoteRead", flux.MustValue(flux.FunctionValue(RemoteReadKind, createRemoteReadOpSpec, remoteReadSignature)))
+	flux.RegisterOpSpec(RemoteReadKind, func() flux.OperationSpec { return &RemoteReadOpSpec{} })
+	plan.RegisterProcedureSpec(RemoteReadKind, newRemoteReadProcedure, RemoteReadKind)
+	execute.RegisterSource(RemoteReadKind, createRemoteReadSource)
+}
+
+// RemoteReadOpSpec reads the samples of the series that match all of
+// the matchers from an endpoint of the Prometheus remote read protocol.
+type RemoteReadOpSpec struct {
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Matchers []string          `json:"matchers"`
+	Start    flux.Time         `json:"start"`
+	Stop     flux.Time         `json:"stop"`
+}
+
+// ReadArgs loads a flux.Arguments into RemoteReadOpSpec.
+// The stop time defaults to now.
+func (o *RemoteReadOpSpec) ReadArgs(args flux.Arguments) error {
+	var err error
+	var ok bool
+
+	if o.URL, err = args.GetRequiredString("url"); err != nil {
+		return err
+	}
+	if o.Headers, err = readHeaders(args); err != nil {
+		return err
+	}
+
+	matchers, err := args.GetRequiredArray("matchers", semantic.String)
+	if err != nil {
+		return err
+	}
+	o.Matchers = make([]string, matchers.Len())
+	for i := range o.Matchers {
+		o.Matchers[i] = matchers.Get(i).Str()
+	}
+	if _, err := parseMatchers(o.Matchers); err != nil {
+		return err
+	}
+
+	if o.Start, err = args.GetRequiredTime("start"); err != nil {
+		return err
+	}
+	if o.Stop, ok, err = args.GetTime("stop"); err != nil {
+		return err
+	} else if !ok {
+		o.Stop = flux.Now
+	}
+	return nil
+}
+
+func createRemoteReadOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	s := new(RemoteReadOpSpec)
+	if err := s.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (RemoteReadOpSpec) Kind() flux.OperationKind {
+	return RemoteReadKind
+}
+
+type RemoteReadProcedureSpec struct {
+	plan.DefaultCost
+	Spec *RemoteReadOpSpec
+}
+
+func (o *RemoteReadProcedureSpec) Kind() plan.ProcedureKind {
+	return RemoteReadKind
+}
+
+func (o *RemoteReadProcedureSpec) Copy() plan.ProcedureSpec {
+	s := *o.Spec
+	if o.Spec.Headers != nil {
+		s.Headers = make(map[string]string, len(o.Spec.Headers))
+		for k, v := range o.Spec.Headers {
+			s.Headers[k] = v
+		}
+	}
+	s.Matchers = append([]string(nil), o.Spec.Matchers...)
+	return &RemoteReadProcedureSpec{Spec: &s}
+}
+
+func newRemoteReadProcedure(qs flux.OperationSpec, a plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*RemoteReadOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &RemoteReadProcedureSpec{Spec: spec}, nil
+}
+
+func createRemoteReadSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := ps.(*RemoteReadProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", ps)
+	}
+	return CreateRemoteReadSource(spec, id, a)
+}
+
+// CreateRemoteReadSource creates a source that reads the series
+// from a Prometheus remote read endpoint.
+func CreateRemoteReadSource(spec *RemoteReadProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	matchers, err := parseMatchers(spec.Spec.Matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if execute.HaveExecutionDependencies(a.Context()) {
+		if deps := execute.GetExecutionDependencies(a.Context()); deps.Now != nil {
+			now = *deps.Now
+		}
+	}
+	bounds := execute.Bounds{
+		Start: values.ConvertTime(spec.Spec.Start.Time(now)),
+		Stop:  values.ConvertTime(spec.Spec.Stop.Time(now)),
+	}
+	if bounds.IsEmpty() {
+		return nil, errors.New(codes.Invalid, "remoteRead start must be before stop")
+	}
+	return execute.CreateSourceFromIterator(&remoteReadSource{
+		spec:     spec.Spec,
+		matchers: matchers,
+		bounds:   bounds,
+		alloc:    a.Allocator(),
+	}, id)
+}
+
+type remoteReadSource struct {
+	spec     *RemoteReadOpSpec
+	matchers []labelMatcher
+	bounds   execute.Bounds
+	alloc    memory.Allocator
+}
+
+func (s *remoteReadSource) Do(ctx context.Context, f func(flux.Table) error) error {
+	req := readRequest{
+		Queries: []query{{
+			StartTimestampMs: int64(s.bounds.Start) / 1e6,
+			// The end of a query is inclusive, the stop of the bounds is not.
+			EndTimestampMs: (int64(s.bounds.Stop) - 1) / 1e6,
+			Matchers:       s.matchers,
+		}},
+	}
+	header := http.Header{}
+	header.Set("X-Prometheus-Remote-Read-Version", remoteProtocolVersion)
+	header.Set("Accept-Encoding", "snappy")
+	body, err := postSnappy(ctx, s.spec.URL, s.spec.Headers, header, req.marshal())
+	if err != nil {
+		return err
+	}
+
+	var resp readResponse
+	if err := resp.unmarshal(body); err != nil {
+		return err
+	}
+	for _, res := range resp.Results {
+		for _, ts := range res.Timeseries {
+			tbl, err := s.table(ts)
+			if err != nil {
+				return err
+			}
+			if err := f(tbl); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// table converts a series into a table. The metric name is the _field,
+// the other labels are columns of the group key and the samples that
+// are within the bounds are the rows.
+func (s *remoteReadSource) table(ts timeSeries) (flux.Table, error) {
+	keyCols := []flux.ColMeta{
+		{Label: execute.DefaultStartColLabel, Type: flux.TTime},
+		{Label: execute.DefaultStopColLabel, Type: flux.TTime},
+		{Label: fieldColLabel, Type: flux.TString},
+		{Label: measurementColLabel, Type: flux.TString},
+	}
+	keyValues := []values.Value{
+		values.NewTime(s.bounds.Start),
+		values.NewTime(s.bounds.Stop),
+		values.NewString(""),
+		values.NewString("prometheus"),
+	}
+	for _, l := range ts.Labels {
+		if l.Name == metricNameLabel {
+			keyValues[2] = values.NewString(l.Value)
+			continue
+		}
+		switch l.Name {
+		case execute.DefaultStartColLabel, execute.DefaultStopColLabel, execute.DefaultTimeColLabel,
+			execute.DefaultValueColLabel, fieldColLabel, measurementColLabel:
+			return nil, errors.Newf(codes.Invalid, "label %q conflicts with a column of the same name", l.Name)
+		}
+		keyCols = append(keyCols, flux.ColMeta{Label: l.Name, Type: flux.TString})
+		keyValues = append(keyValues, values.NewString(l.Value))
+	}
+
+	b := execute.NewColListTableBuilder(execute.NewGroupKey(keyCols, keyValues), s.alloc)
+	if err := execute.AddTableKeyCols(b.Key(), b); err != nil {
+		return nil, err
+	}
+	timeIdx, err := b.AddCol(flux.ColMeta{Label: execute.DefaultTimeColLabel, Type: flux.TTime})
+	if err != nil {
+		return nil, err
+	}
+	valueIdx, err := b.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: flux.TFloat})
+	if err != nil {
+		return nil, err
+	}
+	for _, smpl := range ts.Samples {
+		t := values.Time(smpl.Timestamp * 1e6)
+		if !s.bounds.Contains(t) {
+			continue
+		}
+		if err := execute.AppendKeyValues(b.Key(), b); err != nil {
+			b.Release()
+			return nil, err
+		}
+		if err := b.AppendTime(timeIdx, t); err != nil {
+			b.Release()
+			return nil, err
+		}
+		if err := b.AppendFloat(valueIdx, smpl.Value); err != nil {
+			b.Release()
+			return nil, err
+		}
+	}
+	return b.Table()
+}
+
+// matcherPattern matches a label matcher in PromQL syntax
+// such as job="api" or instance=~"host-.*".
+var matcherPattern = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*("(?:[^"\\]|\\.)*"|` + "`[^`]*`" + `)\s*$`)
+
+// parseMatchers parses the label matchers of a remote read.
+func parseMatchers(matchers []string) ([]labelMatcher, error) {
+	ms := make([]labelMatcher, len(matchers))
+	for i, matcher := range matchers {
+		parts := matcherPattern.FindStringSubmatch(matcher)
+		if parts == nil {
+			return nil, errors.Newf(codes.Invalid, "invalid label matcher %q, must be of the form name=\"value\"", matcher)
+		}
+		value, err := strconv.Unquote(parts[3])
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "invalid label matcher %q", matcher)
+		}
+
+		m := labelMatcher{Name: parts[1], Value: value}
+		switch parts[2] {
+		case "=":
+			m.Type = matchEqual
+		case "!=":
+			m.Type = matchNotEqual
+		case "=~":
+			m.Type = matchRegexp
+		case "!~":
+			m.Type = matchNotRegexp
+		}
+		if m.Type == matchRegexp || m.Type == matchNotRegexp {
+			if _, err := regexp.Compile("^(?:" + m.Value + ")$"); err != nil {
+				return nil, errors.Wrapf(err, codes.Invalid, "invalid regular expression in label matcher %q", matcher)
+			}
+		}
+		ms[i] = m
+	}
+	return ms, nil
+}
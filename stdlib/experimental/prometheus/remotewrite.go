@@ -0,0 +1,302 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+)
+
+const (
+	RemoteWriteKind = "prometheusRemoteWrite"
+
+	// metricNameLabel is the label that holds the name of a metric.
+	metricNameLabel = "__name__"
+
+	fieldColLabel       = "_field"
+	measurementColLabel = "_measurement"
+)
+
+func init() {
+	remoteWriteSignature := runtime.MustLookupBuiltinType("experimental/prometheus", "remoteWrite")
+	runtime.RegisterPackageValue("experimental/prometheus", "remoteWrite", flux.MustValue(flux.FunctionValueWithSideEffect(RemoteWriteKind, createRemoteWriteOpSpec, remoteWriteSignature)))
+	flux.RegisterOpSpec(RemoteWriteKind, func() flux.OperationSpec { return &RemoteWriteOpSpec{} })
+	plan.RegisterProcedureSpecWithSideEffect(RemoteWriteKind, newRemoteWriteProcedure, RemoteWriteKind)
+	execute.RegisterTransformation(RemoteWriteKind, createRemoteWriteTransformation)
+}
+
+// RemoteWriteOpSpec writes the rows of its input to an endpoint of
+// the Prometheus remote write protocol.
+type RemoteWriteOpSpec struct {
+	URL          string            `json:"url"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	NameColumn   string            `json:"nameColumn"`
+	LabelColumns []string          `json:"labelColumns,omitempty"`
+}
+
+// ReadArgs loads a flux.Arguments into RemoteWriteOpSpec.
+// The name column defaults to _field.
+func (o *RemoteWriteOpSpec) ReadArgs(args flux.Arguments) error {
+	var err error
+	var ok bool
+
+	if o.URL, err = args.GetRequiredString("url"); err != nil {
+		return err
+	}
+	if o.Headers, err = readHeaders(args); err != nil {
+		return err
+	}
+
+	o.NameColumn, ok, err = args.GetString("nameColumn")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		o.NameColumn = fieldColLabel
+	}
+
+	labelColumns, ok, err := args.GetArrayAllowEmpty("labelColumns", semantic.String)
+	if err != nil {
+		return err
+	}
+	if ok {
+		o.LabelColumns = make([]string, labelColumns.Len())
+		for i := range o.LabelColumns {
+			o.LabelColumns[i] = labelColumns.Get(i).Str()
+		}
+	}
+	return nil
+}
+
+func createRemoteWriteOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+	s := new(RemoteWriteOpSpec)
+	if err := s.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (RemoteWriteOpSpec) Kind() flux.OperationKind {
+	return RemoteWriteKind
+}
+
+type RemoteWriteProcedureSpec struct {
+	plan.DefaultCost
+	Spec *RemoteWriteOpSpec
+}
+
+func (o *RemoteWriteProcedureSpec) Kind() plan.ProcedureKind {
+	return RemoteWriteKind
+}
+
+func (o *RemoteWriteProcedureSpec) Copy() plan.ProcedureSpec {
+	s := *o.Spec
+	if o.Spec.Headers != nil {
+		s.Headers = make(map[string]string, len(o.Spec.Headers))
+		for k, v := range o.Spec.Headers {
+			s.Headers[k] = v
+		}
+	}
+	if o.Spec.LabelColumns != nil {
+		s.LabelColumns = append([]string{}, o.Spec.LabelColumns...)
+	}
+	return &RemoteWriteProcedureSpec{Spec: &s}
+}
+
+func newRemoteWriteProcedure(qs flux.OperationSpec, a plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*RemoteWriteOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &RemoteWriteProcedureSpec{Spec: spec}, nil
+}
+
+func createRemoteWriteTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*RemoteWriteProcedureSpec)
+	if !ok {
+		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
+	}
+	return NewRemoteWriteTransformation(a.Context(), id, s, a.Allocator())
+}
+
+// NewRemoteWriteTransformation creates a transformation that writes each
+// table chunk as a remote write request and passes it on unchanged.
+func NewRemoteWriteTransformation(ctx context.Context, id execute.DatasetID, spec *RemoteWriteProcedureSpec, mem memory.Allocator) (execute.Transformation, execute.Dataset, error) {
+	return execute.NewNarrowTransformation(id, &remoteWriteTransformation{
+		ctx:  ctx,
+		spec: spec.Spec,
+	}, mem)
+}
+
+type remoteWriteTransformation struct {
+	ctx  context.Context
+	spec *RemoteWriteOpSpec
+}
+
+func (t *remoteWriteTransformation) Process(chunk table.Chunk, d *execute.TransportDataset, mem memory.Allocator) error {
+	req, err := t.writeRequest(chunk)
+	if err != nil {
+		return err
+	}
+	if len(req.Timeseries) > 0 {
+		header := http.Header{}
+		header.Set("X-Prometheus-Remote-Write-Version", remoteProtocolVersion)
+		if _, err := postSnappy(t.ctx, t.spec.URL, t.spec.Headers, header, req.marshal()); err != nil {
+			return err
+		}
+	}
+	chunk.Retain()
+	return d.Process(chunk)
+}
+
+// labelColumns returns the indexes of the label columns of the chunk.
+// Unless they are specified, they are the string columns of the group
+// key other than the name column, _measurement, _start and _stop.
+func (t *remoteWriteTransformation) labelColumns(chunk table.Chunk) ([]int, error) {
+	if t.spec.LabelColumns == nil {
+		var idxs []int
+		for _, c := range chunk.Key().Cols() {
+			switch c.Label {
+			case t.spec.NameColumn, measurementColLabel, execute.DefaultStartColLabel, execute.DefaultStopColLabel:
+				continue
+			}
+			if c.Type == flux.TString {
+				idxs = append(idxs, chunk.Index(c.Label))
+			}
+		}
+		return idxs, nil
+	}
+
+	idxs := make([]int, len(t.spec.LabelColumns))
+	for i, label := range t.spec.LabelColumns {
+		idx := chunk.Index(label)
+		if idx < 0 {
+			return nil, errors.Newf(codes.Invalid, "label column %q does not exist", label)
+		} else if typ := chunk.Col(idx).Type; typ != flux.TString {
+			return nil, errors.Newf(codes.Invalid, "label column %q must be a string, got %v", label, typ)
+		}
+		idxs[i] = idx
+	}
+	return idxs, nil
+}
+
+// writeRequest converts the rows of the chunk into samples of the series
+// that are identified by the name and the label columns. Rows that have
+// a null name, time or value are skipped.
+func (t *remoteWriteTransformation) writeRequest(chunk table.Chunk) (writeRequest, error) {
+	nameIdx := chunk.Index(t.spec.NameColumn)
+	if nameIdx < 0 {
+		return writeRequest{}, errors.Newf(codes.Invalid, "name column %q does not exist", t.spec.NameColumn)
+	} else if typ := chunk.Col(nameIdx).Type; typ != flux.TString {
+		return writeRequest{}, errors.Newf(codes.Invalid, "name column %q must be a string, got %v", t.spec.NameColumn, typ)
+	}
+	timeIdx := chunk.Index(execute.DefaultTimeColLabel)
+	if timeIdx < 0 {
+		return writeRequest{}, errors.Newf(codes.Invalid, "time column %q does not exist", execute.DefaultTimeColLabel)
+	} else if typ := chunk.Col(timeIdx).Type; typ != flux.TTime {
+		return writeRequest{}, errors.Newf(codes.Invalid, "time column %q must be a time, got %v", execute.DefaultTimeColLabel, typ)
+	}
+	valueIdx := chunk.Index(execute.DefaultValueColLabel)
+	if valueIdx < 0 {
+		return writeRequest{}, errors.Newf(codes.Invalid, "value column %q does not exist", execute.DefaultValueColLabel)
+	}
+	value, err := sampleValues(chunk.Col(valueIdx).Type, chunk.Values(valueIdx))
+	if err != nil {
+		return writeRequest{}, err
+	}
+	labelIdxs, err := t.labelColumns(chunk)
+	if err != nil {
+		return writeRequest{}, err
+	}
+
+	var (
+		req    writeRequest
+		series = make(map[string]int)
+		names  = chunk.Strings(nameIdx)
+		times  = chunk.Ints(timeIdx)
+		key    strings.Builder
+	)
+	for i, n := 0, chunk.Len(); i < n; i++ {
+		if names.IsNull(i) || times.IsNull(i) || chunk.Values(valueIdx).IsNull(i) {
+			continue
+		}
+
+		labels := make([]label, 0, len(labelIdxs)+1)
+		labels = append(labels, label{Name: metricNameLabel, Value: names.Value(i)})
+		for _, j := range labelIdxs {
+			vs := chunk.Strings(j)
+			if vs.IsNull(i) || vs.Value(i) == "" {
+				// Prometheus treats an empty label as a missing label.
+				continue
+			}
+			labels = append(labels, label{Name: chunk.Col(j).Label, Value: vs.Value(i)})
+		}
+		sort.Slice(labels, func(i, j int) bool {
+			return labels[i].Name < labels[j].Name
+		})
+
+		key.Reset()
+		for _, l := range labels {
+			key.WriteString(l.Name)
+			key.WriteByte(0)
+			key.WriteString(l.Value)
+			key.WriteByte(0)
+		}
+		idx, ok := series[key.String()]
+		if !ok {
+			idx = len(req.Timeseries)
+			series[key.String()] = idx
+			req.Timeseries = append(req.Timeseries, timeSeries{Labels: labels})
+		}
+		ts := &req.Timeseries[idx]
+		ts.Samples = append(ts.Samples, sample{
+			Value:     value(i),
+			Timestamp: times.Value(i) / 1e6,
+		})
+	}
+
+	// The samples of a series must be sent in time order.
+	for _, ts := range req.Timeseries {
+		samples := ts.Samples
+		sort.SliceStable(samples, func(i, j int) bool {
+			return samples[i].Timestamp < samples[j].Timestamp
+		})
+	}
+	return req, nil
+}
+
+// sampleValues returns a function that converts the values of a numeric
+// column to the float values of the samples.
+func sampleValues(typ flux.ColType, arr array.Array) (func(i int) float64, error) {
+	switch typ {
+	case flux.TFloat:
+		vs := arr.(*array.Float)
+		return vs.Value, nil
+	case flux.TInt:
+		vs := arr.(*array.Int)
+		return func(i int) float64 { return float64(vs.Value(i)) }, nil
+	case flux.TUInt:
+		vs := arr.(*array.Uint)
+		return func(i int) float64 { return float64(vs.Value(i)) }, nil
+	default:
+		return nil, errors.Newf(codes.Invalid, "value column %q must be numeric, got %v", execute.DefaultValueColLabel, typ)
+	}
+}
+
+func (t *remoteWriteTransformation) Close() error {
+	return nil
+}
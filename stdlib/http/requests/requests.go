@@ -159,7 +159,7 @@ var do = values.NewFunction(
 		}
 
 		// Do request, using local anonymous functions to facilitate timing the request
-		statusCode, responseBody, headers, duration, err := func(req *http.Request) (statusCode int, body []byte, headers values.Dictionary, duration time.Duration, err error) {
+		statusCode, responseBody, headers, duration, attempts, err := func(req *http.Request) (statusCode int, body []byte, headers values.Dictionary, duration time.Duration, attempts int, err error) {
 			startTime := time.Now()
 			s, cctx := opentracing.StartSpanFromContext(req.Context(), "requests._do", opentracing.StartTime(startTime))
 			s.SetTag("url", req.URL.String())
@@ -189,9 +189,11 @@ var do = values.NewFunction(
 			if err != nil {
 				return
 			}
+			attempts = fhttp.Attempts(response)
 			s.LogFields(
 				log.Int("statusCode", response.StatusCode),
 				log.Int("responseSize", len(body)),
+				log.Int("attempts", attempts),
 			)
 			headers, err = headerToDict(response.Header)
 			if err != nil {
@@ -209,6 +211,7 @@ var do = values.NewFunction(
 			"headers":    headers,
 			"body":       values.NewBytes(responseBody),
 			"duration":   values.NewDuration(values.ConvertDurationNsecs(duration)),
+			"attempts":   values.NewInt(int64(attempts)),
 		}), nil
 
 	},
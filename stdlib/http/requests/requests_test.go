@@ -88,6 +88,13 @@ resp = requests.do(
 				t.Errorf("unexpected duration want: > 0  got: %q", got)
 			}
 		}
+		if attemptsV, ok := resp.Get("attempts"); !ok {
+			t.Error("no attempts found in response")
+		} else {
+			if want, got := int64(1), attemptsV.Int(); want != got {
+				t.Errorf("unexpected attempts want: %d got: %d", want, got)
+			}
+		}
 	}
 	if want, got := "/path/a/b/c", req.URL.Path; want != got {
 		t.Errorf("unexpected url want: %q got: %q", want, got)
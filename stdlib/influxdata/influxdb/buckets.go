@@ -4,6 +4,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/influxdb"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/plan"
@@ -139,7 +140,28 @@ func (s *BucketsRemoteProcedureSpec) PostPhysicalValidate(id plan.NodeID) error
 
 func createBucketsSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	spec := ps.(*BucketsRemoteProcedureSpec)
-	return CreateSource(id, spec, a)
+	provider := influxdb.GetProvider(a.Context())
+
+	var conf influxdb.Config
+	if spec.Org != nil {
+		conf.Org = *spec.Org
+	}
+	if spec.Host != nil {
+		conf.Host = *spec.Host
+	}
+	if spec.Token != nil {
+		conf.Token = *spec.Token
+	}
+	reader, err := provider.BucketsReaderFor(a.Context(), conf)
+	if err != nil {
+		return nil, err
+	}
+
+	itr := &sourceIterator{
+		reader: reader,
+		mem:    a.Allocator(),
+	}
+	return execute.CreateSourceFromIterator(itr, id)
 }
 
 func (s *BucketsRemoteProcedureSpec) BuildQuery() *ast.File {
@@ -5,6 +5,8 @@
 package influxdb
 
 import (
+	"context"
+
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/dependencies/influxdb"
@@ -192,6 +194,24 @@ func (s *FromRemoteProcedureSpec) TimeBounds(predecessorBounds *plan.Bounds) *pl
 	return bounds
 }
 
+// EstimateStatistics asks the provider to estimate the number of rows and
+// series that will be read. The estimate is unknown when the read is not
+// bounded or the provider does not implement influxdb.StatisticsProvider.
+func (s *FromRemoteProcedureSpec) EstimateStatistics(ctx context.Context, inStats []plan.Statistics) plan.Statistics {
+	if s.Bounds.IsEmpty() {
+		return plan.Statistics{}
+	}
+	sp, ok := influxdb.GetProvider(ctx).(influxdb.StatisticsProvider)
+	if !ok {
+		return plan.Statistics{}
+	}
+	stats, err := sp.ReadStatistics(ctx, s.Config, s.Bounds, s.PredicateSet)
+	if err != nil {
+		return plan.Statistics{}
+	}
+	return stats
+}
+
 func (s *FromRemoteProcedureSpec) Copy() plan.ProcedureSpec {
 	ns := new(FromRemoteProcedureSpec)
 	*ns = *s
@@ -218,15 +238,34 @@ func createFromSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Adm
 		return nil, errors.Newf(codes.Invalid, "bounds must be set")
 	}
 
+	// An incremental run only needs the rows after the watermark
+	// of every group that the previous run saved.
+	// The _start of each table is restored so the group keys
+	// match the saved state.
+	bounds := spec.Bounds
+	var start, readStart execute.Time
+	if t, ok := execute.IncrementalReadStart(a); ok {
+		if b := a.StreamContext().Bounds(); b != nil && t > b.Start && t < b.Stop {
+			bounds = flux.Bounds{
+				Start: flux.Time{Absolute: t.Time()},
+				Stop:  spec.Bounds.Stop,
+				Now:   spec.Bounds.Now,
+			}
+			start, readStart = b.Start, t
+		}
+	}
+
 	provider := influxdb.GetProvider(a.Context())
-	reader, err := provider.ReaderFor(a.Context(), spec.Config, spec.Bounds, spec.PredicateSet)
+	reader, err := provider.ReaderFor(a.Context(), spec.Config, bounds, spec.PredicateSet)
 	if err != nil {
 		return nil, err
 	}
 
 	itr := &sourceIterator{
-		reader: reader,
-		mem:    a.Allocator(),
+		reader:    reader,
+		mem:       a.Allocator(),
+		readStart: readStart,
+		start:     start,
 	}
 	return execute.CreateSourceFromIterator(itr, id)
 }
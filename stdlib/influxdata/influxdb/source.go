@@ -10,14 +10,18 @@ import (
 	"net/url"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/ast/astutil"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/csv"
 	"github.com/influxdata/flux/dependencies/influxdb"
 	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
 )
 
 type ProcedureSpec interface {
@@ -208,8 +212,74 @@ func (s *source) parseError(p []byte) error {
 type sourceIterator struct {
 	reader influxdb.Reader
 	mem    memory.Allocator
+
+	// readStart is the start of the read when it is narrowed for
+	// an incremental run. Tables with a _start of readStart are
+	// reported with the original start of the bounds instead.
+	readStart execute.Time
+	start     execute.Time
 }
 
 func (s *sourceIterator) Do(ctx context.Context, f func(flux.Table) error) error {
-	return s.reader.Read(ctx, f, s.mem)
+	if s.readStart == 0 {
+		return s.reader.Read(ctx, f, s.mem)
+	}
+	return s.reader.Read(ctx, func(tbl flux.Table) error {
+		return f(s.restoreStart(tbl))
+	}, s.mem)
+}
+
+// restoreStart replaces the _start of the table with the
+// start of the bounds if it is the narrowed read start.
+func (s *sourceIterator) restoreStart(tbl flux.Table) flux.Table {
+	key := tbl.Key()
+	idx := execute.ColIdx(execute.DefaultStartColLabel, key.Cols())
+	if idx < 0 || key.Cols()[idx].Type != flux.TTime || key.ValueTime(idx) != s.readStart {
+		return tbl
+	}
+
+	vs := make([]values.Value, len(key.Cols()))
+	for j := range vs {
+		vs[j] = key.Value(j)
+	}
+	vs[idx] = values.NewTime(s.start)
+	return &startTable{
+		Table: tbl,
+		key:   execute.NewGroupKey(key.Cols(), vs),
+		start: s.start,
+		mem:   s.mem,
+	}
+}
+
+// startTable is a table whose _start column
+// is replaced with a different value.
+type startTable struct {
+	flux.Table
+	key   flux.GroupKey
+	start execute.Time
+	mem   memory.Allocator
+}
+
+func (t *startTable) Key() flux.GroupKey {
+	return t.key
+}
+
+func (t *startTable) Do(f func(flux.ColReader) error) error {
+	return t.Table.Do(func(cr flux.ColReader) error {
+		buf := arrow.TableBuffer{
+			GroupKey: t.key,
+			Columns:  cr.Cols(),
+			Values:   make([]array.Array, len(cr.Cols())),
+		}
+		for j, col := range cr.Cols() {
+			if col.Label == execute.DefaultStartColLabel && col.Type == flux.TTime {
+				buf.Values[j] = arrow.Repeat(flux.TTime, values.NewTime(t.start), cr.Len(), t.mem)
+				continue
+			}
+			buf.Values[j] = table.Values(cr, j)
+			buf.Values[j].Retain()
+		}
+		defer buf.Release()
+		return f(&buf)
+	})
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/influxdata/flux/execute/table"
 	"github.com/influxdata/flux/internal/arrowutil"
 	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/execute/deadletter"
 	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
@@ -139,7 +140,18 @@ func (t *toTransformation) Process(chunk table.Chunk, d *execute.TransportDatase
 		t.addTagsFromTable(chunk.Cols(), excludeColumns)
 	}
 
-	if err := t.writeTable(chunk); err != nil {
+	if t.spec.ErrorMode == deadletter.ModeDeadLetter {
+		var rows deadletter.Rows
+		if err := t.writeTable(chunk, &rows); err != nil {
+			rows.AddAll(err)
+		}
+		if rows.Empty() {
+			return nil
+		}
+		return d.Process(rows.Chunk(chunk, 0, mem))
+	}
+
+	if err := t.writeTable(chunk, nil); err != nil {
 		return err
 	}
 
@@ -163,7 +175,11 @@ func (t *toTransformation) addTagsFromTable(cols []flux.ColMeta, exclude map[str
 	sort.Strings(t.tagColumns)
 }
 
-func (t *toTransformation) writeTable(chunk table.Chunk) (err error) {
+// writeTable writes the rows of the chunk. If rows is not nil,
+// the rows that cannot be written are recorded in it instead
+// of returning an error. Each row is then written on its own
+// so that an error can be attributed to the row that caused it.
+func (t *toTransformation) writeTable(chunk table.Chunk, rows *deadletter.Rows) (err error) {
 	spec := t.spec
 
 	// cache tag columns
@@ -207,6 +223,16 @@ func (t *toTransformation) writeTable(chunk table.Chunk) (err error) {
 	metrics := make([]Metric, 0, chunk.Len())
 	er := chunk.Buffer()
 
+	// rowError returns the error for a row that cannot be written.
+	// It returns nil if the error is recorded in rows instead.
+	rowError := func(i int, err error) error {
+		if rows == nil {
+			return err
+		}
+		rows.Add(i, err)
+		return nil
+	}
+
 outer:
 	for i := 0; i < chunk.Len(); i++ {
 		metric := &RowMetric{
@@ -227,7 +253,10 @@ outer:
 				metric.TS = valueTime.Time().Time()
 			case isTag[j]:
 				if col.Type != flux.TString {
-					return errors.New(codes.Invalid, "invalid type for tag column")
+					if err := rowError(i, errors.New(codes.Invalid, "invalid type for tag column")); err != nil {
+						return err
+					}
+					continue outer
 				}
 
 				value := er.Strings(j).Value(i)
@@ -244,15 +273,23 @@ outer:
 		}
 
 		if metric.TS.IsZero() {
-			return errors.New(codes.Invalid, "timestamp missing from block")
+			if err := rowError(i, errors.New(codes.Invalid, "timestamp missing from block")); err != nil {
+				return err
+			}
+			continue
 		}
 
+		var fieldErr error
 		if fn == nil {
-			if fieldValues, err = defaultFieldMapping(&er, i); err != nil {
+			fieldValues, fieldErr = defaultFieldMapping(&er, i)
+		} else {
+			fieldValues, fieldErr = fn.Eval(t.ctx, i, &er)
+		}
+		if fieldErr != nil {
+			if err := rowError(i, fieldErr); err != nil {
 				return err
 			}
-		} else if fieldValues, err = fn.Eval(t.ctx, i, &er); err != nil {
-			return err
+			continue
 		}
 
 		metric.Fields = make([]*Field, 0, fieldValues.Len())
@@ -294,13 +331,23 @@ outer:
 		})
 
 		if err != nil {
-			return err
+			if err := rowError(i, err); err != nil {
+				return err
+			}
+			continue
 		}
 
 		// drop metrics without any measurements
-		if len(metric.Fields) > 0 {
-			metrics = append(metrics, metric)
+		if len(metric.Fields) == 0 {
+			continue
 		}
+		if rows != nil {
+			if err := t.writer.Write(metric); err != nil {
+				rows.Add(i, err)
+			}
+			continue
+		}
+		metrics = append(metrics, metric)
 	}
 
 	// only write if we have any metrics to write
@@ -433,6 +480,7 @@ type ToOpSpec struct {
 	MeasurementColumn string                       `json:"measurementColumn"`
 	TagColumns        []string                     `json:"tagColumns"`
 	FieldFn           interpreter.ResolvedFunction `json:"fieldFn"`
+	ErrorMode         string                       `json:"errorMode,omitempty"`
 }
 
 // ToProcedureSpec is the procedure spec for the `to` flux function.
@@ -469,6 +517,7 @@ func (o *ToProcedureSpec) Copy() plan.ProcedureSpec {
 			MeasurementColumn: s.MeasurementColumn,
 			TagColumns:        append([]string(nil), s.TagColumns...),
 			FieldFn:           s.FieldFn.Copy(),
+			ErrorMode:         s.ErrorMode,
 		},
 	}
 	return res
@@ -542,7 +591,10 @@ func (o *ToOpSpec) ReadArgs(args flux.Arguments) error {
 		}
 	}
 
-	return err
+	if o.ErrorMode, _, err = args.GetString("errorMode"); err != nil {
+		return err
+	}
+	return deadletter.ValidateMode(o.ErrorMode)
 }
 
 func createToOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
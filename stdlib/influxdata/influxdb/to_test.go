@@ -797,3 +797,100 @@ func TestTo_Process(t *testing.T) {
 		})
 	}
 }
+
+// rejectWriter fails to write the metrics with a measurement.
+type rejectWriter struct {
+	pointsWriter
+	reject string
+}
+
+func (t *rejectWriter) Write(metric ...influxdb.Metric) error {
+	for _, m := range metric {
+		if m.Name() == t.reject {
+			return fmt.Errorf("measurement %q is rejected", t.reject)
+		}
+	}
+	return t.pointsWriter.Write(metric...)
+}
+
+func TestTo_ProcessDeadLetter(t *testing.T) {
+	writer := &rejectWriter{reject: "b"}
+	provider := mock.InfluxDBProvider{
+		WriterForFn: func(ctx context.Context, conf influxdb2.Config) (influxdb2.Writer, error) {
+			return writer, nil
+		},
+	}
+	spec := &influxdb.ToProcedureSpec{
+		Spec: &influxdb.ToOpSpec{
+			Org:               "my-org",
+			Bucket:            "my-bucket",
+			TimeColumn:        "_time",
+			MeasurementColumn: "_measurement",
+			ErrorMode:         "deadLetter",
+		},
+	}
+	data := []flux.Table{
+		&executetest.Table{
+			KeyCols: []string{"_measurement"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "_field", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(11), "a", 2.0, "_value"},
+				{execute.Time(21), "a", 3.0, "_value"},
+			},
+		},
+		&executetest.Table{
+			KeyCols: []string{"_measurement"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "_field", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(11), "b", 1.0, "_value"},
+				{execute.Time(21), "b", 4.0, "_value"},
+			},
+		},
+	}
+	want := []*executetest.Table{{
+		KeyCols: []string{"_measurement"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_measurement", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+			{Label: "_field", Type: flux.TString},
+			{Label: "_error", Type: flux.TString},
+		},
+		Data: [][]interface{}{
+			{execute.Time(11), "b", 1.0, "_value", `measurement "b" is rejected`},
+			{execute.Time(21), "b", 4.0, "_value", `measurement "b" is rejected`},
+		},
+	}}
+
+	executetest.ProcessTestHelper2(
+		t,
+		data,
+		want,
+		nil,
+		func(id execute.DatasetID, alloc memory.Allocator) (execute.Transformation, execute.Dataset) {
+			tr, d, err := influxdb.NewToTransformation(context.TODO(), id, spec, provider, alloc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return tr, d
+		},
+	)
+
+	wantWrites := []influxdb.Metric{
+		rowMetric("a", [][2]string{}, [][2]interface{}{{"_value", 2.0}}, time.Unix(0, 11)),
+		rowMetric("a", [][2]string{}, [][2]interface{}{{"_value", 3.0}}, time.Unix(0, 21)),
+	}
+	if !cmp.Equal(wantWrites, writer.writes) {
+		t.Errorf("unexpected writes -want/+got:\n%s", cmp.Diff(wantWrites, writer.writes))
+	}
+}
@@ -459,3 +459,279 @@ func TestLinearInterpolate(t *testing.T) {
 		})
 	}
 }
+
+func TestMethodInterpolate(t *testing.T) {
+	steps := []flux.Table{&executetest.Table{
+		KeyCols: []string{"_field"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_field", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{execute.Time(1), "a", 1.0},
+			{execute.Time(6), "a", 6.0},
+			{execute.Time(14), "a", 14.0},
+		},
+	}}
+	curve := []flux.Table{&executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{execute.Time(0), 0.0},
+			{execute.Time(10), 0.0},
+			{execute.Time(20), 0.0},
+			{execute.Time(30), 10.0},
+			{execute.Time(40), 10.0},
+		},
+	}}
+	every := flux.ConvertDuration(5 * time.Nanosecond)
+
+	testCases := []struct {
+		name    string
+		spec    *interpolate.InterpolateProcedureSpec
+		data    []flux.Table
+		want    []*executetest.Table
+		wantErr error
+	}{
+		{
+			name: "previous",
+			spec: &interpolate.InterpolateProcedureSpec{
+				Method: interpolate.PreviousMethod,
+				Every:  every,
+			},
+			data: steps,
+			want: []*executetest.Table{{
+				KeyCols: []string{"_field"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), "a", 1.0},
+					{execute.Time(5), "a", 1.0},
+					{execute.Time(6), "a", 6.0},
+					{execute.Time(10), "a", 6.0},
+					{execute.Time(14), "a", 14.0},
+				},
+			}},
+		},
+		{
+			name: "next",
+			spec: &interpolate.InterpolateProcedureSpec{
+				Method: interpolate.NextMethod,
+				Every:  every,
+			},
+			data: steps,
+			want: []*executetest.Table{{
+				KeyCols: []string{"_field"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), "a", 1.0},
+					{execute.Time(5), "a", 6.0},
+					{execute.Time(6), "a", 6.0},
+					{execute.Time(10), "a", 14.0},
+					{execute.Time(14), "a", 14.0},
+				},
+			}},
+		},
+		{
+			name: "nearest",
+			spec: &interpolate.InterpolateProcedureSpec{
+				Method: interpolate.NearestMethod,
+				Every:  every,
+			},
+			data: steps,
+			want: []*executetest.Table{{
+				KeyCols: []string{"_field"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), "a", 1.0},
+					{execute.Time(5), "a", 6.0},
+					{execute.Time(6), "a", 6.0},
+					// Equally distant from both points.
+					{execute.Time(10), "a", 6.0},
+					{execute.Time(14), "a", 14.0},
+				},
+			}},
+		},
+		{
+			name: "spline",
+			spec: &interpolate.InterpolateProcedureSpec{
+				Method: interpolate.SplineMethod,
+				Every:  every,
+			},
+			data: curve,
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(0), 0.0},
+					{execute.Time(5), 0.3348214285714285},
+					{execute.Time(10), 0.0},
+					{execute.Time(15), -1.0044642857142856},
+					{execute.Time(20), 0.0},
+					{execute.Time(25), 4.933035714285714},
+					{execute.Time(30), 10.0},
+					{execute.Time(35), 11.272321428571427},
+					{execute.Time(40), 10.0},
+				},
+			}},
+		},
+		{
+			name: "akima",
+			spec: &interpolate.InterpolateProcedureSpec{
+				Method: interpolate.AkimaMethod,
+				Every:  every,
+			},
+			data: curve,
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(0), 0.0},
+					{execute.Time(5), 0.0},
+					{execute.Time(10), 0.0},
+					{execute.Time(15), 0.0},
+					{execute.Time(20), 0.0},
+					{execute.Time(25), 4.375},
+					{execute.Time(30), 10.0},
+					{execute.Time(35), 11.25},
+					{execute.Time(40), 10.0},
+				},
+			}},
+		},
+		{
+			name: "akima two points",
+			spec: &interpolate.InterpolateProcedureSpec{
+				Method: interpolate.AkimaMethod,
+				Every:  every,
+			},
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 1.0},
+					{execute.Time(9), 9.0},
+				},
+			}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 1.0},
+					{execute.Time(5), 5.0},
+					{execute.Time(9), 9.0},
+				},
+			}},
+		},
+		{
+			name: "spline one point",
+			spec: &interpolate.InterpolateProcedureSpec{
+				Method: interpolate.SplineMethod,
+				Every:  every,
+			},
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 1.0},
+				},
+			}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 1.0},
+				},
+			}},
+		},
+		{
+			name: "unsorted",
+			spec: &interpolate.InterpolateProcedureSpec{
+				Method: interpolate.SplineMethod,
+				Every:  every,
+			},
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(9), 9.0},
+					{execute.Time(1), 1.0},
+				},
+			}},
+			wantErr: fmt.Errorf("interpolate.spline requires _time values to be unique and sorted in ascending order"),
+		},
+		{
+			name: "group key error",
+			spec: &interpolate.InterpolateProcedureSpec{
+				Method: interpolate.PreviousMethod,
+				Every:  every,
+			},
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+			}},
+			wantErr: fmt.Errorf("interpolate.previous requires column \"_field\" to be in group key"),
+		},
+		{
+			name: "nulls",
+			spec: &interpolate.InterpolateProcedureSpec{
+				Method: interpolate.NearestMethod,
+				Every:  every,
+			},
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), nil},
+				},
+			}},
+			wantErr: fmt.Errorf("null _value found during nearest interpolation"),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.ProcessTestHelper(
+				t,
+				tc.data,
+				tc.want,
+				tc.wantErr,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					return interpolate.NewMethodTransformation(d, c, tc.spec)
+				},
+			)
+		})
+	}
+}
@@ -128,21 +128,11 @@ func (t *interpolateTransformation) RetractTable(id execute.DatasetID, key flux.
 }
 
 func (t *interpolateTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
-	key, columns, firstPoint := tbl.Key(), tbl.Cols(), true
+	key, firstPoint := tbl.Key(), true
 
-	for _, c := range columns {
-		if key.HasCol(c.Label) {
-			continue
-		}
-		if c.Label == execute.DefaultTimeColLabel {
-			continue
-		}
-		if c.Label == execute.DefaultValueColLabel {
-			continue
-		}
-		return errors.Newf(codes.FailedPrecondition,
-			"interpolate.linear requires column %q to be in group key", c.Label,
-		)
+	ti, vi, err := interpolateColumns("linear", tbl)
+	if err != nil {
+		return err
 	}
 
 	b, created := t.cache.TableBuilder(tbl.Key())
@@ -156,26 +146,6 @@ func (t *interpolateTransformation) Process(id execute.DatasetID, tbl flux.Table
 		return err
 	}
 
-	ti := execute.ColIdx("_time", columns)
-	if ti < 0 {
-		return errors.New(codes.FailedPrecondition,
-			"_time column does not exist",
-		)
-	}
-
-	vi := execute.ColIdx("_value", columns)
-	if vi < 0 {
-		return errors.New(codes.FailedPrecondition,
-			"_value column does not exist",
-		)
-	}
-
-	if ty := columns[vi].Type; ty != flux.TFloat {
-		return errors.Newf(codes.FailedPrecondition,
-			"cannot interpolate %v values; expected float values", ty,
-		)
-	}
-
 	fn := appendFn(b, ti, vi)
 
 	var x0 int64
@@ -260,6 +230,49 @@ func (t *interpolateTransformation) Process(id execute.DatasetID, tbl flux.Table
 	return nil
 }
 
+// interpolateColumns validates the columns of a table passed to one of
+// the interpolate functions and returns the indexes of the _time and
+// _value columns.
+func interpolateColumns(method string, tbl flux.Table) (int, int, error) {
+	key, columns := tbl.Key(), tbl.Cols()
+
+	for _, c := range columns {
+		if key.HasCol(c.Label) {
+			continue
+		}
+		if c.Label == execute.DefaultTimeColLabel {
+			continue
+		}
+		if c.Label == execute.DefaultValueColLabel {
+			continue
+		}
+		return -1, -1, errors.Newf(codes.FailedPrecondition,
+			"interpolate.%s requires column %q to be in group key", method, c.Label,
+		)
+	}
+
+	ti := execute.ColIdx("_time", columns)
+	if ti < 0 {
+		return -1, -1, errors.New(codes.FailedPrecondition,
+			"_time column does not exist",
+		)
+	}
+
+	vi := execute.ColIdx("_value", columns)
+	if vi < 0 {
+		return -1, -1, errors.New(codes.FailedPrecondition,
+			"_value column does not exist",
+		)
+	}
+
+	if ty := columns[vi].Type; ty != flux.TFloat {
+		return -1, -1, errors.Newf(codes.FailedPrecondition,
+			"cannot interpolate %v values; expected float values", ty,
+		)
+	}
+	return ti, vi, nil
+}
+
 func appendFn(b execute.TableBuilder, timeIdx, valueIdx int) func(int64, float64) error {
 	return func(t int64, v float64) error {
 		if err := b.AppendTime(timeIdx, execute.Time(t)); err != nil {
@@ -0,0 +1,378 @@
+package interpolate
+
+import (
+	"math"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	PreviousInterpolateKind = "previousInterpolateKind"
+	NextInterpolateKind     = "nextInterpolateKind"
+	NearestInterpolateKind  = "nearestInterpolateKind"
+	SplineInterpolateKind   = "splineInterpolateKind"
+	AkimaInterpolateKind    = "akimaInterpolateKind"
+)
+
+// Interpolation methods supported by the method transformation.
+const (
+	PreviousMethod = "previous"
+	NextMethod     = "next"
+	NearestMethod  = "nearest"
+	SplineMethod   = "spline"
+	AkimaMethod    = "akima"
+)
+
+var methodKinds = map[string]string{
+	PreviousMethod: PreviousInterpolateKind,
+	NextMethod:     NextInterpolateKind,
+	NearestMethod:  NearestInterpolateKind,
+	SplineMethod:   SplineInterpolateKind,
+	AkimaMethod:    AkimaInterpolateKind,
+}
+
+type InterpolateOpSpec struct {
+	Method string        `json:"method"`
+	Every  flux.Duration `json:"every"`
+}
+
+func init() {
+	for method, kind := range methodKinds {
+		method, kind := method, kind
+		runtime.RegisterPackageValue("interpolate", method,
+			flux.MustValue(flux.FunctionValue(method,
+				func(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+					return createMethodOpSpec(method, args, a)
+				},
+				runtime.MustLookupBuiltinType("interpolate", method),
+			)),
+		)
+		flux.RegisterOpSpec(flux.OperationKind(kind),
+			func() flux.OperationSpec {
+				return &InterpolateOpSpec{Method: method}
+			},
+		)
+		plan.RegisterProcedureSpec(
+			plan.ProcedureKind(kind),
+			newMethodProcedure,
+			flux.OperationKind(kind),
+		)
+		execute.RegisterTransformation(
+			plan.ProcedureKind(kind),
+			createMethodTransformation,
+		)
+	}
+}
+
+func createMethodOpSpec(method string, args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	every, err := args.GetRequiredDuration("every")
+	if err != nil {
+		return nil, err
+	}
+
+	return &InterpolateOpSpec{
+		Method: method,
+		Every:  every,
+	}, nil
+}
+
+func (s *InterpolateOpSpec) Kind() flux.OperationKind {
+	return flux.OperationKind(methodKinds[s.Method])
+}
+
+type InterpolateProcedureSpec struct {
+	plan.DefaultCost
+	Method string        `json:"method"`
+	Every  flux.Duration `json:"every"`
+}
+
+func newMethodProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*InterpolateOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+
+	return &InterpolateProcedureSpec{
+		Method: spec.Method,
+		Every:  spec.Every,
+	}, nil
+}
+
+func (s *InterpolateProcedureSpec) Kind() plan.ProcedureKind {
+	return plan.ProcedureKind(methodKinds[s.Method])
+}
+func (s *InterpolateProcedureSpec) Copy() plan.ProcedureSpec {
+	return &InterpolateProcedureSpec{
+		Method: s.Method,
+		Every:  s.Every,
+	}
+}
+
+// TriggerSpec implements plan.TriggerAwareProcedureSpec
+func (s *InterpolateProcedureSpec) TriggerSpec() plan.TriggerSpec {
+	return plan.NarrowTransformationTriggerSpec{}
+}
+
+func createMethodTransformation(
+	id execute.DatasetID,
+	mode execute.AccumulationMode,
+	spec plan.ProcedureSpec,
+	a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*InterpolateProcedureSpec)
+	if !ok {
+		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t := NewMethodTransformation(d, cache, s)
+	return t, d, nil
+}
+
+// methodTransformation inserts points on the same regular grid as
+// interpolate.linear, but reads all points of a table before
+// interpolating so that methods which look beyond the two
+// surrounding points can be computed.
+type methodTransformation struct {
+	execute.ExecutionNode
+	d      execute.Dataset
+	cache  execute.TableBuilderCache
+	spec   InterpolateProcedureSpec
+	window execute.Window
+}
+
+func NewMethodTransformation(d execute.Dataset, cache execute.TableBuilderCache, spec *InterpolateProcedureSpec) *methodTransformation {
+	return &methodTransformation{
+		d:     d,
+		cache: cache,
+		spec:  *spec,
+		window: execute.Window{
+			Every:  spec.Every,
+			Period: spec.Every,
+		},
+	}
+}
+
+func (t *methodTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *methodTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	key, method := tbl.Key(), t.spec.Method
+
+	ti, vi, err := interpolateColumns(method, tbl)
+	if err != nil {
+		return err
+	}
+
+	b, created := t.cache.TableBuilder(tbl.Key())
+	if !created {
+		return errors.Newf(codes.FailedPrecondition,
+			"duplicate table with key: %v", tbl.Key(),
+		)
+	}
+
+	if err := execute.AddTableCols(tbl, b); err != nil {
+		return err
+	}
+
+	var (
+		xs []int64
+		ys []float64
+	)
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		tc := cr.Times(ti)
+		vc := cr.Floats(vi)
+		for i := 0; i < cr.Len(); i++ {
+			if tc.IsNull(i) {
+				return errors.Newf(codes.FailedPrecondition,
+					"null _time found during %s interpolation", method,
+				)
+			}
+			if vc.IsNull(i) {
+				return errors.Newf(codes.FailedPrecondition,
+					"null _value found during %s interpolation", method,
+				)
+			}
+			xs = append(xs, tc.Value(i))
+			ys = append(ys, vc.Value(i))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	interp, err := newInterpolator(method, xs, ys)
+	if err != nil {
+		return err
+	}
+
+	fn := appendFn(b, ti, vi)
+	for i, xn := range xs {
+		if err := fn(xn, ys[i]); err != nil {
+			return err
+		}
+		if err := execute.AppendKeyValues(key, b); err != nil {
+			return err
+		}
+		if i+1 == len(xs) {
+			break
+		}
+
+		xi := int64(t.window.GetEarliestBounds(values.Time(xn)).Stop)
+		for xi < xs[i+1] {
+			if err := fn(xi, interp(i, xi)); err != nil {
+				return err
+			}
+			if err := execute.AppendKeyValues(key, b); err != nil {
+				return err
+			}
+			xi = int64(execute.Time(xi).Add(t.window.Every))
+		}
+	}
+	return nil
+}
+
+func (t *methodTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+func (t *methodTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+func (t *methodTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
+
+// interpolator computes the value at x, which lies between
+// the points at indexes i and i+1.
+type interpolator func(i int, x int64) float64
+
+func newInterpolator(method string, xs []int64, ys []float64) (interpolator, error) {
+	switch method {
+	case PreviousMethod:
+		return func(i int, x int64) float64 {
+			return ys[i]
+		}, nil
+	case NextMethod:
+		return func(i int, x int64) float64 {
+			return ys[i+1]
+		}, nil
+	case NearestMethod:
+		// Points that are equally distant from both neighbors
+		// take the previous value.
+		return func(i int, x int64) float64 {
+			if x-xs[i] <= xs[i+1]-x {
+				return ys[i]
+			}
+			return ys[i+1]
+		}, nil
+	case SplineMethod, AkimaMethod:
+		for i := 1; i < len(xs); i++ {
+			if xs[i] <= xs[i-1] {
+				return nil, errors.Newf(codes.FailedPrecondition,
+					"interpolate.%s requires _time values to be unique and sorted in ascending order", method,
+				)
+			}
+		}
+		if method == SplineMethod {
+			return splineInterpolator(xs, ys), nil
+		}
+		return akimaInterpolator(xs, ys), nil
+	default:
+		return nil, errors.Newf(codes.Internal, "unknown interpolation method %q", method)
+	}
+}
+
+// splineInterpolator returns a natural cubic spline through the points.
+// The second derivative of the spline is zero at the first and last point.
+func splineInterpolator(xs []int64, ys []float64) interpolator {
+	n := len(xs)
+	h := make([]float64, n)
+	for i := 0; i+1 < n; i++ {
+		h[i] = float64(xs[i+1] - xs[i])
+	}
+
+	// Solve the tridiagonal system for the second derivatives
+	// at the interior points using the Thomas algorithm.
+	m := make([]float64, n)
+	if n > 2 {
+		c := make([]float64, n)
+		d := make([]float64, n)
+		for i := 1; i < n-1; i++ {
+			r := 6 * ((ys[i+1]-ys[i])/h[i] - (ys[i]-ys[i-1])/h[i-1])
+			diag := 2 * (h[i-1] + h[i])
+			if i > 1 {
+				diag -= h[i-1] * c[i-1]
+				r -= h[i-1] * d[i-1]
+			}
+			c[i] = h[i] / diag
+			d[i] = r / diag
+		}
+		m[n-2] = d[n-2]
+		for i := n - 3; i >= 1; i-- {
+			m[i] = d[i] - c[i]*m[i+1]
+		}
+	}
+
+	return func(i int, x int64) float64 {
+		a, b := float64(xs[i+1]-x), float64(x-xs[i])
+		return m[i]*a*a*a/(6*h[i]) + m[i+1]*b*b*b/(6*h[i]) +
+			(ys[i]-m[i]*h[i]*h[i]/6)*a/h[i] +
+			(ys[i+1]-m[i+1]*h[i]*h[i]/6)*b/h[i]
+	}
+}
+
+// akimaInterpolator returns the Akima spline through the points.
+// Unlike a cubic spline, the curve between two points only depends on
+// the slopes of the neighboring segments, which avoids overshooting
+// around outliers. Tables with fewer than three points are interpolated
+// linearly.
+func akimaInterpolator(xs []int64, ys []float64) interpolator {
+	n := len(xs)
+	h := make([]float64, n)
+	for i := 0; i+1 < n; i++ {
+		h[i] = float64(xs[i+1] - xs[i])
+	}
+
+	t := make([]float64, n)
+	if n == 2 {
+		t[0] = (ys[1] - ys[0]) / h[0]
+		t[1] = t[0]
+	} else if n > 2 {
+		// Segment slopes, extended with two extrapolated
+		// slopes on either end.
+		m := make([]float64, n+3)
+		for i := 0; i+1 < n; i++ {
+			m[i+2] = (ys[i+1] - ys[i]) / h[i]
+		}
+		m[1] = 2*m[2] - m[3]
+		m[0] = 2*m[1] - m[2]
+		m[n+1] = 2*m[n] - m[n-1]
+		m[n+2] = 2*m[n+1] - m[n]
+
+		for i := range t {
+			w1, w2 := math.Abs(m[i+3]-m[i+2]), math.Abs(m[i+1]-m[i])
+			if w1+w2 == 0 {
+				t[i] = (m[i+1] + m[i+2]) / 2
+			} else {
+				t[i] = (w1*m[i+1] + w2*m[i+2]) / (w1 + w2)
+			}
+		}
+	}
+
+	return func(i int, x int64) float64 {
+		s := float64(x-xs[i]) / h[i]
+		s2, s3 := s*s, s*s*s
+		return (2*s3-3*s2+1)*ys[i] + (s3-2*s2+s)*h[i]*t[i] +
+			(-2*s3+3*s2)*ys[i+1] + (s3-s2)*h[i]*t[i+1]
+	}
+}
@@ -48,7 +48,36 @@ func (p *EquiJoinProcedureSpec) Copy() plan.ProcedureSpec {
 }
 
 func (p *EquiJoinProcedureSpec) Cost(inStats []plan.Statistics) (cost plan.Cost, outStats plan.Statistics) {
-	return plan.Cost{}, plan.Statistics{}
+	return joinCost(p.Method, inStats)
+}
+
+// joinCost estimates the cost and output of joining the left and right inputs.
+// The estimate assumes that each join key matches at most one row on each side
+// so the output has no more rows than the inputs that contribute to it.
+//
+// The estimate is passed on to the nodes that read the join, such as to choose
+// their parallel factor. It does not choose the order of the joins.
+func joinCost(method string, inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	if len(inStats) != 2 || !inStats[0].Known() || !inStats[1].Known() {
+		return plan.Cost{}, plan.Statistics{}
+	}
+	left, right := inStats[0].Cardinality, inStats[1].Cardinality
+
+	var n int64
+	switch method {
+	case "inner":
+		n = left
+		if right < n {
+			n = right
+		}
+	case "left":
+		n = left
+	case "right":
+		n = right
+	default:
+		n = left + right
+	}
+	return plan.Cost{CPU: left + right}, plan.Statistics{Cardinality: n}
 }
 
 func newEquiJoinProcedureSpec(spec *JoinProcedureSpec, cols []ColumnPair) *EquiJoinProcedureSpec {
@@ -3,12 +3,14 @@ package join
 import (
 	"context"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/array"
 	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/spill"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/table"
 	"github.com/influxdata/flux/internal/errors"
@@ -32,6 +34,11 @@ type MergeJoinTransformation struct {
 	mu          sync.Mutex
 	mem         memory.Allocator
 
+	// spiller is used to move the rows that are waiting for
+	// the other side of the join to disk when memory is under
+	// pressure. It may be nil.
+	spiller *spill.Spiller
+
 	// leftSchema and rightSchema keep track of a union of all the schemas
 	// the join transformation has seen from each side. These are only used
 	// when a group key on one side of a join does not exist on the other side
@@ -58,14 +65,15 @@ func NewMergeJoinTransformation(
 		return nil, errors.New(codes.Internal, "unsupported join spec - not a sortMergeJoin")
 	}
 	return &MergeJoinTransformation{
-		ctx:    ctx,
-		on:     spec.On,
-		as:     NewJoinFn(spec.As),
-		left:   leftID,
-		right:  rightID,
-		method: spec.Method,
-		d:      execute.NewTransportDataset(id, mem),
-		mem:    mem,
+		ctx:     ctx,
+		on:      spec.On,
+		as:      NewJoinFn(spec.As),
+		left:    leftID,
+		right:   rightID,
+		method:  spec.Method,
+		d:       execute.NewTransportDataset(id, mem),
+		mem:     mem,
+		spiller: spill.New(ctx, mem),
 	}, nil
 }
 
@@ -107,6 +115,7 @@ func (t *MergeJoinTransformation) ProcessMessage(m execute.Message) error {
 	case execute.FinishMsg:
 		err := m.Error()
 		if err != nil {
+			t.removeSpilled()
 			t.d.Finish(err)
 			return nil
 		}
@@ -126,6 +135,9 @@ func (t *MergeJoinTransformation) ProcessMessage(m execute.Message) error {
 				}
 				return t.flush(s)
 			})
+			if err != nil {
+				t.removeSpilled()
+			}
 			t.d.Finish(err)
 		}
 	}
@@ -179,9 +191,43 @@ func (t *MergeJoinTransformation) processChunk(chunk table.Chunk, state interfac
 		return s, true, err
 	}
 
+	if t.spiller.ShouldSpill() {
+		if err := t.spill(s); err != nil {
+			return s, true, err
+		}
+	}
 	return s, true, nil
 }
 
+// spill moves the rows of every product that is still waiting
+// for the other side of the join to disk. The state for the
+// current chunk has not been stored in the dataset yet so it
+// is spilled separately.
+func (t *MergeJoinTransformation) spill(current *joinState) error {
+	if err := current.spill(t.spiller, t.mem); err != nil {
+		return err
+	}
+	return t.d.Range(func(key flux.GroupKey, value interface{}) error {
+		s, ok := value.(*joinState)
+		if !ok || s == current {
+			return nil
+		}
+		return s.spill(t.spiller, t.mem)
+	})
+}
+
+// removeSpilled removes the spill files of the products that were never joined.
+func (t *MergeJoinTransformation) removeSpilled() {
+	_ = t.d.Range(func(key flux.GroupKey, value interface{}) error {
+		if s, ok := value.(*joinState); ok {
+			for i := range s.products {
+				s.products[i].removeSpilled()
+			}
+		}
+		return nil
+	})
+}
+
 // mergeJoin takes a table chunk, and attempts to produce joined output from it
 // if possible. It will follow these steps:
 //
@@ -337,7 +383,7 @@ func (s *joinState) insert(key *joinKey, rows joinRows, isLeft bool) (int, bool)
 	for i, product := range s.products {
 		if product.key.equal(*key) {
 			if isLeft {
-				if product.left != nil {
+				if product.hasLeft() {
 					panic(fmt.Sprintf(
 						"join - joinProduct already has left value for key %s",
 						product.key.str(),
@@ -345,7 +391,7 @@ func (s *joinState) insert(key *joinKey, rows joinRows, isLeft bool) (int, bool)
 				}
 				product.left = rows
 			} else {
-				if product.right != nil {
+				if product.hasRight() {
 					panic(fmt.Sprintf(
 						"join - joinProduct already has right value for key %s",
 						product.key.str(),
@@ -390,9 +436,9 @@ func (s *joinState) insert(key *joinKey, rows joinRows, isLeft bool) (int, bool)
 		position--
 		prev := s.products[position]
 		if isLeft {
-			canJoin = prev.left.len() == 0 && prev.right.len() > 0
+			canJoin = !prev.hasLeft() && prev.hasRight()
 		} else {
-			canJoin = prev.left.len() > 0 && prev.right.len() == 0
+			canJoin = prev.hasLeft() && !prev.hasRight()
 		}
 	}
 	return position, canJoin
@@ -424,6 +470,9 @@ func (s *joinState) join(
 	joined := make([]table.Chunk, 0, joinable+1)
 	for i := 0; i <= joinable; i++ {
 		prod := s.products[i]
+		if err := prod.load(mem); err != nil {
+			return joined, err
+		}
 		p, ok, err := prod.evaluate(ctx, method, *fn, mem)
 		if err != nil {
 			return joined, err
@@ -441,6 +490,17 @@ func (s *joinState) finished() bool {
 	return s.left.done && s.right.done
 }
 
+// spill moves the rows of the products that are waiting
+// for the other side of the join to disk.
+func (s *joinState) spill(spiller *spill.Spiller, mem memory.Allocator) error {
+	for i := range s.products {
+		if err := s.products[i].spill(spiller, mem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type sideState struct {
 	schema      []flux.ColMeta
 	joinKeyCols []flux.ColMeta
@@ -597,13 +657,144 @@ func (r joinRows) getRow(i int, typ semantic.MonoType) values.Object {
 type joinProduct struct {
 	key         joinKey
 	left, right joinRows
+
+	// leftFile and rightFile hold the rows of a side
+	// that were spilled to disk. The rows are read back
+	// with the group key of the chunks that were spilled.
+	leftFile, rightFile *spill.File
+	groupKey            flux.GroupKey
 }
 
 func (p *joinProduct) Release() {
 	p.left.Release()
 	p.right.Release()
+	p.removeSpilled()
+}
+
+func (p *joinProduct) hasLeft() bool {
+	return p.left.len() > 0 || p.leftFile != nil
+}
+
+func (p *joinProduct) hasRight() bool {
+	return p.right.len() > 0 || p.rightFile != nil
+}
+
+// spill moves the rows of the product that are in memory to disk.
+func (p *joinProduct) spill(spiller *spill.Spiller, mem memory.Allocator) error {
+	if p.left.len() > 0 {
+		p.groupKey = p.left[0].Key()
+		f, err := spillRows(p.left, spiller, mem)
+		if err != nil {
+			return err
+		}
+		p.left, p.leftFile = nil, f
+	}
+	if p.right.len() > 0 {
+		p.groupKey = p.right[0].Key()
+		f, err := spillRows(p.right, spiller, mem)
+		if err != nil {
+			return err
+		}
+		p.right, p.rightFile = nil, f
+	}
+	return nil
+}
+
+// load reads the rows of the product that were spilled back into memory.
+func (p *joinProduct) load(mem memory.Allocator) error {
+	if p.leftFile != nil {
+		rows, err := loadRows(p.leftFile, p.groupKey)
+		if err != nil {
+			return err
+		}
+		p.left, p.leftFile = rows, nil
+	}
+	if p.rightFile != nil {
+		rows, err := loadRows(p.rightFile, p.groupKey)
+		if err != nil {
+			return err
+		}
+		p.right, p.rightFile = rows, nil
+	}
+	return nil
+}
+
+func (p *joinProduct) removeSpilled() {
+	if p.leftFile != nil {
+		_ = p.leftFile.Remove()
+		p.leftFile = nil
+	}
+	if p.rightFile != nil {
+		_ = p.rightFile.Remove()
+		p.rightFile = nil
+	}
+}
+
+// spillRows writes the rows to a new spill file and releases them.
+func spillRows(rows joinRows, spiller *spill.Spiller, mem memory.Allocator) (*spill.File, error) {
+	defer rows.Release()
+
+	f, err := spiller.Create(rows[0].Cols(), mem)
+	if err != nil {
+		return nil, err
+	}
+	for _, chunk := range rows {
+		if !sameCols(f.Cols(), chunk.Cols()) {
+			if err := f.SetCols(chunk.Cols()); err != nil {
+				_ = f.Remove()
+				return nil, err
+			}
+		}
+		buf := chunk.Buffer()
+		if err := f.Write(&buf); err != nil {
+			_ = f.Remove()
+			return nil, err
+		}
+	}
+	if err := f.Flush(); err != nil {
+		_ = f.Remove()
+		return nil, err
+	}
+	return f, nil
 }
 
+// loadRows reads the rows in the spill file back into memory
+// and removes the file.
+func loadRows(f *spill.File, key flux.GroupKey) (joinRows, error) {
+	defer func() { _ = f.Remove() }()
+
+	r, err := f.Open(key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Release()
+
+	var rows joinRows
+	for {
+		buf, err := r.Next()
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			rows.Release()
+			return nil, err
+		}
+		rows = append(rows, table.ChunkFromBuffer(*buf))
+	}
+}
+
+func sameCols(a, b []flux.ColMeta) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+
 func newJoinProduct(key *joinKey, rows joinRows, isLeft bool) joinProduct {
 	p := joinProduct{
 		key: *key,
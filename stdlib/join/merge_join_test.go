@@ -2,12 +2,15 @@ package join_test
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	arrowmem "github.com/apache/arrow/go/v7/arrow/memory"
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/spill"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/execute/table"
@@ -1901,6 +1904,132 @@ func TestMergeJoin(t *testing.T) {
 	}
 }
 
+func TestMergeJoin_Spill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flux-join-spill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	fn, err := fnFromSrc(`(l, r) => ({_time: l._time, lv: l._value, rv: r._value, label: l.label, group: l.group})`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := join.SortMergeJoinProcedureSpec{
+		On: []join.ColumnPair{
+			{Left: "label", Right: "id"},
+			{Left: "_time", Right: "_time"},
+		},
+		As:     *fn,
+		Method: "inner",
+	}
+	keyCols := []flux.ColMeta{{Label: "group", Type: flux.TUInt}}
+	left := constructChunks(
+		keyCols,
+		[]flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TFloat},
+			{Label: "label", Type: flux.TString},
+			{Label: "group", Type: flux.TUInt},
+		},
+		[]map[string]interface{}{
+			{"_time": execute.Time(1), "_value": 1.2, "label": "a", "group": uint64(1)},
+			{"_time": execute.Time(2), "_value": 2.4, "label": "a", "group": uint64(1)},
+			{"_time": execute.Time(3), "_value": 3.6, "label": "a", "group": uint64(1)},
+		},
+	)
+	right := constructChunks(
+		keyCols,
+		[]flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TInt},
+			{Label: "id", Type: flux.TString},
+			{Label: "group", Type: flux.TUInt},
+		},
+		[]map[string]interface{}{
+			{"_time": execute.Time(1), "_value": int64(1), "id": "a", "group": uint64(1)},
+			{"_time": execute.Time(2), "_value": int64(2), "id": "a", "group": uint64(1)},
+			{"_time": execute.Time(3), "_value": int64(3), "id": "a", "group": uint64(1)},
+		},
+	)
+	want := constructChunks(
+		keyCols,
+		[]flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "lv", Type: flux.TFloat},
+			{Label: "rv", Type: flux.TInt},
+			{Label: "label", Type: flux.TString},
+			{Label: "group", Type: flux.TUInt},
+		},
+		[]map[string]interface{}{
+			{"_time": execute.Time(1), "lv": 1.2, "rv": int64(1), "label": "a", "group": uint64(1)},
+			{"_time": execute.Time(2), "lv": 2.4, "rv": int64(2), "label": "a", "group": uint64(1)},
+			{"_time": execute.Time(3), "lv": 3.6, "rv": int64(3), "label": "a", "group": uint64(1)},
+		},
+	)
+
+	// Simulate memory pressure so the left rows that are
+	// waiting for the right side are spilled to disk.
+	limit := int64(1 << 30)
+	mem := memory.NewResourceAllocator(memory.DefaultAllocator)
+	mem.Limit = &limit
+	if err := mem.Account(int(limit / 10 * 9)); err != nil {
+		t.Fatal(err)
+	}
+	ctx := spill.Inject(context.Background(), spill.Config{Dir: dir})
+
+	mjt, err := join.NewMergeJoinTransformation(ctx, executetest.RandomDatasetID(), &spec, leftID, rightID, mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := executetest.NewDataStore()
+	mjt.Dataset().AddTransformation(store)
+	tr := execute.NewTransformationFromTransport(mjt)
+
+	leftDataset := execute.NewTransportDataset(leftID, mem)
+	leftDataset.AddTransformation(tr)
+	for _, chunk := range left {
+		if err := leftDataset.Process(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr.Finish(leftID, nil)
+
+	if files, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(files) == 0 {
+		t.Fatal("expected the left rows to be spilled")
+	}
+
+	rightDataset := execute.NewTransportDataset(rightID, mem)
+	rightDataset.AddTransformation(tr)
+	for _, chunk := range right {
+		if err := rightDataset.Process(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr.Finish(rightID, nil)
+
+	for _, tbl := range want {
+		wantBuf := tbl.Buffer()
+		gotTbl, err := store.Table(wantBuf.Key())
+		if err != nil {
+			t.Fatalf("got unexpected error: %s", err)
+		}
+		want := table.Stringify(table.FromBuffer(&wantBuf))
+		got := table.Stringify(gotTbl)
+		if !cmp.Equal(want, got) {
+			t.Errorf("table chunks differ, -want/+got:\n%v", cmp.Diff(want, got))
+		}
+	}
+
+	if files, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(files) != 0 {
+		t.Errorf("expected spill files to be removed, found %d", len(files))
+	}
+}
+
 func fnFromSrc(src string) (*interpreter.ResolvedFunction, error) {
 	pkg, err := runtime.AnalyzeSource(context.Background(), src)
 	if err != nil {
@@ -52,7 +52,7 @@ func (p *SortMergeJoinProcedureSpec) RequiredAttributes() []plan.PhysicalAttribu
 }
 
 func (p *SortMergeJoinProcedureSpec) Cost(inStats []plan.Statistics) (cost plan.Cost, outStats plan.Statistics) {
-	return plan.Cost{}, plan.Statistics{}
+	return joinCost(p.Method, inStats)
 }
 
 type SortMergeJoinPredicateRule struct{}
@@ -0,0 +1,299 @@
+package kafka
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/pubsub"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// FromKafkaKind is the Kind for the FromKafka Flux function
+	FromKafkaKind = "fromKafka"
+
+	// DefaultFromKafkaTimeout is how long kafka.from waits
+	// for a message before it stops reading.
+	DefaultFromKafkaTimeout = 5 * time.Second
+)
+
+// FromKafkaOpSpec reads the messages of a topic until there are no more
+// messages or one of the bounds on the number of messages, their offset
+// or their time is reached.
+type FromKafkaOpSpec struct {
+	Brokers     []string      `json:"brokers"`
+	Topic       string        `json:"topic"`
+	Group       string        `json:"group,omitempty"`
+	Partition   int           `json:"partition,omitempty"`
+	Decoder     string        `json:"decoder"`
+	MaxMessages int64         `json:"maxMessages,omitempty"`
+	StopOffset  int64         `json:"stopOffset,omitempty"`
+	Stop        flux.Time     `json:"stop"`
+	Timeout     time.Duration `json:"timeout"`
+}
+
+func init() {
+	fromKafkaSignature := runtime.MustLookupBuiltinType("kafka", "from")
+	runtime.RegisterPackageValue("kafka", "from", flux.MustValue(flux.FunctionValue(FromKafkaKind, createFromKafkaOpSpec, fromKafkaSignature)))
+	flux.RegisterOpSpec(FromKafkaKind, func() flux.OperationSpec { return &FromKafkaOpSpec{} })
+	plan.RegisterProcedureSpec(FromKafkaKind, newFromKafkaProcedure, FromKafkaKind)
+	execute.RegisterSource(FromKafkaKind, createFromKafkaSource)
+}
+
+// DefaultKafkaReaderFactory makes a KafkaReader and is injectable for testing
+var DefaultKafkaReaderFactory = func(conf kafka.ReaderConfig) KafkaReader {
+	return kafka.NewReader(conf)
+}
+
+// KafkaReader is an interface for what we need from DefaultKafkaReaderFactory
+type KafkaReader interface {
+	io.Closer
+	FetchMessage(context.Context) (kafka.Message, error)
+	CommitMessages(context.Context, ...kafka.Message) error
+}
+
+// ReadArgs loads a flux.Arguments into FromKafkaOpSpec.
+// The decoder defaults to line protocol and the timeout to DefaultFromKafkaTimeout.
+func (o *FromKafkaOpSpec) ReadArgs(args flux.Arguments) error {
+	var err error
+	var ok bool
+
+	brokers, err := args.GetRequiredArray("brokers", semantic.String)
+	if err != nil {
+		return err
+	}
+	if brokers.Len() < 1 {
+		return errors.New(codes.Invalid, "at least one broker is required")
+	}
+	o.Brokers = make([]string, brokers.Len())
+	for i := range o.Brokers {
+		o.Brokers[i] = brokers.Get(i).Str()
+	}
+
+	o.Topic, err = args.GetRequiredString("topic")
+	if err != nil {
+		return err
+	}
+	if len(o.Topic) == 0 {
+		return errors.New(codes.Invalid, "invalid topic name")
+	}
+
+	if o.Group, _, err = args.GetString("group"); err != nil {
+		return err
+	}
+	partition, ok, err := args.GetInt("partition")
+	if err != nil {
+		return err
+	}
+	if ok && o.Group != "" {
+		return errors.New(codes.Invalid, "partition cannot be used with group")
+	}
+	o.Partition = int(partition)
+
+	o.Decoder, ok, err = args.GetString("decoder")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		o.Decoder = pubsub.DecoderLineProtocol
+	}
+	if err := pubsub.ValidateDecoder(o.Decoder); err != nil {
+		return err
+	}
+
+	if o.MaxMessages, _, err = args.GetInt("maxMessages"); err != nil {
+		return err
+	}
+	if o.StopOffset, _, err = args.GetInt("stopOffset"); err != nil {
+		return err
+	}
+	if o.MaxMessages < 0 || o.StopOffset < 0 {
+		return errors.New(codes.Invalid, "maxMessages and stopOffset must be positive")
+	}
+	if o.Stop, _, err = args.GetTime("stop"); err != nil {
+		return err
+	}
+
+	timeout, ok, err := args.GetDuration("timeout")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		o.Timeout = DefaultFromKafkaTimeout
+	} else if o.Timeout = timeout.Duration(); o.Timeout <= 0 {
+		return errors.New(codes.Invalid, "timeout must be positive")
+	}
+	return nil
+}
+
+func createFromKafkaOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	s := new(FromKafkaOpSpec)
+	if err := s.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (FromKafkaOpSpec) Kind() flux.OperationKind {
+	return FromKafkaKind
+}
+
+type FromKafkaProcedureSpec struct {
+	plan.DefaultCost
+	Spec *FromKafkaOpSpec
+}
+
+func (o *FromKafkaProcedureSpec) Kind() plan.ProcedureKind {
+	return FromKafkaKind
+}
+
+func (o *FromKafkaProcedureSpec) Copy() plan.ProcedureSpec {
+	s := *o.Spec
+	s.Brokers = append([]string(nil), o.Spec.Brokers...)
+	return &FromKafkaProcedureSpec{Spec: &s}
+}
+
+func newFromKafkaProcedure(qs flux.OperationSpec, a plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromKafkaOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &FromKafkaProcedureSpec{Spec: spec}, nil
+}
+
+func createFromKafkaSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := ps.(*FromKafkaProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", ps)
+	}
+	return CreateSource(spec, id, a)
+}
+
+// CreateSource creates a source that reads the messages of a kafka topic.
+func CreateSource(spec *FromKafkaProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	validator, err := flux.GetDependencies(a.Context()).URLValidator()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range spec.Spec.Brokers {
+		u, err := url.Parse(b)
+		if err != nil {
+			return nil, errors.Newf(codes.Invalid, "invalid kafka broker url: %v", err)
+		}
+		if err := validator.Validate(u); err != nil {
+			return nil, errors.Newf(codes.Invalid, "kafka broker url did not pass validation: %v", err)
+		}
+	}
+
+	var stop time.Time
+	if !spec.Spec.Stop.IsZero() {
+		now := time.Now()
+		if execute.HaveExecutionDependencies(a.Context()) {
+			if deps := execute.GetExecutionDependencies(a.Context()); deps.Now != nil {
+				now = *deps.Now
+			}
+		}
+		stop = spec.Spec.Stop.Time(now)
+	}
+	return execute.CreateSourceFromIterator(&kafkaSource{
+		spec:  spec.Spec,
+		stop:  stop,
+		alloc: a.Allocator(),
+	}, id)
+}
+
+type kafkaSource struct {
+	spec  *FromKafkaOpSpec
+	stop  time.Time
+	alloc memory.Allocator
+}
+
+func (s *kafkaSource) Do(ctx context.Context, f func(flux.Table) error) error {
+	conf := kafka.ReaderConfig{
+		Brokers: s.spec.Brokers,
+		Topic:   s.spec.Topic,
+		GroupID: s.spec.Group,
+	}
+	if s.spec.Group == "" {
+		conf.Partition = s.spec.Partition
+	}
+	reader := DefaultKafkaReaderFactory(conf)
+	deferred := false
+	defer func() {
+		if !deferred {
+			_ = reader.Close()
+		}
+	}()
+
+	consumed, err := s.fetch(ctx, reader)
+	if err != nil {
+		return err
+	}
+	msgs := make([]pubsub.Message, len(consumed))
+	for i, m := range consumed {
+		msgs[i] = pubsub.Message{Value: m.Value, Time: m.Time}
+	}
+	tables, err := pubsub.Decode(s.spec.Decoder, msgs, s.alloc)
+	if err != nil {
+		return err
+	}
+
+	// The offsets of a consumer group are committed so the next read
+	// starts after the messages. They are only committed once the whole
+	// query has succeeded so a failed query reads the same messages again.
+	// The reader stays open until then.
+	if s.spec.Group != "" && len(consumed) > 0 {
+		deferred = execute.OnQueryFinish(ctx, func(ctx context.Context, err error) error {
+			defer func() { _ = reader.Close() }()
+			if err != nil {
+				return nil
+			}
+			return reader.CommitMessages(ctx, consumed...)
+		})
+	}
+	if err := tables.Do(f); err != nil {
+		return err
+	}
+
+	// The query does not support finish hooks so the offsets
+	// are committed once the messages have been processed.
+	if s.spec.Group != "" && len(consumed) > 0 && !deferred {
+		return reader.CommitMessages(ctx, consumed...)
+	}
+	return nil
+}
+
+// fetch returns the messages up to the first message that is out of
+// bounds. It stops when no message arrives before the timeout.
+func (s *kafkaSource) fetch(ctx context.Context, reader KafkaReader) ([]kafka.Message, error) {
+	var msgs []kafka.Message
+	for s.spec.MaxMessages == 0 || int64(len(msgs)) < s.spec.MaxMessages {
+		fetchCtx, cancel := context.WithTimeout(ctx, s.spec.Timeout)
+		m, err := reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil && fetchCtx.Err() == context.DeadlineExceeded {
+				// There are no more messages.
+				break
+			}
+			return nil, err
+		}
+		if s.spec.StopOffset > 0 && m.Offset >= s.spec.StopOffset {
+			break
+		} else if !s.stop.IsZero() && !m.Time.Before(s.stop) {
+			break
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, nil
+}
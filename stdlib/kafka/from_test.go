@@ -0,0 +1,265 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/mock"
+	"github.com/influxdata/flux/querytest"
+	fkafka "github.com/influxdata/flux/stdlib/kafka"
+	"github.com/segmentio/kafka-go"
+)
+
+func TestFromKafka_NewQuery(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name: "from topic",
+			Raw:  `import "kafka" kafka.from(brokers:["brokerurl:8989"], topic:"totallynotfaketopic", group: "flux", decoder: "json")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromKafka0",
+						Spec: &fkafka.FromKafkaOpSpec{
+							Brokers: []string{"brokerurl:8989"},
+							Topic:   "totallynotfaketopic",
+							Group:   "flux",
+							Decoder: "json",
+							Timeout: fkafka.DefaultFromKafkaTimeout,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "partition with group",
+			Raw:     `import "kafka" kafka.from(brokers:["brokerurl:8989"], topic:"totallynotfaketopic", group: "flux", partition: 1)`,
+			WantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+// fakeKafkaReader returns its messages and then
+// waits for more until the context is done.
+type fakeKafkaReader struct {
+	conf      kafka.ReaderConfig
+	messages  []kafka.Message
+	committed []kafka.Message
+	closed    bool
+}
+
+func (r *fakeKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.messages) == 0 {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	m := r.messages[0]
+	r.messages = r.messages[1:]
+	return m, nil
+}
+
+func (r *fakeKafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.committed = append(r.committed, msgs...)
+	return nil
+}
+
+func (r *fakeKafkaReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestFromKafka_Run(t *testing.T) {
+	messages := []kafka.Message{
+		{Offset: 0, Value: []byte("m,host=a v=1 10"), Time: time.Unix(0, 10)},
+		{Offset: 1, Value: []byte("m,host=a v=2\nm,host=b v=3"), Time: time.Unix(0, 20)},
+		{Offset: 2, Value: []byte("m,host=a v=4 30"), Time: time.Unix(0, 30)},
+	}
+	table := func(host string, rows ...[]interface{}) *executetest.Table {
+		return &executetest.Table{
+			KeyCols: []string{"_field", "_measurement", "host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+			},
+			Data: func() [][]interface{} {
+				data := make([][]interface{}, len(rows))
+				for i, row := range rows {
+					data[i] = append(row, "v", "m", host)
+				}
+				return data
+			}(),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		spec          fkafka.FromKafkaOpSpec
+		want          []*executetest.Table
+		wantCommitted int
+	}{
+		{
+			name: "partition",
+			spec: fkafka.FromKafkaOpSpec{Partition: 2},
+			want: []*executetest.Table{
+				// Lines without a timestamp use the time of the message.
+				table("a",
+					[]interface{}{execute.Time(10), 1.0},
+					[]interface{}{execute.Time(20), 2.0},
+					[]interface{}{execute.Time(30), 4.0},
+				),
+				table("b", []interface{}{execute.Time(20), 3.0}),
+			},
+		},
+		{
+			name: "group with max messages",
+			spec: fkafka.FromKafkaOpSpec{Group: "flux", MaxMessages: 1},
+			want: []*executetest.Table{
+				table("a", []interface{}{execute.Time(10), 1.0}),
+			},
+			wantCommitted: 1,
+		},
+		{
+			name: "stop offset",
+			spec: fkafka.FromKafkaOpSpec{Group: "flux", StopOffset: 2},
+			want: []*executetest.Table{
+				table("a",
+					[]interface{}{execute.Time(10), 1.0},
+					[]interface{}{execute.Time(20), 2.0},
+				),
+				table("b", []interface{}{execute.Time(20), 3.0}),
+			},
+			wantCommitted: 2,
+		},
+		{
+			name: "stop",
+			spec: fkafka.FromKafkaOpSpec{Stop: flux.Time{Absolute: time.Unix(0, 20)}},
+			want: []*executetest.Table{
+				table("a", []interface{}{execute.Time(10), 1.0}),
+			},
+		},
+	}
+
+	defer func(factory func(kafka.ReaderConfig) fkafka.KafkaReader) {
+		fkafka.DefaultKafkaReaderFactory = factory
+	}(fkafka.DefaultKafkaReaderFactory)
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			reader := &fakeKafkaReader{messages: messages}
+			fkafka.DefaultKafkaReaderFactory = func(conf kafka.ReaderConfig) fkafka.KafkaReader {
+				reader.conf = conf
+				return reader
+			}
+
+			spec := tc.spec
+			spec.Brokers = []string{"localhost:9092"}
+			spec.Topic = "topic"
+			spec.Decoder = "lineProtocol"
+			spec.Timeout = 10 * time.Millisecond
+			executetest.RunSourceHelper(t, tc.want, nil, func(id execute.DatasetID) execute.Source {
+				a := mock.AdministrationWithContext(flux.NewDefaultDependencies().Inject(context.Background()))
+				s, err := fkafka.CreateSource(&fkafka.FromKafkaProcedureSpec{Spec: &spec}, id, a)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return s
+			})
+
+			if want, got := spec.Group, reader.conf.GroupID; want != got {
+				t.Errorf("unexpected group: want %q, got %q", want, got)
+			}
+			if want, got := spec.Partition, reader.conf.Partition; want != got {
+				t.Errorf("unexpected partition: want %d, got %d", want, got)
+			}
+			if want, got := tc.wantCommitted, len(reader.committed); want != got {
+				t.Errorf("unexpected committed messages: want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestFromKafka_CommitOnQueryFinish(t *testing.T) {
+	messages := []kafka.Message{
+		{Offset: 0, Value: []byte("m,host=a v=1 10"), Time: time.Unix(0, 10)},
+		{Offset: 1, Value: []byte("m,host=a v=2 20"), Time: time.Unix(0, 20)},
+	}
+
+	defer func(factory func(kafka.ReaderConfig) fkafka.KafkaReader) {
+		fkafka.DefaultKafkaReaderFactory = factory
+	}(fkafka.DefaultKafkaReaderFactory)
+
+	for _, tc := range []struct {
+		name          string
+		err           error
+		wantCommitted int
+	}{
+		{
+			name:          "success",
+			wantCommitted: 2,
+		},
+		{
+			name: "failure",
+			err:  errors.New("expected error"),
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			reader := &fakeKafkaReader{messages: messages}
+			fkafka.DefaultKafkaReaderFactory = func(conf kafka.ReaderConfig) fkafka.KafkaReader {
+				return reader
+			}
+
+			hooks := new(execute.QueryHooks)
+			ctx := execute.WithQueryHooks(flux.NewDefaultDependencies().Inject(context.Background()), hooks)
+			spec := fkafka.FromKafkaOpSpec{
+				Brokers: []string{"localhost:9092"},
+				Topic:   "topic",
+				Group:   "flux",
+				Decoder: "lineProtocol",
+				Timeout: 10 * time.Millisecond,
+			}
+			s, err := fkafka.CreateSource(&fkafka.FromKafkaProcedureSpec{Spec: &spec}, executetest.RandomDatasetID(), mock.AdministrationWithContext(ctx))
+			if err != nil {
+				t.Fatal(err)
+			}
+			store := executetest.NewDataStore()
+			s.AddTransformation(store)
+			s.Run(ctx)
+			if err := store.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			// Nothing is committed until the query has finished.
+			if len(reader.committed) != 0 {
+				t.Fatalf("messages were committed before the query finished: %d", len(reader.committed))
+			} else if reader.closed {
+				t.Fatal("reader was closed before the query finished")
+			}
+
+			if err := hooks.Finish(ctx, tc.err); err != nil {
+				t.Fatal(err)
+			}
+			if want, got := tc.wantCommitted, len(reader.committed); want != got {
+				t.Errorf("unexpected committed messages: want %d, got %d", want, got)
+			}
+			if !reader.closed {
+				t.Error("reader was not closed")
+			}
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package kafka
 
 import (
-	"bufio"
+	"bytes"
 	"context"
 	"encoding/binary"
 	"io"
@@ -14,7 +14,7 @@ import (
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
-	"github.com/influxdata/flux/internal/pkg/syncutil"
+	"github.com/influxdata/flux/internal/execute/deadletter"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/flux/semantic"
@@ -38,6 +38,7 @@ type ToKafkaOpSpec struct {
 	TagColumns   []string `json:"tagColumns"`
 	ValueColumns []string `json:"valueColumns"`
 	MsgBufSize   int      `json:"msgBufferSize"` // the maximim number of messages to buffer before sending to kafka, the library we use defaults to 100
+	ErrorMode    string   `json:"errorMode,omitempty"`
 }
 
 func init() {
@@ -147,7 +148,10 @@ func (o *ToKafkaOpSpec) ReadArgs(args flux.Arguments) error {
 		o.MsgBufSize = 0 // so the library will set it  to the default
 	}
 
-	return err
+	if o.ErrorMode, _, err = args.GetString("errorMode"); err != nil {
+		return err
+	}
+	return deadletter.ValidateMode(o.ErrorMode)
 }
 func createToKafkaOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
 	if err := a.AddParentFromArgs(args); err != nil {
@@ -185,6 +189,8 @@ func (o *ToKafkaProcedureSpec) Copy() plan.ProcedureSpec {
 			TimeColumn:   s.TimeColumn,
 			TagColumns:   append([]string(nil), s.TagColumns...),
 			ValueColumns: append([]string(nil), s.ValueColumns...),
+			MsgBufSize:   s.MsgBufSize,
+			ErrorMode:    s.ErrorMode,
 		},
 	}
 	switch s.Balancer {
@@ -278,7 +284,54 @@ type idxType struct {
 	Type flux.ColType
 }
 
-func (t *ToKafkaTransformation) Process(id execute.DatasetID, tbl flux.Table) (err error) {
+func (t *ToKafkaTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	if t.spec.Spec.ErrorMode != deadletter.ModeDeadLetter {
+		builder, new := t.cache.TableBuilder(tbl.Key())
+		if new {
+			if err := execute.AddTableCols(tbl, builder); err != nil {
+				return err
+			}
+		}
+		return t.writeTable(tbl, builder, nil)
+	}
+
+	buf, err := execute.CopyTable(tbl)
+	if err != nil {
+		return err
+	}
+	defer buf.Done()
+
+	var rows deadletter.Rows
+	if err := t.writeTable(buf.Copy(), nil, &rows); err != nil {
+		rows.AddAll(err)
+	}
+	if rows.Empty() {
+		return nil
+	}
+	builder, new := t.cache.TableBuilder(buf.Key())
+	if new {
+		for _, col := range deadletter.Cols(buf.Cols()) {
+			if _, err := builder.AddCol(col); err != nil {
+				return err
+			}
+		}
+	}
+	offset := 0
+	return buf.Copy().Do(func(cr flux.ColReader) error {
+		if err := rows.AppendTo(cr, offset, builder); err != nil {
+			return err
+		}
+		offset += cr.Len()
+		return nil
+	})
+}
+
+// writeTable sends each row of the table to kafka as a message
+// with the row encoded as line protocol. The rows are appended
+// to the builder if it is not nil. If rows is not nil, the rows
+// that cannot be encoded or sent are recorded in it instead
+// of returning an error.
+func (t *ToKafkaTransformation) writeTable(tbl flux.Table, builder execute.TableBuilder, rows *deadletter.Rows) (err error) {
 	w := DefaultKafkaWriterFactory(kafka.WriterConfig{
 		Brokers:       t.spec.Spec.Brokers,
 		Topic:         t.spec.Spec.Topic,
@@ -299,10 +352,9 @@ func (t *ToKafkaTransformation) Process(id execute.DatasetID, tbl flux.Table) (e
 			return
 		}
 	}()
-	pr, pw := io.Pipe() // TODO: replce the pipe with something faster
-	// I'd like a linereader in line-protocol
+	var line bytes.Buffer
 	m := &toKafkaMetric{}
-	e := protocol.NewEncoder(pw)
+	e := protocol.NewEncoder(&line)
 	e.FailOnFieldErr(true)
 	e.SetFieldSortOrder(protocol.SortFields)
 	cols := tbl.Cols()
@@ -333,98 +385,106 @@ func (t *ToKafkaTransformation) Process(id execute.DatasetID, tbl flux.Table) (e
 	}
 	m.name = t.spec.Spec.Name
 
-	builder, new := t.cache.TableBuilder(tbl.Key())
-	if new {
-		if err := execute.AddTableCols(tbl, builder); err != nil {
-			return err
+	// encode writes row i of the column reader to line.
+	encode := func(er flux.ColReader, i int) error {
+		m.truncateTagsAndFields()
+		for j, col := range er.Cols() {
+			switch {
+			case col.Label == timeColLabel:
+				m.t = values.Time(er.Times(j).Value(i)).Time()
+			case measurementNameCol != "" && measurementNameCol == col.Label:
+				if col.Type != flux.TString {
+					return errors.New(codes.FailedPrecondition, "invalid type for measurement column")
+				}
+				m.name = er.Strings(j).Value(i)
+			case isTag[j]:
+				if col.Type != flux.TString {
+					return errors.New(codes.FailedPrecondition, "invalid type for measurement column")
+				}
+				m.tags = append(m.tags, &protocol.Tag{Key: col.Label, Value: er.Strings(j).Value(i)})
+			case isValue[j]:
+				switch col.Type {
+				case flux.TFloat:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Floats(j).Value(i)})
+				case flux.TInt:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Ints(j).Value(i)})
+				case flux.TUInt:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.UInts(j).Value(i)})
+				case flux.TString:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Strings(j).Value(i)})
+				case flux.TTime:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: values.Time(er.Times(j).Value(i))})
+				case flux.TBool:
+					m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Bools(j).Value(i)})
+				default:
+					return errors.Newf(codes.FailedPrecondition, "invalid type for column %s", col.Label)
+				}
+			}
 		}
+		line.Reset()
+		_, err := e.Encode(m)
+		return err
 	}
 
-	var wg syncutil.WaitGroup
-	wg.Do(func() error {
-		if err := tbl.Do(func(er flux.ColReader) error {
-			l := er.Len()
-			for i := 0; i < l; i++ {
-				m.truncateTagsAndFields()
-				for j, col := range er.Cols() {
-					switch {
-					case col.Label == timeColLabel:
-						m.t = values.Time(er.Times(j).Value(i)).Time()
-					case measurementNameCol != "" && measurementNameCol == col.Label:
-						if col.Type != flux.TString {
-							return errors.New(codes.FailedPrecondition, "invalid type for measurement column")
-						}
-						m.name = er.Strings(j).Value(i)
-					case isTag[j]:
-						if col.Type != flux.TString {
-							return errors.New(codes.FailedPrecondition, "invalid type for measurement column")
-						}
-						m.tags = append(m.tags, &protocol.Tag{Key: col.Label, Value: er.Strings(j).Value(i)})
-					case isValue[j]:
-						switch col.Type {
-						case flux.TFloat:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Floats(j).Value(i)})
-						case flux.TInt:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Ints(j).Value(i)})
-						case flux.TUInt:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.UInts(j).Value(i)})
-						case flux.TString:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Strings(j).Value(i)})
-						case flux.TTime:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: values.Time(er.Times(j).Value(i))})
-						case flux.TBool:
-							m.fields = append(m.fields, &protocol.Field{Key: col.Label, Value: er.Bools(j).Value(i)})
-						default:
-							return errors.Newf(codes.FailedPrecondition, "invalid type for column %s", col.Label)
-						}
-					}
-				}
-				_, err := e.Encode(m)
-				if err != nil {
+	// msgs are the messages that have not been sent yet
+	// and msgRows are the rows of the table they were made from.
+	var (
+		msgs    []kafka.Message
+		msgRows []int
+	)
+	flush := func() error {
+		if len(msgs) == 0 {
+			return nil
+		}
+		if err := w.WriteMessages(context.Background(), msgs...); err != nil {
+			if rows == nil {
+				return err
+			}
+			for _, row := range msgRows {
+				rows.Add(row, err)
+			}
+		}
+		msgs, msgRows = nil, nil
+		return nil
+	}
+
+	offset := 0
+	if err := tbl.Do(func(er flux.ColReader) error {
+		l := er.Len()
+		for i := 0; i < l; i++ {
+			if err := encode(er, i); err != nil {
+				if rows == nil {
 					return err
 				}
+				rows.Add(offset+i, err)
+				continue
+			}
+			if builder != nil {
 				if err := execute.AppendRecord(i, er, builder); err != nil {
 					return err
 				}
 			}
-			return nil
-		}); err != nil {
-			_ = pw.Close()
-			return err
-		}
-		return pw.Close()
-	})
-	// write the data to kafka
-	{
-		scan := bufio.NewScanner(pr)
-		msgBuf := make([]kafka.Message, 128)
-		i := 0
-		// todo, make this a little more async
-		for scan.Scan() {
-			v := append([]byte(nil), scan.Bytes()...) // we do this since scan.Bytes()'s result can be overwritten by calls to Scan()
-			if cap(msgBuf[i].Key) != 8 {
-				msgBuf[i].Key = make([]byte, 8)
+			if line.Len() == 0 {
+				continue
 			}
-			binary.LittleEndian.PutUint64(msgBuf[i].Key, xxhash.Sum64(v))
-			msgBuf[i].Value = v
-			if i == t.spec.Spec.MsgBufSize-1 {
-				if err = w.WriteMessages(context.Background(), msgBuf...); err != nil {
+			v := append([]byte(nil), bytes.TrimSuffix(line.Bytes(), []byte("\n"))...)
+			key := make([]byte, 8)
+			binary.LittleEndian.PutUint64(key, xxhash.Sum64(v))
+			msgs = append(msgs, kafka.Message{Key: key, Value: v})
+			msgRows = append(msgRows, offset+i)
+			if n := t.spec.Spec.MsgBufSize; n > 0 && len(msgs) >= n {
+				if err := flush(); err != nil {
 					return err
 				}
-				msgBuf = msgBuf[:0]
-				i = 0
 			}
-			i++
 		}
-		// send the remainder of the messages
-		if len(msgBuf) > 0 {
-			err = w.WriteMessages(context.Background(), msgBuf[:i]...)
-		}
-	}
-	if err := wg.Wait(); err != nil {
+		offset += l
+		return nil
+	}); err != nil {
 		return err
 	}
-	return err
+	// send the remainder of the messages
+	return flush()
 }
 
 func (t *ToKafkaTransformation) UpdateWatermark(id execute.DatasetID, pt execute.Time) error {
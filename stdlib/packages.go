@@ -35,6 +35,7 @@ import (
 	_ "github.com/influxdata/flux/stdlib/experimental"
 	_ "github.com/influxdata/flux/stdlib/experimental/aggregate"
 	_ "github.com/influxdata/flux/stdlib/experimental/array"
+	_ "github.com/influxdata/flux/stdlib/experimental/arrow"
 	_ "github.com/influxdata/flux/stdlib/experimental/bigtable"
 	_ "github.com/influxdata/flux/stdlib/experimental/bitwise"
 	_ "github.com/influxdata/flux/stdlib/experimental/csv"
@@ -44,8 +45,10 @@ import (
 	_ "github.com/influxdata/flux/stdlib/experimental/influxdb"
 	_ "github.com/influxdata/flux/stdlib/experimental/iox"
 	_ "github.com/influxdata/flux/stdlib/experimental/json"
+	_ "github.com/influxdata/flux/stdlib/experimental/lineprotocol"
 	_ "github.com/influxdata/flux/stdlib/experimental/mqtt"
 	_ "github.com/influxdata/flux/stdlib/experimental/oee"
+	_ "github.com/influxdata/flux/stdlib/experimental/parquet"
 	_ "github.com/influxdata/flux/stdlib/experimental/prometheus"
 	_ "github.com/influxdata/flux/stdlib/experimental/query"
 	_ "github.com/influxdata/flux/stdlib/experimental/record"
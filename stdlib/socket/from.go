@@ -1,6 +1,7 @@
 // Package socket implements a source that gets input from a socket connection and produces tables given a decoder.
-// This is a good candidate for streaming use cases. For now, it produces a single table for everything
-// that it receives from the start to the end of the connection.
+// By default, it produces a single table for everything that it receives from the start to the end of the connection.
+// When a window duration is specified, the connection is read as an unbounded stream and a table is produced
+// for each window along with watermark updates so downstream transformations can emit results incrementally.
 package socket
 
 import (
@@ -17,6 +18,7 @@ import (
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/flux/values"
@@ -25,8 +27,9 @@ import (
 const FromSocketKind = "fromSocket"
 
 type FromSocketOpSpec struct {
-	URL     string `json:"url"`
-	Decoder string `json:"decoder"`
+	URL     string        `json:"url"`
+	Decoder string        `json:"decoder"`
+	Every   flux.Duration `json:"every,omitempty"`
 }
 
 func init() {
@@ -80,6 +83,18 @@ func createFromSocketOpSpec(args flux.Arguments, a *flux.Administration) (flux.O
 		return nil, errors.Newf(codes.Invalid, "invalid decoder %s, must be one of %v", spec.Decoder, decoders)
 	}
 
+	if every, ok, err := args.GetDuration("every"); err != nil {
+		return nil, err
+	} else if ok {
+		if !every.IsPositive() || !every.NanoOnly() {
+			return nil, errors.New(codes.Invalid, "every must be a positive duration without months")
+		}
+		if spec.Decoder != "line" {
+			return nil, errors.Newf(codes.Invalid, "every is not supported by the %s decoder", spec.Decoder)
+		}
+		spec.Every = every
+	}
+
 	return spec, nil
 }
 
@@ -95,6 +110,7 @@ type FromSocketProcedureSpec struct {
 	plan.DefaultCost
 	URL     string
 	Decoder string
+	Every   flux.Duration
 }
 
 func newFromSocketProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
@@ -106,6 +122,7 @@ func newFromSocketProcedure(qs flux.OperationSpec, pa plan.Administration) (plan
 	return &FromSocketProcedureSpec{
 		URL:     spec.URL,
 		Decoder: spec.Decoder,
+		Every:   spec.Every,
 	}, nil
 }
 
@@ -117,6 +134,7 @@ func (s *FromSocketProcedureSpec) Copy() plan.ProcedureSpec {
 	ns := new(FromSocketProcedureSpec)
 	ns.URL = s.URL
 	ns.Decoder = s.Decoder
+	ns.Every = s.Every
 	return ns
 }
 
@@ -155,10 +173,17 @@ func createFromSocketSource(s plan.ProcedureSpec, dsid execute.DatasetID, a exec
 		return nil, errors.Wrap(err, codes.Inherit, "error in creating socket source")
 	}
 
-	return NewSocketSource(spec, conn, &nowTimeProvider{}, dsid)
+	return NewSocketSource(spec, conn, &nowTimeProvider{}, dsid, a.Allocator())
 }
 
-func NewSocketSource(spec *FromSocketProcedureSpec, rc io.ReadCloser, tp line.TimeProvider, dsid execute.DatasetID) (execute.Source, error) {
+func NewSocketSource(spec *FromSocketProcedureSpec, rc io.ReadCloser, tp line.TimeProvider, dsid execute.DatasetID, mem memory.Allocator) (execute.Source, error) {
+	if !spec.Every.IsZero() {
+		if spec.Decoder != "line" {
+			return nil, errors.Newf(codes.Invalid, "every is not supported by the %s decoder", spec.Decoder)
+		}
+		return newStreamSource(rc, spec.Every, tp, dsid, mem), nil
+	}
+
 	var decoder flux.ResultDecoder
 	switch spec.Decoder {
 	case "csv":
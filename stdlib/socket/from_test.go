@@ -35,6 +35,35 @@ socket.from()`,
 socket.from(url: "url", decoder: "wrong")`,
 			WantErr: true,
 		},
+		{
+			Name: "from every with csv decoder",
+			Raw: `import "socket"
+socket.from(url: "url", decoder: "csv", every: 1m)`,
+			WantErr: true,
+		},
+		{
+			Name: "from every negative",
+			Raw: `import "socket"
+socket.from(url: "url", decoder: "line", every: -1m)`,
+			WantErr: true,
+		},
+		{
+			Name: "from every",
+			Raw: `import "socket"
+socket.from(url: "url", decoder: "line", every: 1m)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromSocket0",
+						Spec: &socket.FromSocketOpSpec{
+							URL:     "url",
+							Decoder: "line",
+							Every:   flux.ConvertDuration(time.Minute),
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "from ok",
 			Raw: `import "socket"
@@ -215,7 +244,7 @@ source
 			c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
 			c.SetTriggerSpec(plan.DefaultTriggerSpec)
 			r := ioutil.NopCloser(bytes.NewReader([]byte(tc.input)))
-			ss, err := socket.NewSocketSource(tc.spec, r, &mock.AscendingTimeProvider{}, id)
+			ss, err := socket.NewSocketSource(tc.spec, r, &mock.AscendingTimeProvider{}, id, executetest.UnlimitedAllocator)
 			if err != nil {
 				t.Fatal(err)
 			}
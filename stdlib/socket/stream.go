@@ -0,0 +1,218 @@
+package socket
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+)
+
+// streamSource reads a connection as an unbounded stream of lines.
+//
+// Lines are assigned to windows of a fixed duration using the time they
+// were received. When a window is complete, a table with the lines from
+// that window is sent downstream followed by a watermark at the stop of
+// the window. A watermark and the processing time are also sent
+// periodically so that downstream triggers fire while the connection
+// is idle.
+type streamSource struct {
+	execute.ExecutionNode
+	d     execute.DatasetID
+	rc    io.ReadCloser
+	sep   byte
+	every execute.Duration
+	tp    line.TimeProvider
+	ts    execute.TransformationSet
+	mem   memory.Allocator
+
+	// tick is how often the watermark is advanced
+	// when no lines are received.
+	tick time.Duration
+}
+
+func newStreamSource(rc io.ReadCloser, every execute.Duration, tp line.TimeProvider, dsid execute.DatasetID, mem memory.Allocator) *streamSource {
+	return &streamSource{
+		d:     dsid,
+		rc:    rc,
+		sep:   '\n',
+		every: every,
+		tp:    tp,
+		mem:   mem,
+		tick:  every.Duration(),
+	}
+}
+
+func (ss *streamSource) AddTransformation(t execute.Transformation) {
+	ss.ts = append(ss.ts, t)
+}
+
+// streamLine is a line read from the connection or the error
+// that ended the stream.
+type streamLine struct {
+	value string
+	err   error
+}
+
+func (ss *streamSource) Run(ctx context.Context) {
+	err := ss.run(ctx)
+	ss.ts.Finish(ss.d, err)
+}
+
+func (ss *streamSource) run(ctx context.Context) error {
+	lines := make(chan streamLine)
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		_ = ss.rc.Close()
+	}()
+	go ss.read(lines, done)
+
+	ticker := time.NewTicker(ss.tick)
+	defer ticker.Stop()
+
+	var w *streamWindow
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case l := <-lines:
+			if l.err == io.EOF {
+				// The connection was closed so there will be no more data.
+				// Send the current window and let Finish flush the downstream
+				// transformations.
+				if w != nil {
+					return ss.flush(w, ss.tp.CurrentTime())
+				}
+				return nil
+			} else if l.err != nil {
+				return errors.Wrap(l.err, codes.Inherit, "decode error")
+			}
+
+			now := ss.tp.CurrentTime()
+			if w != nil && now >= w.stop {
+				if err := ss.flush(w, now); err != nil {
+					return err
+				}
+				w = nil
+			}
+			if w == nil {
+				start := ss.truncate(now)
+				w = newStreamWindow(start, start.Add(ss.every), ss.mem)
+			}
+			if err := w.append(now, l.value); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			now := ss.tp.CurrentTime()
+			if w != nil && now >= w.stop {
+				if err := ss.flush(w, now); err != nil {
+					return err
+				}
+				w = nil
+			}
+			if err := ss.advance(now, ss.truncate(now)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// read sends each line from the connection to the channel.
+// The last value sent contains the error that stopped the read.
+func (ss *streamSource) read(lines chan<- streamLine, done <-chan struct{}) {
+	r := bufio.NewReader(ss.rc)
+	for {
+		s, err := r.ReadString(ss.sep)
+		if err != nil {
+			select {
+			case lines <- streamLine{err: err}:
+			case <-done:
+			}
+			return
+		}
+
+		select {
+		case lines <- streamLine{value: strings.Trim(s, string(ss.sep))}:
+		case <-done:
+			return
+		}
+	}
+}
+
+// flush sends the table for the window downstream and advances
+// the watermark to the end of the window.
+func (ss *streamSource) flush(w *streamWindow, now execute.Time) error {
+	tbl, err := w.builder.Table()
+	if err != nil {
+		return err
+	}
+	if err := ss.ts.Process(ss.d, tbl); err != nil {
+		return err
+	}
+	return ss.advance(now, w.stop)
+}
+
+// advance notifies the downstream transformations of the
+// current processing time and watermark.
+func (ss *streamSource) advance(now, mark execute.Time) error {
+	if err := ss.ts.UpdateProcessingTime(ss.d, now); err != nil {
+		return err
+	}
+	return ss.ts.UpdateWatermark(ss.d, mark)
+}
+
+// truncate returns the start of the window that contains t.
+func (ss *streamSource) truncate(t execute.Time) execute.Time {
+	return t - execute.Time(t.Remainder(ss.every).Duration())
+}
+
+// streamWindow accumulates the lines received within [start, stop).
+//
+// The table for the window is grouped by its bounds so that each window
+// is a separate table downstream. The line decoder has no other columns
+// to group by.
+type streamWindow struct {
+	start, stop execute.Time
+	builder     *execute.ColListTableBuilder
+}
+
+func newStreamWindow(start, stop execute.Time, mem memory.Allocator) *streamWindow {
+	key := execute.NewGroupKey(
+		[]flux.ColMeta{
+			{Label: execute.DefaultStartColLabel, Type: flux.TTime},
+			{Label: execute.DefaultStopColLabel, Type: flux.TTime},
+		},
+		[]values.Value{
+			values.NewTime(start),
+			values.NewTime(stop),
+		},
+	)
+	builder := execute.NewColListTableBuilder(key, mem)
+	_ = execute.AddTableKeyCols(key, builder)
+	_, _ = builder.AddCol(flux.ColMeta{Label: execute.DefaultTimeColLabel, Type: flux.TTime})
+	_, _ = builder.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: flux.TString})
+	return &streamWindow{
+		start:   start,
+		stop:    stop,
+		builder: builder,
+	}
+}
+
+func (w *streamWindow) append(t execute.Time, v string) error {
+	if err := execute.AppendKeyValues(w.builder.Key(), w.builder); err != nil {
+		return err
+	}
+	if err := w.builder.AppendTime(2, t); err != nil {
+		return err
+	}
+	return w.builder.AppendString(3, v)
+}
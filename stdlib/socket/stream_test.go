@@ -0,0 +1,220 @@
+package socket
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/mock"
+	"github.com/influxdata/flux/values"
+)
+
+// streamEvent records a message sent by the stream source.
+type streamEvent struct {
+	Kind  string
+	Time  execute.Time
+	Table *executetest.Table
+	Err   string
+}
+
+func runStreamSource(t *testing.T, ctx context.Context, rc io.ReadCloser, every time.Duration) []streamEvent {
+	t.Helper()
+
+	var events []streamEvent
+	ss := newStreamSource(rc, values.ConvertDurationNsecs(every), &mock.AscendingTimeProvider{}, executetest.RandomDatasetID(), executetest.UnlimitedAllocator)
+	// Only advance the watermark when lines are received
+	// so the output does not depend on the wall clock.
+	ss.tick = time.Hour
+	ss.AddTransformation(&mock.Transformation{
+		ProcessFn: func(id execute.DatasetID, tbl flux.Table) error {
+			et, err := executetest.ConvertTable(tbl)
+			if err != nil {
+				return err
+			}
+			events = append(events, streamEvent{Kind: "process", Table: et})
+			return nil
+		},
+		UpdateWatermarkFn: func(id execute.DatasetID, ts execute.Time) error {
+			events = append(events, streamEvent{Kind: "watermark", Time: ts})
+			return nil
+		},
+		UpdateProcessingTimeFn: func(id execute.DatasetID, ts execute.Time) error {
+			events = append(events, streamEvent{Kind: "processingTime", Time: ts})
+			return nil
+		},
+		FinishFn: func(id execute.DatasetID, err error) {
+			ev := streamEvent{Kind: "finish"}
+			if err != nil {
+				ev.Err = err.Error()
+			}
+			events = append(events, ev)
+		},
+	})
+	ss.Run(ctx)
+	return events
+}
+
+// windowTable returns the table for the window [start, stop)
+// with rows of _time and _value.
+func windowTable(start, stop execute.Time, rows ...[]interface{}) *executetest.Table {
+	tbl := &executetest.Table{
+		KeyCols: []string{"_start", "_stop"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_start", Type: flux.TTime},
+			{Label: "_stop", Type: flux.TTime},
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TString},
+		},
+	}
+	for _, row := range rows {
+		tbl.Data = append(tbl.Data, append([]interface{}{start, stop}, row...))
+	}
+	tbl.Normalize()
+	return tbl
+}
+
+func TestStreamSource_Windows(t *testing.T) {
+	input := "a\nb\nc\nd\ne\n"
+	rc := ioutil.NopCloser(bytes.NewReader([]byte(input)))
+
+	// The time provider returns 0, 1, 2, ... on each call
+	// and lines are assigned to windows of 2ns.
+	// Reading "c" at 2 completes the first window.
+	// Reading "e" at 4 completes the second window.
+	// The final call at EOF returns 5 which is used
+	// as the processing time when the last window is sent.
+	want := []streamEvent{
+		{Kind: "process", Table: windowTable(0, 2,
+			[]interface{}{execute.Time(0), "a"},
+			[]interface{}{execute.Time(1), "b"},
+		)},
+		{Kind: "processingTime", Time: 2},
+		{Kind: "watermark", Time: 2},
+		{Kind: "process", Table: windowTable(2, 4,
+			[]interface{}{execute.Time(2), "c"},
+			[]interface{}{execute.Time(3), "d"},
+		)},
+		{Kind: "processingTime", Time: 4},
+		{Kind: "watermark", Time: 4},
+		{Kind: "process", Table: windowTable(4, 6,
+			[]interface{}{execute.Time(4), "e"},
+		)},
+		{Kind: "processingTime", Time: 5},
+		{Kind: "watermark", Time: 6},
+		{Kind: "finish"},
+	}
+
+	got := runStreamSource(t, context.Background(), rc, 2)
+	for _, ev := range got {
+		if ev.Table != nil {
+			ev.Table.Normalize()
+		}
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected events -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestStreamSource_Cancel(t *testing.T) {
+	// A pipe that is never written to simulates
+	// an idle connection that never closes.
+	r, w := io.Pipe()
+	defer func() { _ = w.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got := runStreamSource(t, ctx, r, 2)
+	want := []streamEvent{
+		{Kind: "finish", Err: context.Canceled.Error()},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected events -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestStreamSource_OpenConnection(t *testing.T) {
+	// The connection stays open between the writes so each
+	// window must be sent before the connection is closed.
+	r, w := io.Pipe()
+
+	processed := make(chan *executetest.Table)
+	ss := newStreamSource(r, values.ConvertDurationNsecs(2), &mock.AscendingTimeProvider{}, executetest.RandomDatasetID(), executetest.UnlimitedAllocator)
+	ss.tick = time.Hour
+	ss.AddTransformation(&mock.Transformation{
+		ProcessFn: func(id execute.DatasetID, tbl flux.Table) error {
+			et, err := executetest.ConvertTable(tbl)
+			if err != nil {
+				return err
+			}
+			et.Normalize()
+			processed <- et
+			return nil
+		},
+		UpdateWatermarkFn: func(id execute.DatasetID, ts execute.Time) error {
+			return nil
+		},
+		UpdateProcessingTimeFn: func(id execute.DatasetID, ts execute.Time) error {
+			return nil
+		},
+		FinishFn: func(id execute.DatasetID, err error) {
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			close(processed)
+		},
+	})
+	go ss.Run(context.Background())
+
+	write := func(s string) {
+		t.Helper()
+		if _, err := io.WriteString(w, s); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	read := func(want *executetest.Table) {
+		t.Helper()
+		select {
+		case got, ok := <-processed:
+			if !ok {
+				t.Fatal("source finished before the window was sent")
+			}
+			if !cmp.Equal(want, got) {
+				t.Errorf("unexpected table -want/+got:\n%s", cmp.Diff(want, got))
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the window")
+		}
+	}
+
+	// Reading "c" at 2 completes the first window.
+	write("a\nb\nc\n")
+	read(windowTable(0, 2,
+		[]interface{}{execute.Time(0), "a"},
+		[]interface{}{execute.Time(1), "b"},
+	))
+
+	// Reading "e" at 4 completes the second window.
+	write("d\ne\n")
+	read(windowTable(2, 4,
+		[]interface{}{execute.Time(2), "c"},
+		[]interface{}{execute.Time(3), "d"},
+	))
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	read(windowTable(4, 6,
+		[]interface{}{execute.Time(4), "e"},
+	))
+	if _, ok := <-processed; ok {
+		t.Fatal("unexpected table after the connection was closed")
+	}
+}
@@ -3,6 +3,8 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
@@ -11,6 +13,8 @@ import (
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
 	_ "github.com/lib/pq"
 	_ "github.com/vertica/vertica-sql-go"
 )
@@ -21,9 +25,12 @@ const FromSQLKind = "fromSQL"
 const layout = "2006-01-02 15:04:05.999999999"
 
 type FromSQLOpSpec struct {
-	DriverName     string `json:"driverName,omitempty"`
-	DataSourceName string `json:"dataSourceName,omitempty"`
-	Query          string `json:"query,omitempty"`
+	DriverName     string        `json:"driverName,omitempty"`
+	DataSourceName string        `json:"dataSourceName,omitempty"`
+	Query          string        `json:"query,omitempty"`
+	Params         []interface{} `json:"params,omitempty"`
+	GroupBy        []string      `json:"groupBy,omitempty"`
+	Cache          bool          `json:"cache,omitempty"`
 }
 
 func init() {
@@ -32,6 +39,7 @@ func init() {
 	flux.RegisterOpSpec(FromSQLKind, newFromSQLOp)
 	plan.RegisterProcedureSpec(FromSQLKind, newFromSQLProcedure, FromSQLKind)
 	execute.RegisterSource(FromSQLKind, createFromSQLSource)
+	plan.RegisterCostBasedRules(PushDownGroupRule{})
 }
 
 func createFromSQLOpSpec(args flux.Arguments, administration *flux.Administration) (flux.OperationSpec, error) {
@@ -52,9 +60,63 @@ func createFromSQLOpSpec(args flux.Arguments, administration *flux.Administratio
 	} else {
 		spec.Query = query
 	}
+	if params, ok := args.Get("params"); ok {
+		p, err := queryParams(params.Array())
+		if err != nil {
+			return nil, err
+		}
+		spec.Params = p
+	}
+	if groupBy, ok, err := args.GetArray("groupBy", semantic.String); err != nil {
+		return nil, err
+	} else if ok {
+		spec.GroupBy = make([]string, groupBy.Len())
+		groupBy.Range(func(i int, v values.Value) {
+			spec.GroupBy[i] = v.Str()
+		})
+	}
+	if cache, ok, err := args.GetBool("cache"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Cache = cache
+	}
 	return spec, nil
 }
 
+// queryParams converts the values of the params argument
+// to the arguments of the query.
+func queryParams(params values.Array) ([]interface{}, error) {
+	args := make([]interface{}, params.Len())
+	var err error
+	params.Range(func(i int, v values.Value) {
+		if err != nil || v.IsNull() {
+			return
+		}
+		switch v.Type().Nature() {
+		case semantic.String:
+			args[i] = v.Str()
+		case semantic.Int:
+			args[i] = v.Int()
+		case semantic.UInt:
+			args[i] = v.UInt()
+		case semantic.Float:
+			args[i] = v.Float()
+		case semantic.Bool:
+			args[i] = v.Bool()
+		case semantic.Time:
+			args[i] = v.Time().Time()
+		case semantic.Bytes:
+			args[i] = v.Bytes()
+		default:
+			err = errors.Newf(codes.Invalid, "unsupported query parameter type %v", v.Type())
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
 func newFromSQLOp() flux.OperationSpec {
 	return new(FromSQLOpSpec)
 }
@@ -68,6 +130,9 @@ type FromSQLProcedureSpec struct {
 	DriverName     string
 	DataSourceName string
 	Query          string
+	Params         []interface{}
+	GroupBy        []string
+	Cache          bool
 }
 
 func newFromSQLProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
@@ -80,6 +145,9 @@ func newFromSQLProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.Pr
 		DriverName:     spec.DriverName,
 		DataSourceName: spec.DataSourceName,
 		Query:          spec.Query,
+		Params:         spec.Params,
+		GroupBy:        spec.GroupBy,
+		Cache:          spec.Cache,
 	}, nil
 }
 
@@ -92,9 +160,63 @@ func (s *FromSQLProcedureSpec) Copy() plan.ProcedureSpec {
 	ns.DriverName = s.DriverName
 	ns.DataSourceName = s.DataSourceName
 	ns.Query = s.Query
+	ns.Cache = s.Cache
+	if s.Params != nil {
+		ns.Params = make([]interface{}, len(s.Params))
+		copy(ns.Params, s.Params)
+	}
+	if s.GroupBy != nil {
+		ns.GroupBy = make([]string, len(s.GroupBy))
+		copy(ns.GroupBy, s.GroupBy)
+	}
 	return ns
 }
 
+// Cost estimates the number of rows returned by the query.
+// The estimate is only known when the query limits its rows.
+func (s *FromSQLProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	return plan.Cost{}, plan.Statistics{Cardinality: estimateQueryRows(s.Query)}
+}
+
+// EstimateStatistics asks the database to estimate the number of rows
+// returned by the query. A LIMIT or TOP clause bounds the estimate.
+// The estimate is unknown when the database does not report one.
+func (s *FromSQLProcedureSpec) EstimateStatistics(ctx context.Context, inStats []plan.Statistics) plan.Statistics {
+	n, ok := explainQueryRows(ctx, s)
+	if !ok {
+		return plan.Statistics{}
+	}
+	if limit := estimateQueryRows(s.Query); limit > 0 && limit < n {
+		n = limit
+	}
+	return plan.Statistics{Cardinality: n}
+}
+
+// Fingerprint writes the query and the database it is run against.
+// The contents of a database can change between queries so the
+// results are only cached when the cache parameter is set.
+func (s *FromSQLProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	if !s.Cache {
+		return false
+	}
+	f.String(s.DriverName)
+	f.String(s.DataSourceName)
+	f.String(s.Query)
+	f.Int(int64(len(s.Params)))
+	for _, p := range s.Params {
+		switch p := p.(type) {
+		case time.Time:
+			f.String("time:" + p.Format(time.RFC3339Nano))
+		case []byte:
+			f.String("bytes:" + string(p))
+		default:
+			f.String(fmt.Sprintf("%T:%v", p, p))
+		}
+	}
+	f.Strings(s.GroupBy)
+	return true
+}
+
 func createFromSQLSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	spec, ok := prSpec.(*FromSQLProcedureSpec)
 	if !ok {
@@ -136,24 +258,17 @@ func createFromSQLSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a ex
 		return nil, errors.Newf(codes.Invalid, "sql driver %s not supported", spec.DriverName)
 	}
 
-	readFn := func(ctx context.Context, rows *sql.Rows) (flux.Table, error) {
-		reader, err := newRowReader(rows)
-		if err != nil {
-			_ = rows.Close()
-			return nil, err
-		}
-		return read(ctx, reader, a.Allocator())
-	}
-	iterator := &sqlIterator{spec: spec, id: dsid, read: readFn}
+	iterator := &sqlIterator{spec: spec, id: dsid, newRowReader: newRowReader, alloc: a.Allocator()}
 	return execute.CreateSourceFromIterator(iterator, dsid)
 }
 
 var _ execute.SourceIterator = (*sqlIterator)(nil)
 
 type sqlIterator struct {
-	spec *FromSQLProcedureSpec
-	id   execute.DatasetID
-	read func(ctx context.Context, rows *sql.Rows) (flux.Table, error)
+	spec         *FromSQLProcedureSpec
+	id           execute.DatasetID
+	newRowReader func(rows *sql.Rows) (execute.RowReader, error)
+	alloc        memory.Allocator
 }
 
 func (c *sqlIterator) connect(ctx context.Context) (*sql.DB, error) {
@@ -176,49 +291,89 @@ func (c *sqlIterator) Do(ctx context.Context, f func(flux.Table) error) error {
 	}
 	defer func() { _ = db.Close() }()
 
-	rows, err := db.QueryContext(ctx, c.spec.Query)
+	rows, err := db.QueryContext(ctx, c.spec.Query, c.spec.Params...)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = rows.Close() }()
 
-	table, err := c.read(ctx, rows)
+	reader, err := c.newRowReader(rows)
 	if err != nil {
 		return err
 	}
-	return f(table)
-}
-
-// read will use the RowReader to construct a flux.Table.
-func read(ctx context.Context, reader execute.RowReader, alloc memory.Allocator) (flux.Table, error) {
 	// Ensure that the reader is always freed so the underlying
 	// cursor can be returned.
 	defer func() { _ = reader.Close() }()
 
-	groupKey := execute.NewGroupKey(nil, nil)
-	builder := execute.NewColListTableBuilder(groupKey, alloc)
-	for i, dataType := range reader.ColumnTypes() {
-		if _, err := builder.AddCol(flux.ColMeta{Label: reader.ColumnNames()[i], Type: dataType}); err != nil {
-			return nil, err
-		}
-	}
-	for reader.Next() {
-		row, err := reader.GetNextRow()
-		if err != nil {
-			return nil, err
-		}
-
-		for i, col := range row {
-			if err := builder.AppendValue(i, col); err != nil {
-				return nil, err
+	// The rows are streamed from the cursor into the tables
+	// as they are read so the result set is never buffered.
+	// Each table is read before the cursor is advanced or closed.
+	if len(c.spec.GroupBy) > 0 {
+		err = readGroups(ctx, reader, c.spec.GroupBy, c.alloc, f)
+	} else {
+		var table *streamTable
+		if table, err = read(ctx, reader, c.alloc); err == nil {
+			if err = f(table); err == nil {
+				err = table.wait(ctx)
 			}
 		}
 	}
+	if err != nil {
+		return err
+	}
 
 	// An error may have been encountered while reading.
 	// This will get reported when we go to close the reader.
-	if err := reader.Close(); err != nil {
+	return reader.Close()
+}
+
+// read returns a table with the rows of the reader.
+// The rows are read in chunks when the table is read.
+func read(ctx context.Context, reader execute.RowReader, alloc memory.Allocator) (*streamTable, error) {
+	stream, err := newRowStream(reader, nil)
+	if err != nil {
+		return nil, err
+	}
+	row, err := stream.peek()
+	if err != nil {
 		return nil, err
 	}
-	return builder.Table()
+	return newStreamTable(stream.groupKey(row), stream, alloc, row == nil), nil
+}
+
+// readGroups calls f with a table for each group of rows of the reader.
+// Rows with the same group key must be next to each other.
+// Each table must be read or discarded with Done before
+// the next table is passed to f.
+func readGroups(ctx context.Context, reader execute.RowReader, groupBy []string, alloc memory.Allocator, f func(flux.Table) error) error {
+	stream, err := newRowStream(reader, groupBy)
+	if err != nil {
+		return err
+	}
+	seen := execute.NewRandomAccessGroupLookup()
+	for {
+		tbl, err := stream.nextTable(alloc)
+		if err != nil {
+			return err
+		} else if tbl == nil {
+			return nil
+		}
+		if _, ok := seen.Lookup(tbl.key); ok {
+			return errors.Newf(codes.Invalid, "found rows for group key %v after other groups, the query must order its results by the groupBy columns", tbl.key)
+		}
+		seen.Set(tbl.key, true)
+		if err := f(tbl); err != nil {
+			return err
+		}
+		// The table is read asynchronously so wait until it
+		// is done before moving on to the next group.
+		if err := tbl.wait(ctx); err != nil {
+			return err
+		}
+		// Discard any rows that were not read so the
+		// next group starts at the next row.
+		if err := stream.skipGroup(tbl.key); err != nil {
+			return err
+		}
+	}
 }
@@ -1,10 +1,19 @@
 package sql
 
 import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/dependencies/url"
 	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/execute/table/static"
+	"github.com/influxdata/flux/memory"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 func TestFromSqlUrlValidation(t *testing.T) {
@@ -258,3 +267,158 @@ func TestFromSqliteUrlValidation(t *testing.T) {
 	}
 	testCases.Run(t, createFromSQLSource)
 }
+
+func TestReadGroups(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	if _, err := db.Exec(`CREATE TABLE t (host TEXT, v INTEGER);
+		INSERT INTO t (host, v) VALUES ('a', 1), ('a', 2), ('a', 3), ('b', 4), ('c', 5), ('b', 6)`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read the tables in chunks of two rows.
+	defer func(n int) { readChunkSize = n }(readChunkSize)
+	readChunkSize = 2
+
+	readTables := func(t *testing.T, query string, args ...interface{}) (table.Iterator, []int, error) {
+		t.Helper()
+		rows, err := db.QueryContext(context.Background(), query, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = rows.Close() }()
+		reader, err := NewSqliteRowReader(rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var (
+			tables table.Iterator
+			chunks []int
+		)
+		err = readGroups(context.Background(), reader, []string{"host"}, memory.DefaultAllocator, func(tbl flux.Table) error {
+			// The table for host b is never read.
+			if tbl.Key().ValueString(0) == "b" {
+				tbl.Done()
+				return nil
+			}
+			chunks = append(chunks, 0)
+			buffered, err := table.Copy(&chunkCounter{Table: tbl, n: &chunks[len(chunks)-1]})
+			if err != nil {
+				return err
+			}
+			tables = append(tables, buffered)
+			return nil
+		})
+		return tables, chunks, err
+	}
+
+	t.Run("ordered", func(t *testing.T) {
+		got, chunks, err := readTables(t, "SELECT host, v FROM t WHERE v < ? ORDER BY host, v", int64(6))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := static.TableGroup{
+			static.Table{
+				static.StringKey("host", "a"),
+				static.Ints("v", 1, 2, 3),
+			},
+			static.Table{
+				static.StringKey("host", "c"),
+				static.Ints("v", 5),
+			},
+		}
+		if diff := table.Diff(want, got); diff != "" {
+			t.Fatalf("unexpected diff -want/+got:\n%s", diff)
+		}
+		if want, got := []int{2, 1}, chunks; len(want) != len(got) || want[0] != got[0] || want[1] != got[1] {
+			t.Fatalf("unexpected chunks: want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("asynchronous", func(t *testing.T) {
+		rows, err := db.QueryContext(context.Background(), "SELECT host, v FROM t ORDER BY host, v")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = rows.Close() }()
+		reader, err := NewSqliteRowReader(rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Read each table after f has returned
+		// like a downstream transport would.
+		type result struct {
+			tbl flux.Table
+			err error
+		}
+		var (
+			wg      sync.WaitGroup
+			results = make(chan result, 3)
+		)
+		err = readGroups(context.Background(), reader, []string{"host"}, memory.DefaultAllocator, func(tbl flux.Table) error {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				buffered, err := table.Copy(tbl)
+				results <- result{tbl: buffered, err: err}
+			}()
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Wait()
+		close(results)
+
+		var got table.Iterator
+		for r := range results {
+			if r.err != nil {
+				t.Fatal(r.err)
+			}
+			got = append(got, r.tbl)
+		}
+
+		want := static.TableGroup{
+			static.Table{
+				static.StringKey("host", "a"),
+				static.Ints("v", 1, 2, 3),
+			},
+			static.Table{
+				static.StringKey("host", "b"),
+				static.Ints("v", 4, 6),
+			},
+			static.Table{
+				static.StringKey("host", "c"),
+				static.Ints("v", 5),
+			},
+		}
+		if diff := table.Diff(want, got); diff != "" {
+			t.Fatalf("unexpected diff -want/+got:\n%s", diff)
+		}
+	})
+
+	t.Run("unordered", func(t *testing.T) {
+		_, _, err := readTables(t, "SELECT host, v FROM t ORDER BY v")
+		if err == nil || !strings.Contains(err.Error(), "must order its results by the groupBy columns") {
+			t.Fatalf("expected ordering error, got %v", err)
+		}
+	})
+}
+
+// chunkCounter counts the column readers of a table.
+type chunkCounter struct {
+	flux.Table
+	n *int
+}
+
+func (c *chunkCounter) Do(f func(flux.ColReader) error) error {
+	return c.Table.Do(func(cr flux.ColReader) error {
+		*c.n++
+		return f(cr)
+	})
+}
@@ -0,0 +1,129 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/runtime"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestFromSQLite3_Query(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "from.db")
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	if _, err := db.Exec(`CREATE TABLE t (host TEXT, v INTEGER);
+		INSERT INTO t (host, v) VALUES ('a', 1), ('a', 2), ('b', 3), ('c', 4), ('b', 5), ('c', 6)`); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name  string
+		query string
+		want  []*executetest.Table
+	}{
+		{
+			name: "ungrouped",
+			query: `import "sql"
+sql.from(driverName: "sqlite3", dataSourceName: "` + dsn + `", query: "SELECT host, v FROM t")
+	|> filter(fn: (r) => r.v > 1)
+	|> sum(column: "v")`,
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "v", Type: flux.TInt},
+				},
+				Data: [][]interface{}{
+					{int64(20)},
+				},
+			}},
+		},
+		{
+			name: "grouped",
+			query: `import "sql"
+sql.from(driverName: "sqlite3", dataSourceName: "` + dsn + `", query: "SELECT host, v FROM t ORDER BY host, v", groupBy: ["host"])
+	|> sum(column: "v")`,
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "host", Type: flux.TString},
+						{Label: "v", Type: flux.TInt},
+					},
+					Data: [][]interface{}{
+						{"a", int64(3)},
+					},
+				},
+				{
+					KeyCols: []string{"host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "host", Type: flux.TString},
+						{Label: "v", Type: flux.TInt},
+					},
+					Data: [][]interface{}{
+						{"b", int64(8)},
+					},
+				},
+				{
+					KeyCols: []string{"host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "host", Type: flux.TString},
+						{Label: "v", Type: flux.TInt},
+					},
+					Data: [][]interface{}{
+						{"c", int64(10)},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			prog, err := lang.Compile(tc.query, runtime.Default, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := flux.NewDefaultDependencies().Inject(context.Background())
+			query, err := prog.Start(ctx, &memory.ResourceAllocator{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer query.Done()
+
+			var got []*executetest.Table
+			for res := range query.Results() {
+				if err := res.Tables().Do(func(tbl flux.Table) error {
+					et, err := executetest.ConvertTable(tbl)
+					if err != nil {
+						return err
+					}
+					got = append(got, et)
+					return nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			query.Done()
+			if err := query.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			executetest.NormalizeTables(got)
+			executetest.NormalizeTables(tc.want)
+			if !cmp.Equal(tc.want, got) {
+				t.Fatalf("unexpected tables -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
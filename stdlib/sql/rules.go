@@ -0,0 +1,194 @@
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/stdlib/universe"
+)
+
+// groupPushDownMinRows is the estimated number of rows sql.from must read
+// before grouping is pushed down into the query. Smaller results are grouped
+// by flux so the database is not asked to sort them. A query whose number
+// of rows cannot be estimated by the database is grouped by flux.
+const groupPushDownMinRows = 10000
+
+var (
+	limitClause = regexp.MustCompile(`(?is)\bLIMIT\s+(\d+)\s*;?\s*$`)
+	topClause   = regexp.MustCompile(`(?is)^\s*SELECT\s+TOP\s+(\d+)\b`)
+	selectList  = regexp.MustCompile(`(?is)^\s*SELECT\s+(.+?)\s+FROM\s`)
+	selectItem  = regexp.MustCompile(`(?is)^(?:[A-Za-z_][A-Za-z0-9_]*\.)?([A-Za-z_][A-Za-z0-9_]*)(?:\s+AS\s+([A-Za-z_][A-Za-z0-9_]*))?$`)
+)
+
+// explainQueryRows asks the database to estimate the number of rows
+// returned by the query from the statistics of its tables.
+// Only postgres reports an estimate.
+func explainQueryRows(ctx context.Context, spec *FromSQLProcedureSpec) (int64, bool) {
+	switch spec.DriverName {
+	case "postgres", "sqlmock":
+	default:
+		return 0, false
+	}
+
+	// Planning must not connect to a database
+	// that the query would not be allowed to read.
+	validator, err := flux.GetDependencies(ctx).URLValidator()
+	if err != nil {
+		return 0, false
+	}
+	if err := validateDataSource(validator, spec.DriverName, spec.DataSourceName); err != nil {
+		return 0, false
+	}
+	db, err := getOpenFunc(spec.DriverName, spec.DataSourceName)()
+	if err != nil {
+		return 0, false
+	}
+	defer func() { _ = db.Close() }()
+
+	var data string
+	if err := db.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+spec.Query, spec.Params...).Scan(&data); err != nil {
+		return 0, false
+	}
+	var explain []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(data), &explain); err != nil || len(explain) != 1 {
+		return 0, false
+	}
+	return int64(explain[0].Plan.Rows), true
+}
+
+// estimateQueryRows estimates the number of rows returned by the query
+// from its LIMIT or TOP clause. It returns zero when the query
+// does not limit the number of rows.
+func estimateQueryRows(query string) int64 {
+	for _, re := range []*regexp.Regexp{limitClause, topClause} {
+		if m := re.FindStringSubmatch(query); m != nil {
+			n, err := strconv.ParseInt(m[1], 10, 64)
+			if err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// queryColumns returns the names of the columns selected by the query
+// when the select list only contains column names.
+func queryColumns(query string) ([]string, bool) {
+	m := selectList.FindStringSubmatch(query)
+	if m == nil {
+		return nil, false
+	}
+	var columns []string
+	for _, item := range strings.Split(m[1], ",") {
+		im := selectItem.FindStringSubmatch(strings.TrimSpace(item))
+		if im == nil {
+			return nil, false
+		}
+		if im[2] != "" {
+			columns = append(columns, im[2])
+		} else {
+			columns = append(columns, im[1])
+		}
+	}
+	return columns, true
+}
+
+// supportsGroupPushDown reports whether rows with equal values are
+// ordered next to each other when the driver orders by a column.
+// Databases that compare strings with case insensitive collations
+// by default, or that change the case of unquoted identifiers, are excluded.
+// So are databases that cannot estimate the number of rows of a query.
+func supportsGroupPushDown(driverName string) bool {
+	switch driverName {
+	case "postgres", "sqlmock":
+		return true
+	default:
+		return false
+	}
+}
+
+// PushDownGroupRule pushes a group that follows sql.from into the query
+// by ordering the results by the group columns and reading them with groupBy.
+// The rule is cost based: the group is only pushed down when the database
+// estimates that sql.from reads enough rows that sorting them in the
+// database is cheaper than grouping them in flux.
+type PushDownGroupRule struct{}
+
+func (PushDownGroupRule) Name() string {
+	return "sql.PushDownGroupRule"
+}
+
+func (PushDownGroupRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.GroupKind, plan.Pat(FromSQLKind))
+}
+
+func (PushDownGroupRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	fromNode := node.Predecessors()[0]
+	if len(fromNode.Successors()) != 1 {
+		return node, false, nil
+	}
+	fromSpec := fromNode.ProcedureSpec().(*FromSQLProcedureSpec)
+	groupSpec := node.ProcedureSpec().(*universe.GroupProcedureSpec)
+	if len(fromSpec.GroupBy) > 0 || !supportsGroupPushDown(fromSpec.DriverName) ||
+		groupSpec.GroupMode != flux.GroupModeBy || len(groupSpec.GroupKeys) == 0 {
+		return node, false, nil
+	}
+
+	if stats := plan.EstimateStatistics(ctx, fromNode); !stats.Known() || stats.Cardinality < groupPushDownMinRows {
+		return node, false, nil
+	}
+
+	// The group key columns must be known to be in the result
+	// because group ignores missing columns while the database does not.
+	// They are ordered as they are in the result, like the group key made by group.
+	columns, ok := queryColumns(fromSpec.Query)
+	if !ok {
+		return node, false, nil
+	}
+	index := make(map[string]int, len(columns))
+	for i, c := range columns {
+		index[c] = i
+	}
+	groupBy := make([]string, 0, len(groupSpec.GroupKeys))
+	for _, key := range groupSpec.GroupKeys {
+		if _, ok := index[key]; !ok {
+			return node, false, nil
+		}
+		groupBy = append(groupBy, key)
+	}
+	sort.SliceStable(groupBy, func(i, j int) bool {
+		return index[groupBy[i]] < index[groupBy[j]]
+	})
+
+	quoteIdent, err := getQuoteIdentFunc(fromSpec.DriverName)
+	if err != nil {
+		return node, false, nil
+	}
+	orderBy := make([]string, len(groupBy))
+	for i, key := range groupBy {
+		orderBy[i] = quoteIdent(key)
+	}
+
+	newSpec := fromSpec.Copy().(*FromSQLProcedureSpec)
+	newSpec.Query = fmt.Sprintf("SELECT * FROM (%s) AS flux_group ORDER BY %s",
+		strings.TrimRight(strings.TrimSpace(fromSpec.Query), ";"),
+		strings.Join(orderBy, ", "),
+	)
+	newSpec.GroupBy = groupBy
+	n, err := plan.MergeToPhysicalNode(node, fromNode, newSpec)
+	if err != nil {
+		return nil, false, err
+	}
+	return n, true, nil
+}
@@ -0,0 +1,130 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+	"github.com/influxdata/flux/stdlib/sql"
+	"github.com/influxdata/flux/stdlib/universe"
+)
+
+func TestPushDownGroupRule(t *testing.T) {
+	// mockDSN returns the data source of a database
+	// that estimates the query returns n rows.
+	mockDSN := func(name string, n int) string {
+		dsn := "sqlmock://rules@localhost/" + name
+		_, mock, _ := sqlmock.NewWithDSN(dsn)
+		query := mock.ExpectQuery(`EXPLAIN \(FORMAT JSON\)`)
+		if n < 0 {
+			query.WillReturnError(errors.New("permission denied"))
+		} else {
+			query.WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).
+				AddRow(fmt.Sprintf(`[{"Plan": {"Plan Rows": %d}}]`, n)))
+		}
+		return dsn
+	}
+	fromSpec := func(driverName, dsn, query string) *sql.FromSQLProcedureSpec {
+		return &sql.FromSQLProcedureSpec{
+			DriverName:     driverName,
+			DataSourceName: dsn,
+			Query:          query,
+		}
+	}
+	groupBy := func(keys ...string) *universe.GroupProcedureSpec {
+		return &universe.GroupProcedureSpec{
+			GroupMode: flux.GroupModeBy,
+			GroupKeys: keys,
+		}
+	}
+	before := func(from *sql.FromSQLProcedureSpec, group *universe.GroupProcedureSpec) *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plan.CreatePhysicalNode("from", from),
+				plan.CreatePhysicalNode("group", group),
+			},
+			Edges: [][2]int{
+				{0, 1},
+			},
+		}
+	}
+
+	largeDSN := mockDSN("large", 50000)
+	tcs := []plantest.RuleTestCase{
+		{
+			Name:   "large query",
+			Rules:  []plan.Rule{sql.PushDownGroupRule{}},
+			Before: before(fromSpec("sqlmock", largeDSN, "SELECT host, value, region FROM cpu;"), groupBy("region", "host")),
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("merged_from_group", &sql.FromSQLProcedureSpec{
+						DriverName:     "sqlmock",
+						DataSourceName: largeDSN,
+						Query:          `SELECT * FROM (SELECT host, value, region FROM cpu) AS flux_group ORDER BY "host", "region"`,
+						GroupBy:        []string{"host", "region"},
+					}),
+				},
+			},
+		},
+		{
+			Name:     "small query",
+			Rules:    []plan.Rule{sql.PushDownGroupRule{}},
+			Before:   before(fromSpec("sqlmock", mockDSN("small", 10), "SELECT host, value FROM cpu"), groupBy("host")),
+			NoChange: true,
+		},
+		{
+			Name:     "limited query",
+			Rules:    []plan.Rule{sql.PushDownGroupRule{}},
+			Before:   before(fromSpec("sqlmock", mockDSN("limited", 50000), "SELECT host, value FROM cpu LIMIT 10"), groupBy("host")),
+			NoChange: true,
+		},
+		{
+			Name:     "unknown size",
+			Rules:    []plan.Rule{sql.PushDownGroupRule{}},
+			Before:   before(fromSpec("sqlmock", mockDSN("unknown", -1), "SELECT host, value FROM cpu"), groupBy("host")),
+			NoChange: true,
+		},
+		{
+			Name:     "unknown columns",
+			Rules:    []plan.Rule{sql.PushDownGroupRule{}},
+			Before:   before(fromSpec("sqlmock", mockDSN("columns", 50000), "SELECT * FROM cpu"), groupBy("host")),
+			NoChange: true,
+		},
+		{
+			Name:     "missing column",
+			Rules:    []plan.Rule{sql.PushDownGroupRule{}},
+			Before:   before(fromSpec("sqlmock", mockDSN("missing", 50000), "SELECT host, value FROM cpu"), groupBy("region")),
+			NoChange: true,
+		},
+		{
+			Name:     "unsupported driver",
+			Rules:    []plan.Rule{sql.PushDownGroupRule{}},
+			Before:   before(fromSpec("sqlite3", "file::memory:", "SELECT host, value FROM cpu"), groupBy("host")),
+			NoChange: true,
+		},
+		{
+			Name:  "group except",
+			Rules: []plan.Rule{sql.PushDownGroupRule{}},
+			Before: before(fromSpec("sqlmock", mockDSN("except", 50000), "SELECT host, value FROM cpu"), &universe.GroupProcedureSpec{
+				GroupMode: flux.GroupModeExcept,
+				GroupKeys: []string{"value"},
+			}),
+			NoChange: true,
+		},
+	}
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	for _, tc := range tcs {
+		tc := tc
+		tc.Context = ctx
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package sql
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+)
+
+// readChunkSize is the maximum number of rows that are
+// read from the database into each column reader of a table.
+var readChunkSize = table.BufferSize
+
+// rowStream reads the rows of a RowReader one at a time so
+// they can be split into tables without buffering the result set.
+type rowStream struct {
+	reader execute.RowReader
+	cols   []flux.ColMeta
+	// keyCols are the indices of the group key columns.
+	keyCols []int
+	next    []values.Value
+	done    bool
+}
+
+// newRowStream creates a stream that groups the rows of the
+// reader by the columns in groupBy. Rows with the same group
+// key must be next to each other in the result set.
+func newRowStream(reader execute.RowReader, groupBy []string) (*rowStream, error) {
+	types, names := reader.ColumnTypes(), reader.ColumnNames()
+	cols := make([]flux.ColMeta, len(types))
+	for i, typ := range types {
+		cols[i] = flux.ColMeta{Label: names[i], Type: typ}
+	}
+	keyCols := make([]int, len(groupBy))
+	for i, label := range groupBy {
+		j := execute.ColIdx(label, cols)
+		if j < 0 {
+			return nil, errors.Newf(codes.Invalid, "groupBy column %q is not in the query result", label)
+		}
+		keyCols[i] = j
+	}
+	return &rowStream{
+		reader:  reader,
+		cols:    cols,
+		keyCols: keyCols,
+	}, nil
+}
+
+// peek returns the next row without consuming it.
+// It returns nil when there are no more rows.
+func (s *rowStream) peek() ([]values.Value, error) {
+	if s.next == nil && !s.done {
+		if !s.reader.Next() {
+			s.done = true
+			return nil, nil
+		}
+		row, err := s.reader.GetNextRow()
+		if err != nil {
+			return nil, err
+		}
+		s.next = row
+	}
+	return s.next, nil
+}
+
+// groupKey returns the group key of the row.
+func (s *rowStream) groupKey(row []values.Value) flux.GroupKey {
+	if len(s.keyCols) == 0 {
+		return execute.NewGroupKey(nil, nil)
+	}
+	cols := make([]flux.ColMeta, len(s.keyCols))
+	vs := make([]values.Value, len(s.keyCols))
+	for i, j := range s.keyCols {
+		cols[i], vs[i] = s.cols[j], row[j]
+	}
+	return execute.NewGroupKey(cols, vs)
+}
+
+// inGroup reports whether the row has the group key.
+func (s *rowStream) inGroup(row []values.Value, key flux.GroupKey) bool {
+	for i, j := range s.keyCols {
+		v, kv := row[j], key.Value(i)
+		if v.IsNull() || kv.IsNull() {
+			if v.IsNull() != kv.IsNull() {
+				return false
+			}
+			continue
+		}
+		if !v.Equal(kv) {
+			return false
+		}
+	}
+	return true
+}
+
+// readGroup appends up to n rows with the group key to the builder
+// and returns the number of rows that were appended.
+func (s *rowStream) readGroup(key flux.GroupKey, builder execute.TableBuilder, n int) (int, error) {
+	count := 0
+	for ; count < n; count++ {
+		row, err := s.peek()
+		if err != nil {
+			return count, err
+		} else if row == nil || !s.inGroup(row, key) {
+			break
+		}
+		for j, v := range row {
+			if err := builder.AppendValue(j, v); err != nil {
+				return count, err
+			}
+		}
+		s.next = nil
+	}
+	return count, nil
+}
+
+// skipGroup discards the rows with the group key.
+func (s *rowStream) skipGroup(key flux.GroupKey) error {
+	for {
+		row, err := s.peek()
+		if err != nil {
+			return err
+		} else if row == nil || !s.inGroup(row, key) {
+			return nil
+		}
+		s.next = nil
+	}
+}
+
+// streamTable is a table with the rows of a stream that have the
+// same group key. The rows are read from the database in chunks
+// when the table is read so that only one chunk is in memory.
+//
+// The table may be read after it has been passed downstream, so the
+// stream must not be advanced or closed until the table is done.
+type streamTable struct {
+	key    flux.GroupKey
+	stream *rowStream
+	alloc  memory.Allocator
+	empty  bool
+	used   int32
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func newStreamTable(key flux.GroupKey, stream *rowStream, alloc memory.Allocator, empty bool) *streamTable {
+	return &streamTable{
+		key:    key,
+		stream: stream,
+		alloc:  alloc,
+		empty:  empty,
+		done:   make(chan struct{}),
+	}
+}
+
+func (t *streamTable) Key() flux.GroupKey {
+	return t.key
+}
+
+func (t *streamTable) Cols() []flux.ColMeta {
+	return t.stream.cols
+}
+
+func (t *streamTable) Do(f func(flux.ColReader) error) error {
+	if !atomic.CompareAndSwapInt32(&t.used, 0, 1) {
+		return errors.New(codes.Internal, "table already read")
+	}
+	defer t.finish()
+	for {
+		builder := execute.NewColListTableBuilder(t.key, t.alloc)
+		for _, col := range t.stream.cols {
+			if _, err := builder.AddCol(col); err != nil {
+				return err
+			}
+		}
+		n, err := t.stream.readGroup(t.key, builder, readChunkSize)
+		if err != nil {
+			builder.Release()
+			return err
+		} else if n == 0 {
+			return nil
+		}
+		tbl, err := builder.Table()
+		if err != nil {
+			return err
+		}
+		if err := tbl.Do(f); err != nil {
+			return err
+		}
+	}
+}
+
+// Done discards the rows of the table that have not been read.
+func (t *streamTable) Done() {
+	if atomic.CompareAndSwapInt32(&t.used, 0, 1) {
+		_ = t.stream.skipGroup(t.key)
+		t.finish()
+	}
+}
+
+func (t *streamTable) finish() {
+	t.doneOnce.Do(func() { close(t.done) })
+}
+
+// wait blocks until the table has been read or discarded
+// or the context is canceled.
+func (t *streamTable) wait(ctx context.Context) error {
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *streamTable) Empty() bool {
+	return t.empty
+}
+
+// nextTable returns a table with the next group of rows in the stream.
+// It returns nil when there are no more rows.
+func (s *rowStream) nextTable(alloc memory.Allocator) (*streamTable, error) {
+	row, err := s.peek()
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return newStreamTable(s.groupKey(row), s, alloc, false), nil
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/execute/deadletter"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
 )
 
@@ -20,10 +22,14 @@ const (
 )
 
 type ToSQLOpSpec struct {
-	DriverName     string `json:"driverName,omitempty"`
-	DataSourceName string `json:"dataSourcename,omitempty"`
-	Table          string `json:"table,omitempty"`
-	BatchSize      int    `json:"batchSize,omitempty"`
+	DriverName     string   `json:"driverName,omitempty"`
+	DataSourceName string   `json:"dataSourcename,omitempty"`
+	Table          string   `json:"table,omitempty"`
+	BatchSize      int      `json:"batchSize,omitempty"`
+	ErrorMode      string   `json:"errorMode,omitempty"`
+	Mode           string   `json:"mode,omitempty"`
+	KeyColumns     []string `json:"keyColumns,omitempty"`
+	AddColumns     bool     `json:"addColumns,omitempty"`
 }
 
 func init() {
@@ -72,7 +78,34 @@ func (o *ToSQLOpSpec) ReadArgs(args flux.Arguments) error {
 		o.BatchSize = int(b)
 	}
 
-	return err
+	if o.ErrorMode, _, err = args.GetString("errorMode"); err != nil {
+		return err
+	}
+	if err := deadletter.ValidateMode(o.ErrorMode); err != nil {
+		return err
+	}
+
+	if o.Mode, _, err = args.GetString("mode"); err != nil {
+		return err
+	}
+	keyColumns, ok, err := args.GetArray("keyColumns", semantic.String)
+	if err != nil {
+		return err
+	}
+	if ok {
+		o.KeyColumns = make([]string, keyColumns.Len())
+		keyColumns.Range(func(i int, v values.Value) {
+			o.KeyColumns[i] = v.Str()
+		})
+	}
+	if err := validateMode(o.DriverName, o.Mode, o.KeyColumns); err != nil {
+		return err
+	}
+
+	if o.AddColumns, _, err = args.GetBool("addColumns"); err != nil {
+		return err
+	}
+	return nil
 }
 
 func createToSQLOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
@@ -107,6 +140,10 @@ func (o *ToSQLProcedureSpec) Copy() plan.ProcedureSpec {
 			DataSourceName: s.DataSourceName,
 			Table:          s.Table,
 			BatchSize:      s.BatchSize,
+			ErrorMode:      s.ErrorMode,
+			Mode:           s.Mode,
+			KeyColumns:     append([]string(nil), s.KeyColumns...),
+			AddColumns:     s.AddColumns,
 		},
 	}
 	return res
@@ -142,6 +179,10 @@ type ToSQLTransformation struct {
 	spec  *ToSQLProcedureSpec
 	db    *sql.DB
 	tx    *sql.Tx
+
+	// keysValidated is set once the table is known to have
+	// a unique index on the key columns.
+	keysValidated bool
 }
 
 func (t *ToSQLTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
@@ -162,8 +203,10 @@ func NewToSQLTransformation(d execute.Dataset, deps flux.Dependencies, cache exe
 	if err != nil {
 		return nil, err
 	}
+	// In the dead letter mode each batch is committed on its own
+	// so a failed batch does not roll back the batches before it.
 	var tx *sql.Tx
-	if supportsTx(spec.Spec.DriverName) {
+	if supportsTx(spec.Spec.DriverName) && spec.Spec.ErrorMode != deadletter.ModeDeadLetter {
 		tx, err = db.Begin()
 		if err != nil {
 			return nil, err
@@ -183,7 +226,24 @@ type idxType struct {
 	Type flux.ColType
 }
 
+// execer executes a statement in the transaction or,
+// when there is no transaction, directly on the database.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (t *ToSQLTransformation) execer() execer {
+	if t.tx != nil {
+		return t.tx
+	}
+	return t.db
+}
+
 func (t *ToSQLTransformation) Process(id execute.DatasetID, tbl flux.Table) (err error) {
+	if t.spec.Spec.ErrorMode == deadletter.ModeDeadLetter {
+		return t.processDeadLetter(tbl)
+	}
 	colNames, valStrings, valArgs, err := CreateInsertComponents(t, tbl)
 	if err != nil {
 		return err
@@ -197,6 +257,62 @@ func (t *ToSQLTransformation) Process(id execute.DatasetID, tbl flux.Table) (err
 	return err
 }
 
+// processDeadLetter writes the table one batch at a time and outputs
+// the rows of the batches that failed with their error.
+func (t *ToSQLTransformation) processDeadLetter(tbl flux.Table) error {
+	buf, err := execute.CopyTable(tbl)
+	if err != nil {
+		return err
+	}
+	defer buf.Done()
+
+	var rows deadletter.Rows
+	colNames, valStrings, valArgs, err := CreateInsertComponents(t, buf.Copy())
+	if err != nil {
+		// None of the rows were written, for example because the table
+		// has a column with an invalid type or the database table
+		// could not be created, so each row failed with the error.
+		n := 0
+		for i := 0; i < buf.BufferN(); i++ {
+			n += buf.Buffer(i).Len()
+		}
+		rows.AddRange(0, n, err)
+	}
+	offset := 0
+	for i := range valStrings {
+		n := len(valStrings[i])
+		vs, args := dedupKeys(t.spec.Spec, colNames, valStrings[i], valArgs[i])
+		query, err := insertQuery(t.spec.Spec, colNames, vs)
+		if err == nil && t.spec.Spec.DriverName != "sqlmock" {
+			_, err = t.db.Exec(query, args...)
+		}
+		if err != nil {
+			rows.AddRange(offset, offset+n, err)
+		}
+		offset += n
+	}
+	if rows.Empty() {
+		return nil
+	}
+
+	builder, created := t.cache.TableBuilder(buf.Key())
+	if created {
+		for _, col := range deadletter.Cols(buf.Cols()) {
+			if _, err := builder.AddCol(col); err != nil {
+				return err
+			}
+		}
+	}
+	offset = 0
+	return buf.Copy().Do(func(cr flux.ColReader) error {
+		if err := rows.AppendTo(cr, offset, builder); err != nil {
+			return err
+		}
+		offset += cr.Len()
+		return nil
+	})
+}
+
 func (t *ToSQLTransformation) UpdateWatermark(id execute.DatasetID, pt execute.Time) error {
 	return t.d.UpdateWatermark(pt)
 }
@@ -207,14 +323,16 @@ func (t *ToSQLTransformation) UpdateProcessingTime(id execute.DatasetID, pt exec
 
 func (t *ToSQLTransformation) Finish(id execute.DatasetID, err error) {
 	if supportsTx(t.spec.Spec.DriverName) {
-		var txErr error
-		if err == nil {
-			txErr = t.tx.Commit()
-		} else {
-			txErr = t.tx.Rollback()
-		}
-		if txErr != nil {
-			err = errors.Wrap(err, codes.Inherit, txErr)
+		if t.tx != nil {
+			var txErr error
+			if err == nil {
+				txErr = t.tx.Commit()
+			} else {
+				txErr = t.tx.Rollback()
+			}
+			if txErr != nil {
+				err = errors.Wrap(err, codes.Inherit, txErr)
+			}
 		}
 		if dbErr := t.db.Close(); dbErr != nil {
 			err = errors.Wrap(err, codes.Inherit, dbErr)
@@ -389,17 +507,31 @@ func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []
 		}
 	}
 
+	for _, k := range t.spec.Spec.KeyColumns {
+		if _, ok := labels[k]; !ok {
+			return nil, nil, nil, errors.Newf(codes.Invalid, "key column %q does not exist in the table", k)
+		}
+	}
+
 	// Creates the placeholders for values in the query
 	// eg: (?,?)
 	valuePlaceHolders := fmt.Sprintf("(%s)", strings.Join(questionMarks, ","))
 
-	builder, new := t.cache.TableBuilder(tbl.Key())
-	if new {
-		if err := execute.AddTableCols(tbl, builder); err != nil {
-			return nil, nil, nil, err
+	// In the dead letter mode only the rows that fail are output,
+	// and they are appended once the batches have been executed.
+	var builder execute.TableBuilder
+	if t.spec.Spec.ErrorMode != deadletter.ModeDeadLetter {
+		b, new := t.cache.TableBuilder(tbl.Key())
+		if new {
+			if err := execute.AddTableCols(tbl, b); err != nil {
+				return nil, nil, nil, err
+			}
 		}
+		builder = b
 	}
 
+	// The columns of the table are only added once.
+	added := false
 	if err := tbl.Do(func(er flux.ColReader) error {
 		l := er.Len()
 
@@ -432,17 +564,35 @@ func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []
 				// SAP HANA does not support INSERT/UPDATE batching via a single SQL command
 				batchSize = 1
 			} else {
+				// XXX: Items in `newSQLTableCols` should include _quoted column identifiers_, ref: influxdata/idpe#8689
+				defs := strings.Join(newSQLTableCols, ",")
+				// SQL Server and SAP HANA are handled above. MERGE does not
+				// need a key and HANA does not support the upsert modes.
+				if pk := primaryKeyDef(t.spec.Spec, quoteIdent, labels); pk != "" {
+					defs += "," + pk
+				}
 				q = fmt.Sprintf(
 					"CREATE TABLE IF NOT EXISTS %s (%s)",
 					quoteIdent(t.spec.Spec.Table),
-					// XXX: Items in `newSQLTableCols` should include _quoted column identifiers_, ref: influxdata/idpe#8689
-					strings.Join(newSQLTableCols, ","),
+					defs,
 				)
 			}
-			_, err = t.tx.Exec(q)
+			_, err = t.execer().Exec(q)
 			if err != nil {
 				return err
 			}
+			if !t.keysValidated {
+				if err := validateUniqueKey(t.execer(), t.spec.Spec, quoteIdent); err != nil {
+					return err
+				}
+				t.keysValidated = true
+			}
+			if t.spec.Spec.AddColumns && !added {
+				if err := addColumns(t.execer(), driverName, quoteIdent(t.spec.Spec.Table), colNames, newSQLTableCols); err != nil {
+					return err
+				}
+				added = true
+			}
 		}
 
 		for i := 0; i < l; i++ {
@@ -490,8 +640,10 @@ func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []
 				}
 			}
 
-			if err := execute.AppendRecord(i, er, builder); err != nil {
-				return err
+			if builder != nil {
+				if err := execute.AppendRecord(i, er, builder); err != nil {
+					return err
+				}
 			}
 
 			if (i != 0 && i%batchSize == 0) || (batchSize == 1) {
@@ -516,11 +668,32 @@ func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []
 
 // ExecuteQueries runs the SQL statements required to insert the new rows.
 func ExecuteQueries(tx *sql.Tx, s *ToSQLOpSpec, colNames []string, valueStrings *[]string, valueArgs *[]interface{}) (err error) {
-	concatValueStrings := strings.Join(*valueStrings, ",")
+	vs, args := dedupKeys(s, colNames, *valueStrings, *valueArgs)
+	query, err := insertQuery(s, colNames, vs)
+	if err != nil {
+		return err
+	}
+	if s.DriverName != "sqlmock" {
+		_, err := tx.Exec(query, args...)
+		if err != nil {
+			// this err which is extremely helpful as it comes from the SQL driver should be
+			// bubbled up further up the stack so user can see the issue
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return errors.Newf(codes.Aborted, "transaction failed (%s) while recovering from %s", err, rbErr)
+			}
+			return err
+		}
+	}
+	return err
+}
+
+// insertQuery returns the statement that writes a batch of rows.
+func insertQuery(s *ToSQLOpSpec, colNames []string, valueStrings []string) (string, error) {
+	concatValueStrings := strings.Join(valueStrings, ",")
 
 	quoteIdent, err := getQuoteIdentFunc(s.DriverName)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// PostgreSQL uses $n instead of ? for placeholders
@@ -539,11 +712,7 @@ func ExecuteQueries(tx *sql.Tx, s *ToSQLOpSpec, colNames []string, valueStrings
 	// N.B. identifiers that will be string formatted into SQL statements must be
 	// quoted/escaped, ref: influxdata/idpe#8689
 	quotedTable := quoteIdent(s.Table)
-	quotedColNames := make([]string, len(colNames))
-	for idx, name := range colNames {
-		quotedColNames[idx] = quoteIdent(name)
-	}
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", quotedTable, strings.Join(quotedColNames, ","), concatValueStrings)
+	query := writeStatement(s, quoteIdent, colNames, concatValueStrings)
 
 	if isMssqlDriver(s.DriverName) && mssqlCheckParameter(s.DataSourceName, mssqlIdentityInsertEnabled) {
 		// XXX: identifiers that will be string formatted into SQL statements must be quoted, ref: influxdata/idpe#8689
@@ -555,16 +724,5 @@ func ExecuteQueries(tx *sql.Tx, s *ToSQLOpSpec, colNames []string, valueStrings
 		epilogue := fmt.Sprintf("IF @tableHasIdentity = 1 BEGIN SET IDENTITY_INSERT %s OFF END", quotedTable)
 		query = strings.Join([]string{prologue, query, epilogue}, "; ")
 	}
-	if s.DriverName != "sqlmock" {
-		_, err := tx.Exec(query, *valueArgs...)
-		if err != nil {
-			// this err which is extremely helpful as it comes from the SQL driver should be
-			// bubbled up further up the stack so user can see the issue
-			if rbErr := tx.Rollback(); rbErr != nil {
-				return errors.Newf(codes.Aborted, "transaction failed (%s) while recovering from %s", err, rbErr)
-			}
-			return err
-		}
-	}
-	return err
+	return query, nil
 }
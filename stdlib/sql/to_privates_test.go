@@ -293,3 +293,157 @@ func assertErrorIsNil(t *testing.T, got error) {
 		t.Errorf("expected error to be nil, got %v", got)
 	}
 }
+
+func TestWriteStatement(t *testing.T) {
+	colNames := []string{"_time", "host", "_value"}
+	testCases := []struct {
+		driverName string
+		mode       string
+		want       string
+	}{
+		{
+			driverName: "postgres",
+			mode:       "insert",
+			want:       `INSERT INTO "t" ("_time","host","_value") VALUES (?,?,?)`,
+		},
+		{
+			driverName: "postgres",
+			mode:       "upsert",
+			want:       `INSERT INTO "t" ("_time","host","_value") VALUES (?,?,?) ON CONFLICT ("_time","host") DO UPDATE SET "_value" = EXCLUDED."_value"`,
+		},
+		{
+			driverName: "sqlite3",
+			mode:       "upsert",
+			want:       `INSERT INTO "t" ("_time","host","_value") VALUES (?,?,?) ON CONFLICT ("_time","host") DO UPDATE SET "_value" = EXCLUDED."_value"`,
+		},
+		{
+			driverName: "sqlite3",
+			mode:       "replace",
+			want:       `INSERT OR REPLACE INTO "t" ("_time","host","_value") VALUES (?,?,?)`,
+		},
+		{
+			driverName: "mysql",
+			mode:       "upsert",
+			want:       "INSERT INTO `t` (`_time`,`host`,`_value`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE `_value` = VALUES(`_value`)",
+		},
+		{
+			driverName: "mysql",
+			mode:       "replace",
+			want:       "REPLACE INTO `t` (`_time`,`host`,`_value`) VALUES (?,?,?)",
+		},
+		{
+			driverName: "sqlserver",
+			mode:       "upsert",
+			want:       `MERGE INTO "t" AS target USING (VALUES (?,?,?)) AS source ("_time","host","_value") ON target."_time" = source."_time" AND target."host" = source."host" WHEN MATCHED THEN UPDATE SET "_value" = source."_value" WHEN NOT MATCHED THEN INSERT ("_time","host","_value") VALUES (source."_time",source."host",source."_value");`,
+		},
+		{
+			driverName: "snowflake",
+			mode:       "upsert",
+			want:       `MERGE INTO "t" AS target USING (SELECT column1 AS "_time",column2 AS "host",column3 AS "_value" FROM VALUES (?,?,?)) AS source ON target."_time" = source."_time" AND target."host" = source."host" WHEN MATCHED THEN UPDATE SET "_value" = source."_value" WHEN NOT MATCHED THEN INSERT ("_time","host","_value") VALUES (source."_time",source."host",source."_value")`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.driverName+"/"+tc.mode, func(t *testing.T) {
+			s := &ToSQLOpSpec{
+				DriverName: tc.driverName,
+				Table:      "t",
+				Mode:       tc.mode,
+				KeyColumns: []string{"_time", "host"},
+			}
+			if err := validateMode(s.DriverName, s.Mode, s.KeyColumns); err != nil {
+				t.Fatal(err)
+			}
+			quoteIdent, err := getQuoteIdentFunc(tc.driverName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := writeStatement(s, quoteIdent, colNames, "(?,?,?)"); got != tc.want {
+				t.Errorf("unexpected statement -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestValidateMode(t *testing.T) {
+	if err := validateMode("postgres", "upsert", nil); err == nil {
+		t.Error("expected error for upsert without key columns")
+	}
+	if err := validateMode("vertica", "upsert", []string{"_time"}); err == nil {
+		t.Error("expected error for unsupported driver")
+	}
+	if err := validateMode("postgres", "merge", []string{"_time"}); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+	if err := validateMode("vertica", "insert", nil); err != nil {
+		t.Errorf("unexpected error for insert: %s", err)
+	}
+}
+
+func TestPrimaryKeyDef(t *testing.T) {
+	labels := map[string]idxType{
+		"_time": {Idx: 0, Type: flux.TTime},
+		"host":  {Idx: 1, Type: flux.TString},
+	}
+	testCases := []struct {
+		driverName string
+		mode       string
+		want       string
+	}{
+		{driverName: "postgres", mode: "insert", want: ""},
+		{driverName: "postgres", mode: "upsert", want: `PRIMARY KEY ("_time","host")`},
+		{driverName: "sqlite3", mode: "replace", want: `PRIMARY KEY ("_time","host")`},
+		{driverName: "mysql", mode: "upsert", want: "PRIMARY KEY (`_time`,`host`(255))"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.driverName+"/"+tc.mode, func(t *testing.T) {
+			s := &ToSQLOpSpec{
+				DriverName: tc.driverName,
+				Table:      "t",
+				Mode:       tc.mode,
+				KeyColumns: []string{"_time", "host"},
+			}
+			quoteIdent, err := getQuoteIdentFunc(tc.driverName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := primaryKeyDef(s, quoteIdent, labels); got != tc.want {
+				t.Errorf("unexpected primary key -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestDedupKeys(t *testing.T) {
+	colNames := []string{"host", "_value"}
+	valueStrings := []string{"(?,?)", "(?,?)", "(?,?)", "(?,?)"}
+	valueArgs := []interface{}{
+		"a", 1.0,
+		"b", 2.0,
+		"a", 3.0,
+		nil, 4.0,
+	}
+
+	s := &ToSQLOpSpec{Mode: "upsert", KeyColumns: []string{"host"}}
+	gotStrings, gotArgs := dedupKeys(s, colNames, valueStrings, valueArgs)
+	wantStrings := []string{"(?,?)", "(?,?)", "(?,?)"}
+	wantArgs := []interface{}{
+		"b", 2.0,
+		"a", 3.0,
+		nil, 4.0,
+	}
+	if !cmp.Equal(wantStrings, gotStrings) {
+		t.Errorf("unexpected value strings -want/+got:\n%s", cmp.Diff(wantStrings, gotStrings))
+	}
+	if !cmp.Equal(wantArgs, gotArgs) {
+		t.Errorf("unexpected value args -want/+got:\n%s", cmp.Diff(wantArgs, gotArgs))
+	}
+
+	// Rows are written as is in the insert mode.
+	s.Mode = "insert"
+	gotStrings, gotArgs = dedupKeys(s, colNames, valueStrings, valueArgs)
+	if !cmp.Equal(valueStrings, gotStrings) || !cmp.Equal(valueArgs, gotArgs) {
+		t.Error("expected rows to be unchanged in insert mode")
+	}
+}
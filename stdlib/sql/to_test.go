@@ -1,6 +1,9 @@
 package sql_test
 
 import (
+	"database/sql"
+	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -688,3 +691,231 @@ func TestToSQLite3_Process(t *testing.T) {
 		})
 	}
 }
+
+func TestToSQLite3_DeadLetter(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "deadletter.db")
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	if _, err := db.Exec(`CREATE TABLE "TestTable" ("_time" DATETIME, "tag" TEXT, "_value" FLOAT CHECK ("_value" >= 0))`); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := &fsql.ToSQLProcedureSpec{
+		Spec: &fsql.ToSQLOpSpec{
+			DriverName:     "sqlite3",
+			DataSourceName: dsn,
+			Table:          "TestTable",
+			BatchSize:      fsql.DefaultBatchSize,
+			ErrorMode:      "deadLetter",
+		},
+	}
+	data := []flux.Table{
+		&executetest.Table{
+			KeyCols: []string{"tag"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "tag", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(11), "a", 2.0},
+				{execute.Time(21), "a", 1.0},
+			},
+		},
+		&executetest.Table{
+			KeyCols: []string{"tag"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "tag", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(11), "b", 3.0},
+				{execute.Time(21), "b", -1.0},
+			},
+		},
+	}
+	// The rows of the batch with the invalid value are output with the error.
+	want := []*executetest.Table{{
+		KeyCols: []string{"tag"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "tag", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+			{Label: "_error", Type: flux.TString},
+		},
+		Data: [][]interface{}{
+			{execute.Time(11), "b", 3.0, "CHECK constraint failed: TestTable"},
+			{execute.Time(21), "b", -1.0, "CHECK constraint failed: TestTable"},
+		},
+	}}
+
+	executetest.ProcessTestHelper(
+		t,
+		data,
+		want,
+		nil,
+		func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+			tr, err := fsql.NewToSQLTransformation(d, dependenciestest.Default(), c, spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return tr
+		},
+	)
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM "TestTable"`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, n; want != got {
+		t.Errorf("unexpected number of rows written: want %d, got %d", want, got)
+	}
+}
+
+func TestToSQLite3_DeadLetterTableError(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "deadletter.db")
+	spec := &fsql.ToSQLProcedureSpec{
+		Spec: &fsql.ToSQLOpSpec{
+			DriverName:     "sqlite3",
+			DataSourceName: dsn,
+			Table:          "TestTable",
+			BatchSize:      fsql.DefaultBatchSize,
+			ErrorMode:      "deadLetter",
+			Mode:           "upsert",
+			KeyColumns:     []string{"id"},
+		},
+	}
+	data := []flux.Table{
+		&executetest.Table{
+			KeyCols: []string{"tag"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "tag", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(11), "a", 2.0},
+				{execute.Time(21), "a", 1.0},
+			},
+		},
+	}
+	// The table does not have the key column so none of its rows
+	// can be written and each row is output with the error.
+	want := []*executetest.Table{{
+		KeyCols: []string{"tag"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "tag", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+			{Label: "_error", Type: flux.TString},
+		},
+		Data: [][]interface{}{
+			{execute.Time(11), "a", 2.0, `key column "id" does not exist in the table`},
+			{execute.Time(21), "a", 1.0, `key column "id" does not exist in the table`},
+		},
+	}}
+
+	executetest.ProcessTestHelper(
+		t,
+		data,
+		want,
+		nil,
+		func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+			tr, err := fsql.NewToSQLTransformation(d, dependenciestest.Default(), c, spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return tr
+		},
+	)
+}
+
+func TestToSQLite3_UpsertUniqueKey(t *testing.T) {
+	testCases := []struct {
+		name    string
+		index   string
+		wantErr error
+	}{
+		{
+			name:  "unique index",
+			index: `CREATE UNIQUE INDEX "tag_idx" ON "TestTable" ("tag")`,
+		},
+		{
+			name:    "no unique index",
+			wantErr: errors.New(`mode "upsert" requires a primary key or unique index on the key columns [tag], but table "TestTable" does not have one`),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dsn := filepath.Join(t.TempDir(), "upsert.db")
+			db, err := sql.Open("sqlite3", dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = db.Close() }()
+			if _, err := db.Exec(`CREATE TABLE "TestTable" ("_time" DATETIME, "tag" TEXT, "_value" FLOAT)`); err != nil {
+				t.Fatal(err)
+			}
+			if tc.index != "" {
+				if _, err := db.Exec(tc.index); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			spec := &fsql.ToSQLProcedureSpec{
+				Spec: &fsql.ToSQLOpSpec{
+					DriverName:     "sqlite3",
+					DataSourceName: dsn,
+					Table:          "TestTable",
+					BatchSize:      fsql.DefaultBatchSize,
+					Mode:           "upsert",
+					KeyColumns:     []string{"tag"},
+				},
+			}
+			data := []flux.Table{
+				&executetest.Table{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "tag", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{execute.Time(11), "a", 2.0},
+						{execute.Time(21), "a", 1.0},
+					},
+				},
+			}
+
+			executetest.ProcessTestHelper(
+				t,
+				data,
+				nil,
+				tc.wantErr,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					tr, err := fsql.NewToSQLTransformation(d, dependenciestest.Default(), c, spec)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return tr
+				},
+			)
+			if tc.wantErr != nil {
+				return
+			}
+
+			var n int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM "TestTable"`).Scan(&n); err != nil {
+				t.Fatal(err)
+			}
+			if want, got := 1, n; want != got {
+				t.Errorf("unexpected number of rows written: want %d, got %d", want, got)
+			}
+		})
+	}
+}
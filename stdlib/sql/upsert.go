@@ -0,0 +1,339 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// The modes of sql.to that control what happens
+// to rows that already exist in the table.
+const (
+	// modeInsert inserts every row.
+	modeInsert = "insert"
+	// modeUpsert inserts new rows and updates the columns
+	// of the rows with the same key columns.
+	modeUpsert = "upsert"
+	// modeReplace inserts new rows and replaces
+	// the rows with the same key columns.
+	modeReplace = "replace"
+)
+
+// validateMode returns an error if the driver does not support the mode
+// or if the mode requires key columns that were not specified.
+func validateMode(driverName, mode string, keyColumns []string) error {
+	switch mode {
+	case "", modeInsert:
+		return nil
+	case modeUpsert, modeReplace:
+	default:
+		return errors.Newf(codes.Invalid, "invalid mode %q, must be %q, %q or %q", mode, modeInsert, modeUpsert, modeReplace)
+	}
+	switch {
+	case driverName == "postgres", driverName == "sqlmock", driverName == "mysql",
+		driverName == "sqlite3", driverName == "snowflake", isMssqlDriver(driverName):
+	default:
+		return errors.Newf(codes.Invalid, "mode %q is not supported for %s", mode, driverName)
+	}
+	if len(keyColumns) == 0 {
+		return errors.Newf(codes.Invalid, "mode %q requires keyColumns", mode)
+	}
+	return nil
+}
+
+// writeStatement returns the statement that writes the rows in values
+// to the table according to the mode of the spec. The values are a list
+// of tuples of placeholders for the columns.
+func writeStatement(s *ToSQLOpSpec, quoteIdent quoteIdentFunc, colNames []string, values string) string {
+	quotedTable := quoteIdent(s.Table)
+	quotedColNames := make([]string, len(colNames))
+	for idx, name := range colNames {
+		quotedColNames[idx] = quoteIdent(name)
+	}
+	columns := strings.Join(quotedColNames, ",")
+	if s.Mode == "" || s.Mode == modeInsert {
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", quotedTable, columns, values)
+	}
+
+	isKey := make(map[string]bool, len(s.KeyColumns))
+	for _, k := range s.KeyColumns {
+		isKey[k] = true
+	}
+	var keys, others []string
+	for _, name := range colNames {
+		if isKey[name] {
+			keys = append(keys, quoteIdent(name))
+		} else {
+			others = append(others, quoteIdent(name))
+		}
+	}
+
+	switch driverName := s.DriverName; {
+	case driverName == "mysql":
+		if s.Mode == modeReplace {
+			return fmt.Sprintf("REPLACE INTO %s (%s) VALUES %s", quotedTable, columns, values)
+		}
+		if len(others) == 0 {
+			// Updating a key column to its own value does nothing.
+			others = keys
+		}
+		set := make([]string, len(others))
+		for i, c := range others {
+			set[i] = fmt.Sprintf("%s = VALUES(%s)", c, c)
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s",
+			quotedTable, columns, values, strings.Join(set, ","))
+	case driverName == "snowflake", isMssqlDriver(driverName):
+		// Neither database supports replacing rows, so both
+		// modes update the columns that are written.
+		var source string
+		if driverName == "snowflake" {
+			// Snowflake names the columns of VALUES column1, column2 and so on.
+			aliases := make([]string, len(quotedColNames))
+			for i, c := range quotedColNames {
+				aliases[i] = fmt.Sprintf("column%d AS %s", i+1, c)
+			}
+			source = fmt.Sprintf("(SELECT %s FROM VALUES %s) AS source", strings.Join(aliases, ","), values)
+		} else {
+			source = fmt.Sprintf("(VALUES %s) AS source (%s)", values, columns)
+		}
+		on := make([]string, len(keys))
+		for i, c := range keys {
+			on[i] = fmt.Sprintf("target.%s = source.%s", c, c)
+		}
+		var matched string
+		if len(others) > 0 {
+			set := make([]string, len(others))
+			for i, c := range others {
+				set[i] = fmt.Sprintf("%s = source.%s", c, c)
+			}
+			matched = fmt.Sprintf(" WHEN MATCHED THEN UPDATE SET %s", strings.Join(set, ","))
+		}
+		inserted := make([]string, len(quotedColNames))
+		for i, c := range quotedColNames {
+			inserted[i] = "source." + c
+		}
+		query := fmt.Sprintf("MERGE INTO %s AS target USING %s ON %s%s WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)",
+			quotedTable, source, strings.Join(on, " AND "), matched, columns, strings.Join(inserted, ","))
+		if isMssqlDriver(driverName) {
+			// SQL Server requires MERGE to be terminated with a semicolon.
+			query += ";"
+		}
+		return query
+	default:
+		// PostgreSQL and SQLite share the ON CONFLICT clause.
+		// PostgreSQL does not support replacing rows so
+		// both modes update the columns that are written.
+		if s.Mode == modeReplace && driverName == "sqlite3" {
+			return fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES %s", quotedTable, columns, values)
+		}
+		if len(others) == 0 {
+			return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON CONFLICT (%s) DO NOTHING",
+				quotedTable, columns, values, strings.Join(keys, ","))
+		}
+		set := make([]string, len(others))
+		for i, c := range others {
+			set[i] = fmt.Sprintf("%s = EXCLUDED.%s", c, c)
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON CONFLICT (%s) DO UPDATE SET %s",
+			quotedTable, columns, values, strings.Join(keys, ","), strings.Join(set, ","))
+	}
+}
+
+// primaryKeyDef returns the primary key definition that is added to the
+// table when it is created, so the database can find the rows with the
+// same key columns. It returns an empty string if the mode does not
+// need a key.
+func primaryKeyDef(s *ToSQLOpSpec, quoteIdent quoteIdentFunc, labels map[string]idxType) string {
+	if s.Mode != modeUpsert && s.Mode != modeReplace {
+		return ""
+	}
+	keys := make([]string, len(s.KeyColumns))
+	for i, k := range s.KeyColumns {
+		keys[i] = quoteIdent(k)
+		if s.DriverName == "mysql" && labels[k].Type == flux.TString {
+			// MySQL can only index a prefix of TEXT columns.
+			keys[i] += "(255)"
+		}
+	}
+	return fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(keys, ","))
+}
+
+// uniqueIndexQuery returns the query that lists the columns of the primary
+// key and the unique indexes of a table as rows of the index name and the
+// column name, along with the arguments of the query. It returns an empty
+// query for the drivers that do not need a unique index to find the rows
+// with the same key columns.
+func uniqueIndexQuery(driverName, table string, quoteIdent quoteIdentFunc) (string, []interface{}) {
+	switch driverName {
+	case "postgres":
+		return `SELECT i.indexrelid::text, a.attname FROM pg_index i ` +
+			`JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey) ` +
+			`WHERE i.indrelid = $1::regclass AND i.indisunique AND i.indpred IS NULL`, []interface{}{quoteIdent(table)}
+	case "mysql":
+		return `SELECT INDEX_NAME, COLUMN_NAME FROM INFORMATION_SCHEMA.STATISTICS ` +
+			`WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND NON_UNIQUE = 0`, []interface{}{table}
+	case "sqlite3":
+		// An INTEGER PRIMARY KEY is the rowid of the table and does
+		// not have an index, so the primary key columns are listed too.
+		return `SELECT il.name, ii.name FROM pragma_index_list(?) AS il, pragma_index_info(il.name) AS ii ` +
+			`WHERE il."unique" = 1 ` +
+			`UNION ALL SELECT '', name FROM pragma_table_info(?) WHERE pk > 0`, []interface{}{table, table}
+	default:
+		// MERGE matches the rows with the key columns itself.
+		return "", nil
+	}
+}
+
+// validateUniqueKey returns an error if the table does not have a primary
+// key or unique index on exactly the key columns. PostgreSQL and SQLite
+// reject ON CONFLICT without such an index, and MySQL only updates rows that
+// conflict on a unique index, so the rows would be inserted again instead.
+// Tables created by sql.to have a primary key on the key columns, but tables
+// that already exist must be created with one.
+func validateUniqueKey(conn execer, s *ToSQLOpSpec, quoteIdent quoteIdentFunc) error {
+	if s.Mode != modeUpsert && s.Mode != modeReplace {
+		return nil
+	}
+	query, args := uniqueIndexQuery(s.DriverName, s.Table, quoteIdent)
+	if query == "" {
+		return nil
+	}
+	rows, err := conn.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	indexes := make(map[string][]string)
+	for rows.Next() {
+		var index, column string
+		if err := rows.Scan(&index, &column); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		indexes[index] = append(indexes[index], column)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	for _, columns := range indexes {
+		if sameColumns(columns, s.KeyColumns) {
+			return nil
+		}
+	}
+	return errors.Newf(codes.FailedPrecondition,
+		"mode %q requires a primary key or unique index on the key columns %v, but table %q does not have one",
+		s.Mode, s.KeyColumns, s.Table)
+}
+
+// sameColumns reports whether both lists have the same column names
+// in any order. Some databases do not preserve the case of column names.
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+outer:
+	for _, x := range a {
+		for _, y := range b {
+			if strings.EqualFold(x, y) {
+				continue outer
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// dedupKeys removes the rows of a batch that have the same key columns
+// as a later row in the batch. PostgreSQL and SQLite cannot update the
+// same row twice with ON CONFLICT DO UPDATE, and MERGE fails when more
+// than one source row matches a row, so only the last row is written.
+// The rows are not changed in insert mode.
+func dedupKeys(s *ToSQLOpSpec, colNames []string, valueStrings []string, valueArgs []interface{}) ([]string, []interface{}) {
+	if (s.Mode != modeUpsert && s.Mode != modeReplace) || len(valueStrings) < 2 {
+		return valueStrings, valueArgs
+	}
+	keyIdx := make([]int, 0, len(s.KeyColumns))
+	for _, k := range s.KeyColumns {
+		for j, name := range colNames {
+			if name == k {
+				keyIdx = append(keyIdx, j)
+				break
+			}
+		}
+	}
+
+	n := len(colNames)
+	keys := make([]string, len(valueStrings))
+	last := make(map[string]int, len(valueStrings))
+	for i := range valueStrings {
+		var sb strings.Builder
+		for _, j := range keyIdx {
+			fmt.Fprintf(&sb, "%T:%v\x00", valueArgs[i*n+j], valueArgs[i*n+j])
+		}
+		keys[i] = sb.String()
+		last[keys[i]] = i
+	}
+	if len(last) == len(valueStrings) {
+		return valueStrings, valueArgs
+	}
+
+	args := make([]interface{}, 0, len(last)*n)
+	for i, key := range keys {
+		if last[key] == i {
+			args = append(args, valueArgs[i*n:(i+1)*n]...)
+		}
+	}
+	return valueStrings[:len(last)], args
+}
+
+// addColumns adds the columns that are not in the table yet.
+// The columns are the column names of the data and their
+// definitions as used to create the table.
+func addColumns(conn execer, driverName, quotedTable string, colNames, colDefs []string) error {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", quotedTable)
+	if isMssqlDriver(driverName) {
+		query = fmt.Sprintf("SELECT TOP 0 * FROM %s", quotedTable)
+	}
+	rows, err := conn.Query(query)
+	if err != nil {
+		return err
+	}
+	existing, err := rows.Columns()
+	if closeErr := rows.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+outer:
+	for i, name := range colNames {
+		for _, e := range existing {
+			// Some databases do not preserve the case of column names.
+			if strings.EqualFold(name, e) {
+				continue outer
+			}
+		}
+		var q string
+		switch {
+		case isMssqlDriver(driverName):
+			q = fmt.Sprintf("ALTER TABLE %s ADD %s", quotedTable, colDefs[i])
+		case driverName == "hdb":
+			q = fmt.Sprintf("ALTER TABLE %s ADD (%s)", quotedTable, colDefs[i])
+		default:
+			q = fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", quotedTable, colDefs[i])
+		}
+		if _, err := conn.Exec(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -67,6 +67,17 @@ func (s *CountProcedureSpec) Copy() plan.ProcedureSpec {
 	}
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+func (s *CountProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.Strings(s.Columns)
+	return true
+}
+
+// IncrementalTimeColumn implements execute.IncrementalProcedureSpec.
+func (s *CountProcedureSpec) IncrementalTimeColumn() string {
+	return execute.DefaultTimeColLabel
+}
+
 func (s *CountProcedureSpec) AggregateMethod() string {
 	return CountKind
 }
@@ -88,7 +99,7 @@ func createCountTransformation(id execute.DatasetID, mode execute.AccumulationMo
 	if !ok {
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
-	return execute.NewSimpleAggregateTransformation(a.Context(), id, new(CountAgg), s.SimpleAggregateConfig, a.Allocator())
+	return execute.NewIncrementalAggregateTransformation(id, new(CountAgg), s.SimpleAggregateConfig, a)
 }
 
 func (a *CountAgg) NewBoolAgg() execute.DoBoolAgg {
@@ -132,3 +143,11 @@ func (a *CountAgg) ValueInt() int64 {
 func (a *CountAgg) IsNull() bool {
 	return false
 }
+
+func (a *CountAgg) MarshalBinary() ([]byte, error) {
+	return execute.EncodeState(a.count)
+}
+
+func (a *CountAgg) UnmarshalBinary(data []byte) error {
+	return execute.DecodeState(data, &a.count)
+}
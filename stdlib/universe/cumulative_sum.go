@@ -87,6 +87,11 @@ func (s *CumulativeSumProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// IncrementalTimeColumn implements execute.IncrementalProcedureSpec.
+func (s *CumulativeSumProcedureSpec) IncrementalTimeColumn() string {
+	return execute.DefaultTimeColLabel
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *CumulativeSumProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
@@ -97,24 +102,56 @@ func createCumulativeSumTransformation(id execute.DatasetID, mode execute.Accumu
 	if !ok {
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
-	return NewCumulativeSumTransformation(id, s, a.Allocator())
+	state, err := execute.NewIncrementalState(a, execute.DefaultTimeColLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newCumulativeSumTransformation(id, s, state, a.Allocator())
 }
 
 type cumulativeSumTransformation struct {
 	columns []string
+
+	// state is the state saved between runs of the query and
+	// groups holds the sums of each group so they can be saved.
+	// Both are nil if the query is not run incrementally.
+	state  *execute.IncrementalState
+	groups *execute.GroupLookup
 }
 
 func NewCumulativeSumTransformation(id execute.DatasetID, spec *CumulativeSumProcedureSpec, mem memory.Allocator) (execute.Transformation, execute.Dataset, error) {
+	return newCumulativeSumTransformation(id, spec, nil, mem)
+}
+
+func newCumulativeSumTransformation(id execute.DatasetID, spec *CumulativeSumProcedureSpec, state *execute.IncrementalState, mem memory.Allocator) (execute.Transformation, execute.Dataset, error) {
 	tr := &cumulativeSumTransformation{
 		columns: spec.Columns,
+		state:   state,
+	}
+	if state != nil {
+		tr.groups = execute.NewGroupLookup()
 	}
 	return execute.NewNarrowStateTransformation(id, tr, mem)
 }
 
 func (c *cumulativeSumTransformation) Process(chunk table.Chunk, state interface{}, d *execute.TransportDataset, mem memory.Allocator) (interface{}, bool, error) {
+	if c.state != nil {
+		// Only process the rows that are newer than the previous run.
+		buffer := chunk.Buffer()
+		filtered, err := c.state.Filter(&buffer, mem)
+		if err != nil {
+			return nil, false, err
+		}
+		chunk = table.ChunkFromBuffer(*filtered)
+		defer chunk.Release()
+	}
+
 	s, _ := state.(map[string]*cumulativeSumState)
 	if s == nil {
-		s = make(map[string]*cumulativeSumState)
+		var err error
+		if s, err = c.restore(chunk.Key()); err != nil {
+			return nil, false, err
+		}
 	}
 
 	if err := c.processChunk(chunk, s, d, mem); err != nil {
@@ -163,8 +200,81 @@ func (c *cumulativeSumTransformation) processChunk(chunk table.Chunk, state map[
 	return d.Process(out)
 }
 
+// restore returns the sums for a new group. When the query is run
+// incrementally, the sums continue from the previous run.
+func (c *cumulativeSumTransformation) restore(key flux.GroupKey) (map[string]*cumulativeSumState, error) {
+	if c.state == nil {
+		return make(map[string]*cumulativeSumState), nil
+	}
+	if s, ok := c.groups.Lookup(key); ok {
+		return s.(map[string]*cumulativeSumState), nil
+	}
+
+	s := make(map[string]*cumulativeSumState)
+	if data, ok := c.state.Restore(key); ok {
+		var snapshot []cumulativeSumSnapshot
+		if err := execute.DecodeState(data, &snapshot); err != nil {
+			return nil, err
+		}
+		for _, ss := range snapshot {
+			sumer := newCumulativeSumState(ss.Type)
+			if sumer == nil {
+				continue
+			}
+			switch sum := sumer.cumulativeSum.(type) {
+			case *cumulativeSumFloat:
+				sum.sum = ss.Float
+			case *cumulativeSumInt:
+				sum.sum = ss.Int
+			case *cumulativeSumUint:
+				sum.sum = ss.Uint
+			}
+			s[ss.Label] = sumer
+		}
+	}
+	c.groups.Set(key, s)
+	return s, nil
+}
+
 func (c *cumulativeSumTransformation) Close() error {
-	return nil
+	if c.state == nil {
+		return nil
+	}
+	if err := c.groups.Range(func(key flux.GroupKey, value interface{}) error {
+		var snapshot []cumulativeSumSnapshot
+		for label, sumer := range value.(map[string]*cumulativeSumState) {
+			ss := cumulativeSumSnapshot{Label: label, Type: sumer.inType}
+			switch sum := sumer.cumulativeSum.(type) {
+			case *cumulativeSumFloat:
+				ss.Float = sum.sum
+			case *cumulativeSumInt:
+				ss.Int = sum.sum
+			case *cumulativeSumUint:
+				ss.Uint = sum.sum
+			default:
+				continue
+			}
+			snapshot = append(snapshot, ss)
+		}
+		data, err := execute.EncodeState(snapshot)
+		if err != nil {
+			return err
+		}
+		c.state.Set(key, data)
+		return nil
+	}); err != nil {
+		return err
+	}
+	return c.state.Save()
+}
+
+// cumulativeSumSnapshot is the saved form of the sum of a column.
+type cumulativeSumSnapshot struct {
+	Label string
+	Type  flux.ColType
+	Float float64
+	Int   int64
+	Uint  uint64
 }
 
 type cumulativeSum interface {
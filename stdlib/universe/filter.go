@@ -16,6 +16,7 @@ import (
 	"github.com/influxdata/flux/internal/arrowutil"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/internal/execute/table"
+	"github.com/influxdata/flux/internal/feature"
 	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
@@ -107,7 +108,7 @@ func newFilterProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.Pro
 
 func (s *FilterProcedureSpec) PassThroughAttribute(attrKey string) bool {
 	switch attrKey {
-	case plan.ParallelRunKey, plan.CollationKey:
+	case plan.ParallelRunKey, plan.ParallelGroupRunKey, plan.CollationKey:
 		return true
 	}
 	return false
@@ -154,22 +155,51 @@ func NewFilterTransformation(ctx context.Context, spec *FilterProcedureSpec, id
 		fn:              fn,
 		keepEmptyTables: spec.KeepEmptyTables,
 	}
+	if spec.Fn.Fn.Vectorized != nil && feature.VectorizedFilter().Enabled(ctx) {
+		t.vectorFn = execute.NewVectorPredicateFn(spec.Fn.Fn.Vectorized, compiler.ToScope(spec.Fn.Scope))
+	}
 	return execute.NewNarrowTransformation(id, t, alloc)
 }
 
 type filterTransformation struct {
 	ctx             context.Context
 	fn              *execute.RowPredicateFn
+	vectorFn        *execute.VectorPredicateFn
 	keepEmptyTables bool
 }
 
 func (t *filterTransformation) Process(chunk table.Chunk, d *execute.TransportDataset, mem arrowmem.Allocator) error {
+	var (
+		bitset *arrowmem.Buffer
+		err    error
+	)
+	if t.vectorFn != nil {
+		bitset, err = t.filterVector(chunk, mem)
+	} else {
+		bitset, err = t.filterRows(chunk, mem)
+	}
+	if err != nil {
+		return err
+	}
+	defer bitset.Release()
+
+	// Filter the table using the computed bitset.
+	out, ok := t.filterChunk(chunk, bitset, mem)
+	if !ok {
+		return nil
+	}
+	return d.Process(out)
+}
+
+// filterRows evaluates the predicate for each row of the chunk
+// and returns a bitset of the rows that passed.
+func (t *filterTransformation) filterRows(chunk table.Chunk, mem arrowmem.Allocator) (*arrowmem.Buffer, error) {
 	// Prepare the function for the column types.
 	cols := chunk.Cols()
 	fn, err := t.fn.Prepare(cols)
 	if err != nil {
 		// TODO(nathanielc): Should we not fail the query for failed compilation?
-		return err
+		return nil, err
 	}
 
 	// Prefill the columns that can be inferred from the group key.
@@ -186,25 +216,39 @@ func (t *filterTransformation) Process(chunk table.Chunk, d *execute.TransportDa
 	}
 
 	// Filter the table and pass in the indices we have to read.
-	out, ok, err := t.filterChunk(fn, chunk, record, indices, mem)
-	if err != nil || !ok {
-		return err
-	}
-	return d.Process(out)
+	buffer := chunk.Buffer()
+	return t.filter(fn, &buffer, record, indices, mem)
 }
 
-func (t *filterTransformation) filterChunk(fn *execute.RowPredicatePreparedFn, chunk table.Chunk, record values.Object, indices []int, mem arrowmem.Allocator) (table.Chunk, bool, error) {
-	buffer := chunk.Buffer()
-	bitset, err := t.filter(fn, &buffer, record, indices, mem)
+// filterVector evaluates the vectorized predicate once for the
+// entire chunk and converts the resulting boolean mask into a bitset.
+// A null value in the mask is treated the same as false.
+func (t *filterTransformation) filterVector(chunk table.Chunk, mem arrowmem.Allocator) (*arrowmem.Buffer, error) {
+	fn, err := t.vectorFn.Prepare(chunk.Cols())
 	if err != nil {
-		return table.Chunk{}, false, err
+		return nil, err
 	}
-	defer bitset.Release()
 
+	mask, err := fn.Eval(t.ctx, chunk, mem)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Inherit, "failed to evaluate filter function")
+	}
+	defer mask.Release()
+
+	l := chunk.Len()
+	bitset := arrowmem.NewResizableBuffer(mem)
+	bitset.Resize(l)
+	for i := 0; i < l; i++ {
+		bitutil.SetBitTo(bitset.Buf(), i, mask.IsValid(i) && mask.Value(i))
+	}
+	return bitset, nil
+}
+
+func (t *filterTransformation) filterChunk(chunk table.Chunk, bitset *arrowmem.Buffer, mem arrowmem.Allocator) (table.Chunk, bool) {
 	n := bitutil.CountSetBits(bitset.Buf(), 0, bitset.Len())
 	if n == 0 && !t.keepEmptyTables {
 		// Drop this chunk if it is empty and we are not keeping empty tables.
-		return table.Chunk{}, false, nil
+		return table.Chunk{}, false
 	}
 
 	// Produce arrays for each column.
@@ -222,7 +266,7 @@ func (t *filterTransformation) filterChunk(fn *execute.RowPredicatePreparedFn, c
 		GroupKey: chunk.Key(),
 		Columns:  chunk.Cols(),
 		Values:   vs,
-	}), true, nil
+	}), true
 }
 
 func (t *filterTransformation) filter(fn *execute.RowPredicatePreparedFn, cr flux.ColReader, record values.Object, indices []int, mem arrowmem.Allocator) (*arrowmem.Buffer, error) {
@@ -313,6 +357,9 @@ func (MergeFiltersRule) Rewrite(ctx context.Context, filterNode plan.Node) (plan
 	// set a new variables that converted the single body statement to a return type that can used with expr
 	ret := filterSpec2.Fn.Fn.Block.Body[0].(*semantic.ReturnStatement)
 	ret.Argument = expr
+	// The vectorized predicate no longer matches the merged body
+	// so the merged filter must be evaluated row by row.
+	filterSpec2.Fn.Fn.Vectorized = nil
 	// return the pred node
 	anyNode := filterNode.Predecessors()[0]
 	return anyNode, true, nil
@@ -10,7 +10,9 @@ import (
 	"github.com/influxdata/flux/dependency"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
+	fluxfeature "github.com/influxdata/flux/internal/feature"
 	"github.com/influxdata/flux/internal/gen"
+	"github.com/influxdata/flux/internal/pkg/feature"
 	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/memory"
@@ -740,6 +742,186 @@ func TestFilter_Process(t *testing.T) {
 	}
 }
 
+func TestFilter_Process_Vectorized(t *testing.T) {
+	testCases := []struct {
+		name string
+		fn   string
+		data []flux.Table
+		want []*executetest.Table
+	}{
+		{
+			name: "comparison",
+			fn:   `(r) => r._value > 5.0`,
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 1.0},
+					{execute.Time(2), 6.0},
+					{execute.Time(3), nil},
+					{execute.Time(4), 7.0},
+				},
+			}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(2), 6.0},
+					{execute.Time(4), 7.0},
+				},
+			}},
+		},
+		{
+			name: "logical with group key",
+			fn:   `(r) => r._value > 5.0 and r.t1 == "a"`,
+			data: []flux.Table{
+				&executetest.Table{
+					KeyCols: []string{"t1"},
+					ColMeta: []flux.ColMeta{
+						{Label: "t1", Type: flux.TString},
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{"a", execute.Time(1), 3.0},
+						{"a", execute.Time(2), 6.0},
+						{"a", execute.Time(3), 8.0},
+					},
+				},
+				&executetest.Table{
+					KeyCols: []string{"t1"},
+					ColMeta: []flux.ColMeta{
+						{Label: "t1", Type: flux.TString},
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{"b", execute.Time(1), 7.0},
+						{"b", execute.Time(2), 8.0},
+					},
+				},
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"t1"},
+					ColMeta: []flux.ColMeta{
+						{Label: "t1", Type: flux.TString},
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{"a", execute.Time(2), 6.0},
+						{"a", execute.Time(3), 8.0},
+					},
+				},
+			},
+		},
+		{
+			name: "logical with null",
+			fn:   `(r) => r.a == "x" or r.b == "y"`,
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "a", Type: flux.TString},
+					{Label: "b", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), nil, "y"},
+					{execute.Time(2), "x", nil},
+					{execute.Time(3), nil, "z"},
+					{execute.Time(4), nil, nil},
+					{execute.Time(5), "z", "y"},
+				},
+			}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "a", Type: flux.TString},
+					{Label: "b", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), nil, "y"},
+					{execute.Time(2), "x", nil},
+					{execute.Time(5), "z", "y"},
+				},
+			}},
+		},
+		{
+			name: "compare booleans",
+			fn:   `(r) => r.a == r.b`,
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "a", Type: flux.TBool},
+					{Label: "b", Type: flux.TBool},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), true, true},
+					{execute.Time(2), true, false},
+					{execute.Time(3), false, false},
+					{execute.Time(4), nil, false},
+				},
+			}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "a", Type: flux.TBool},
+					{Label: "b", Type: flux.TBool},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), true, true},
+					{execute.Time(3), false, false},
+				},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := feature.Inject(context.Background(), executetest.TestFlagger{
+				fluxfeature.VectorizedMap().Key():    true,
+				fluxfeature.VectorizedConst().Key():  true,
+				fluxfeature.VectorizedFilter().Key(): true,
+			})
+
+			pkg, err := runtime.AnalyzeSource(ctx, tc.fn)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			stmt := pkg.Files[0].Body[0].(*semantic.ExpressionStatement)
+			fn := stmt.Expression.(*semantic.FunctionExpression)
+			if fn.Vectorized == nil {
+				t.Fatal("expected to find vectorized node, but found none")
+			}
+
+			spec := &universe.FilterProcedureSpec{
+				Fn: interpreter.ResolvedFunction{
+					Fn:    fn,
+					Scope: valuestest.Scope(),
+				},
+			}
+			executetest.ProcessTestHelper2(
+				t,
+				tc.data,
+				tc.want,
+				nil,
+				func(id execute.DatasetID, alloc memory.Allocator) (execute.Transformation, execute.Dataset) {
+					ctx := memory.WithAllocator(ctx, alloc)
+					tx, d, err := universe.NewFilterTransformation(ctx, spec, id, alloc)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return tx, d
+				},
+			)
+		})
+	}
+}
+
 // TestFilter_ConcurrentTables ensures that filter can handle
 // multiple tables with multiple buffers and not trigger a race
 // condition.
@@ -71,6 +71,12 @@ func (s *FirstProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+func (s *FirstProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.String(s.Column)
+	return true
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *FirstProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/flux/array"
 	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/spill"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/internal/execute/dataset"
@@ -142,6 +143,24 @@ func (s *GroupProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+func (s *GroupProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.Int(int64(s.GroupMode))
+	f.Strings(s.GroupKeys)
+	return true
+}
+
+// Cost reports the cost of regrouping the input, which holds every
+// row in memory until the tables for the new group keys are complete.
+// The number of groups produced is not known.
+func (s *GroupProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	stats := plan.MergeStatistics(inStats)
+	return plan.Cost{
+		CPU: stats.Cardinality,
+		MEM: stats.Cardinality,
+	}, plan.Statistics{Cardinality: stats.Cardinality}
+}
+
 func createGroupTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
 	s, ok := spec.(*GroupProcedureSpec)
 	if !ok {
@@ -191,24 +210,35 @@ type groupTransformation struct {
 	cache table.BuilderCache
 	mem   memory.Allocator
 
+	// spiller is used to move the buffered tables to disk
+	// when memory is under pressure. It may be nil.
+	spiller *spill.Spiller
+
 	mode flux.GroupMode
 	keys []string
 }
 
 func NewGroupTransformation(ctx context.Context, spec *GroupProcedureSpec, id execute.DatasetID, mem memory.Allocator) (execute.Transformation, execute.Dataset, error) {
+	spiller := spill.New(ctx, mem)
 	t := &groupTransformation{
 		cache: table.BuilderCache{
 			New: func(key flux.GroupKey) table.Builder {
-				return table.NewBufferedBuilder(key, mem)
+				return spill.NewBuilder(key, spiller, mem)
 			},
 		},
-		mem:  mem,
-		mode: spec.GroupMode,
-		keys: spec.GroupKeys,
+		mem:     mem,
+		spiller: spiller,
+		mode:    spec.GroupMode,
+		keys:    spec.GroupKeys,
 	}
 	t.d = dataset.New(id, &t.cache)
 	sort.Strings(t.keys)
-	if feature.GroupTransformationGroup().Enabled(ctx) {
+	// The group transformation adapter passes each regrouped chunk
+	// downstream and never flushes a key before the input is finished,
+	// so the groups are buffered by the downstream transformations where
+	// they cannot be spilled. When spilling is enabled, buffer the groups
+	// here instead so they can be moved to disk.
+	if spiller == nil && feature.GroupTransformationGroup().Enabled(ctx) {
 		a := &groupTransformationAdapter{t: t}
 		gt, d, err := execute.NewGroupTransformation(id, a, mem)
 		if err != nil {
@@ -229,7 +259,7 @@ func (t *groupTransformation) Process(id execute.DatasetID, tbl flux.Table) erro
 	if key, ok, err := t.getTableKey(tbl.Key(), tbl.Cols()); err != nil {
 		return err
 	} else if ok {
-		ab, _ := table.GetBufferedBuilder(key, &t.cache)
+		ab, _ := spill.GetBuilder(key, &t.cache)
 		return t.appendTable(ab, tbl)
 	}
 
@@ -290,9 +320,20 @@ func (t *groupTransformation) getTableKey(key flux.GroupKey, cols []flux.ColMeta
 	return execute.NewGroupKey(cols, vs), true, nil
 }
 
-func (t *groupTransformation) appendTable(ab *table.BufferedBuilder, tbl flux.Table) error {
+func (t *groupTransformation) appendTable(ab *spill.Builder, tbl flux.Table) error {
 	// Read the table and append each of the columns.
-	return ab.AppendTable(tbl)
+	if err := ab.AppendTable(tbl); err != nil {
+		return err
+	}
+	if !t.spiller.ShouldSpill() {
+		return nil
+	}
+
+	// Memory is under pressure so move the buffered tables
+	// for every group to disk.
+	return t.cache.ForEach(func(key flux.GroupKey, builder table.Builder) error {
+		return builder.(*spill.Builder).Spill()
+	})
 }
 
 func (t *groupTransformation) groupChunkByRow(tbl table.Chunk, d *execute.TransportDataset, mem arrowmem.Allocator) error {
@@ -395,7 +436,7 @@ func (t *groupTransformation) groupByRow(tbl flux.Table) error {
 			return err
 		}
 
-		ab, _ := table.GetBufferedBuilder(key, &t.cache)
+		ab, _ := spill.GetBuilder(key, &t.cache)
 		return t.appendTable(ab, tbl)
 	})
 }
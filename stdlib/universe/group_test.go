@@ -3,13 +3,19 @@ package universe_test
 import (
 	"context"
 	"errors"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/spill"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
+	fluxfeature "github.com/influxdata/flux/internal/feature"
 	"github.com/influxdata/flux/internal/gen"
+	"github.com/influxdata/flux/internal/pkg/feature"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/plan/plantest"
@@ -782,6 +788,111 @@ func TestGroup_Process(t *testing.T) {
 	}
 }
 
+func TestGroup_Spill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flux-group-spill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// Enable the group transformation interface to ensure the
+	// groups are still buffered where they can be spilled.
+	ctx := feature.Inject(context.Background(), executetest.TestFlagger{
+		fluxfeature.GroupTransformationGroup().Key(): true,
+	})
+	ctx = spill.Inject(ctx, spill.Config{Dir: dir})
+
+	// Simulate memory pressure by accounting for memory
+	// that is above the spill threshold.
+	limit := int64(1000)
+	pressure := &memory.ResourceAllocator{Limit: &limit}
+	if err := pressure.Account(900); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := &universe.GroupProcedureSpec{
+		GroupMode: flux.GroupModeBy,
+		GroupKeys: []string{"t1"},
+	}
+	tr, d, err := universe.NewGroupTransformation(ctx, spec, executetest.RandomDatasetID(), pressure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := executetest.NewDataStore()
+	d.SetTriggerSpec(plan.DefaultTriggerSpec)
+	d.AddTransformation(store)
+
+	parentID := executetest.RandomDatasetID()
+	for _, tbl := range []flux.Table{
+		&executetest.Table{
+			KeyCols: []string{"t1", "t2"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "t1", Type: flux.TString},
+				{Label: "t2", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), 2.0, "a", "x"},
+			},
+		},
+		&executetest.Table{
+			KeyCols: []string{"t1", "t2"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "t1", Type: flux.TString},
+				{Label: "t2", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(2), 1.0, "a", "y"},
+			},
+		},
+	} {
+		if err := tr.Process(parentID, tbl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Both tables belong to the same group so they
+	// are appended to a single spill file.
+	if files, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(files) != 1 {
+		t.Fatalf("unexpected number of spill files -want/+got:\n\t- %d\n\t+ %d", 1, len(files))
+	}
+
+	tr.Finish(parentID, nil)
+	got, err := executetest.TablesFromCache(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*executetest.Table{{
+		KeyCols: []string{"t1"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TFloat},
+			{Label: "t1", Type: flux.TString},
+			{Label: "t2", Type: flux.TString},
+		},
+		Data: [][]interface{}{
+			{execute.Time(1), 2.0, "a", "x"},
+			{execute.Time(2), 1.0, "a", "y"},
+		},
+	}}
+	executetest.NormalizeTables(got)
+	executetest.NormalizeTables(want)
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(want, got))
+	}
+
+	if files, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(files) != 0 {
+		t.Errorf("expected spill files to be removed, found %d", len(files))
+	}
+}
+
 func TestMergeGroupRule(t *testing.T) {
 	var (
 		from      = &influxdb.FromProcedureSpec{}
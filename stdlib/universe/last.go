@@ -69,6 +69,12 @@ func (s *LastProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+func (s *LastProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.String(s.Column)
+	return true
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *LastProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
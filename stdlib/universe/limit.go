@@ -91,6 +91,24 @@ func (s *LimitProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+func (s *LimitProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.Int(s.N)
+	f.Int(s.Offset)
+	return true
+}
+
+// Cost reports that limit produces at most n rows for each table.
+func (s *LimitProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	stats := plan.MergeStatistics(inStats)
+	if stats.GroupCardinality > 0 {
+		if n := s.N * stats.GroupCardinality; n < stats.Cardinality {
+			stats.Cardinality = n
+		}
+	}
+	return plan.Cost{}, stats
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *LimitProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
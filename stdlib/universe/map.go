@@ -86,6 +86,38 @@ func newMapProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.Proced
 	}, nil
 }
 
+// PassThroughAttribute implements plan.PassThroughAttributer.
+// A map function that extends its input record can only change the group key
+// columns it sets, so it can run on partitions of the group keys that ignore them.
+func (s *MapProcedureSpec) PassThroughAttribute(attrKey string) bool {
+	if attrKey != plan.ParallelGroupRunKey {
+		return false
+	}
+	_, ok := mapModifiedColumns(s.Fn.Fn)
+	return ok
+}
+
+// mapModifiedColumns returns the columns set by a map function that
+// returns its input record extended with new properties.
+func mapModifiedColumns(fn *semantic.FunctionExpression) ([]string, bool) {
+	if fn == nil || fn.Parameters == nil || len(fn.Parameters.List) != 1 || fn.Block == nil || len(fn.Block.Body) == 0 {
+		return nil, false
+	}
+	ret, ok := fn.Block.Body[len(fn.Block.Body)-1].(*semantic.ReturnStatement)
+	if !ok {
+		return nil, false
+	}
+	obj, ok := ret.Argument.(*semantic.ObjectExpression)
+	if !ok || obj.With == nil || obj.With.Name != fn.Parameters.List[0].Key.Name {
+		return nil, false
+	}
+	labels := make([]string, len(obj.Properties))
+	for i, p := range obj.Properties {
+		labels[i] = p.Key.Key()
+	}
+	return labels, true
+}
+
 func (s *MapProcedureSpec) Kind() plan.ProcedureKind {
 	return MapKind
 }
@@ -69,6 +69,12 @@ func (s *MaxProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+func (s *MaxProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.String(s.Column)
+	return true
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *MaxProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
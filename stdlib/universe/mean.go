@@ -70,6 +70,17 @@ func (s *MeanProcedureSpec) Copy() plan.ProcedureSpec {
 	}
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+func (s *MeanProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.Strings(s.Columns)
+	return true
+}
+
+// IncrementalTimeColumn implements execute.IncrementalProcedureSpec.
+func (s *MeanProcedureSpec) IncrementalTimeColumn() string {
+	return execute.DefaultTimeColLabel
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *MeanProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
@@ -85,7 +96,7 @@ func createMeanTransformation(id execute.DatasetID, mode execute.AccumulationMod
 	if !ok {
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
-	return execute.NewSimpleAggregateTransformation(a.Context(), id, new(MeanAgg), s.SimpleAggregateConfig, a.Allocator())
+	return execute.NewIncrementalAggregateTransformation(id, new(MeanAgg), s.SimpleAggregateConfig, a)
 }
 
 func (a *MeanAgg) NewBoolAgg() execute.DoBoolAgg {
@@ -162,3 +173,9 @@ func (a *MeanAgg) ValueFloat() float64 {
 func (a *MeanAgg) IsNull() bool {
 	return a.count == 0
 }
+func (a *MeanAgg) MarshalBinary() ([]byte, error) {
+	return execute.EncodeState(a.count, a.sum)
+}
+func (a *MeanAgg) UnmarshalBinary(data []byte) error {
+	return execute.DecodeState(data, &a.count, &a.sum)
+}
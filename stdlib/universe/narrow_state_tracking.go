@@ -16,6 +16,10 @@ import (
 )
 
 func NewNarrowStateTrackingTransformation(ctx context.Context, spec *StateTrackingProcedureSpec, id execute.DatasetID, mem memory.Allocator) (execute.Transformation, execute.Dataset, error) {
+	return newNarrowStateTrackingTransformation(ctx, spec, id, nil, mem)
+}
+
+func newNarrowStateTrackingTransformation(ctx context.Context, spec *StateTrackingProcedureSpec, id execute.DatasetID, state *execute.IncrementalState, mem memory.Allocator) (execute.Transformation, execute.Dataset, error) {
 	fn := execute.NewRowPredicateFn(spec.Fn.Fn, compiler.ToScope(spec.Fn.Scope))
 	t := &narrowStateTrackingTransformation{
 		ctx:      ctx,
@@ -24,6 +28,10 @@ func NewNarrowStateTrackingTransformation(ctx context.Context, spec *StateTracki
 		countCol: spec.CountColumn,
 		durCol:   spec.DurationColumn,
 		unit:     int64(spec.DurationUnit.Duration()),
+		state:    state,
+	}
+	if state != nil {
+		t.groups = execute.NewGroupLookup()
 	}
 	nt, d, err := execute.NewNarrowStateTransformation(id, t, mem)
 	if err != nil {
@@ -41,6 +49,12 @@ type narrowStateTrackingTransformation struct {
 	durCol string
 
 	unit int64
+
+	// state is the state saved between runs of the query and
+	// groups holds the tracked state of each group so it can be saved.
+	// Both are nil if the query is not run incrementally.
+	state  *execute.IncrementalState
+	groups *execute.GroupLookup
 }
 
 type trackedState struct {
@@ -55,23 +69,75 @@ type trackedState struct {
 }
 
 func (n *narrowStateTrackingTransformation) Process(chunk table.Chunk, state interface{}, d *execute.TransportDataset, mem memory.Allocator) (interface{}, bool, error) {
+	if n.state != nil {
+		// Only process the rows that are newer than the previous run.
+		buffer := chunk.Buffer()
+		filtered, err := n.state.Filter(&buffer, mem)
+		if err != nil {
+			return nil, false, err
+		}
+		chunk = table.ChunkFromBuffer(*filtered)
+		defer chunk.Release()
+	}
+
 	// Track whether or not the state has been modified
 	mod := false
 
 	// Initialize state
 	if state == nil {
-		state = trackedState{
-			count:    -1,
-			duration: -1,
+		s, err := n.restore(chunk.Key())
+		if err != nil {
+			return nil, false, err
 		}
+		state = s
 		mod = true
 	}
 	s := state.(trackedState)
 	mod, err := n.processChunk(chunk, &s, d, mem, mod)
+	if err == nil && n.groups != nil {
+		n.groups.Set(chunk.Key(), s)
+	}
 	return s, mod, err
 }
 
-func (n *narrowStateTrackingTransformation) Close() error { return nil }
+// restore returns the initial state for a new group. When the query
+// is run incrementally, the state continues from the previous run.
+func (n *narrowStateTrackingTransformation) restore(key flux.GroupKey) (trackedState, error) {
+	if n.state != nil {
+		if s, ok := n.groups.Lookup(key); ok {
+			return s.(trackedState), nil
+		}
+		if data, ok := n.state.Restore(key); ok {
+			var s trackedState
+			if err := execute.DecodeState(data, &s.start, &s.prevTime, &s.count, &s.duration, &s.countInState, &s.durationInState); err != nil {
+				return trackedState{}, err
+			}
+			return s, nil
+		}
+	}
+	return trackedState{
+		count:    -1,
+		duration: -1,
+	}, nil
+}
+
+func (n *narrowStateTrackingTransformation) Close() error {
+	if n.state == nil {
+		return nil
+	}
+	if err := n.groups.Range(func(key flux.GroupKey, value interface{}) error {
+		s := value.(trackedState)
+		data, err := execute.EncodeState(s.start, s.prevTime, s.count, s.duration, s.countInState, s.durationInState)
+		if err != nil {
+			return err
+		}
+		n.state.Set(key, data)
+		return nil
+	}); err != nil {
+		return err
+	}
+	return n.state.Save()
+}
 
 // Updates the state object for each row in the chunk, creates a new chunk with
 // columns tracking counts and/or durations, and passes that chunk to the next
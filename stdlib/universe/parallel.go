@@ -7,20 +7,30 @@ import (
 	"sync"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/execute/groupkey"
+	"github.com/influxdata/flux/internal/feature"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/values"
 	"github.com/opentracing/opentracing-go"
 )
 
 const (
-	ParallelMergeKind = "ParallelMergeKind"
+	ParallelMergeKind   = "ParallelMergeKind"
+	PartitionGroupsKind = "PartitionGroupsKind"
 )
 
 type PartitionMergeProcedureSpec struct {
 	plan.DefaultCost
 	Factor int
+
+	// PartitionedByGroup is set when the predecessors run
+	// on partitions of the group keys made by PartitionGroupsProcedureSpec.
+	PartitionedByGroup bool
 }
 
 func (o *PartitionMergeProcedureSpec) OutputAttributes() plan.PhysicalAttributes {
@@ -30,6 +40,13 @@ func (o *PartitionMergeProcedureSpec) OutputAttributes() plan.PhysicalAttributes
 }
 
 func (o *PartitionMergeProcedureSpec) RequiredAttributes() []plan.PhysicalAttributes {
+	if o.PartitionedByGroup {
+		return []plan.PhysicalAttributes{
+			{
+				plan.ParallelGroupRunKey: plan.ParallelGroupRunAttribute{Factor: o.Factor},
+			},
+		}
+	}
 	return []plan.PhysicalAttributes{
 		{
 			plan.ParallelRunKey: plan.ParallelRunAttribute{Factor: o.Factor},
@@ -43,13 +60,16 @@ func (o *PartitionMergeProcedureSpec) Kind() plan.ProcedureKind {
 
 func (o *PartitionMergeProcedureSpec) Copy() plan.ProcedureSpec {
 	return &PartitionMergeProcedureSpec{
-		DefaultCost: o.DefaultCost,
-		Factor:      o.Factor,
+		DefaultCost:        o.DefaultCost,
+		Factor:             o.Factor,
+		PartitionedByGroup: o.PartitionedByGroup,
 	}
 }
 
 func init() {
 	execute.RegisterTransformation(ParallelMergeKind, createPartitionMergeTransformation)
+	execute.RegisterTransformation(PartitionGroupsKind, createPartitionGroupsTransformation)
+	plan.RegisterParallelizeRules(ParallelizeGroupsRule{})
 }
 
 func createPartitionMergeTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
@@ -202,3 +222,282 @@ func (t *PartitionMergeTransformation) Finish(id execute.DatasetID, err error) {
 		t.dataset.Finish(err)
 	}
 }
+
+// PartitionGroupsProcedureSpec splits its input into partitions of the group keys
+// so the operations that follow it can process the partitions in parallel.
+// Every table with the same group key is in the same partition.
+type PartitionGroupsProcedureSpec struct {
+	plan.DefaultCost
+	Factor int
+
+	// Exclude lists the group key columns that are not used to assign
+	// a table to a partition. Tables whose group keys only differ in these
+	// columns are in the same partition.
+	Exclude []string
+}
+
+func (o *PartitionGroupsProcedureSpec) OutputAttributes() plan.PhysicalAttributes {
+	return plan.PhysicalAttributes{
+		plan.ParallelGroupRunKey: plan.ParallelGroupRunAttribute{Factor: o.Factor},
+	}
+}
+
+// ParallelRouter implements the plan.ParallelRouter interface.
+// A single copy of the node reads the input and sends each table
+// to the copy of its successor that processes the partition of the table.
+func (o *PartitionGroupsProcedureSpec) ParallelRouter() {}
+
+func (o *PartitionGroupsProcedureSpec) Kind() plan.ProcedureKind {
+	return PartitionGroupsKind
+}
+
+func (o *PartitionGroupsProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *o
+	if o.Exclude != nil {
+		ns.Exclude = make([]string, len(o.Exclude))
+		copy(ns.Exclude, o.Exclude)
+	}
+	return &ns
+}
+
+func createPartitionGroupsTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*PartitionGroupsProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+
+	d := &partitionGroupsDataset{
+		id:      id,
+		mem:     a.Allocator(),
+		factor:  s.Factor,
+		exclude: s.Exclude,
+	}
+	t := &partitionGroupsTransformation{d: d}
+	return execute.NewTransformationFromTransport(t), d, nil
+}
+
+// partitionGroupsTransformation passes each chunk of the input
+// to the dataset that routes it to its partition. Chunks are
+// not buffered so each partition only holds its own tables.
+type partitionGroupsTransformation struct {
+	d *partitionGroupsDataset
+}
+
+func (t *partitionGroupsTransformation) ProcessMessage(m execute.Message) error {
+	defer m.Ack()
+
+	switch m := m.(type) {
+	case execute.FinishMsg:
+		t.Finish(m.SrcDatasetID(), m.Error())
+		return nil
+	case execute.ProcessChunkMsg:
+		chunk := m.TableChunk()
+		chunk.Retain()
+		return t.d.Process(chunk)
+	case execute.FlushKeyMsg:
+		return t.d.FlushKey(m.Key())
+	case execute.ProcessMsg:
+		panic("unreachable")
+	}
+	return nil
+}
+
+// Finish is implemented to remain compatible with legacy upstreams.
+func (t *partitionGroupsTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
+
+// partitionGroupsDataset sends the chunks of each table to a single partition.
+//
+// The copies of the successor add their transformations in the
+// order of their partitions, so the transformation at index i
+// processes partition i. Each partition has its own TransportDataset
+// that sends the chunks of its tables and the end of the input
+// to the copy of the successor.
+type partitionGroupsDataset struct {
+	id         execute.DatasetID
+	mem        memory.Allocator
+	partitions []*execute.TransportDataset
+	factor     int
+	exclude    []string
+}
+
+func (d *partitionGroupsDataset) AddTransformation(t execute.Transformation) {
+	p := execute.NewTransportDataset(d.id, d.mem)
+	p.AddTransformation(t)
+	d.partitions = append(d.partitions, p)
+}
+
+// Process sends the chunk to the partition of its group key.
+func (d *partitionGroupsDataset) Process(chunk table.Chunk) error {
+	p, err := d.partition(chunk.Key())
+	if err != nil {
+		chunk.Release()
+		return err
+	}
+	return p.Process(chunk)
+}
+
+// FlushKey sends the flush key message to the partition of the group key.
+func (d *partitionGroupsDataset) FlushKey(key flux.GroupKey) error {
+	p, err := d.partition(key)
+	if err != nil {
+		return err
+	}
+	return p.FlushKey(key)
+}
+
+func (d *partitionGroupsDataset) RetractTable(key flux.GroupKey) error      { return nil }
+func (d *partitionGroupsDataset) UpdateProcessingTime(t execute.Time) error { return nil }
+func (d *partitionGroupsDataset) UpdateWatermark(mark execute.Time) error   { return nil }
+
+func (d *partitionGroupsDataset) Finish(err error) {
+	for _, p := range d.partitions {
+		p.Finish(err)
+	}
+}
+
+func (d *partitionGroupsDataset) SetTriggerSpec(t plan.TriggerSpec) {
+}
+
+// partition returns the dataset that sends
+// to the partition of the group key.
+func (d *partitionGroupsDataset) partition(key flux.GroupKey) (*execute.TransportDataset, error) {
+	if len(d.partitions) != d.factor {
+		return nil, errors.Newf(codes.Internal, "partition groups expected %d successors, got %d", d.factor, len(d.partitions))
+	}
+	return d.partitions[d.partitionOf(key)], nil
+}
+
+// partitionOf returns the partition of the group key.
+func (d *partitionGroupsDataset) partitionOf(key flux.GroupKey) int {
+	cols := make([]flux.ColMeta, 0, len(key.Cols()))
+	vals := make([]values.Value, 0, len(key.Cols()))
+	for j, c := range key.Cols() {
+		if execute.ContainsStr(d.exclude, c.Label) {
+			continue
+		}
+		cols = append(cols, c)
+		vals = append(vals, key.Value(j))
+	}
+	return int(groupkey.Hash(groupkey.New(cols, vals)) % uint64(d.factor))
+}
+
+// ParallelizeGroupsRule runs a chain of operations that process each group
+// independently on partitions of the group keys. A partition node is inserted
+// before the chain to split the tables between the parallel copies of the chain
+// and a merge node is inserted after it to combine their results.
+//
+// An operation is part of the chain when it passes through the parallel-group-run
+// attribute. The number of partitions is chosen from the estimated number of rows
+// read by the chain and is limited by the queryParallelism feature flag.
+type ParallelizeGroupsRule struct{}
+
+func (ParallelizeGroupsRule) Name() string {
+	return "universe.ParallelizeGroupsRule"
+}
+
+func (ParallelizeGroupsRule) Pattern() plan.Pattern {
+	return plan.Any()
+}
+
+func (ParallelizeGroupsRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	max := feature.QueryParallelism().Int(ctx)
+	if max <= 1 || !partitionsGroups(node) {
+		return node, false, nil
+	}
+
+	// The rule is applied to the last operation of the chain. The planner
+	// visits successors first, so the chain has already been parallelized
+	// when the successor of the node is part of the chain.
+	if succs := node.Successors(); len(succs) == 1 && len(succs[0].Predecessors()) == 1 && partitionsGroups(succs[0]) {
+		return node, false, nil
+	}
+
+	chain := []plan.Node{node}
+	for {
+		preds := chain[len(chain)-1].Predecessors()
+		if len(preds) != 1 {
+			return node, false, nil
+		}
+		pred := preds[0]
+		if len(pred.Successors()) != 1 || !partitionsGroups(pred) {
+			break
+		}
+		chain = append(chain, pred)
+	}
+
+	input, ok := chain[len(chain)-1].Predecessors()[0].(*plan.PhysicalPlanNode)
+	if !ok || plan.ParallelRunFactor(input) > 1 {
+		return node, false, nil
+	}
+	factor := plan.ParallelFactor(ctx, input, max)
+	if factor <= 1 {
+		return node, false, nil
+	}
+
+	var exclude []string
+	for _, n := range chain {
+		for _, label := range partitionExcludeColumns(n.ProcedureSpec()) {
+			if !execute.ContainsStr(exclude, label) {
+				exclude = append(exclude, label)
+			}
+		}
+	}
+
+	// The planner attaches the successors of the node to the node
+	// that is returned, so the last operation of the chain is replaced
+	// by a copy that becomes the predecessor of the merge node.
+	top := plan.CreatePhysicalNode(node.ID(), node.ProcedureSpec().(plan.PhysicalProcedureSpec))
+	top.Source = node.(*plan.PhysicalPlanNode).Source
+	plan.ReplaceNode(node, top)
+	chain[0] = top
+
+	bottom := chain[len(chain)-1]
+	partition := plan.CreatePhysicalNode("partitionGroups_"+bottom.ID(), &PartitionGroupsProcedureSpec{
+		Factor:  factor,
+		Exclude: exclude,
+	})
+	for i, succ := range input.Successors() {
+		if succ == bottom {
+			input.Successors()[i] = partition
+		}
+	}
+	partition.AddPredecessors(input)
+	partition.AddSuccessors(bottom)
+	bottom.ClearPredecessors()
+	bottom.AddPredecessors(partition)
+
+	merge := plan.CreatePhysicalNode("mergeGroups_"+top.ID(), &PartitionMergeProcedureSpec{
+		Factor:             factor,
+		PartitionedByGroup: true,
+	})
+	top.AddSuccessors(merge)
+	merge.AddPredecessors(top)
+	return merge, true, nil
+}
+
+// partitionsGroups reports whether the node can run on partitions of the group keys.
+func partitionsGroups(node plan.Node) bool {
+	spec, ok := node.ProcedureSpec().(plan.PassThroughAttributer)
+	return ok && spec.PassThroughAttribute(plan.ParallelGroupRunKey)
+}
+
+// partitionExcludeColumns returns the group key columns that the operation
+// may change. The operation can combine tables whose group keys only differ
+// in these columns, so they must not be used to partition its input.
+func partitionExcludeColumns(spec plan.ProcedureSpec) []string {
+	switch spec := spec.(type) {
+	case *MapProcedureSpec:
+		labels, _ := mapModifiedColumns(spec.Fn.Fn)
+		return labels
+	case *WindowProcedureSpec:
+		return []string{spec.TimeColumn, spec.StartColumn, spec.StopColumn}
+	case *PivotProcedureSpec:
+		return append(append([]string{}, spec.ColumnKey...), spec.ValueColumn)
+	case *SortedPivotProcedureSpec:
+		return append(append([]string{}, spec.ColumnKey...), spec.ValueColumn)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,257 @@
+package universe_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	fluxfeature "github.com/influxdata/flux/internal/feature"
+	"github.com/influxdata/flux/internal/pkg/feature"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+	"github.com/influxdata/flux/stdlib/universe"
+)
+
+// statsSourceSpec is a source that reports a fixed cardinality.
+type statsSourceSpec struct {
+	cardinality int64
+}
+
+func (s statsSourceSpec) Kind() plan.ProcedureKind {
+	return "stats-source"
+}
+
+func (s statsSourceSpec) Copy() plan.ProcedureSpec {
+	return s
+}
+
+func (s statsSourceSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	return plan.Cost{}, plan.Statistics{Cardinality: s.cardinality}
+}
+
+func TestParallelizeGroupsRule(t *testing.T) {
+	ctx := feature.Inject(context.Background(), executetest.TestFlagger{
+		fluxfeature.QueryParallelism().Key(): 4,
+	})
+
+	var (
+		filter = &universe.FilterProcedureSpec{
+			Fn: interpreter.ResolvedFunction{
+				Fn: executetest.FunctionExpression(t, `(r) => r._value > 0.0`),
+			},
+		}
+		window = &universe.WindowProcedureSpec{
+			Window: plan.WindowSpec{
+				Every:  flux.ConvertDuration(time.Minute),
+				Period: flux.ConvertDuration(time.Minute),
+			},
+			TimeColumn:  execute.DefaultTimeColLabel,
+			StartColumn: execute.DefaultStartColLabel,
+			StopColumn:  execute.DefaultStopColLabel,
+		}
+		mean = &universe.MeanProcedureSpec{
+			SimpleAggregateConfig: execute.DefaultSimpleAggregateConfig,
+		}
+		mapWith = &universe.MapProcedureSpec{
+			Fn: interpreter.ResolvedFunction{
+				Fn: executetest.FunctionExpression(t, `(r) => ({r with host: "a", _value: r._value * 2.0})`),
+			},
+		}
+		mapObject = &universe.MapProcedureSpec{
+			Fn: interpreter.ResolvedFunction{
+				Fn: executetest.FunctionExpression(t, `(r) => ({_value: r._value * 2.0})`),
+			},
+		}
+		group = &universe.GroupProcedureSpec{
+			GroupMode: flux.GroupModeBy,
+			GroupKeys: []string{"host"},
+		}
+	)
+
+	// from returns a source that is large enough to be read
+	// by the maximum number of partitions.
+	from := func() plan.Node {
+		return plan.CreatePhysicalNode("from", statsSourceSpec{
+			cardinality: 100 * plan.RowsPerParallelPartition,
+		})
+	}
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name: "filter window mean",
+			// from -> filter -> window -> mean => from -> partition -> filter -> window -> mean -> merge
+			Context: ctx,
+			Rules:   []plan.Rule{universe.ParallelizeGroupsRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					from(),
+					plan.CreatePhysicalNode("filter", filter),
+					plan.CreatePhysicalNode("window", window),
+					plan.CreatePhysicalNode("mean", mean),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}, {2, 3}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					from(),
+					plan.CreatePhysicalNode("partitionGroups_filter", &universe.PartitionGroupsProcedureSpec{
+						Factor:  4,
+						Exclude: []string{"_time", "_start", "_stop"},
+					}),
+					plan.CreatePhysicalNode("filter", filter),
+					plan.CreatePhysicalNode("window", window),
+					plan.CreatePhysicalNode("mean", mean),
+					plan.CreatePhysicalNode("mergeGroups_mean", &universe.PartitionMergeProcedureSpec{
+						Factor:             4,
+						PartitionedByGroup: true,
+					}),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}},
+			},
+		},
+		{
+			Name: "map extending the record",
+			// from -> map -> mean => from -> partition -> map -> mean -> merge
+			Context: ctx,
+			Rules:   []plan.Rule{universe.ParallelizeGroupsRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					from(),
+					plan.CreatePhysicalNode("map", mapWith),
+					plan.CreatePhysicalNode("mean", mean),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					from(),
+					plan.CreatePhysicalNode("partitionGroups_map", &universe.PartitionGroupsProcedureSpec{
+						Factor:  4,
+						Exclude: []string{"host", "_value"},
+					}),
+					plan.CreatePhysicalNode("map", mapWith),
+					plan.CreatePhysicalNode("mean", mean),
+					plan.CreatePhysicalNode("mergeGroups_mean", &universe.PartitionMergeProcedureSpec{
+						Factor:             4,
+						PartitionedByGroup: true,
+					}),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}},
+			},
+		},
+		{
+			Name: "map replacing the record",
+			// from -> filter -> map => from -> partition -> filter -> merge -> map
+			Context: ctx,
+			Rules:   []plan.Rule{universe.ParallelizeGroupsRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					from(),
+					plan.CreatePhysicalNode("filter", filter),
+					plan.CreatePhysicalNode("map", mapObject),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					from(),
+					plan.CreatePhysicalNode("partitionGroups_filter", &universe.PartitionGroupsProcedureSpec{
+						Factor: 4,
+					}),
+					plan.CreatePhysicalNode("filter", filter),
+					plan.CreatePhysicalNode("mergeGroups_filter", &universe.PartitionMergeProcedureSpec{
+						Factor:             4,
+						PartitionedByGroup: true,
+					}),
+					plan.CreatePhysicalNode("map", mapObject),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}},
+			},
+		},
+		{
+			Name: "group",
+			// from -> group -> mean => from -> group -> partition -> mean -> merge
+			Context: ctx,
+			Rules:   []plan.Rule{universe.ParallelizeGroupsRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					from(),
+					plan.CreatePhysicalNode("group", group),
+					plan.CreatePhysicalNode("mean", mean),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					from(),
+					plan.CreatePhysicalNode("group", group),
+					plan.CreatePhysicalNode("partitionGroups_mean", &universe.PartitionGroupsProcedureSpec{
+						Factor: 4,
+					}),
+					plan.CreatePhysicalNode("mean", mean),
+					plan.CreatePhysicalNode("mergeGroups_mean", &universe.PartitionMergeProcedureSpec{
+						Factor:             4,
+						PartitionedByGroup: true,
+					}),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}},
+			},
+		},
+		{
+			Name: "small input",
+			// The input is read by a single partition.
+			Context: ctx,
+			Rules:   []plan.Rule{universe.ParallelizeGroupsRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", statsSourceSpec{cardinality: 10}),
+					plan.CreatePhysicalNode("filter", filter),
+					plan.CreatePhysicalNode("mean", mean),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+		{
+			Name: "unknown cardinality",
+			// The size of the input is not known.
+			Context: ctx,
+			Rules:   []plan.Rule{universe.ParallelizeGroupsRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plantest.CreatePhysicalMockNode("from"),
+					plan.CreatePhysicalNode("filter", filter),
+					plan.CreatePhysicalNode("mean", mean),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+		{
+			Name: "disabled",
+			// The queryParallelism flag is not set.
+			Rules: []plan.Rule{universe.ParallelizeGroupsRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					from(),
+					plan.CreatePhysicalNode("filter", filter),
+					plan.CreatePhysicalNode("mean", mean),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
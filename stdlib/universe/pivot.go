@@ -11,6 +11,7 @@ import (
 	"github.com/influxdata/flux/array"
 	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/spill"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/table"
 	"github.com/influxdata/flux/internal/errors"
@@ -140,6 +141,13 @@ func newPivotProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.Proc
 	return p, nil
 }
 
+// PassThroughAttribute implements plan.PassThroughAttributer.
+// Pivot only removes the column key and the value column from the group key,
+// so it can run on partitions of the group keys that ignore them.
+func (s *PivotProcedureSpec) PassThroughAttribute(attrKey string) bool {
+	return attrKey == plan.ParallelGroupRunKey
+}
+
 func (s *PivotProcedureSpec) Kind() plan.ProcedureKind {
 	return PivotKind
 }
@@ -159,6 +167,11 @@ func createPivotTransformation(id execute.DatasetID, mode execute.AccumulationMo
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
 
+	if spiller := spill.New(a.Context(), a.Allocator()); spiller != nil {
+		t := newSpillingPivotTransformation(id, s, spiller, a.Allocator())
+		return t, t.d, nil
+	}
+
 	cache := execute.NewTableBuilderCache(a.Allocator())
 	d := execute.NewDataset(id, mode, cache)
 	t := NewPivotTransformation(d, cache, s)
@@ -415,6 +428,140 @@ func (t *pivotTransformation) Finish(id execute.DatasetID, err error) {
 	t.d.Finish(err)
 }
 
+// spillingPivotTransformation buffers the input tables of pivot so they
+// can be moved to disk when memory is under pressure. The input tables
+// are pivoted one output table at a time when the input is finished so
+// only a single pivoted table is held in memory.
+type spillingPivotTransformation struct {
+	execute.ExecutionNode
+	d       *execute.PassthroughDataset
+	spec    *PivotProcedureSpec
+	spiller *spill.Spiller
+	alloc   memory.Allocator
+
+	// groups holds the buffered input tables for each output group key.
+	groups *execute.GroupLookup
+}
+
+// pivotSpillGroup holds the buffered input tables of a single output table
+// in the order they were received.
+type pivotSpillGroup struct {
+	builders []*spill.Builder
+	index    *execute.GroupLookup
+}
+
+func newSpillingPivotTransformation(id execute.DatasetID, spec *PivotProcedureSpec, spiller *spill.Spiller, alloc memory.Allocator) *spillingPivotTransformation {
+	return &spillingPivotTransformation{
+		d:       execute.NewPassthroughDataset(id),
+		spec:    spec,
+		spiller: spiller,
+		alloc:   alloc,
+		groups:  execute.NewGroupLookup(),
+	}
+}
+
+func (t *spillingPivotTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *spillingPivotTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	// Input tables with different group keys are buffered separately
+	// because their value columns may have different types.
+	key := t.outputKey(tbl.Key())
+	gr := t.groups.LookupOrCreate(key, func() interface{} {
+		return &pivotSpillGroup{index: execute.NewGroupLookup()}
+	}).(*pivotSpillGroup)
+	b := gr.index.LookupOrCreate(tbl.Key(), func() interface{} {
+		b := spill.NewBuilder(tbl.Key(), t.spiller, t.alloc)
+		gr.builders = append(gr.builders, b)
+		return b
+	}).(*spill.Builder)
+
+	if err := b.AppendTable(tbl); err != nil {
+		return err
+	}
+	if !t.spiller.ShouldSpill() {
+		return nil
+	}
+
+	// Memory is under pressure so move the buffered
+	// input tables for every group to disk.
+	return t.groups.Range(func(key flux.GroupKey, value interface{}) error {
+		for _, b := range value.(*pivotSpillGroup).builders {
+			if err := b.Spill(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// outputKey computes the group key of the pivoted table by removing
+// the columns in the column key and the value column.
+func (t *spillingPivotTransformation) outputKey(key flux.GroupKey) flux.GroupKey {
+	cols := make([]flux.ColMeta, 0, len(key.Cols()))
+	vs := make([]values.Value, 0, len(key.Cols()))
+	for i, col := range key.Cols() {
+		if col.Label == t.spec.ValueColumn || execute.ContainsStr(t.spec.ColumnKey, col.Label) {
+			continue
+		}
+		cols = append(cols, col)
+		vs = append(vs, key.Value(i))
+	}
+	return execute.NewGroupKey(cols, vs)
+}
+
+// pivot pivots the buffered input tables of a single output table.
+func (t *spillingPivotTransformation) pivot(id execute.DatasetID, key flux.GroupKey, gr *pivotSpillGroup) (flux.Table, error) {
+	cache := execute.NewTableBuilderCache(t.alloc)
+	pt := NewPivotTransformation(nil, cache, t.spec)
+	defer cache.ExpireTable(key)
+
+	for len(gr.builders) > 0 {
+		b := gr.builders[0]
+		gr.builders = gr.builders[1:]
+		tbl, err := b.Table()
+		b.Release()
+		if err != nil {
+			return nil, err
+		}
+		if err := pt.Process(id, tbl); err != nil {
+			return nil, err
+		}
+	}
+	return cache.Table(key)
+}
+
+func (t *spillingPivotTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+
+func (t *spillingPivotTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *spillingPivotTransformation) Finish(id execute.DatasetID, err error) {
+	if err == nil {
+		err = t.groups.Range(func(key flux.GroupKey, value interface{}) error {
+			tbl, err := t.pivot(id, key, value.(*pivotSpillGroup))
+			if err != nil {
+				return err
+			}
+			return t.d.Process(tbl)
+		})
+	}
+
+	// Release the buffers of any group that was not pivoted.
+	_ = t.groups.Range(func(key flux.GroupKey, value interface{}) error {
+		for _, b := range value.(*pivotSpillGroup).builders {
+			b.Release()
+		}
+		return nil
+	})
+	t.groups.Clear()
+	t.d.Finish(err)
+}
+
 type SortedPivotProcedureSpec struct {
 	plan.DefaultCost
 	RowKey      []string
@@ -422,6 +569,11 @@ type SortedPivotProcedureSpec struct {
 	ValueColumn string
 }
 
+// PassThroughAttribute implements plan.PassThroughAttributer.
+func (s *SortedPivotProcedureSpec) PassThroughAttribute(attrKey string) bool {
+	return attrKey == plan.ParallelGroupRunKey
+}
+
 func (s *SortedPivotProcedureSpec) Kind() plan.ProcedureKind {
 	return SortedPivotKind
 }
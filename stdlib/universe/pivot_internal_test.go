@@ -3,6 +3,7 @@ package universe
 import (
 	"context"
 
+	"github.com/influxdata/flux/dependencies/spill"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/memory"
 )
@@ -14,3 +15,10 @@ import (
 func NewSortedPivotTransformation(ctx context.Context, spec SortedPivotProcedureSpec, id execute.DatasetID, alloc memory.Allocator) (execute.Transformation, execute.Dataset, error) {
 	return newSortedPivotTransformation(ctx, spec, id, alloc)
 }
+
+// NewSpillingPivotTransformation is exposed so the tests can create a
+// pivot transformation that spills its input with the given spiller.
+func NewSpillingPivotTransformation(id execute.DatasetID, spec *PivotProcedureSpec, spiller *spill.Spiller, mem memory.Allocator) (execute.Transformation, execute.Dataset) {
+	t := newSpillingPivotTransformation(id, spec, spiller, mem)
+	return t, t.d
+}
@@ -2,11 +2,14 @@ package universe_test
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/spill"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/internal/errors"
@@ -813,6 +816,39 @@ func TestPivot_Process(t *testing.T) {
 				},
 			)
 		})
+		t.Run(tc.name+" with spill", func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "flux-pivot-spill")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			// Simulate memory pressure so every input
+			// table is spilled to disk.
+			limit := int64(1000)
+			pressure := &memory.ResourceAllocator{Limit: &limit}
+			if err := pressure.Account(900); err != nil {
+				t.Fatal(err)
+			}
+			ctx := spill.Inject(context.Background(), spill.Config{Dir: dir})
+			spiller := spill.New(ctx, pressure)
+
+			executetest.ProcessTestHelper2(
+				t,
+				tc.data,
+				tc.want,
+				tc.wantErr,
+				func(id execute.DatasetID, alloc memory.Allocator) (execute.Transformation, execute.Dataset) {
+					return universe.NewSpillingPivotTransformation(id, tc.spec, spiller, pressure)
+				},
+			)
+
+			if files, err := ioutil.ReadDir(dir); err != nil {
+				t.Fatal(err)
+			} else if len(files) != 0 {
+				t.Errorf("expected spill files to be removed, found %d", len(files))
+			}
+		})
 	}
 }
 
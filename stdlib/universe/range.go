@@ -128,6 +128,17 @@ func (s *RangeProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+// Relative bounds are written as the times they resolve to.
+func (s *RangeProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.Int(s.Bounds.Start.Time(s.Bounds.Now).UnixNano())
+	f.Int(s.Bounds.Stop.Time(s.Bounds.Now).UnixNano())
+	f.String(s.TimeColumn)
+	f.String(s.StartColumn)
+	f.String(s.StopColumn)
+	return true
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *RangeProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
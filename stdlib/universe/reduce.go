@@ -4,6 +4,7 @@ import (
 	"context"
 	"sort"
 
+	"github.com/apache/arrow/go/v7/arrow/memory"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/compiler"
@@ -96,17 +97,29 @@ func (s *ReduceProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// IncrementalTimeColumn implements execute.IncrementalProcedureSpec.
+func (s *ReduceProcedureSpec) IncrementalTimeColumn() string {
+	return execute.DefaultTimeColLabel
+}
+
 func createReduceTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
 	s, ok := spec.(*ReduceProcedureSpec)
 	if !ok {
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
+	state, err := execute.NewIncrementalState(a, execute.DefaultTimeColLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	cache := execute.NewTableBuilderCache(a.Allocator())
 	d := execute.NewDataset(id, mode, cache)
 	t, err := NewReduceTransformation(a.Context(), s, d, cache)
 	if err != nil {
 		return nil, nil, err
 	}
+	t.state = state
+	t.mem = a.Allocator()
 	return t, d, nil
 }
 
@@ -117,6 +130,11 @@ type reduceTransformation struct {
 	ctx      context.Context
 	fn       *execute.RowReduceFn
 	identity values.Object
+
+	// state is the state saved between runs of the query.
+	// It is nil if the query is not run incrementally.
+	state *execute.IncrementalState
+	mem   memory.Allocator
 }
 
 func NewReduceTransformation(ctx context.Context, spec *ReduceProcedureSpec, d execute.Dataset, cache execute.TableBuilderCache) (*reduceTransformation, error) {
@@ -139,9 +157,29 @@ func (t *reduceTransformation) Process(id execute.DatasetID, tbl flux.Table) err
 	}
 
 	// Start the reduce operation with the neutral element as the accumulator.
+	// When the query is run incrementally, the accumulator from
+	// the previous run is used instead.
 	const accumulatorParamName = "accumulator"
-	params := map[string]values.Value{accumulatorParamName: t.identity}
+	var accumulator values.Value = t.identity
+	if t.state != nil {
+		if data, ok := t.state.Restore(tbl.Key()); ok {
+			if err := execute.DecodeState(data, &accumulator); err != nil {
+				return err
+			}
+		}
+	}
+	params := map[string]values.Value{accumulatorParamName: accumulator}
 	if err := tbl.Do(func(cr flux.ColReader) error {
+		if t.state != nil {
+			// Only process the rows that are newer than the previous run.
+			buffer, err := t.state.Filter(cr, t.mem)
+			if err != nil {
+				return err
+			}
+			defer buffer.Release()
+			cr = buffer
+		}
+
 		l := cr.Len()
 		for i := 0; i < l; i++ {
 			// the RowReduce function type takes a row of values, and an accumulator value, and
@@ -157,9 +195,21 @@ func (t *reduceTransformation) Process(id execute.DatasetID, tbl flux.Table) err
 		return err
 	}
 
+	if t.state != nil {
+		data, err := execute.EncodeState(params[accumulatorParamName])
+		if err != nil {
+			return err
+		}
+		t.state.Set(tbl.Key(), data)
+	}
+	return t.appendResult(tbl.Key(), params[accumulatorParamName].Object())
+}
+
+// appendResult writes the final value of the accumulator
+// to the table for the group key.
+func (t *reduceTransformation) appendResult(inKey flux.GroupKey, m values.Object) error {
 	// Compute the group key by replacing columns from the reducer if needed.
-	m := params[accumulatorParamName].Object()
-	key := t.computeGroupKey(tbl.Key(), m)
+	key := t.computeGroupKey(inKey, m)
 
 	builder, created := t.cache.TableBuilder(key)
 	if !created {
@@ -242,5 +292,27 @@ func (t *reduceTransformation) UpdateProcessingTime(id execute.DatasetID, pt exe
 	return t.d.UpdateProcessingTime(pt)
 }
 func (t *reduceTransformation) Finish(id execute.DatasetID, err error) {
+	if err == nil && t.state != nil {
+		err = t.finishState()
+	}
 	t.d.Finish(err)
 }
+
+// finishState produces the results for the groups that the previous run
+// saved but that had no table in this run and saves the state.
+func (t *reduceTransformation) finishState() error {
+	if err := t.state.Remaining(func(key flux.GroupKey, data []byte) error {
+		var accumulator values.Value
+		if err := execute.DecodeState(data, &accumulator); err != nil {
+			return err
+		}
+		if accumulator.Type().Nature() != semantic.Object {
+			return errors.Newf(codes.Internal, "unexpected reduce state of type %s", accumulator.Type())
+		}
+		t.state.Set(key, data)
+		return t.appendResult(key, accumulator.Object())
+	}); err != nil {
+		return err
+	}
+	return t.state.Save()
+}
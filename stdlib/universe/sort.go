@@ -2,6 +2,8 @@ package universe
 
 import (
 	"container/heap"
+	"context"
+	"io"
 	"sort"
 
 	"github.com/apache/arrow/go/v7/arrow/memory"
@@ -9,10 +11,12 @@ import (
 	"github.com/influxdata/flux/array"
 	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/spill"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/table"
 	"github.com/influxdata/flux/internal/arrowutil"
 	"github.com/influxdata/flux/internal/errors"
+	itable "github.com/influxdata/flux/internal/execute/table"
 	"github.com/influxdata/flux/internal/mutable"
 	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/plan"
@@ -100,6 +104,19 @@ func (s *SortProcedureSpec) Copy() plan.ProcedureSpec {
 	return &ns
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+func (s *SortProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.Strings(s.Columns)
+	f.Bool(s.Desc)
+	return true
+}
+
+// Cost reports the cost of sorting every row of the input.
+func (s *SortProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	stats := plan.MergeStatistics(inStats)
+	return plan.SortCost(stats), stats
+}
+
 func (s *SortProcedureSpec) OutputAttributes() plan.PhysicalAttributes {
 	return plan.PhysicalAttributes{
 		plan.CollationKey: &plan.CollationAttr{
@@ -119,7 +136,8 @@ func createSortTransformation(id execute.DatasetID, mode execute.AccumulationMod
 	if !ok {
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
-	return NewSortTransformation(id, s, a.Allocator())
+	t := newSortTransformation(id, s, spill.New(a.Context(), a.Allocator()), a.Allocator())
+	return t, t.d, nil
 }
 
 type sortTransformation struct {
@@ -128,20 +146,30 @@ type sortTransformation struct {
 	mem     memory.Allocator
 	cols    []string
 	compare arrowutil.CompareFunc
+
+	// spiller is used to write sorted runs to disk
+	// when memory is under pressure. It may be nil.
+	spiller *spill.Spiller
 }
 
 func NewSortTransformation(id execute.DatasetID, spec *SortProcedureSpec, mem memory.Allocator) (execute.Transformation, execute.Dataset, error) {
+	t := newSortTransformation(id, spec, nil, mem)
+	return t, t.d, nil
+}
+
+func newSortTransformation(id execute.DatasetID, spec *SortProcedureSpec, spiller *spill.Spiller, mem memory.Allocator) *sortTransformation {
 	t := &sortTransformation{
 		d:       execute.NewPassthroughDataset(id),
 		mem:     mem,
 		cols:    spec.Columns,
 		compare: arrowutil.Compare,
+		spiller: spiller,
 	}
 	if spec.Desc {
 		// If descending, use the descending comparison.
 		t.compare = arrowutil.CompareDesc
 	}
-	return t, t.d, nil
+	return t
 }
 
 func (s *sortTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
@@ -152,19 +180,103 @@ func (s *sortTransformation) Process(id execute.DatasetID, tbl flux.Table) error
 		sortCols: sortCols,
 		compare:  s.compare,
 	}
+	var runs []*spill.File
 	if err := tbl.Do(func(cr flux.ColReader) error {
-		return s.processView(mh, cr)
+		if err := s.processView(mh, cr); err != nil {
+			return err
+		}
+		if !s.spiller.ShouldSpill() {
+			return nil
+		}
+		// Memory is under pressure so write the buffered views
+		// to disk as a sorted run.
+		run, err := s.spill(mh)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, run)
+		return nil
 	}); err != nil {
+		for _, item := range mh.items {
+			item.Release()
+		}
+		for _, run := range runs {
+			_ = run.Remove()
+		}
 		return err
 	}
 
-	out, err := mh.Table(-1, s.mem)
+	var (
+		out flux.Table
+		err error
+	)
+	if len(runs) > 0 {
+		out, err = s.mergeRuns(mh, runs)
+	} else {
+		out, err = mh.Table(-1, s.mem)
+	}
 	if err != nil {
 		return err
 	}
 	return s.d.Process(out)
 }
 
+// spill merges the items in the heap into a sorted run
+// that is written to a spill file.
+func (s *sortTransformation) spill(mh *sortTableMergeHeap) (*spill.File, error) {
+	run, err := s.spiller.Create(mh.cols, s.mem)
+	if err != nil {
+		return nil, err
+	}
+	if err := mh.Merge(-1, s.mem, func(buffer *arrow.TableBuffer) error {
+		return run.Write(buffer)
+	}); err != nil {
+		_ = run.Remove()
+		return nil, err
+	}
+	return run, nil
+}
+
+// mergeRuns produces a table by merging the sorted runs on disk with
+// the items that remain in memory. The table reads each run one buffer
+// at a time and the runs are removed once the table has been read.
+func (s *sortTransformation) mergeRuns(mh *sortTableMergeHeap, runs []*spill.File) (flux.Table, error) {
+	return itable.Stream(mh.key, mh.cols, func(ctx context.Context, w *itable.StreamWriter) error {
+		defer func() {
+			for _, item := range mh.items {
+				item.Release()
+			}
+			mh.items = nil
+			for _, run := range runs {
+				_ = run.Remove()
+			}
+		}()
+
+		for _, run := range runs {
+			r, err := run.Open(mh.key)
+			if err != nil {
+				return err
+			}
+			item := &sortTableMergeHeapItem{run: r, pending: run.Len()}
+			if !item.load() {
+				err := item.err
+				item.Release()
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			mh.items = append(mh.items, item)
+		}
+
+		return mh.Merge(-1, s.mem, func(buffer *arrow.TableBuffer) error {
+			out := *buffer
+			out.Retain()
+			return w.UnsafeWriteBuffer(&out)
+		})
+	})
+}
+
 func (s *sortTransformation) sortCols(key flux.GroupKey, cols []flux.ColMeta) []int {
 	sortCols := make([]int, 0, len(s.cols))
 	for _, col := range s.cols {
@@ -268,12 +380,21 @@ type sortTableMergeHeapItem struct {
 	cr        flux.ColReader
 	indices   *array.Int
 	i, offset int
+
+	// run is set when the item reads a sorted run from a spill file.
+	// Each buffer in the run is already sorted and the next buffer
+	// is read when the current one is exhausted. pending is the number
+	// of rows in the run that have not been read yet and err is the
+	// error that stopped reading the run.
+	run     *spill.Reader
+	pending int
+	err     error
 }
 
 func (s *sortTableMergeHeapItem) Next() bool {
 	s.i++
 	if s.i >= s.cr.Len() {
-		return false
+		return s.load()
 	}
 	s.offset = s.i
 	if s.indices != nil {
@@ -282,6 +403,34 @@ func (s *sortTableMergeHeapItem) Next() bool {
 	return true
 }
 
+// load reads the next buffer of the run.
+// It returns false if there is no run or the run has been read.
+func (s *sortTableMergeHeapItem) load() bool {
+	if s.run == nil {
+		return false
+	}
+	for {
+		buf, err := s.run.Next()
+		if err != nil {
+			if err != io.EOF {
+				s.err = err
+			}
+			return false
+		}
+		if buf.Len() == 0 {
+			buf.Release()
+			continue
+		}
+
+		if s.cr != nil {
+			s.cr.Release()
+		}
+		s.cr, s.i, s.offset = buf, 0, 0
+		s.pending -= buf.Len()
+		return true
+	}
+}
+
 func (s *sortTableMergeHeapItem) Release() {
 	if s.indices != nil {
 		s.indices.Release()
@@ -291,6 +440,10 @@ func (s *sortTableMergeHeapItem) Release() {
 		s.cr.Release()
 		s.cr = nil
 	}
+	if s.run != nil {
+		s.run.Release()
+		s.run = nil
+	}
 }
 
 type sortTableMergeHeap struct {
@@ -299,6 +452,10 @@ type sortTableMergeHeap struct {
 	items    []*sortTableMergeHeapItem
 	sortCols []int
 	compare  arrowutil.CompareFunc
+
+	// err is the error from the last item that failed
+	// to read the next buffer of its run.
+	err error
 }
 
 func (s *sortTableMergeHeap) Len() int {
@@ -334,7 +491,7 @@ func (s *sortTableMergeHeap) Pop() interface{} {
 func (s *sortTableMergeHeap) ValueLen() int {
 	var n int
 	for _, item := range s.items {
-		n += item.cr.Len() - item.i
+		n += item.cr.Len() - item.i + item.pending
 	}
 	return n
 }
@@ -350,6 +507,27 @@ func (s *sortTableMergeHeap) Table(limit int, mem memory.Allocator) (flux.Table,
 
 	// Construct the buffered builder that will contain the full table.
 	builder := table.NewBufferedBuilder(s.key, mem)
+	if err := s.Merge(limit, mem, func(buffer *arrow.TableBuffer) error {
+		return builder.AppendBuffer(buffer)
+	}); err != nil {
+		builder.Release()
+		return nil, err
+	}
+	return builder.Table()
+}
+
+// Merge merges the items in the heap and calls the function with
+// each merged buffer in sorted order. The buffer is released after
+// the function returns. All of the items are released when this returns.
+func (s *sortTableMergeHeap) Merge(limit int, mem memory.Allocator, fn func(buffer *arrow.TableBuffer) error) error {
+	// Release the remaining items and clear the items.
+	// There are either none left or the remaining ones were filtered.
+	defer func() {
+		for _, item := range s.items {
+			item.Release()
+		}
+		s.items = s.items[:0]
+	}()
 
 	// Initialize the heap now that we have all of the data.
 	heap.Init(s)
@@ -398,22 +576,16 @@ func (s *sortTableMergeHeap) Table(limit int, mem memory.Allocator) (flux.Table,
 		if limit > 0 {
 			limit -= buffer.Len()
 		}
-		if err := builder.AppendBuffer(&buffer); err != nil {
-			buffer.Release()
-			return nil, err
-		}
+		err := fn(&buffer)
 		buffer.Release()
+		if err != nil {
+			return err
+		}
+		if s.err != nil {
+			return s.err
+		}
 	}
-
-	// Release the remaining items and clear the items.
-	// There are either none left or the remaining ones were filtered.
-	for _, item := range s.items {
-		item.Release()
-	}
-	s.items = s.items[:0]
-
-	// Determine the next buffer size.
-	return builder.Table()
+	return nil
 }
 
 func (s *sortTableMergeHeap) NextBuffer(builders []array.Builder, keys []array.Array, n int, mem memory.Allocator) arrow.TableBuffer {
@@ -442,8 +614,19 @@ func (s *sortTableMergeHeap) NextBuffer(builders []array.Builder, keys []array.A
 		} else {
 			// Remove this item from the heap since it
 			// no longer has anymore rows.
+			if item.err != nil {
+				s.err = item.err
+			}
 			item.Release()
 			heap.Pop(s)
+
+			// Stop reading if a run could not be read.
+			// The rows that were read are returned and
+			// the error is reported by Merge.
+			if s.err != nil {
+				n = i + 1
+				break
+			}
 		}
 	}
 
@@ -0,0 +1,14 @@
+package universe
+
+import (
+	"github.com/influxdata/flux/dependencies/spill"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/memory"
+)
+
+// NewSpillingSortTransformation is exposed so the tests can create a
+// sort transformation that writes sorted runs with the given spiller.
+func NewSpillingSortTransformation(id execute.DatasetID, spec *SortProcedureSpec, spiller *spill.Spiller, mem memory.Allocator) (execute.Transformation, execute.Dataset, error) {
+	t := newSortTransformation(id, spec, spiller, mem)
+	return t, t.d, nil
+}
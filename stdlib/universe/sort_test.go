@@ -1,11 +1,16 @@
 package universe_test
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/spill"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/execute/table"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/querytest"
 	"github.com/influxdata/flux/stdlib/universe"
@@ -397,3 +402,61 @@ func TestSort_Process(t *testing.T) {
 		})
 	}
 }
+
+func TestSort_Spill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flux-sort-spill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// Simulate memory pressure by accounting for memory
+	// that is above the spill threshold.
+	limit := int64(1000)
+	pressure := &memory.ResourceAllocator{Limit: &limit}
+	if err := pressure.Account(900); err != nil {
+		t.Fatal(err)
+	}
+	ctx := spill.Inject(context.Background(), spill.Config{Dir: dir})
+	spiller := spill.New(ctx, pressure)
+
+	// Use enough rows that each sorted run is
+	// read back from disk in multiple buffers.
+	n := 3 * table.BufferSize
+	input := &executetest.Table{
+		KeyCols: []string{"t0"},
+		ColMeta: []flux.ColMeta{
+			{Label: "t0", Type: flux.TString},
+			{Label: "_value", Type: flux.TInt},
+		},
+	}
+	want := &executetest.Table{
+		KeyCols: []string{"t0"},
+		ColMeta: input.ColMeta,
+	}
+	for i := 0; i < n; i++ {
+		input.Data = append(input.Data, []interface{}{"a", int64(n - i - 1)})
+		want.Data = append(want.Data, []interface{}{"a", int64(i)})
+	}
+
+	executetest.ProcessTestHelper2(
+		t,
+		[]flux.Table{input},
+		[]*executetest.Table{want},
+		nil,
+		func(id execute.DatasetID, alloc memory.Allocator) (execute.Transformation, execute.Dataset) {
+			spec := &universe.SortProcedureSpec{Columns: []string{"_value"}}
+			tr, d, err := universe.NewSpillingSortTransformation(id, spec, spiller, alloc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return tr, d
+		},
+	)
+
+	if files, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(files) != 0 {
+		t.Errorf("expected spill files to be removed, found %d", len(files))
+	}
+}
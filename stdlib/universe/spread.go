@@ -75,6 +75,11 @@ func (s *SpreadProcedureSpec) Copy() plan.ProcedureSpec {
 	}
 }
 
+// IncrementalTimeColumn implements execute.IncrementalProcedureSpec.
+func (s *SpreadProcedureSpec) IncrementalTimeColumn() string {
+	return execute.DefaultTimeColLabel
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *SpreadProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
@@ -85,7 +90,7 @@ func createSpreadTransformation(id execute.DatasetID, mode execute.AccumulationM
 	if !ok {
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
-	return execute.NewSimpleAggregateTransformation(a.Context(), id, new(SpreadAgg), s.SimpleAggregateConfig, a.Allocator())
+	return execute.NewIncrementalAggregateTransformation(id, new(SpreadAgg), s.SimpleAggregateConfig, a)
 }
 
 // SpreadAgg finds the difference between the max and min values a table
@@ -133,6 +138,30 @@ func (a *SpreadAgg) IsNull() bool {
 	return !a.minSet || !a.maxSet
 }
 
+func (a *SpreadIntAgg) MarshalBinary() ([]byte, error) {
+	return execute.EncodeState(a.minSet, a.maxSet, a.min, a.max)
+}
+
+func (a *SpreadIntAgg) UnmarshalBinary(data []byte) error {
+	return execute.DecodeState(data, &a.minSet, &a.maxSet, &a.min, &a.max)
+}
+
+func (a *SpreadUIntAgg) MarshalBinary() ([]byte, error) {
+	return execute.EncodeState(a.minSet, a.maxSet, a.min, a.max)
+}
+
+func (a *SpreadUIntAgg) UnmarshalBinary(data []byte) error {
+	return execute.DecodeState(data, &a.minSet, &a.maxSet, &a.min, &a.max)
+}
+
+func (a *SpreadFloatAgg) MarshalBinary() ([]byte, error) {
+	return execute.EncodeState(a.minSet, a.maxSet, a.min, a.max)
+}
+
+func (a *SpreadFloatAgg) UnmarshalBinary(data []byte) error {
+	return execute.DecodeState(data, &a.minSet, &a.maxSet, &a.min, &a.max)
+}
+
 // DoInt searches for the min and max value of the array and caches them in the aggregate
 func (a *SpreadIntAgg) DoInt(vs *array.Int) {
 	for i := 0; i < vs.Len(); i++ {
@@ -5,6 +5,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/apache/arrow/go/v7/arrow/memory"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/compiler"
@@ -129,6 +130,11 @@ func (s *StateTrackingProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// IncrementalTimeColumn implements execute.IncrementalProcedureSpec.
+func (s *StateTrackingProcedureSpec) IncrementalTimeColumn() string {
+	return s.TimeCol
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *StateTrackingProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
@@ -140,8 +146,12 @@ func createStateTrackingTransformation(id execute.DatasetID, mode execute.Accumu
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
 	mem := a.Allocator()
+	state, err := execute.NewIncrementalState(a, s.TimeCol)
+	if err != nil {
+		return nil, nil, err
+	}
 	if feature.OptimizeStateTracking().Enabled(a.Context()) {
-		return NewNarrowStateTrackingTransformation(a.Context(), s, id, mem)
+		return newNarrowStateTrackingTransformation(a.Context(), s, id, state, mem)
 	}
 
 	cache := execute.NewTableBuilderCache(mem)
@@ -150,6 +160,8 @@ func createStateTrackingTransformation(id execute.DatasetID, mode execute.Accumu
 	if err != nil {
 		return nil, nil, err
 	}
+	t.state = state
+	t.mem = mem
 	return t, d, nil
 }
 
@@ -165,6 +177,11 @@ type stateTrackingTransformation struct {
 	durationColumn string
 
 	durationUnit int64
+
+	// state is the state saved between runs of the query.
+	// It is nil if the query is not run incrementally.
+	state *execute.IncrementalState
+	mem   memory.Allocator
 }
 
 func NewStateTrackingTransformation(ctx context.Context, spec *StateTrackingProcedureSpec, d execute.Dataset, cache execute.TableBuilderCache) (*stateTrackingTransformation, error) {
@@ -232,6 +249,14 @@ func (t *stateTrackingTransformation) Process(id execute.DatasetID, tbl flux.Tab
 		countInState    bool
 		durationInState bool
 	)
+	if t.state != nil {
+		// Continue tracking the state from the previous run.
+		if data, ok := t.state.Restore(tbl.Key()); ok {
+			if err := execute.DecodeState(data, &startTime, &prevTime, &count, &duration, &countInState, &durationInState); err != nil {
+				return err
+			}
+		}
+	}
 
 	timeIdx := execute.ColIdx(t.timeCol, tbl.Cols())
 	if timeIdx < 0 {
@@ -240,7 +265,17 @@ func (t *stateTrackingTransformation) Process(id execute.DatasetID, tbl flux.Tab
 	colMap := make([]int, len(tbl.Cols()))
 	colMap = execute.ColMap(colMap, builder, tbl.Cols())
 	// Append modified rows
-	return tbl.Do(func(cr flux.ColReader) error {
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		if t.state != nil {
+			// Only process the rows that are newer than the previous run.
+			buffer, err := t.state.Filter(cr, t.mem)
+			if err != nil {
+				return err
+			}
+			defer buffer.Release()
+			cr = buffer
+		}
+
 		l := cr.Len()
 		for i := 0; i < l; i++ {
 			match, err := fn.EvalRow(t.ctx, i, cr)
@@ -311,7 +346,18 @@ func (t *stateTrackingTransformation) Process(id execute.DatasetID, tbl flux.Tab
 			}
 		}
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	if t.state != nil {
+		data, err := execute.EncodeState(startTime, prevTime, count, duration, countInState, durationInState)
+		if err != nil {
+			return err
+		}
+		t.state.Set(tbl.Key(), data)
+	}
+	return nil
 }
 
 func (t *stateTrackingTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
@@ -321,5 +367,8 @@ func (t *stateTrackingTransformation) UpdateProcessingTime(id execute.DatasetID,
 	return t.d.UpdateProcessingTime(pt)
 }
 func (t *stateTrackingTransformation) Finish(id execute.DatasetID, err error) {
+	if err == nil && t.state != nil {
+		err = t.state.Save()
+	}
 	t.d.Finish(err)
 }
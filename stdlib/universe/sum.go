@@ -69,6 +69,17 @@ func (s *SumProcedureSpec) Copy() plan.ProcedureSpec {
 	}
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+func (s *SumProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.Strings(s.Columns)
+	return true
+}
+
+// IncrementalTimeColumn implements execute.IncrementalProcedureSpec.
+func (s *SumProcedureSpec) IncrementalTimeColumn() string {
+	return execute.DefaultTimeColLabel
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *SumProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
@@ -88,7 +99,7 @@ func createSumTransformation(id execute.DatasetID, mode execute.AccumulationMode
 	if !ok {
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
-	return execute.NewSimpleAggregateTransformation(a.Context(), id, new(SumAgg), s.SimpleAggregateConfig, a.Allocator())
+	return execute.NewIncrementalAggregateTransformation(id, new(SumAgg), s.SimpleAggregateConfig, a)
 }
 
 func (a *SumAgg) NewBoolAgg() execute.DoBoolAgg {
@@ -136,6 +147,12 @@ func (a *SumIntAgg) ValueInt() int64 {
 func (a *SumIntAgg) IsNull() bool {
 	return !a.ok
 }
+func (a *SumIntAgg) MarshalBinary() ([]byte, error) {
+	return execute.EncodeState(a.sum, a.ok)
+}
+func (a *SumIntAgg) UnmarshalBinary(data []byte) error {
+	return execute.DecodeState(data, &a.sum, &a.ok)
+}
 
 type SumUIntAgg struct {
 	sum uint64
@@ -166,6 +183,12 @@ func (a *SumUIntAgg) ValueUInt() uint64 {
 func (a *SumUIntAgg) IsNull() bool {
 	return !a.ok
 }
+func (a *SumUIntAgg) MarshalBinary() ([]byte, error) {
+	return execute.EncodeState(a.sum, a.ok)
+}
+func (a *SumUIntAgg) UnmarshalBinary(data []byte) error {
+	return execute.DecodeState(data, &a.sum, &a.ok)
+}
 
 type SumFloatAgg struct {
 	sum float64
@@ -196,3 +219,9 @@ func (a *SumFloatAgg) ValueFloat() float64 {
 func (a *SumFloatAgg) IsNull() bool {
 	return !a.ok
 }
+func (a *SumFloatAgg) MarshalBinary() ([]byte, error) {
+	return execute.EncodeState(a.sum, a.ok)
+}
+func (a *SumFloatAgg) UnmarshalBinary(data []byte) error {
+	return execute.DecodeState(data, &a.sum, &a.ok)
+}
@@ -180,6 +180,13 @@ func newWindowProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.Pro
 	return p, nil
 }
 
+// PassThroughAttribute implements plan.PassThroughAttributer.
+// Window only changes the time bounds of the group key,
+// so it can run on partitions of the group keys that ignore them.
+func (s *WindowProcedureSpec) PassThroughAttribute(attrKey string) bool {
+	return attrKey == plan.ParallelGroupRunKey
+}
+
 func (s *WindowProcedureSpec) Kind() plan.ProcedureKind {
 	return WindowKind
 }
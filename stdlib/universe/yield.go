@@ -70,6 +70,12 @@ func (s *YieldProcedureSpec) Copy() plan.ProcedureSpec {
 	return &YieldProcedureSpec{Name: s.Name}
 }
 
+// Fingerprint implements plan.FingerprintProcedureSpec.
+func (s *YieldProcedureSpec) Fingerprint(f *plan.Fingerprinter) bool {
+	f.String(s.Name)
+	return true
+}
+
 func (s *YieldProcedureSpec) YieldName() string {
 	return s.Name
 }
@@ -704,4 +704,70 @@ var binaryVectorFuncLookup = map[BinaryFuncSignature]BinaryVectorFunction{
 		}
 		return vectorPow(l, r, mem)
 	},
+	{Operator: ast.EqualOperator, Left: semantic.Vector, Right: semantic.Vector}: func(lv, rv Value, mem memory.Allocator) (Value, error) {
+		l := lv.Vector()
+		r := rv.Vector()
+		v, err := tryFoldConstants(l, r, ast.EqualOperator)
+		if err != nil {
+			return nil, err
+		} else if v != nil {
+			return v, nil
+		}
+		return vectorEq(l, r, mem)
+	},
+	{Operator: ast.NotEqualOperator, Left: semantic.Vector, Right: semantic.Vector}: func(lv, rv Value, mem memory.Allocator) (Value, error) {
+		l := lv.Vector()
+		r := rv.Vector()
+		v, err := tryFoldConstants(l, r, ast.NotEqualOperator)
+		if err != nil {
+			return nil, err
+		} else if v != nil {
+			return v, nil
+		}
+		return vectorNeq(l, r, mem)
+	},
+	{Operator: ast.LessThanOperator, Left: semantic.Vector, Right: semantic.Vector}: func(lv, rv Value, mem memory.Allocator) (Value, error) {
+		l := lv.Vector()
+		r := rv.Vector()
+		v, err := tryFoldConstants(l, r, ast.LessThanOperator)
+		if err != nil {
+			return nil, err
+		} else if v != nil {
+			return v, nil
+		}
+		return vectorLt(l, r, mem)
+	},
+	{Operator: ast.LessThanEqualOperator, Left: semantic.Vector, Right: semantic.Vector}: func(lv, rv Value, mem memory.Allocator) (Value, error) {
+		l := lv.Vector()
+		r := rv.Vector()
+		v, err := tryFoldConstants(l, r, ast.LessThanEqualOperator)
+		if err != nil {
+			return nil, err
+		} else if v != nil {
+			return v, nil
+		}
+		return vectorLte(l, r, mem)
+	},
+	{Operator: ast.GreaterThanOperator, Left: semantic.Vector, Right: semantic.Vector}: func(lv, rv Value, mem memory.Allocator) (Value, error) {
+		l := lv.Vector()
+		r := rv.Vector()
+		v, err := tryFoldConstants(l, r, ast.GreaterThanOperator)
+		if err != nil {
+			return nil, err
+		} else if v != nil {
+			return v, nil
+		}
+		return vectorGt(l, r, mem)
+	},
+	{Operator: ast.GreaterThanEqualOperator, Left: semantic.Vector, Right: semantic.Vector}: func(lv, rv Value, mem memory.Allocator) (Value, error) {
+		l := lv.Vector()
+		r := rv.Vector()
+		v, err := tryFoldConstants(l, r, ast.GreaterThanEqualOperator)
+		if err != nil {
+			return nil, err
+		} else if v != nil {
+			return v, nil
+		}
+		return vectorGte(l, r, mem)
+	},
 }